@@ -1,5 +1,10 @@
 import { ChartConfig } from "@/components/ui/chart";
-import { AreaChartType, BarChartType, PieChartType } from "@/types/Chart";
+import {
+  AreaChartType,
+  BarChartType,
+  PieChartType,
+  WalletDailySummaryType,
+} from "@/types/Chart";
 import { ChartArea, ChartBar, ChartPie } from "./charts";
 import {
   Card,
@@ -216,8 +221,17 @@ export default function Dashboard() {
   const UserSummary: UserSummaryType = userSummary?.data[0] || {};
   const UserMonthlySummary: BarChartType[] = userMonthlySummary?.data || [];
   const UserMostExpenses: PieChartType[] = userMostExpenses?.data || [];
-  const UserWalletDailySummary: AreaChartType[] =
-    userWalletDailySummary?.data || [];
+  const UserWalletDailySummary: AreaChartType[] = (
+    userWalletDailySummary?.data || []
+  ).map((item: WalletDailySummaryType) => {
+    // Wallet types without a color of their own are charted as others
+    const point: AreaChartType = { date: item.date, others: 0 };
+    Object.entries(item.types).forEach(([walletType, total]) => {
+      const key = walletType in areaChartConfig ? walletType : "others";
+      point[key] = Number(point[key] || 0) + total;
+    });
+    return point;
+  });
 
   const [UserMostExpensesWithFill, setUserMostExpensesWithFill] = useState<
     PieChartType[]
@@ -1,4 +1,4 @@
-import { MdAttachFile } from "react-icons/md";
+import { MdAttachFile, MdOutlineEmail } from "react-icons/md";
 import { IoDocumentTextOutline } from "react-icons/io5";
 import { FaRegImage } from "react-icons/fa6";
 
@@ -7,7 +7,12 @@ export default function FileIcon({ ext }: { ext: string }) {
     return <IoDocumentTextOutline />;
   }
 
+  if (ext.toLowerCase() === "email" || ext.toLowerCase() === "eml") {
+    return <MdOutlineEmail />;
+  }
+
   if (
+    ext.toLowerCase() === "image" ||
     ext.toLowerCase() === "jpg" ||
     ext.toLowerCase() === "jpeg" ||
     ext.toLowerCase() === "png" ||
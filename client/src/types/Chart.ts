@@ -1,9 +1,13 @@
+export type WalletDailySummaryType = {
+  user_id: string;
+  date: string;
+  types: Record<string, number>;
+};
+
+// Balance by wallet type, e.g. bank or e-wallet, keyed next to the date
 export type AreaChartType = {
   date: string;
-  physical: number;
-  "e-wallet": number;
-  bank: number;
-  others: number;
+  [walletType: string]: string | number;
 };
 
 export type BarChartType = {
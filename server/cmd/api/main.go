@@ -5,6 +5,7 @@ import (
 	"server/config/env"
 	"server/config/log"
 	"server/config/miniofs"
+	"server/config/pricing"
 	"server/config/queue"
 	"server/config/redis"
 	"server/interface/http/router"
@@ -45,9 +46,11 @@ func init() {
 	queue.SetupRabbitMQ(env.Cfg.RabbitMQ) // Initialize RabbitMQ connection
 	log.Info("Setup RabbitMQ Connection Success")
 
-	log.Info("Setup MinIO Connection Start")
-	miniofs.SetupMinio(env.Cfg.Minio) // Initialize MinIO connection
-	log.Info("Setup MinIO Connection Success")
+	log.Info("Setup File Storage Start")
+	miniofs.SetupStorage(env.Cfg) // Initialize the configured storage backend (MinIO or local disk)
+	log.Info("Setup File Storage Success")
+
+	pricing.SetupProvider(env.Cfg) // Initialize the configured price provider, if any
 
 	log.Info("Starting Refina API...")
 }
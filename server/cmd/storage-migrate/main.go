@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"server/config/db"
+	"server/config/env"
+	"server/config/log"
+	"server/config/miniofs"
+	"server/internal/repository"
+	"server/internal/service"
+)
+
+var (
+	from = flag.String("from", miniofs.STORAGE_DRIVER_MINIO, "source storage driver (minio|local)")
+	to   = flag.String("to", miniofs.STORAGE_DRIVER_LOCAL, "target storage driver (minio|local)")
+)
+
+func init() {
+	log.SetupLogger() // Initialize the logger configuration
+
+	var err error
+	var missing []string
+	if missing, err = env.LoadByViper(); err != nil {
+		log.Error("Failed to read JSON config file:" + err.Error())
+		log.Info("Switch loading environment variables to .env file")
+		if missing, err = env.LoadNative(); err != nil {
+			log.Log.Fatalf("Failed to load environment variables: %v", err)
+		}
+		log.Info("Environment variables by .env file loaded successfully")
+	} else {
+		log.Info("Environment variables by Viper loaded successfully")
+	}
+
+	if len(missing) > 0 {
+		for _, envVar := range missing {
+			log.Warn("Missing environment variable: " + envVar)
+		}
+	}
+
+	log.Info("Setup Database Connection Start")
+	db.SetupDatabase(env.Cfg.Database) // Initialize the database connection and run migrations
+	log.Info("Setup Database Connection Success")
+}
+
+// Copies every object from one storage backend to another and rewrites attachment references.
+// Objects are copied before references are rewritten, so the command can safely be re-run after a failure.
+func main() {
+	flag.Parse()
+
+	if *from == *to {
+		log.Log.Fatalf("Source and target storage driver must differ, got %s", *from)
+	}
+
+	source, err := miniofs.NewStorage(*from, env.Cfg)
+	if err != nil {
+		log.Log.Fatalf("Failed to initialize source storage: %v", err)
+	}
+	target, err := miniofs.NewStorage(*to, env.Cfg)
+	if err != nil {
+		log.Log.Fatalf("Failed to initialize target storage: %v", err)
+	}
+
+	ctx := context.Background()
+	for _, bucket := range miniofs.BUCKETS {
+		copied, err := miniofs.CopyObjects(ctx, source, target, bucket)
+		if err != nil {
+			log.Log.Fatalf("Failed to copy bucket %s: %v", bucket, err)
+		}
+		log.Info(fmt.Sprintf("Copied %d objects of bucket %s from %s to %s", copied, bucket, *from, *to))
+	}
+
+	txManager := repository.NewTxManager(db.DB)
+	attachmentRepo := repository.NewAttachmentsRepository(db.DB)
+	blobRepo := repository.NewAttachmentBlobsRepository(db.DB)
+	attachmentService := service.NewAttachmentsService(txManager, attachmentRepo, blobRepo, target)
+
+	rewritten, err := attachmentService.RewriteStorageReferences(ctx, source, target)
+	if err != nil {
+		log.Log.Fatalf("Failed to rewrite attachment references: %v", err)
+	}
+
+	log.Info(fmt.Sprintf("Rewrote storage references of %d attachments, set STORAGE_DRIVER=%s to use the new backend", rewritten, *to))
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"server/config/db"
+	"server/config/env"
+	"server/config/log"
+	"server/config/miniofs"
+	"server/internal/repository"
+	"server/internal/service"
+)
+
+const batchSize = 100
+
+func init() {
+	log.SetupLogger() // Initialize the logger configuration
+
+	var err error
+	var missing []string
+	if missing, err = env.LoadByViper(); err != nil {
+		log.Error("Failed to read JSON config file:" + err.Error())
+		log.Info("Switch loading environment variables to .env file")
+		if missing, err = env.LoadNative(); err != nil {
+			log.Log.Fatalf("Failed to load environment variables: %v", err)
+		}
+		log.Info("Environment variables by .env file loaded successfully")
+	} else {
+		log.Info("Environment variables by Viper loaded successfully")
+	}
+
+	if len(missing) > 0 {
+		for _, envVar := range missing {
+			log.Warn("Missing environment variable: " + envVar)
+		}
+	}
+
+	log.Info("Setup Database Connection Start")
+	db.SetupDatabase(env.Cfg.Database) // Initialize the database connection and run migrations
+	log.Info("Setup Database Connection Success")
+
+	log.Info("Setup File Storage Start")
+	miniofs.SetupStorage(env.Cfg) // Initialize the configured storage backend (MinIO or local disk)
+	log.Info("Setup File Storage Success")
+}
+
+func main() {
+	txManager := repository.NewTxManager(db.DB)
+	attachmentRepo := repository.NewAttachmentsRepository(db.DB)
+	blobRepo := repository.NewAttachmentBlobsRepository(db.DB)
+	attachmentService := service.NewAttachmentsService(txManager, attachmentRepo, blobRepo, miniofs.FileStorage)
+
+	log.Info("Backfilling attachment thumbnails...")
+	processed, err := attachmentService.BackfillThumbnails(context.Background(), batchSize)
+	if err != nil {
+		log.Log.Fatalf("Failed to backfill attachment thumbnails: %v", err)
+	}
+
+	log.Info(fmt.Sprintf("Attachment thumbnails backfilled for %d attachments", processed))
+}
@@ -2,14 +2,18 @@ package main
 
 import (
 	"context"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"server/config/db"
 	"server/config/env"
 	"server/config/log"
+	"server/config/miniofs"
 	"server/config/queue"
 	"server/config/redis"
-	"server/internal/repository"
-	"server/internal/service"
+	"server/interface/worker"
+	_ "server/interface/worker/jobs"
 )
 
 func init() {
@@ -46,6 +50,10 @@ func init() {
 	queue.SetupRabbitMQ(env.Cfg.RabbitMQ) // Initialize RabbitMQ connection
 	log.Info("Setup RabbitMQ Connection Success")
 
+	log.Info("Setup File Storage Start")
+	miniofs.SetupStorage(env.Cfg) // Reports are uploaded to the configured storage backend
+	log.Info("Setup File Storage Success")
+
 	log.Info("Starting Refina worker...")
 }
 
@@ -53,14 +61,10 @@ func main() {
 	defer log.Info("Refina worker stopped")
 	defer queue.Close() // Close RabbitMQ connection when the application exits
 
-	userRepo := repository.NewUsersRepository(db.DB)
-	reportsRepo := repository.NewReportsRepository(db.DB)
-	reportsService := service.NewReportsService(reportsRepo, userRepo)
-
-	ctx := context.Background()
-	if err := reportsService.UpdateUserReport(ctx); err != nil {
-		log.Error("Failed to update user report: " + err.Error())
-	}
+	// Jobs keep running through failed messages and lost connections until the worker is stopped
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	log.Info("Refina worker started successfully")
+	worker.Run(ctx, worker.Dependencies{DB: db.DB, Storage: miniofs.FileStorage}) // Every job registered in interface/worker/jobs
 }
@@ -2,6 +2,9 @@ package db
 
 import (
 	"fmt"
+	"time"
+	// Embeds the time zone database so Location never depends on the host
+	_ "time/tzdata"
 
 	"server/config/env"
 	"server/config/log"
@@ -10,10 +13,24 @@ import (
 	"gorm.io/gorm"
 )
 
+// TIME_ZONE is the TimeZone of every database session, dates without a time of day are read in it
+const TIME_ZONE = "Asia/Jakarta"
+
 var DB *gorm.DB
 
+// Location is TIME_ZONE, use it wherever "today" has to match the dates of the database
+var Location = mustLoadLocation(TIME_ZONE)
+
+func mustLoadLocation(name string) *time.Location {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		panic(fmt.Sprintf("failed to load time zone %s: %v", name, err))
+	}
+	return location
+}
+
 func SetupDatabase(cfg env.Database) {
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=Asia/Jakarta", cfg.DBHost, cfg.DBUser, cfg.DBPassword, cfg.DBName, cfg.DBPort)
+	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=%s", cfg.DBHost, cfg.DBUser, cfg.DBPassword, cfg.DBName, cfg.DBPort, TIME_ZONE)
 
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
 	if err != nil {
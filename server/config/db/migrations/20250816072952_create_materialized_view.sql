@@ -65,8 +65,6 @@ SELECT * FROM pivoted
 ORDER BY user_id, date;
 
 CREATE INDEX IF NOT EXISTS idx_view_user_wallet_daily_summaries_user_id ON view_user_wallet_daily_summaries (user_id, date);
-
-SELECT cron.schedule('0 18 * * *', 'REFRESH MATERIALIZED VIEW view_user_wallet_daily_summaries');
 -- +goose StatementEnd
 
 -- +goose StatementBegin
@@ -144,8 +142,6 @@ LEFT JOIN current_balance cb ON cb.user_id = u.id
 LEFT JOIN previous_balance pb ON pb.user_id = u.id;
 
 CREATE INDEX IF NOT EXISTS idx_view_user_summaries_user_id ON view_user_summaries (user_id);
-
-SELECT cron.schedule('0 18 * * *', 'REFRESH MATERIALIZED VIEW view_user_summaries');
 -- +goose StatementEnd
 
 -- +goose StatementBegin
@@ -172,8 +168,6 @@ ORDER BY
 	u.id, to_char(t.transaction_date, 'YYYY-MM') ASC;
 
 CREATE INDEX IF NOT EXISTS idx_view_user_monthly_summaries_user_id ON view_user_monthly_summaries (user_id, month_name);
-
-SELECT cron.schedule('0 18 * * *', 'REFRESH MATERIALIZED VIEW view_user_monthly_summaries');
 -- +goose StatementEnd
 
 -- +goose StatementBegin
@@ -202,31 +196,25 @@ WHERE rank <= 7
 ORDER BY user_id ASC, total DESC;
 
 CREATE INDEX IF NOT EXISTS idx_view_user_most_expenses_user_id ON view_user_most_expenses (user_id, parent_category_name);
-
-SELECT cron.schedule('0 18 * * *', 'REFRESH MATERIALIZED VIEW view_user_most_expenses');
 -- +goose StatementEnd
 
 -- +goose Down
 -- +goose StatementBegin
-DELETE FROM cron.job WHERE command LIKE 'REFRESH MATERIALIZED VIEW view_user_summaries%';
 DROP INDEX IF EXISTS idx_view_user_summaries_user_id;
 DROP MATERIALIZED VIEW IF EXISTS view_user_summaries;
 -- +goose StatementEnd
 
 -- +goose StatementBegin
-DELETE FROM cron.job WHERE command LIKE 'REFRESH MATERIALIZED VIEW view_user_monthly_summaries%';
 DROP INDEX IF EXISTS idx_view_user_monthly_summaries_user_id;
 DROP MATERIALIZED VIEW IF EXISTS view_user_monthly_summaries;
 -- +goose StatementEnd
 
 -- +goose StatementBegin
-DELETE FROM cron.job WHERE command LIKE 'REFRESH MATERIALIZED VIEW view_user_most_expenses%';
 DROP INDEX IF EXISTS idx_view_user_most_expenses_user_id;
 DROP MATERIALIZED VIEW IF EXISTS view_user_most_expenses;
 -- +goose StatementEnd
 
 -- +goose StatementBegin
-DELETE FROM cron.job WHERE command LIKE 'REFRESH MATERIALIZED VIEW view_user_wallet_daily_summaries%';
 DROP INDEX IF EXISTS idx_view_user_wallet_daily_summaries_user_id;
 DROP MATERIALIZED VIEW IF EXISTS view_user_wallet_daily_summaries;
 -- +goose StatementEnd
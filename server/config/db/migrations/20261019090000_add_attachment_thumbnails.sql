@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE attachments
+    ADD COLUMN thumbnail_small text,
+    ADD COLUMN thumbnail_medium text;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE attachments
+    DROP COLUMN IF EXISTS thumbnail_medium,
+    DROP COLUMN IF EXISTS thumbnail_small;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Format now holds the document kind instead of the file extension
+UPDATE attachments
+SET format = CASE
+    WHEN format IN ('.jpg', '.jpeg', '.png', '.gif', '.webp', '.ico') THEN 'image'
+    WHEN format = '.pdf' THEN 'pdf'
+    WHEN format = '.eml' THEN 'email'
+    ELSE 'file'
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE attachments
+SET format = COALESCE(substring(image from '(\.[A-Za-z0-9]+)$'), '');
+-- +goose StatementEnd
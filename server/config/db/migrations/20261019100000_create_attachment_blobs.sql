@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE attachment_blobs (
+    id uuid DEFAULT uuid_generate_v4() NOT NULL,
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    hash char(64) NOT NULL,
+    bucket_name varchar(63) NOT NULL,
+    object_name text NOT NULL,
+    url text NOT NULL,
+    format text,
+    size bigint,
+    ref_count integer DEFAULT 0 NOT NULL,
+    thumbnail_small text,
+    thumbnail_medium text,
+    CONSTRAINT attachment_blobs_pkey PRIMARY KEY (id),
+    CONSTRAINT attachment_blobs_ref_count_check CHECK (ref_count >= 0)
+);
+
+CREATE UNIQUE INDEX idx_attachment_blobs_hash ON attachment_blobs (hash);
+CREATE INDEX idx_attachment_blobs_deleted_at ON attachment_blobs (deleted_at);
+
+-- Attachments uploaded before deduplication keep their own file and have no blob
+ALTER TABLE attachments ADD COLUMN blob_id uuid REFERENCES attachment_blobs (id) ON DELETE SET NULL;
+CREATE INDEX idx_attachments_blob_id ON attachments (blob_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE attachments DROP COLUMN IF EXISTS blob_id;
+DROP TABLE IF EXISTS attachment_blobs;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Attachments can belong to any resource, existing rows belong to their transaction
+ALTER TABLE attachments ADD COLUMN owner_type varchar(20);
+ALTER TABLE attachments ADD COLUMN owner_id uuid;
+
+UPDATE attachments SET owner_type = 'transaction', owner_id = transaction_id;
+
+ALTER TABLE attachments ALTER COLUMN owner_type SET NOT NULL;
+ALTER TABLE attachments ALTER COLUMN owner_id SET NOT NULL;
+ALTER TABLE attachments DROP COLUMN transaction_id;
+
+CREATE INDEX idx_attachments_owner ON attachments (owner_type, owner_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Attachments of other resources cannot be represented without owner columns
+DELETE FROM attachments WHERE owner_type <> 'transaction';
+
+ALTER TABLE attachments ADD COLUMN transaction_id uuid;
+UPDATE attachments SET transaction_id = owner_id;
+ALTER TABLE attachments ALTER COLUMN transaction_id SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_attachments_owner;
+ALTER TABLE attachments DROP COLUMN owner_id;
+ALTER TABLE attachments DROP COLUMN owner_type;
+-- +goose StatementEnd
@@ -0,0 +1,93 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE investment_trades (
+    id uuid DEFAULT uuid_generate_v4() NOT NULL,
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    investment_id uuid NOT NULL REFERENCES investments (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    type varchar(10) NOT NULL,
+    trade_date timestamp NOT NULL,
+    quantity decimal(18,6) NOT NULL,
+    price decimal(18,6) NOT NULL,
+    fee decimal(18,2) DEFAULT 0 NOT NULL,
+    description text,
+    CONSTRAINT investment_trades_pkey PRIMARY KEY (id),
+    CONSTRAINT investment_trades_type_check CHECK (type IN ('buy', 'sell', 'fee')),
+    CONSTRAINT investment_trades_values_check CHECK (quantity >= 0 AND price >= 0 AND fee >= 0)
+);
+
+CREATE INDEX idx_investment_trades_investment_id ON investment_trades (investment_id, trade_date);
+CREATE INDEX idx_investment_trades_deleted_at ON investment_trades (deleted_at);
+
+-- Amount and quantity become the cost basis and held quantity of the position, derived from its trades
+ALTER TABLE investments ADD COLUMN cost_method varchar(10) DEFAULT 'fifo' NOT NULL;
+ALTER TABLE investments ALTER COLUMN quantity TYPE decimal(18,6);
+
+-- Every existing investment was a single purchase
+INSERT INTO investment_trades (created_at, updated_at, investment_id, type, trade_date, quantity, price, fee, description)
+SELECT NOW(), NOW(), id, 'buy', investment_date, quantity, amount / quantity, 0, description
+FROM investments
+WHERE deleted_at IS NULL AND quantity > 0;
+
+DROP VIEW IF EXISTS view_user_investments;
+CREATE VIEW view_user_investments AS
+SELECT investments.id, users.id AS user_id,
+	investment_types.name AS investment_type,
+	investments.name AS investment_name,
+	investments.amount AS investment_amount,
+	investments.quantity AS investment_quantity,
+	investment_types.unit AS investment_unit,
+	investments.investment_date AS investment_date,
+	investments.cost_method,
+	COALESCE(trades.trade_count, 0) AS trade_count,
+	COALESCE(trades.total_invested, 0) AS total_invested,
+	COALESCE(trades.total_proceeds, 0) AS total_proceeds,
+	COALESCE(trades.total_fees, 0) AS total_fees,
+	-- cost of the sold quantity is everything invested minus the cost basis still held
+	COALESCE(trades.total_proceeds - (trades.total_invested - investments.amount) - trades.standalone_fees, 0) AS realized_pnl,
+	last_trade.price AS last_price,
+	investments.quantity * last_trade.price AS market_value,
+	investments.quantity * last_trade.price - investments.amount AS unrealized_pnl
+FROM investments
+LEFT JOIN users ON users.id = investments.user_id AND users.deleted_at IS NULL
+LEFT JOIN investment_types ON investment_types.id = investments.investment_type_id AND investment_types.deleted_at IS NULL
+LEFT JOIN LATERAL (
+	SELECT COUNT(*) AS trade_count,
+		SUM(CASE WHEN type = 'buy' THEN quantity * price + fee ELSE 0 END) AS total_invested,
+		SUM(CASE WHEN type = 'sell' THEN quantity * price - fee ELSE 0 END) AS total_proceeds,
+		SUM(fee) AS total_fees,
+		SUM(CASE WHEN type = 'fee' THEN fee ELSE 0 END) AS standalone_fees
+	FROM investment_trades
+	WHERE investment_trades.investment_id = investments.id AND investment_trades.deleted_at IS NULL
+) trades ON TRUE
+LEFT JOIN LATERAL (
+	SELECT price
+	FROM investment_trades
+	WHERE investment_trades.investment_id = investments.id AND investment_trades.deleted_at IS NULL AND type <> 'fee'
+	ORDER BY trade_date DESC, created_at DESC
+	LIMIT 1
+) last_trade ON TRUE
+WHERE investments.deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP VIEW IF EXISTS view_user_investments;
+CREATE VIEW view_user_investments AS
+SELECT investments.id, users.id AS user_id,
+	investment_types.name AS investment_type,
+	investments.name AS investment_name,
+	investments.amount AS investment_amount,
+	investments.quantity AS investment_quantity,
+	investment_types.unit AS investment_unit,
+	investments.investment_date AS investment_date
+FROM investments
+LEFT JOIN users ON users.id = investments.user_id AND users.deleted_at IS NULL
+LEFT JOIN investment_types ON investment_types.id = investments.investment_type_id AND investment_types.deleted_at IS NULL
+WHERE investments.deleted_at IS NULL;
+
+ALTER TABLE investments ALTER COLUMN quantity TYPE decimal(18,2);
+ALTER TABLE investments DROP COLUMN IF EXISTS cost_method;
+DROP TABLE IF EXISTS investment_trades;
+-- +goose StatementEnd
@@ -0,0 +1,275 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Prices are shared by every holding of the same instrument, keyed by the normalized investment name until instruments exist
+CREATE TABLE investment_prices (
+    id uuid DEFAULT uuid_generate_v4() NOT NULL,
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    instrument varchar(50) NOT NULL,
+    price_date date NOT NULL,
+    price decimal(18,6) NOT NULL,
+    source varchar(30) NOT NULL,
+    CONSTRAINT investment_prices_pkey PRIMARY KEY (id),
+    CONSTRAINT investment_prices_price_check CHECK (price >= 0)
+);
+
+CREATE UNIQUE INDEX idx_investment_prices_instrument_date ON investment_prices (instrument, price_date);
+CREATE INDEX idx_investment_prices_deleted_at ON investment_prices (deleted_at);
+
+-- Positions are valued at the latest price, falling back to the last trade price
+CREATE OR REPLACE VIEW view_user_investments AS
+SELECT investments.id, users.id AS user_id,
+	investment_types.name AS investment_type,
+	investments.name AS investment_name,
+	investments.amount AS investment_amount,
+	investments.quantity AS investment_quantity,
+	investment_types.unit AS investment_unit,
+	investments.investment_date AS investment_date,
+	investments.cost_method,
+	COALESCE(trades.trade_count, 0) AS trade_count,
+	COALESCE(trades.total_invested, 0) AS total_invested,
+	COALESCE(trades.total_proceeds, 0) AS total_proceeds,
+	COALESCE(trades.total_fees, 0) AS total_fees,
+	-- cost of the sold quantity is everything invested minus the cost basis still held
+	COALESCE(trades.total_proceeds - (trades.total_invested - investments.amount) - trades.standalone_fees, 0) AS realized_pnl,
+	COALESCE(latest_price.price, last_trade.price) AS last_price,
+	investments.quantity * COALESCE(latest_price.price, last_trade.price) AS market_value,
+	investments.quantity * COALESCE(latest_price.price, last_trade.price) - investments.amount AS unrealized_pnl,
+	latest_price.price_date AS price_date
+FROM investments
+LEFT JOIN users ON users.id = investments.user_id AND users.deleted_at IS NULL
+LEFT JOIN investment_types ON investment_types.id = investments.investment_type_id AND investment_types.deleted_at IS NULL
+LEFT JOIN LATERAL (
+	SELECT COUNT(*) AS trade_count,
+		SUM(CASE WHEN type = 'buy' THEN quantity * price + fee ELSE 0 END) AS total_invested,
+		SUM(CASE WHEN type = 'sell' THEN quantity * price - fee ELSE 0 END) AS total_proceeds,
+		SUM(fee) AS total_fees,
+		SUM(CASE WHEN type = 'fee' THEN fee ELSE 0 END) AS standalone_fees
+	FROM investment_trades
+	WHERE investment_trades.investment_id = investments.id AND investment_trades.deleted_at IS NULL
+) trades ON TRUE
+LEFT JOIN LATERAL (
+	SELECT price
+	FROM investment_trades
+	WHERE investment_trades.investment_id = investments.id AND investment_trades.deleted_at IS NULL AND type <> 'fee'
+	ORDER BY trade_date DESC, created_at DESC
+	LIMIT 1
+) last_trade ON TRUE
+LEFT JOIN LATERAL (
+	SELECT price, price_date
+	FROM investment_prices
+	WHERE investment_prices.instrument = UPPER(TRIM(investments.name)) AND investment_prices.deleted_at IS NULL
+	ORDER BY price_date DESC
+	LIMIT 1
+) latest_price ON TRUE
+WHERE investments.deleted_at IS NULL;
+
+-- Net worth includes the value of investments
+DROP MATERIALIZED VIEW IF EXISTS view_user_summaries;
+CREATE MATERIALIZED VIEW view_user_summaries AS
+WITH
+current_month AS (
+SELECT
+	u.id AS user_id,
+	SUM(CASE WHEN c.type = 'income' THEN t.amount ELSE 0 END) AS income_now,
+	SUM(CASE WHEN c.type = 'expense' THEN t.amount ELSE 0 END) AS expense_now
+FROM users u
+JOIN wallets w ON w.user_id = u.id
+LEFT JOIN transactions t ON t.wallet_id = w.id
+LEFT JOIN categories c ON c.id = t.category_id
+WHERE t.transaction_date >= date_trunc('month', current_date)
+	AND t.transaction_date < date_trunc('month', current_date + INTERVAL '1 month')
+GROUP BY u.id
+),
+previous_month AS (
+SELECT
+	u.id AS user_id,
+	SUM(CASE WHEN c.type = 'income' THEN t.amount ELSE 0 END) AS income_prev,
+	SUM(CASE WHEN c.type = 'expense' THEN t.amount ELSE 0 END) AS expense_prev
+FROM users u
+JOIN wallets w ON w.user_id = u.id
+LEFT JOIN transactions t ON t.wallet_id = w.id
+LEFT JOIN categories c ON c.id = t.category_id
+WHERE t.transaction_date >= date_trunc('month', current_date - INTERVAL '1 month')
+	AND t.transaction_date < date_trunc('month', current_date)
+GROUP BY u.id
+),
+current_balance AS (
+SELECT
+	u.id AS user_id,
+	SUM(w.balance) AS balance_now
+FROM users u
+JOIN wallets w ON w.user_id = u.id
+GROUP BY u.id
+),
+investment_value AS (
+SELECT
+	user_id,
+	SUM(COALESCE(market_value, investment_amount)) AS investment_value_now
+FROM view_user_investments
+GROUP BY user_id
+),
+previous_balance AS (
+SELECT
+	user_id,
+	(physical + e_wallet + bank + others) AS balance_prev
+FROM view_user_wallet_daily_summaries
+WHERE date = (date_trunc('month', current_date) - INTERVAL '1 day')::date
+)
+SELECT
+u.id AS user_id,
+u.name,
+COALESCE(cm.income_now, 0) AS income_now,
+COALESCE(cm.expense_now, 0) AS expense_now,
+COALESCE(cm.income_now, 0) - COALESCE(cm.expense_now, 0) AS profit_now,
+COALESCE(cb.balance_now, 0) AS balance_now,
+ROUND((
+	(COALESCE(cm.income_now, 0) - COALESCE(pm.income_prev, 0)) /
+	NULLIF(pm.income_prev, 0)
+) * 100, 2) AS user_income_growth_percentage,
+ROUND((
+	(COALESCE(cm.expense_now, 0) - COALESCE(pm.expense_prev, 0)) /
+	NULLIF(pm.expense_prev, 0)
+) * 100, 2) AS user_expense_growth_percentage,
+ROUND((
+	((COALESCE(cm.income_now, 0) - COALESCE(cm.expense_now, 0)) -
+	(COALESCE(pm.income_prev, 0) - COALESCE(pm.expense_prev, 0))) /
+	NULLIF((COALESCE(pm.income_prev, 0) - COALESCE(pm.expense_prev, 0)), 0)
+) * 100, 2) AS user_profit_growth_percentage,
+ROUND((
+	(COALESCE(cb.balance_now, 0) - COALESCE(pb.balance_prev, 0)) /
+	NULLIF(pb.balance_prev, 0)
+) * 100, 2) AS user_balance_growth_percentage,
+COALESCE(iv.investment_value_now, 0) AS investment_value_now,
+COALESCE(cb.balance_now, 0) + COALESCE(iv.investment_value_now, 0) AS net_worth_now
+FROM users u
+LEFT JOIN current_month cm ON cm.user_id = u.id
+LEFT JOIN previous_month pm ON pm.user_id = u.id
+LEFT JOIN current_balance cb ON cb.user_id = u.id
+LEFT JOIN previous_balance pb ON pb.user_id = u.id
+LEFT JOIN investment_value iv ON iv.user_id = u.id;
+
+CREATE INDEX IF NOT EXISTS idx_view_user_summaries_user_id ON view_user_summaries (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP MATERIALIZED VIEW IF EXISTS view_user_summaries;
+CREATE MATERIALIZED VIEW view_user_summaries AS
+WITH
+current_month AS (
+SELECT
+	u.id AS user_id,
+	SUM(CASE WHEN c.type = 'income' THEN t.amount ELSE 0 END) AS income_now,
+	SUM(CASE WHEN c.type = 'expense' THEN t.amount ELSE 0 END) AS expense_now
+FROM users u
+JOIN wallets w ON w.user_id = u.id
+LEFT JOIN transactions t ON t.wallet_id = w.id
+LEFT JOIN categories c ON c.id = t.category_id
+WHERE t.transaction_date >= date_trunc('month', current_date)
+	AND t.transaction_date < date_trunc('month', current_date + INTERVAL '1 month')
+GROUP BY u.id
+),
+previous_month AS (
+SELECT
+	u.id AS user_id,
+	SUM(CASE WHEN c.type = 'income' THEN t.amount ELSE 0 END) AS income_prev,
+	SUM(CASE WHEN c.type = 'expense' THEN t.amount ELSE 0 END) AS expense_prev
+FROM users u
+JOIN wallets w ON w.user_id = u.id
+LEFT JOIN transactions t ON t.wallet_id = w.id
+LEFT JOIN categories c ON c.id = t.category_id
+WHERE t.transaction_date >= date_trunc('month', current_date - INTERVAL '1 month')
+	AND t.transaction_date < date_trunc('month', current_date)
+GROUP BY u.id
+),
+current_balance AS (
+SELECT
+	u.id AS user_id,
+	SUM(w.balance) AS balance_now
+FROM users u
+JOIN wallets w ON w.user_id = u.id
+GROUP BY u.id
+),
+previous_balance AS (
+SELECT
+	user_id,
+	(physical + e_wallet + bank + others) AS balance_prev
+FROM view_user_wallet_daily_summaries
+WHERE date = (date_trunc('month', current_date) - INTERVAL '1 day')::date
+)
+SELECT
+u.id AS user_id,
+u.name,
+COALESCE(cm.income_now, 0) AS income_now,
+COALESCE(cm.expense_now, 0) AS expense_now,
+COALESCE(cm.income_now, 0) - COALESCE(cm.expense_now, 0) AS profit_now,
+COALESCE(cb.balance_now, 0) AS balance_now,
+ROUND((
+	(COALESCE(cm.income_now, 0) - COALESCE(pm.income_prev, 0)) /
+	NULLIF(pm.income_prev, 0)
+) * 100, 2) AS user_income_growth_percentage,
+ROUND((
+	(COALESCE(cm.expense_now, 0) - COALESCE(pm.expense_prev, 0)) /
+	NULLIF(pm.expense_prev, 0)
+) * 100, 2) AS user_expense_growth_percentage,
+ROUND((
+	((COALESCE(cm.income_now, 0) - COALESCE(cm.expense_now, 0)) -
+	(COALESCE(pm.income_prev, 0) - COALESCE(pm.expense_prev, 0))) /
+	NULLIF((COALESCE(pm.income_prev, 0) - COALESCE(pm.expense_prev, 0)), 0)
+) * 100, 2) AS user_profit_growth_percentage,
+ROUND((
+	(COALESCE(cb.balance_now, 0) - COALESCE(pb.balance_prev, 0)) /
+	NULLIF(pb.balance_prev, 0)
+) * 100, 2) AS user_balance_growth_percentage
+FROM users u
+LEFT JOIN current_month cm ON cm.user_id = u.id
+LEFT JOIN previous_month pm ON pm.user_id = u.id
+LEFT JOIN current_balance cb ON cb.user_id = u.id
+LEFT JOIN previous_balance pb ON pb.user_id = u.id;
+
+CREATE INDEX IF NOT EXISTS idx_view_user_summaries_user_id ON view_user_summaries (user_id);
+
+DROP VIEW IF EXISTS view_user_investments;
+CREATE VIEW view_user_investments AS
+SELECT investments.id, users.id AS user_id,
+	investment_types.name AS investment_type,
+	investments.name AS investment_name,
+	investments.amount AS investment_amount,
+	investments.quantity AS investment_quantity,
+	investment_types.unit AS investment_unit,
+	investments.investment_date AS investment_date,
+	investments.cost_method,
+	COALESCE(trades.trade_count, 0) AS trade_count,
+	COALESCE(trades.total_invested, 0) AS total_invested,
+	COALESCE(trades.total_proceeds, 0) AS total_proceeds,
+	COALESCE(trades.total_fees, 0) AS total_fees,
+	-- cost of the sold quantity is everything invested minus the cost basis still held
+	COALESCE(trades.total_proceeds - (trades.total_invested - investments.amount) - trades.standalone_fees, 0) AS realized_pnl,
+	last_trade.price AS last_price,
+	investments.quantity * last_trade.price AS market_value,
+	investments.quantity * last_trade.price - investments.amount AS unrealized_pnl
+FROM investments
+LEFT JOIN users ON users.id = investments.user_id AND users.deleted_at IS NULL
+LEFT JOIN investment_types ON investment_types.id = investments.investment_type_id AND investment_types.deleted_at IS NULL
+LEFT JOIN LATERAL (
+	SELECT COUNT(*) AS trade_count,
+		SUM(CASE WHEN type = 'buy' THEN quantity * price + fee ELSE 0 END) AS total_invested,
+		SUM(CASE WHEN type = 'sell' THEN quantity * price - fee ELSE 0 END) AS total_proceeds,
+		SUM(fee) AS total_fees,
+		SUM(CASE WHEN type = 'fee' THEN fee ELSE 0 END) AS standalone_fees
+	FROM investment_trades
+	WHERE investment_trades.investment_id = investments.id AND investment_trades.deleted_at IS NULL
+) trades ON TRUE
+LEFT JOIN LATERAL (
+	SELECT price
+	FROM investment_trades
+	WHERE investment_trades.investment_id = investments.id AND investment_trades.deleted_at IS NULL AND type <> 'fee'
+	ORDER BY trade_date DESC, created_at DESC
+	LIMIT 1
+) last_trade ON TRUE
+WHERE investments.deleted_at IS NULL;
+
+DROP TABLE IF EXISTS investment_prices;
+-- +goose StatementEnd
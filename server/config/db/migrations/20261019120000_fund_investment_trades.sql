@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Wallet transaction that paid for a buy or received the proceeds of a sell
+ALTER TABLE investment_trades ADD COLUMN transaction_id uuid;
+CREATE INDEX idx_investment_trades_transaction_id ON investment_trades (transaction_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_investment_trades_transaction_id;
+ALTER TABLE investment_trades DROP COLUMN transaction_id;
+-- +goose StatementEnd
@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE investment_incomes (
+    id uuid DEFAULT uuid_generate_v4() NOT NULL,
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    investment_id uuid NOT NULL REFERENCES investments (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    type varchar(10) NOT NULL,
+    payment_date timestamp NOT NULL,
+    gross_amount decimal(18,2) NOT NULL,
+    tax_amount decimal(18,2) DEFAULT 0 NOT NULL,
+    wallet_id uuid NOT NULL,
+    transaction_id uuid,
+    description text,
+    CONSTRAINT investment_incomes_pkey PRIMARY KEY (id),
+    CONSTRAINT investment_incomes_type_check CHECK (type IN ('dividend', 'coupon', 'interest')),
+    CONSTRAINT investment_incomes_amount_check CHECK (gross_amount > 0 AND tax_amount >= 0 AND tax_amount < gross_amount)
+);
+
+CREATE INDEX idx_investment_incomes_investment_id ON investment_incomes (investment_id, payment_date);
+CREATE INDEX idx_investment_incomes_transaction_id ON investment_incomes (transaction_id);
+CREATE INDEX idx_investment_incomes_deleted_at ON investment_incomes (deleted_at);
+
+-- Fixed-income terms, used for the coupon schedule
+ALTER TABLE investments ADD COLUMN coupon_rate decimal(7,4);
+ALTER TABLE investments ADD COLUMN coupon_frequency integer;
+ALTER TABLE investments ADD COLUMN maturity_date date;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE investments DROP COLUMN maturity_date;
+ALTER TABLE investments DROP COLUMN coupon_frequency;
+ALTER TABLE investments DROP COLUMN coupon_rate;
+
+DROP TABLE IF EXISTS investment_incomes;
+-- +goose StatementEnd
@@ -0,0 +1,82 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Target share of every asset class of a user, investment_type_id NULL is cash (wallet balances)
+CREATE TABLE target_allocations (
+    id uuid DEFAULT uuid_generate_v4() NOT NULL,
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    user_id uuid NOT NULL REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    investment_type_id uuid REFERENCES investment_types (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    target_percent decimal(5,2) NOT NULL,
+    tolerance_percent decimal(5,2) DEFAULT 5 NOT NULL,
+    CONSTRAINT target_allocations_pkey PRIMARY KEY (id),
+    CONSTRAINT target_allocations_percent_check CHECK (target_percent BETWEEN 0 AND 100 AND tolerance_percent BETWEEN 0 AND 100)
+);
+
+CREATE UNIQUE INDEX idx_target_allocations_user_class ON target_allocations (user_id, COALESCE(investment_type_id, '00000000-0000-0000-0000-000000000000'::uuid)) WHERE deleted_at IS NULL;
+
+-- Current value of every asset class against its target, only for users that set a target allocation
+CREATE VIEW view_user_allocations AS
+WITH
+targets AS (
+	SELECT user_id, investment_type_id, target_percent, tolerance_percent
+	FROM target_allocations
+	WHERE deleted_at IS NULL
+),
+classes AS (
+	SELECT investments.user_id, investments.investment_type_id,
+		SUM(COALESCE(view_user_investments.market_value, view_user_investments.investment_amount)) AS current_value
+	FROM investments
+	JOIN view_user_investments ON view_user_investments.id = investments.id
+	GROUP BY investments.user_id, investments.investment_type_id
+	UNION ALL
+	SELECT user_id, NULL::uuid AS investment_type_id, SUM(balance) AS current_value
+	FROM wallets
+	WHERE deleted_at IS NULL
+	GROUP BY user_id
+),
+combined AS (
+	SELECT COALESCE(classes.user_id, targets.user_id) AS user_id,
+		COALESCE(classes.investment_type_id, targets.investment_type_id) AS investment_type_id,
+		COALESCE(classes.current_value, 0) AS current_value,
+		COALESCE(targets.target_percent, 0) AS target_percent,
+		COALESCE(targets.tolerance_percent, 5) AS tolerance_percent
+	FROM classes
+	FULL JOIN targets ON targets.user_id = classes.user_id
+		AND targets.investment_type_id IS NOT DISTINCT FROM classes.investment_type_id
+),
+shares AS (
+	SELECT combined.*,
+		CASE WHEN SUM(current_value) OVER (PARTITION BY user_id) > 0
+			THEN current_value / SUM(current_value) OVER (PARTITION BY user_id) * 100
+			ELSE 0
+		END AS current_percent
+	FROM combined
+	WHERE user_id IN (SELECT user_id FROM targets)
+)
+SELECT shares.user_id, shares.investment_type_id,
+	COALESCE(investment_types.name, 'Cash') AS investment_type,
+	shares.current_value,
+	shares.current_percent,
+	shares.target_percent,
+	shares.tolerance_percent,
+	shares.current_percent - shares.target_percent AS drift_percent
+FROM shares
+LEFT JOIN investment_types ON investment_types.id = shares.investment_type_id;
+
+-- Largest drift per user, shown in the dashboard summary
+CREATE VIEW view_user_allocation_drift AS
+SELECT user_id,
+	MAX(ABS(drift_percent)) AS allocation_drift,
+	BOOL_OR(ABS(drift_percent) > tolerance_percent) AS rebalance_needed
+FROM view_user_allocations
+GROUP BY user_id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP VIEW IF EXISTS view_user_allocation_drift;
+DROP VIEW IF EXISTS view_user_allocations;
+DROP TABLE IF EXISTS target_allocations;
+-- +goose StatementEnd
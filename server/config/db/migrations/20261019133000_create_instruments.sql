@@ -0,0 +1,194 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Instruments are shared by every user, holdings and prices reference them instead of a free-text name
+CREATE TABLE instruments (
+    id uuid DEFAULT uuid_generate_v4() NOT NULL,
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    investment_type_id uuid REFERENCES investment_types (id) ON UPDATE CASCADE ON DELETE SET NULL,
+    code varchar(50) NOT NULL,
+    name varchar(50) NOT NULL,
+    issuer varchar(100),
+    currency varchar(3) DEFAULT 'IDR' NOT NULL,
+    unit text,
+    risk_level smallint,
+    kind varchar(20) NOT NULL,
+    CONSTRAINT instruments_pkey PRIMARY KEY (id),
+    CONSTRAINT instruments_kind_check CHECK (kind IN ('stock', 'mutual_fund', 'bond', 'gold', 'crypto', 'deposit', 'other')),
+    CONSTRAINT instruments_risk_level_check CHECK (risk_level BETWEEN 1 AND 5)
+);
+
+CREATE UNIQUE INDEX idx_instruments_code ON instruments (code) WHERE deleted_at IS NULL;
+CREATE INDEX idx_instruments_deleted_at ON instruments (deleted_at);
+
+-- Every distinct investment name becomes an instrument, under the investment type it is held with the most
+INSERT INTO instruments (created_at, updated_at, investment_type_id, code, name, unit, kind)
+SELECT DISTINCT ON (code) NOW(), NOW(), investment_type_id, code, name, unit, kind
+FROM (
+	SELECT UPPER(TRIM(investments.name)) AS code,
+		TRIM(investments.name) AS name,
+		investments.investment_type_id,
+		investment_types.unit,
+		CASE investment_types.name
+			WHEN 'Stocks' THEN 'stock'
+			WHEN 'Mutual Funds' THEN 'mutual_fund'
+			WHEN 'Bonds' THEN 'bond'
+			WHEN 'Government Securities' THEN 'bond'
+			WHEN 'Gold' THEN 'gold'
+			WHEN 'Deposits' THEN 'deposit'
+			ELSE 'other'
+		END AS kind,
+		COUNT(*) OVER (PARTITION BY UPPER(TRIM(investments.name)), investments.investment_type_id) AS holdings
+	FROM investments
+	LEFT JOIN investment_types ON investment_types.id = investments.investment_type_id
+) candidates
+ORDER BY code, holdings DESC;
+
+-- Prices imported for instruments nobody holds
+INSERT INTO instruments (created_at, updated_at, code, name, kind)
+SELECT DISTINCT NOW(), NOW(), investment_prices.instrument, investment_prices.instrument, 'other'
+FROM investment_prices
+WHERE NOT EXISTS (SELECT 1 FROM instruments WHERE instruments.code = investment_prices.instrument);
+
+ALTER TABLE investments ADD COLUMN instrument_id uuid REFERENCES instruments (id) ON UPDATE CASCADE;
+UPDATE investments SET instrument_id = instruments.id
+FROM instruments
+WHERE instruments.code = UPPER(TRIM(investments.name));
+ALTER TABLE investments ALTER COLUMN instrument_id SET NOT NULL;
+CREATE INDEX idx_investments_instrument_id ON investments (instrument_id);
+
+ALTER TABLE investment_prices ADD COLUMN instrument_id uuid REFERENCES instruments (id) ON UPDATE CASCADE ON DELETE CASCADE;
+UPDATE investment_prices SET instrument_id = instruments.id
+FROM instruments
+WHERE instruments.code = investment_prices.instrument;
+ALTER TABLE investment_prices ALTER COLUMN instrument_id SET NOT NULL;
+DROP INDEX IF EXISTS idx_investment_prices_instrument_date;
+CREATE UNIQUE INDEX idx_investment_prices_instrument_date ON investment_prices (instrument_id, price_date);
+
+CREATE OR REPLACE VIEW view_user_investments AS
+SELECT investments.id, users.id AS user_id,
+	investment_types.name AS investment_type,
+	instruments.name AS investment_name,
+	investments.amount AS investment_amount,
+	investments.quantity AS investment_quantity,
+	investment_types.unit AS investment_unit,
+	investments.investment_date AS investment_date,
+	investments.cost_method,
+	COALESCE(trades.trade_count, 0) AS trade_count,
+	COALESCE(trades.total_invested, 0) AS total_invested,
+	COALESCE(trades.total_proceeds, 0) AS total_proceeds,
+	COALESCE(trades.total_fees, 0) AS total_fees,
+	-- cost of the sold quantity is everything invested minus the cost basis still held
+	COALESCE(trades.total_proceeds - (trades.total_invested - investments.amount) - trades.standalone_fees, 0) AS realized_pnl,
+	COALESCE(latest_price.price, last_trade.price) AS last_price,
+	investments.quantity * COALESCE(latest_price.price, last_trade.price) AS market_value,
+	investments.quantity * COALESCE(latest_price.price, last_trade.price) - investments.amount AS unrealized_pnl,
+	latest_price.price_date AS price_date,
+	investments.instrument_id,
+	instruments.code AS instrument_code
+FROM investments
+JOIN instruments ON instruments.id = investments.instrument_id
+LEFT JOIN users ON users.id = investments.user_id AND users.deleted_at IS NULL
+LEFT JOIN investment_types ON investment_types.id = investments.investment_type_id AND investment_types.deleted_at IS NULL
+LEFT JOIN LATERAL (
+	SELECT COUNT(*) AS trade_count,
+		SUM(CASE WHEN type = 'buy' THEN quantity * price + fee ELSE 0 END) AS total_invested,
+		SUM(CASE WHEN type = 'sell' THEN quantity * price - fee ELSE 0 END) AS total_proceeds,
+		SUM(fee) AS total_fees,
+		SUM(CASE WHEN type = 'fee' THEN fee ELSE 0 END) AS standalone_fees
+	FROM investment_trades
+	WHERE investment_trades.investment_id = investments.id AND investment_trades.deleted_at IS NULL
+) trades ON TRUE
+LEFT JOIN LATERAL (
+	SELECT price
+	FROM investment_trades
+	WHERE investment_trades.investment_id = investments.id AND investment_trades.deleted_at IS NULL AND type <> 'fee'
+	ORDER BY trade_date DESC, created_at DESC
+	LIMIT 1
+) last_trade ON TRUE
+LEFT JOIN LATERAL (
+	SELECT price, price_date
+	FROM investment_prices
+	WHERE investment_prices.instrument_id = investments.instrument_id AND investment_prices.deleted_at IS NULL
+	ORDER BY price_date DESC
+	LIMIT 1
+) latest_price ON TRUE
+WHERE investments.deleted_at IS NULL;
+
+ALTER TABLE investment_prices DROP COLUMN instrument;
+ALTER TABLE investments DROP COLUMN name;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE investments ADD COLUMN name varchar(50);
+UPDATE investments SET name = instruments.name
+FROM instruments
+WHERE instruments.id = investments.instrument_id;
+ALTER TABLE investments ALTER COLUMN name SET NOT NULL;
+
+ALTER TABLE investment_prices ADD COLUMN instrument varchar(50);
+UPDATE investment_prices SET instrument = instruments.code
+FROM instruments
+WHERE instruments.id = investment_prices.instrument_id;
+ALTER TABLE investment_prices ALTER COLUMN instrument SET NOT NULL;
+
+-- The instrument columns stay in the view, other views depend on its column list
+CREATE OR REPLACE VIEW view_user_investments AS
+SELECT investments.id, users.id AS user_id,
+	investment_types.name AS investment_type,
+	investments.name AS investment_name,
+	investments.amount AS investment_amount,
+	investments.quantity AS investment_quantity,
+	investment_types.unit AS investment_unit,
+	investments.investment_date AS investment_date,
+	investments.cost_method,
+	COALESCE(trades.trade_count, 0) AS trade_count,
+	COALESCE(trades.total_invested, 0) AS total_invested,
+	COALESCE(trades.total_proceeds, 0) AS total_proceeds,
+	COALESCE(trades.total_fees, 0) AS total_fees,
+	COALESCE(trades.total_proceeds - (trades.total_invested - investments.amount) - trades.standalone_fees, 0) AS realized_pnl,
+	COALESCE(latest_price.price, last_trade.price) AS last_price,
+	investments.quantity * COALESCE(latest_price.price, last_trade.price) AS market_value,
+	investments.quantity * COALESCE(latest_price.price, last_trade.price) - investments.amount AS unrealized_pnl,
+	latest_price.price_date AS price_date,
+	NULL::uuid AS instrument_id,
+	UPPER(TRIM(investments.name))::varchar(50) AS instrument_code
+FROM investments
+LEFT JOIN users ON users.id = investments.user_id AND users.deleted_at IS NULL
+LEFT JOIN investment_types ON investment_types.id = investments.investment_type_id AND investment_types.deleted_at IS NULL
+LEFT JOIN LATERAL (
+	SELECT COUNT(*) AS trade_count,
+		SUM(CASE WHEN type = 'buy' THEN quantity * price + fee ELSE 0 END) AS total_invested,
+		SUM(CASE WHEN type = 'sell' THEN quantity * price - fee ELSE 0 END) AS total_proceeds,
+		SUM(fee) AS total_fees,
+		SUM(CASE WHEN type = 'fee' THEN fee ELSE 0 END) AS standalone_fees
+	FROM investment_trades
+	WHERE investment_trades.investment_id = investments.id AND investment_trades.deleted_at IS NULL
+) trades ON TRUE
+LEFT JOIN LATERAL (
+	SELECT price
+	FROM investment_trades
+	WHERE investment_trades.investment_id = investments.id AND investment_trades.deleted_at IS NULL AND type <> 'fee'
+	ORDER BY trade_date DESC, created_at DESC
+	LIMIT 1
+) last_trade ON TRUE
+LEFT JOIN LATERAL (
+	SELECT price, price_date
+	FROM investment_prices
+	WHERE investment_prices.instrument = UPPER(TRIM(investments.name)) AND investment_prices.deleted_at IS NULL
+	ORDER BY price_date DESC
+	LIMIT 1
+) latest_price ON TRUE
+WHERE investments.deleted_at IS NULL;
+
+DROP INDEX IF EXISTS idx_investment_prices_instrument_date;
+CREATE UNIQUE INDEX idx_investment_prices_instrument_date ON investment_prices (instrument, price_date);
+ALTER TABLE investment_prices DROP COLUMN instrument_id;
+
+DROP INDEX IF EXISTS idx_investments_instrument_id;
+ALTER TABLE investments DROP COLUMN instrument_id;
+
+DROP TABLE IF EXISTS instruments;
+-- +goose StatementEnd
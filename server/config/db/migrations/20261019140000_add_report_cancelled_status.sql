@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TYPE report_status ADD VALUE IF NOT EXISTS 'cancelled';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Enum values cannot be dropped, the type is recreated without it
+UPDATE reports SET status = 'failed' WHERE status = 'cancelled';
+ALTER TABLE reports ALTER COLUMN status TYPE text;
+DROP TYPE report_status;
+CREATE TYPE report_status AS ENUM ('processing', 'completed', 'failed');
+ALTER TABLE reports ALTER COLUMN status TYPE report_status USING status::report_status;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE reports ADD COLUMN type varchar(32) DEFAULT 'financial' NOT NULL;
+CREATE INDEX idx_reports_user_id_type_request_at ON reports (user_id, type, request_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_reports_user_id_type_request_at;
+ALTER TABLE reports DROP COLUMN IF EXISTS type;
+-- +goose StatementEnd
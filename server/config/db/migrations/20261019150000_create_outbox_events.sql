@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Messages written in the same transaction as the change they describe, published to RabbitMQ by the outbox relay
+CREATE TABLE outbox_events (
+    id uuid DEFAULT uuid_generate_v4() NOT NULL,
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    exchange varchar(255) NOT NULL,
+    routing_key varchar(255) NOT NULL,
+    payload jsonb NOT NULL,
+    attempts integer DEFAULT 0 NOT NULL,
+    last_error text,
+    next_attempt_at timestamp with time zone DEFAULT now() NOT NULL,
+    sent_at timestamp with time zone,
+    CONSTRAINT outbox_events_pkey PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_outbox_events_pending ON outbox_events (next_attempt_at, created_at) WHERE sent_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS outbox_events;
+-- +goose StatementEnd
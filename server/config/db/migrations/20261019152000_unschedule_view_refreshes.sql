@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The worker schedules the view refreshes now (see scheduled_job_runs), drop the pg_cron jobs that earlier
+-- versions of the init migrations created on databases with the extension
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'pg_cron') THEN
+        PERFORM cron.unschedule(jobid) FROM cron.job WHERE command LIKE 'REFRESH MATERIALIZED VIEW view_user_%';
+    END IF;
+END
+$$;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- The init migrations no longer schedule the refreshes, so there are no jobs to restore
+SELECT 1;
+-- +goose StatementEnd
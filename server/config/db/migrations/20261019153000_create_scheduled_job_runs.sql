@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+-- One row per run of a worker scheduled job, scheduled_at is set for runs started by the schedule
+CREATE TABLE scheduled_job_runs (
+    id uuid DEFAULT uuid_generate_v4() NOT NULL,
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    job_name varchar(100) NOT NULL,
+    trigger varchar(20) NOT NULL,
+    status varchar(20) NOT NULL,
+    scheduled_at timestamp with time zone,
+    triggered_by uuid,
+    started_at timestamp with time zone,
+    finished_at timestamp with time zone,
+    error text,
+    CONSTRAINT scheduled_job_runs_pkey PRIMARY KEY (id),
+    CONSTRAINT fk_scheduled_job_runs_triggered_by FOREIGN KEY (triggered_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- Workers firing the same schedule race on this index, only the first one runs the job
+CREATE UNIQUE INDEX idx_scheduled_job_runs_schedule ON scheduled_job_runs (job_name, scheduled_at) WHERE scheduled_at IS NOT NULL;
+CREATE INDEX idx_scheduled_job_runs_job_name ON scheduled_job_runs (job_name, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS scheduled_job_runs;
+-- +goose StatementEnd
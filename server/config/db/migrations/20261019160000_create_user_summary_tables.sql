@@ -0,0 +1,459 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Per-user summaries kept up to date by the worker from transaction events, the dashboard views read from them
+CREATE TABLE user_monthly_summaries (
+    user_id uuid NOT NULL,
+    month date NOT NULL,
+    total_income numeric(18,2) DEFAULT 0 NOT NULL,
+    total_expense numeric(18,2) DEFAULT 0 NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT user_monthly_summaries_pkey PRIMARY KEY (user_id, month),
+    CONSTRAINT fk_user_monthly_summaries_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE user_category_monthly_expenses (
+    user_id uuid NOT NULL,
+    month date NOT NULL,
+    parent_category_name varchar(255) NOT NULL,
+    total numeric(18,2) DEFAULT 0 NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT user_category_monthly_expenses_pkey PRIMARY KEY (user_id, month, parent_category_name),
+    CONSTRAINT fk_user_category_monthly_expenses_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Net amount booked on a wallet per day, the balance of a past day is the current balance minus the later days
+CREATE TABLE user_wallet_daily_flows (
+    wallet_id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    date date NOT NULL,
+    amount numeric(18,2) DEFAULT 0 NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT user_wallet_daily_flows_pkey PRIMARY KEY (wallet_id, date),
+    CONSTRAINT fk_user_wallet_daily_flows_wallet FOREIGN KEY (wallet_id) REFERENCES wallets(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_user_wallet_daily_flows_user_id ON user_wallet_daily_flows (user_id, date);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO user_monthly_summaries (user_id, month, total_income, total_expense)
+SELECT
+	w.user_id,
+	date_trunc('month', t.transaction_date)::date,
+	COALESCE(SUM(CASE WHEN c.type = 'income' THEN t.amount END), 0),
+	COALESCE(SUM(CASE WHEN c.type = 'expense' THEN t.amount END), 0)
+FROM transactions t
+JOIN wallets w ON w.id = t.wallet_id
+JOIN categories c ON c.id = t.category_id
+WHERE t.deleted_at IS NULL
+GROUP BY w.user_id, date_trunc('month', t.transaction_date);
+
+INSERT INTO user_category_monthly_expenses (user_id, month, parent_category_name, total)
+SELECT
+	w.user_id,
+	date_trunc('month', t.transaction_date)::date,
+	COALESCE(parent.name, c.name),
+	SUM(t.amount)
+FROM transactions t
+JOIN wallets w ON w.id = t.wallet_id
+JOIN categories c ON c.id = t.category_id
+LEFT JOIN categories parent ON parent.id = c.parent_id
+WHERE t.deleted_at IS NULL
+	AND c.type = 'expense'
+GROUP BY w.user_id, date_trunc('month', t.transaction_date), COALESCE(parent.name, c.name);
+
+INSERT INTO user_wallet_daily_flows (wallet_id, user_id, date, amount)
+SELECT
+	t.wallet_id,
+	w.user_id,
+	DATE(t.transaction_date),
+	SUM(
+	CASE
+		WHEN c.type = 'expense' OR (c.type = 'fund_transfer' AND c.name = 'Cash Out') THEN -1 * t.amount
+		WHEN c.type = 'income' OR (c.type = 'fund_transfer' AND c.name = 'Cash In') THEN t.amount
+		ELSE 0
+	END
+	)
+FROM transactions t
+JOIN wallets w ON w.id = t.wallet_id
+JOIN categories c ON c.id = t.category_id
+WHERE t.deleted_at IS NULL
+GROUP BY t.wallet_id, w.user_id, DATE(t.transaction_date);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP MATERIALIZED VIEW IF EXISTS view_user_summaries;
+DROP MATERIALIZED VIEW IF EXISTS view_user_monthly_summaries;
+DROP MATERIALIZED VIEW IF EXISTS view_user_most_expenses;
+DROP MATERIALIZED VIEW IF EXISTS view_user_wallet_daily_summaries;
+
+-- The views keep the columns of the materialized views they replace
+CREATE VIEW view_user_wallet_daily_summaries AS
+WITH date_series AS (
+SELECT generate_series(
+	CURRENT_DATE - INTERVAL '89 days',
+	CURRENT_DATE,
+	INTERVAL '1 day'
+)::date AS date
+),
+wallet_info AS (
+SELECT
+	w.id AS wallet_id,
+	w.user_id,
+	wt.type AS wallet_type,
+	w.balance AS current_balance
+FROM wallets w
+JOIN wallet_types wt ON wt.id = w.wallet_type_id
+WHERE w.deleted_at IS NULL
+),
+daily_reverse_cumulative AS (
+SELECT
+	ds.date,
+	wi.user_id,
+	wi.wallet_type,
+	wi.current_balance
+	- COALESCE((
+		SELECT SUM(f.amount)
+		FROM user_wallet_daily_flows f
+		WHERE f.wallet_id = wi.wallet_id
+		AND f.date > ds.date
+	), 0) AS total_amount
+FROM date_series ds
+CROSS JOIN wallet_info wi
+)
+SELECT
+	date,
+	user_id,
+	SUM(CASE WHEN wallet_type = 'physical' THEN total_amount ELSE 0 END) AS physical,
+	SUM(CASE WHEN wallet_type = 'e-wallet' THEN total_amount ELSE 0 END) AS e_wallet,
+	SUM(CASE WHEN wallet_type = 'bank' THEN total_amount ELSE 0 END) AS bank,
+	SUM(CASE WHEN wallet_type NOT IN ('physical', 'e-wallet', 'bank') THEN total_amount ELSE 0 END) AS others
+FROM daily_reverse_cumulative
+GROUP BY date, user_id
+ORDER BY user_id, date;
+
+CREATE VIEW view_user_summaries AS
+WITH
+current_month AS (
+SELECT
+	user_id,
+	total_income AS income_now,
+	total_expense AS expense_now
+FROM user_monthly_summaries
+WHERE month = date_trunc('month', CURRENT_DATE)::date
+),
+previous_month AS (
+SELECT
+	user_id,
+	total_income AS income_prev,
+	total_expense AS expense_prev
+FROM user_monthly_summaries
+WHERE month = (date_trunc('month', CURRENT_DATE) - INTERVAL '1 month')::date
+),
+current_balance AS (
+SELECT
+	user_id,
+	SUM(balance) AS balance_now
+FROM wallets
+WHERE deleted_at IS NULL
+GROUP BY user_id
+),
+investment_value AS (
+SELECT
+	user_id,
+	SUM(COALESCE(market_value, investment_amount)) AS investment_value_now
+FROM view_user_investments
+GROUP BY user_id
+),
+previous_balance AS (
+-- Balance at the end of last month, the current balance without everything booked this month
+SELECT
+	w.user_id,
+	SUM(w.balance) - COALESCE(SUM(f.amount), 0) AS balance_prev
+FROM wallets w
+LEFT JOIN LATERAL (
+	SELECT SUM(amount) AS amount
+	FROM user_wallet_daily_flows
+	WHERE wallet_id = w.id
+		AND date >= date_trunc('month', CURRENT_DATE)::date
+) f ON TRUE
+WHERE w.deleted_at IS NULL
+GROUP BY w.user_id
+)
+SELECT
+u.id AS user_id,
+u.name,
+COALESCE(cm.income_now, 0) AS income_now,
+COALESCE(cm.expense_now, 0) AS expense_now,
+COALESCE(cm.income_now, 0) - COALESCE(cm.expense_now, 0) AS profit_now,
+COALESCE(cb.balance_now, 0) AS balance_now,
+ROUND((
+	(COALESCE(cm.income_now, 0) - COALESCE(pm.income_prev, 0)) /
+	NULLIF(pm.income_prev, 0)
+) * 100, 2) AS user_income_growth_percentage,
+ROUND((
+	(COALESCE(cm.expense_now, 0) - COALESCE(pm.expense_prev, 0)) /
+	NULLIF(pm.expense_prev, 0)
+) * 100, 2) AS user_expense_growth_percentage,
+ROUND((
+	((COALESCE(cm.income_now, 0) - COALESCE(cm.expense_now, 0)) -
+	(COALESCE(pm.income_prev, 0) - COALESCE(pm.expense_prev, 0))) /
+	NULLIF((COALESCE(pm.income_prev, 0) - COALESCE(pm.expense_prev, 0)), 0)
+) * 100, 2) AS user_profit_growth_percentage,
+ROUND((
+	(COALESCE(cb.balance_now, 0) - COALESCE(pb.balance_prev, 0)) /
+	NULLIF(pb.balance_prev, 0)
+) * 100, 2) AS user_balance_growth_percentage,
+COALESCE(iv.investment_value_now, 0) AS investment_value_now,
+COALESCE(cb.balance_now, 0) + COALESCE(iv.investment_value_now, 0) AS net_worth_now
+FROM users u
+LEFT JOIN current_month cm ON cm.user_id = u.id
+LEFT JOIN previous_month pm ON pm.user_id = u.id
+LEFT JOIN current_balance cb ON cb.user_id = u.id
+LEFT JOIN previous_balance pb ON pb.user_id = u.id
+LEFT JOIN investment_value iv ON iv.user_id = u.id;
+
+CREATE VIEW view_user_monthly_summaries AS
+SELECT
+	user_id,
+	to_char(month, 'YYYY-MM') AS month,
+	rtrim(to_char(month, 'month')) AS month_name,
+	total_income,
+	total_expense
+FROM user_monthly_summaries
+WHERE month >= date_trunc('month', CURRENT_DATE) - INTERVAL '11 months'
+ORDER BY user_id, 2 ASC;
+
+CREATE VIEW view_user_most_expenses AS
+WITH transaction_totals AS (
+	SELECT
+		user_id,
+		parent_category_name,
+		SUM(total) AS total,
+		ROW_NUMBER() OVER (
+		PARTITION BY user_id
+		ORDER BY SUM(total) DESC
+		) AS rank
+	FROM user_category_monthly_expenses
+	WHERE month >= date_trunc('month', CURRENT_DATE) - INTERVAL '2 months'
+	GROUP BY user_id, parent_category_name
+)
+SELECT *
+FROM transaction_totals
+WHERE rank <= 7
+ORDER BY user_id ASC, total DESC;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP VIEW IF EXISTS view_user_summaries;
+DROP VIEW IF EXISTS view_user_monthly_summaries;
+DROP VIEW IF EXISTS view_user_most_expenses;
+DROP VIEW IF EXISTS view_user_wallet_daily_summaries;
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS view_user_wallet_daily_summaries AS
+WITH date_series AS (
+SELECT generate_series(
+	CURRENT_DATE - INTERVAL '89 days',
+	CURRENT_DATE,
+	INTERVAL '1 day'
+)::date AS date
+),
+wallet_info AS (
+SELECT
+	w.id AS wallet_id,
+	w.user_id,
+	wt.type AS wallet_type,
+	w.balance AS current_balance
+FROM wallets w
+JOIN wallet_types wt ON wt.id = w.wallet_type_id
+),
+tx_summary AS (
+SELECT
+	t.wallet_id,
+	DATE(t.transaction_date) AS date,
+	SUM(
+	CASE
+		WHEN c.type = 'expense' OR (c.type = 'fund_transfer' AND c.name = 'Cash Out') THEN -1 * t.amount
+		WHEN c.type = 'income' OR (c.type = 'fund_transfer' AND c.name = 'Cash In') THEN t.amount
+		ELSE 0
+	END
+	) AS amount
+FROM transactions t
+JOIN categories c ON t.category_id = c.id
+WHERE t.deleted_at IS NULL
+GROUP BY t.wallet_id, DATE(t.transaction_date)
+),
+daily_reverse_cumulative AS (
+SELECT
+	ds.date,
+	wi.user_id,
+	wi.wallet_id,
+	wi.wallet_type,
+	wi.current_balance 
+	- COALESCE((
+		SELECT SUM(ts2.amount)
+		FROM tx_summary ts2
+		WHERE ts2.wallet_id = wi.wallet_id
+		AND ts2.date > ds.date
+	), 0) AS total_amount
+FROM date_series ds
+CROSS JOIN wallet_info wi
+),
+pivoted AS (
+SELECT
+	date,
+	user_id,
+	SUM(CASE WHEN wallet_type = 'physical' THEN total_amount ELSE 0 END) AS physical,
+	SUM(CASE WHEN wallet_type = 'e-wallet' THEN total_amount ELSE 0 END) AS e_wallet,
+	SUM(CASE WHEN wallet_type = 'bank' THEN total_amount ELSE 0 END) AS bank,
+	SUM(CASE WHEN wallet_type NOT IN ('physical', 'e-wallet', 'bank') THEN total_amount ELSE 0 END) AS others
+FROM daily_reverse_cumulative
+GROUP BY date, user_id
+)
+SELECT * FROM pivoted
+ORDER BY user_id, date;
+
+CREATE INDEX IF NOT EXISTS idx_view_user_wallet_daily_summaries_user_id ON view_user_wallet_daily_summaries (user_id, date);
+
+CREATE MATERIALIZED VIEW view_user_summaries AS
+WITH
+current_month AS (
+SELECT
+	u.id AS user_id,
+	SUM(CASE WHEN c.type = 'income' THEN t.amount ELSE 0 END) AS income_now,
+	SUM(CASE WHEN c.type = 'expense' THEN t.amount ELSE 0 END) AS expense_now
+FROM users u
+JOIN wallets w ON w.user_id = u.id
+LEFT JOIN transactions t ON t.wallet_id = w.id
+LEFT JOIN categories c ON c.id = t.category_id
+WHERE t.transaction_date >= date_trunc('month', current_date)
+	AND t.transaction_date < date_trunc('month', current_date + INTERVAL '1 month')
+GROUP BY u.id
+),
+previous_month AS (
+SELECT
+	u.id AS user_id,
+	SUM(CASE WHEN c.type = 'income' THEN t.amount ELSE 0 END) AS income_prev,
+	SUM(CASE WHEN c.type = 'expense' THEN t.amount ELSE 0 END) AS expense_prev
+FROM users u
+JOIN wallets w ON w.user_id = u.id
+LEFT JOIN transactions t ON t.wallet_id = w.id
+LEFT JOIN categories c ON c.id = t.category_id
+WHERE t.transaction_date >= date_trunc('month', current_date - INTERVAL '1 month')
+	AND t.transaction_date < date_trunc('month', current_date)
+GROUP BY u.id
+),
+current_balance AS (
+SELECT
+	u.id AS user_id,
+	SUM(w.balance) AS balance_now
+FROM users u
+JOIN wallets w ON w.user_id = u.id
+GROUP BY u.id
+),
+investment_value AS (
+SELECT
+	user_id,
+	SUM(COALESCE(market_value, investment_amount)) AS investment_value_now
+FROM view_user_investments
+GROUP BY user_id
+),
+previous_balance AS (
+SELECT
+	user_id,
+	(physical + e_wallet + bank + others) AS balance_prev
+FROM view_user_wallet_daily_summaries
+WHERE date = (date_trunc('month', current_date) - INTERVAL '1 day')::date
+)
+SELECT
+u.id AS user_id,
+u.name,
+COALESCE(cm.income_now, 0) AS income_now,
+COALESCE(cm.expense_now, 0) AS expense_now,
+COALESCE(cm.income_now, 0) - COALESCE(cm.expense_now, 0) AS profit_now,
+COALESCE(cb.balance_now, 0) AS balance_now,
+ROUND((
+	(COALESCE(cm.income_now, 0) - COALESCE(pm.income_prev, 0)) /
+	NULLIF(pm.income_prev, 0)
+) * 100, 2) AS user_income_growth_percentage,
+ROUND((
+	(COALESCE(cm.expense_now, 0) - COALESCE(pm.expense_prev, 0)) /
+	NULLIF(pm.expense_prev, 0)
+) * 100, 2) AS user_expense_growth_percentage,
+ROUND((
+	((COALESCE(cm.income_now, 0) - COALESCE(cm.expense_now, 0)) -
+	(COALESCE(pm.income_prev, 0) - COALESCE(pm.expense_prev, 0))) /
+	NULLIF((COALESCE(pm.income_prev, 0) - COALESCE(pm.expense_prev, 0)), 0)
+) * 100, 2) AS user_profit_growth_percentage,
+ROUND((
+	(COALESCE(cb.balance_now, 0) - COALESCE(pb.balance_prev, 0)) /
+	NULLIF(pb.balance_prev, 0)
+) * 100, 2) AS user_balance_growth_percentage,
+COALESCE(iv.investment_value_now, 0) AS investment_value_now,
+COALESCE(cb.balance_now, 0) + COALESCE(iv.investment_value_now, 0) AS net_worth_now
+FROM users u
+LEFT JOIN current_month cm ON cm.user_id = u.id
+LEFT JOIN previous_month pm ON pm.user_id = u.id
+LEFT JOIN current_balance cb ON cb.user_id = u.id
+LEFT JOIN previous_balance pb ON pb.user_id = u.id
+LEFT JOIN investment_value iv ON iv.user_id = u.id;
+
+CREATE INDEX IF NOT EXISTS idx_view_user_summaries_user_id ON view_user_summaries (user_id);
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS view_user_monthly_summaries AS
+SELECT 
+	u.id AS user_id,
+	to_char(t.transaction_date, 'YYYY-MM') AS month,
+	rtrim(to_char(t.transaction_date, 'month')) as month_name,
+	SUM(CASE WHEN c."type" = 'income' THEN t.amount ELSE 0 END) AS total_income,
+	SUM(CASE WHEN c."type" = 'expense' THEN t.amount ELSE 0 END) AS total_expense
+FROM 
+	users u
+JOIN 
+	wallets w ON u.id = w.user_id
+JOIN 
+	transactions t ON w.id = t.wallet_id
+JOIN
+	categories c ON c.id = t.category_id
+WHERE 
+	t.transaction_date >= date_trunc('month', CURRENT_DATE) - INTERVAL '11 months'
+GROUP BY 
+	u.id, to_char(t.transaction_date, 'YYYY-MM'), to_char(t.transaction_date, 'month')
+ORDER BY 
+	u.id, to_char(t.transaction_date, 'YYYY-MM') ASC;
+
+CREATE INDEX IF NOT EXISTS idx_view_user_monthly_summaries_user_id ON view_user_monthly_summaries (user_id, month_name);
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS view_user_most_expenses AS
+WITH transaction_totals AS (
+	SELECT 
+		user_id,
+		parent.name AS parent_category_name,
+		SUM(transactions.amount) AS total,
+		ROW_NUMBER() OVER (
+		PARTITION BY user_id 
+		ORDER BY SUM(transactions.amount) DESC
+		) AS rank
+	FROM users
+	LEFT JOIN wallets ON wallets.user_id = users.id
+	LEFT JOIN transactions ON transactions.wallet_id = wallets.id
+	LEFT JOIN categories ON categories.id = transactions.category_id
+	LEFT JOIN categories parent ON parent.id = categories.parent_id
+	WHERE categories."type" = 'expense'
+		AND transactions.transaction_date >= date_trunc('month', CURRENT_DATE) - INTERVAL '2 months'
+	GROUP BY parent.name, user_id
+)
+SELECT *
+FROM transaction_totals
+WHERE rank <= 7
+ORDER BY user_id ASC, total DESC;
+
+CREATE INDEX IF NOT EXISTS idx_view_user_most_expenses_user_id ON view_user_most_expenses (user_id, parent_category_name);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_wallet_daily_flows;
+DROP TABLE IF EXISTS user_category_monthly_expenses;
+DROP TABLE IF EXISTS user_monthly_summaries;
+-- +goose StatementEnd
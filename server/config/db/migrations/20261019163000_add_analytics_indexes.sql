@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Cash flow analytics read the transactions of a user's wallets within a date range
+CREATE INDEX IF NOT EXISTS idx_wallets_user_id ON wallets (user_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_transactions_wallet_id_date ON transactions (wallet_id, transaction_date) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories (parent_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_categories_parent_id;
+DROP INDEX IF EXISTS idx_transactions_wallet_id_date;
+DROP INDEX IF EXISTS idx_wallets_user_id;
+-- +goose StatementEnd
@@ -0,0 +1,90 @@
+-- +goose Up
+-- +goose StatementBegin
+-- One row per wallet type instead of a column for each of the types that existed when the view was written
+DROP VIEW IF EXISTS view_user_wallet_daily_summaries;
+
+CREATE VIEW view_user_wallet_daily_summaries AS
+WITH date_series AS (
+SELECT generate_series(
+	CURRENT_DATE - INTERVAL '89 days',
+	CURRENT_DATE,
+	INTERVAL '1 day'
+)::date AS date
+),
+wallet_info AS (
+SELECT
+	w.id AS wallet_id,
+	w.user_id,
+	wt.type AS wallet_type,
+	w.balance AS current_balance
+FROM wallets w
+JOIN wallet_types wt ON wt.id = w.wallet_type_id
+WHERE w.deleted_at IS NULL
+)
+SELECT
+	ds.date,
+	wi.user_id,
+	wi.wallet_type,
+	SUM(
+	wi.current_balance
+	- COALESCE((
+		SELECT SUM(f.amount)
+		FROM user_wallet_daily_flows f
+		WHERE f.wallet_id = wi.wallet_id
+		AND f.date > ds.date
+	), 0)
+	) AS total
+FROM date_series ds
+CROSS JOIN wallet_info wi
+GROUP BY ds.date, wi.user_id, wi.wallet_type
+ORDER BY wi.user_id, ds.date, wi.wallet_type;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP VIEW IF EXISTS view_user_wallet_daily_summaries;
+
+CREATE VIEW view_user_wallet_daily_summaries AS
+WITH date_series AS (
+SELECT generate_series(
+	CURRENT_DATE - INTERVAL '89 days',
+	CURRENT_DATE,
+	INTERVAL '1 day'
+)::date AS date
+),
+wallet_info AS (
+SELECT
+	w.id AS wallet_id,
+	w.user_id,
+	wt.type AS wallet_type,
+	w.balance AS current_balance
+FROM wallets w
+JOIN wallet_types wt ON wt.id = w.wallet_type_id
+WHERE w.deleted_at IS NULL
+),
+daily_reverse_cumulative AS (
+SELECT
+	ds.date,
+	wi.user_id,
+	wi.wallet_type,
+	wi.current_balance
+	- COALESCE((
+		SELECT SUM(f.amount)
+		FROM user_wallet_daily_flows f
+		WHERE f.wallet_id = wi.wallet_id
+		AND f.date > ds.date
+	), 0) AS total_amount
+FROM date_series ds
+CROSS JOIN wallet_info wi
+)
+SELECT
+	date,
+	user_id,
+	SUM(CASE WHEN wallet_type = 'physical' THEN total_amount ELSE 0 END) AS physical,
+	SUM(CASE WHEN wallet_type = 'e-wallet' THEN total_amount ELSE 0 END) AS e_wallet,
+	SUM(CASE WHEN wallet_type = 'bank' THEN total_amount ELSE 0 END) AS bank,
+	SUM(CASE WHEN wallet_type NOT IN ('physical', 'e-wallet', 'bank') THEN total_amount ELSE 0 END) AS others
+FROM daily_reverse_cumulative
+GROUP BY date, user_id
+ORDER BY user_id, date;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Transactions booked by the worker on a schedule, next_date is the date of the next booking
+CREATE TABLE recurring_transactions (
+    id uuid DEFAULT uuid_generate_v4() NOT NULL,
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    user_id uuid NOT NULL REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    wallet_id uuid NOT NULL REFERENCES wallets (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    category_id uuid NOT NULL REFERENCES categories (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    amount decimal(18,2) NOT NULL,
+    description text,
+    frequency varchar(16) NOT NULL,
+    start_date timestamp NOT NULL,
+    end_date timestamp,
+    occurrences integer DEFAULT 0 NOT NULL,
+    next_date timestamp NOT NULL,
+    CONSTRAINT recurring_transactions_pkey PRIMARY KEY (id),
+    CONSTRAINT recurring_transactions_amount_check CHECK (amount > 0),
+    CONSTRAINT recurring_transactions_frequency_check CHECK (frequency IN ('daily', 'weekly', 'monthly', 'yearly'))
+);
+
+CREATE INDEX idx_recurring_transactions_user_id ON recurring_transactions (user_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_recurring_transactions_next_date ON recurring_transactions (next_date) WHERE deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS recurring_transactions;
+-- +goose StatementEnd
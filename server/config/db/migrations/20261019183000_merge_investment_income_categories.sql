@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+-- create_investment_incomes used to insert its own Dividen, Kupon and Bunga categories, move what was booked
+-- under them to the seeded categories of data.INVESTMENT_INCOME_CATEGORIES. Fresh databases have nothing to move.
+CREATE TEMP TABLE merged_income_categories ON COMMIT DROP AS
+SELECT merged.old_id, child.id AS new_id
+FROM (VALUES
+    ('c2e8a4f1-5b9d-4c37-8e12-7f6a3d9b0c48'::uuid, 'Pendapatan Pasif',   'Dividen Saham'),
+    ('5d1f7b3a-9e2c-4a84-b6d0-1c8e4f2a9b63'::uuid, 'Pendapatan Lainnya', 'Pendapatan Investasi'),
+    ('a7c3e9d5-2f4b-4d16-9a8e-3b5c7d1f0e29'::uuid, 'Pendapatan Pasif',   'Bunga Deposito')
+) AS merged (old_id, parent_name, name)
+JOIN categories parent ON parent.name = merged.parent_name AND parent.type = 'income' AND parent.parent_id IS NULL
+JOIN categories child ON child.name = merged.name AND child.type = 'income' AND child.parent_id = parent.id;
+
+UPDATE transactions SET category_id = merged.new_id
+FROM merged_income_categories merged
+WHERE transactions.category_id = merged.old_id;
+
+UPDATE recurring_transactions SET category_id = merged.new_id
+FROM merged_income_categories merged
+WHERE recurring_transactions.category_id = merged.old_id;
+
+DELETE FROM categories WHERE id IN (SELECT old_id FROM merged_income_categories);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- The merged categories are not restored, their transactions stay under the seeded categories
+SELECT 1;
+-- +goose StatementEnd
@@ -112,6 +112,8 @@ INSERT INTO categories (id, parent_id, name, type) VALUES
 ('b5a5d097-6346-4b72-b975-8ebf0b4b72f1', 'e8f83876-0e5c-4e88-b16b-f3229d5c8412',    'Cash Out',                         'fund_transfer'),
 ('8d99002a-f0be-4baf-aa40-6605dc9bbfa2', NULL,                                      'Bisnis',                           'expense'),
 ('66239d17-3320-4c98-9b8c-fb8d84827085', NULL,                                      'Investasi',                        'expense'),
+('3f9d2c1e-7a4b-4e8f-9c61-2b5d8e0a7f13', NULL,                                      'Investasi',                        'income'),
+('8b47e5a2-1c3d-4f69-a0b8-6d2e9f1c4a57', '3f9d2c1e-7a4b-4e8f-9c61-2b5d8e0a7f13',    'Penjualan Investasi',              'income'),
 ('17f190f7-36fb-4785-83d0-029568e768c6', '8d99002a-f0be-4baf-aa40-6605dc9bbfa2',    'Pemasaran & Iklan',                'expense'),
 ('99140343-3e68-40b2-8727-b94ab047f40e', '66239d17-3320-4c98-9b8c-fb8d84827085',    'Deposito',                         'expense'),
 ('b0a06cfe-a0bb-44c1-a95f-0a095e0f29a1', NULL,                                      'Utang',                            'expense'),
@@ -285,32 +285,32 @@ INSERT INTO transactions (id, created_at, updated_at, deleted_at, wallet_id, cat
 ('537e13fc-afe3-47f7-a670-5678802e18f1', '2025-08-14 02:59:11.755239+00', '2025-08-14 02:59:11.755239+00', NULL, 'ee396999-b9eb-4471-ab89-d84a3a20fd36', 'ae009d3c-d03a-4e2e-a505-891f4adcdef7', 5000.00, '2025-08-14 09:58:51', 'kerupuk'),
 ('db627f94-cb64-4e50-a9d2-ef687a06c3bf', '2025-08-16 06:12:56.989994+00', '2025-08-16 06:12:56.989994+00', NULL, 'ee396999-b9eb-4471-ab89-d84a3a20fd36', '552de2ae-6900-42ef-ace5-bfbdc1fce31a', 5000.00, '2025-08-15 13:12:24', 'parkir grand city');
 
-INSERT INTO attachments (id, created_at, updated_at, deleted_at, transaction_id, image, format, size) VALUES
-('c4d02ab6-3c0e-408c-a3d9-df41e2ceca93', '2025-07-06 08:00:26.960551+00', '2025-07-06 08:00:26.960551+00', NULL, 'ddb679bd-bcc8-4e04-8839-3b5d2aad39da', 'TA_ddb679bd-bcc8-4e04-8839-3b5d2aad39da_20250706080026000000000_0..jpeg', '.jpeg', 56435),
-('7075691f-9e47-4c5d-a4c0-a41809a01451', '2025-07-06 08:39:31.916095+00', '2025-07-06 08:39:31.916095+00', NULL, 'ed98cb5d-d11b-447f-89e4-9864e4e435b9', 'TA_ed98cb5d-d11b-447f-89e4-9864e4e435b9_20250706083931000000000_0..jpeg', '.jpeg', 304970),
-('d7028bfd-d0f7-4dfc-853a-7dfff60cb46c', '2025-07-06 08:42:44.386902+00', '2025-07-06 08:42:44.386902+00', NULL, '3beda494-bba9-47fd-aa5b-f4c41e501c2e', 'TA_3beda494-bba9-47fd-aa5b-f4c41e501c2e_20250706084244000000000_0..jpeg', '.jpeg', 303266),
-('a52f2296-6187-4363-85fc-cb6bbe96f6de', '2025-07-06 08:47:26.17289+00', '2025-07-06 08:47:26.17289+00', NULL, '63457e75-2ea2-4f18-94de-8248dd380b97', 'TA_63457e75-2ea2-4f18-94de-8248dd380b97_20250706084726000000000_0..jpeg', '.jpeg', 358032),
-('baa4e2ef-3181-4060-8cf5-899ae1e51ff7', '2025-07-06 08:49:19.618604+00', '2025-07-06 08:49:19.618604+00', NULL, 'bd4e31f7-23e1-4242-9c8d-c98f76eb2963', 'TA_bd4e31f7-23e1-4242-9c8d-c98f76eb2963_20250706084919000000000_1..jpeg', '.jpeg', 394353),
-('f25903a4-a351-4f5a-82b1-79b61ee85e25', '2025-07-06 08:51:05.077293+00', '2025-07-06 08:51:05.077293+00', NULL, 'ac1e34c9-9613-4044-ab49-673cd43400aa', 'TA_ac1e34c9-9613-4044-ab49-673cd43400aa_20250706085105000000000_0..jpeg', '.jpeg', 158706),
-('219da04d-18da-4ba2-a3a6-d83d00d0fd80', '2025-07-06 08:53:33.016411+00', '2025-07-06 08:53:33.016411+00', NULL, '26ecc77d-5ea4-4258-8e8e-b3ac296d52c2', 'TA_26ecc77d-5ea4-4258-8e8e-b3ac296d52c2_20250706085333000000000_1..jpeg', '.jpeg', 102246),
-('efeb454c-491b-4895-9b25-31ac4701e18e', '2025-07-09 02:09:00.116257+00', '2025-07-09 02:09:00.116257+00', NULL, 'd76693f4-0a9f-4d11-a536-c2df35a60551', 'TA_d76693f4-0a9f-4d11-a536-c2df35a60551_20250709020900000000000_0..jpeg', '.jpeg', 479393),
-('466aa33b-6389-4e59-9274-a775dce3eab6', '2025-07-13 10:02:40.316611+00', '2025-07-13 10:02:40.316611+00', NULL, 'c3a62ec6-f490-412b-a2f4-8eaea675742c', 'TA_c3a62ec6-f490-412b-a2f4-8eaea675742c_20250713100240000000000_0..jpeg', '.jpeg', 145605),
-('4086db4c-db50-45bd-805f-fc4263360e40', '2025-07-16 03:25:11.055693+00', '2025-07-16 03:25:11.055693+00', NULL, '9b3f41d9-69e2-4b90-93dd-98be44fc873f', 'TA_9b3f41d9-69e2-4b90-93dd-98be44fc873f_20250716032511000000000_0..jpeg', '.jpeg', 108348),
-('d73410b5-d538-45c3-a5d3-80cfcb198d5b', '2025-07-16 03:29:14.816987+00', '2025-07-16 03:29:14.816987+00', NULL, 'd114eac5-b0eb-4e24-8ec2-73e6fa008868', 'TA_d114eac5-b0eb-4e24-8ec2-73e6fa008868_20250716032914000000000_0..jpeg', '.jpeg', 106787),
-('7778a649-ed0b-41f7-a27a-08f4f525b1aa', '2025-04-02 08:58:39.966604+00', '2025-04-02 08:58:39.966604+00', NULL, '032726ce-8fc1-4595-87c2-c8c61063ce44', '-202504021558397ba7ad10-55d3-4285-9f21-0394075d90bb.jpg', NULL, NULL),
-('cf494e85-84e4-4740-a1d5-50de4324e9d2', '2025-04-02 08:58:39.961593+00', '2025-04-02 08:58:39.961593+00', NULL, '032726ce-8fc1-4595-87c2-c8c61063ce44', '-20250402155839a248ae8a-7ab8-4810-a239-8e6b4841eb90.png', NULL, NULL),
-('20e85779-b2f8-4b63-98f7-501bcc3786df', '2025-04-07 02:37:02.388633+00', '2025-04-07 02:37:02.388633+00', NULL, '4a52b8f6-1941-4b3c-8966-c17a49488a38', '-202504070937025b9bb1e9-360f-44d4-991b-bd556446952c.jpg', NULL, NULL),
-('23aba04d-e451-4a07-98ad-2edaafd4be2e', '2025-04-12 05:54:54.887269+00', '2025-04-12 05:54:54.887269+00', NULL, 'ba3b9152-544e-4ec1-9474-2af9fe83a97d', '-20250412125454a7de7d27-d175-4398-8765-9bc5010b854c.jpg', NULL, NULL),
-('6595b450-5821-405d-bbeb-96ca97ee9a11', '2025-04-13 08:01:46.684871+00', '2025-04-13 08:01:46.684871+00', NULL, '298979c6-1b98-4eff-be57-5f6bc86181c1', '-20250413150146e39b346a-6936-422a-adaf-337967525c14.jpg', NULL, NULL),
-('0099637a-b520-431b-8631-4a414f98db44', '2025-04-13 08:01:46.723169+00', '2025-04-13 08:01:46.723169+00', NULL, 'e5b7bdf8-45d3-438c-9985-a651bf5da53d', '-202504131501464a76890f-2d09-41ec-94c1-88f3581109b6.jpg', NULL, NULL),
-('b713d4d1-b2e8-43fb-8b57-5cd807b64b0d', '2025-06-25 23:37:23.98254+00', '2025-06-25 23:37:23.98254+00', NULL, '8bf27897-26eb-418b-81f2-9a159df897f7', '20250625233723-8bf27897-26eb-418b-81f2-9a159df897f7.jpeg', NULL, NULL),
-('cf628b98-dbfe-4847-86a6-180e730058e1', '2025-06-30 05:31:06.176595+00', '2025-06-30 05:31:06.176595+00', NULL, '45fe314b-135e-43d4-8132-57fd94fc8902', '20250630053106-45fe314b-135e-43d4-8132-57fd94fc8902.jpg', NULL, NULL),
-('a3ac8a96-f75a-41d4-a44e-29af4046a6cf', '2025-07-01 02:18:35.95137+00', '2025-07-01 02:18:35.95137+00', NULL, '3b331ec4-eea8-4420-94e5-9fc057e27589', 'TA_3b331ec4-eea8-4420-94e5-9fc057e27589_20250701021835000000000..jpeg', NULL, NULL),
-('995e9faf-ae58-4b54-a10e-edc0ea6cbb33', '2025-07-01 02:18:35.956733+00', '2025-07-01 02:18:35.956733+00', NULL, '3b331ec4-eea8-4420-94e5-9fc057e27589', 'TA_3b331ec4-eea8-4420-94e5-9fc057e27589_20250701021835000000000..jpeg', NULL, NULL),
-('82bb0544-d09c-4943-b8c7-e79363ade873', '2025-07-21 02:04:18.933619+00', '2025-07-21 02:04:18.933619+00', NULL, 'f493b022-9c9b-4f7c-8a89-5c609bc2acf4', 'TA_f493b022-9c9b-4f7c-8a89-5c609bc2acf4_20250721020418000000000_0.jpeg', '.jpeg', 568802),
-('db8e477b-9f0a-4600-8d2b-3326a5230ba1', '2025-07-29 03:44:16.58424+00', '2025-07-29 03:44:16.58424+00', NULL, '0ebe7bb8-63b5-46ba-98c6-1bacbd62e56f', 'TA_0ebe7bb8-63b5-46ba-98c6-1bacbd62e56f_20250729034416000000000_0.jpeg', '.jpeg', 86623),
-('1a0fcc48-9164-42f6-b229-c5432f026c27', '2025-07-31 02:21:23.572689+00', '2025-07-31 02:21:23.572689+00', NULL, 'b58457dd-a3f5-40d5-a944-ca86d0c68890', 'TA_b58457dd-a3f5-40d5-a944-ca86d0c68890_20250731022123000000000_0.jpeg', '.jpeg', 85777),
-('84c4f9c7-efdb-4b12-9f56-037c27350d69', '2025-07-31 02:23:26.207117+00', '2025-07-31 02:23:26.207117+00', NULL, '153e0dcb-4fb6-488a-af69-237a0ad080ff', 'TA_153e0dcb-4fb6-488a-af69-237a0ad080ff_20250731022326000000000_0.jpeg', '.jpeg', 53484);
+INSERT INTO attachments (id, created_at, updated_at, deleted_at, owner_type, owner_id, image, format, size) VALUES
+('c4d02ab6-3c0e-408c-a3d9-df41e2ceca93', '2025-07-06 08:00:26.960551+00', '2025-07-06 08:00:26.960551+00', NULL, 'transaction', 'ddb679bd-bcc8-4e04-8839-3b5d2aad39da', 'TA_ddb679bd-bcc8-4e04-8839-3b5d2aad39da_20250706080026000000000_0..jpeg', 'image', 56435),
+('7075691f-9e47-4c5d-a4c0-a41809a01451', '2025-07-06 08:39:31.916095+00', '2025-07-06 08:39:31.916095+00', NULL, 'transaction', 'ed98cb5d-d11b-447f-89e4-9864e4e435b9', 'TA_ed98cb5d-d11b-447f-89e4-9864e4e435b9_20250706083931000000000_0..jpeg', 'image', 304970),
+('d7028bfd-d0f7-4dfc-853a-7dfff60cb46c', '2025-07-06 08:42:44.386902+00', '2025-07-06 08:42:44.386902+00', NULL, 'transaction', '3beda494-bba9-47fd-aa5b-f4c41e501c2e', 'TA_3beda494-bba9-47fd-aa5b-f4c41e501c2e_20250706084244000000000_0..jpeg', 'image', 303266),
+('a52f2296-6187-4363-85fc-cb6bbe96f6de', '2025-07-06 08:47:26.17289+00', '2025-07-06 08:47:26.17289+00', NULL, 'transaction', '63457e75-2ea2-4f18-94de-8248dd380b97', 'TA_63457e75-2ea2-4f18-94de-8248dd380b97_20250706084726000000000_0..jpeg', 'image', 358032),
+('baa4e2ef-3181-4060-8cf5-899ae1e51ff7', '2025-07-06 08:49:19.618604+00', '2025-07-06 08:49:19.618604+00', NULL, 'transaction', 'bd4e31f7-23e1-4242-9c8d-c98f76eb2963', 'TA_bd4e31f7-23e1-4242-9c8d-c98f76eb2963_20250706084919000000000_1..jpeg', 'image', 394353),
+('f25903a4-a351-4f5a-82b1-79b61ee85e25', '2025-07-06 08:51:05.077293+00', '2025-07-06 08:51:05.077293+00', NULL, 'transaction', 'ac1e34c9-9613-4044-ab49-673cd43400aa', 'TA_ac1e34c9-9613-4044-ab49-673cd43400aa_20250706085105000000000_0..jpeg', 'image', 158706),
+('219da04d-18da-4ba2-a3a6-d83d00d0fd80', '2025-07-06 08:53:33.016411+00', '2025-07-06 08:53:33.016411+00', NULL, 'transaction', '26ecc77d-5ea4-4258-8e8e-b3ac296d52c2', 'TA_26ecc77d-5ea4-4258-8e8e-b3ac296d52c2_20250706085333000000000_1..jpeg', 'image', 102246),
+('efeb454c-491b-4895-9b25-31ac4701e18e', '2025-07-09 02:09:00.116257+00', '2025-07-09 02:09:00.116257+00', NULL, 'transaction', 'd76693f4-0a9f-4d11-a536-c2df35a60551', 'TA_d76693f4-0a9f-4d11-a536-c2df35a60551_20250709020900000000000_0..jpeg', 'image', 479393),
+('466aa33b-6389-4e59-9274-a775dce3eab6', '2025-07-13 10:02:40.316611+00', '2025-07-13 10:02:40.316611+00', NULL, 'transaction', 'c3a62ec6-f490-412b-a2f4-8eaea675742c', 'TA_c3a62ec6-f490-412b-a2f4-8eaea675742c_20250713100240000000000_0..jpeg', 'image', 145605),
+('4086db4c-db50-45bd-805f-fc4263360e40', '2025-07-16 03:25:11.055693+00', '2025-07-16 03:25:11.055693+00', NULL, 'transaction', '9b3f41d9-69e2-4b90-93dd-98be44fc873f', 'TA_9b3f41d9-69e2-4b90-93dd-98be44fc873f_20250716032511000000000_0..jpeg', 'image', 108348),
+('d73410b5-d538-45c3-a5d3-80cfcb198d5b', '2025-07-16 03:29:14.816987+00', '2025-07-16 03:29:14.816987+00', NULL, 'transaction', 'd114eac5-b0eb-4e24-8ec2-73e6fa008868', 'TA_d114eac5-b0eb-4e24-8ec2-73e6fa008868_20250716032914000000000_0..jpeg', 'image', 106787),
+('7778a649-ed0b-41f7-a27a-08f4f525b1aa', '2025-04-02 08:58:39.966604+00', '2025-04-02 08:58:39.966604+00', NULL, 'transaction', '032726ce-8fc1-4595-87c2-c8c61063ce44', '-202504021558397ba7ad10-55d3-4285-9f21-0394075d90bb.jpg', NULL, NULL),
+('cf494e85-84e4-4740-a1d5-50de4324e9d2', '2025-04-02 08:58:39.961593+00', '2025-04-02 08:58:39.961593+00', NULL, 'transaction', '032726ce-8fc1-4595-87c2-c8c61063ce44', '-20250402155839a248ae8a-7ab8-4810-a239-8e6b4841eb90.png', NULL, NULL),
+('20e85779-b2f8-4b63-98f7-501bcc3786df', '2025-04-07 02:37:02.388633+00', '2025-04-07 02:37:02.388633+00', NULL, 'transaction', '4a52b8f6-1941-4b3c-8966-c17a49488a38', '-202504070937025b9bb1e9-360f-44d4-991b-bd556446952c.jpg', NULL, NULL),
+('23aba04d-e451-4a07-98ad-2edaafd4be2e', '2025-04-12 05:54:54.887269+00', '2025-04-12 05:54:54.887269+00', NULL, 'transaction', 'ba3b9152-544e-4ec1-9474-2af9fe83a97d', '-20250412125454a7de7d27-d175-4398-8765-9bc5010b854c.jpg', NULL, NULL),
+('6595b450-5821-405d-bbeb-96ca97ee9a11', '2025-04-13 08:01:46.684871+00', '2025-04-13 08:01:46.684871+00', NULL, 'transaction', '298979c6-1b98-4eff-be57-5f6bc86181c1', '-20250413150146e39b346a-6936-422a-adaf-337967525c14.jpg', NULL, NULL),
+('0099637a-b520-431b-8631-4a414f98db44', '2025-04-13 08:01:46.723169+00', '2025-04-13 08:01:46.723169+00', NULL, 'transaction', 'e5b7bdf8-45d3-438c-9985-a651bf5da53d', '-202504131501464a76890f-2d09-41ec-94c1-88f3581109b6.jpg', NULL, NULL),
+('b713d4d1-b2e8-43fb-8b57-5cd807b64b0d', '2025-06-25 23:37:23.98254+00', '2025-06-25 23:37:23.98254+00', NULL, 'transaction', '8bf27897-26eb-418b-81f2-9a159df897f7', '20250625233723-8bf27897-26eb-418b-81f2-9a159df897f7.jpeg', NULL, NULL),
+('cf628b98-dbfe-4847-86a6-180e730058e1', '2025-06-30 05:31:06.176595+00', '2025-06-30 05:31:06.176595+00', NULL, 'transaction', '45fe314b-135e-43d4-8132-57fd94fc8902', '20250630053106-45fe314b-135e-43d4-8132-57fd94fc8902.jpg', NULL, NULL),
+('a3ac8a96-f75a-41d4-a44e-29af4046a6cf', '2025-07-01 02:18:35.95137+00', '2025-07-01 02:18:35.95137+00', NULL, 'transaction', '3b331ec4-eea8-4420-94e5-9fc057e27589', 'TA_3b331ec4-eea8-4420-94e5-9fc057e27589_20250701021835000000000..jpeg', NULL, NULL),
+('995e9faf-ae58-4b54-a10e-edc0ea6cbb33', '2025-07-01 02:18:35.956733+00', '2025-07-01 02:18:35.956733+00', NULL, 'transaction', '3b331ec4-eea8-4420-94e5-9fc057e27589', 'TA_3b331ec4-eea8-4420-94e5-9fc057e27589_20250701021835000000000..jpeg', NULL, NULL),
+('82bb0544-d09c-4943-b8c7-e79363ade873', '2025-07-21 02:04:18.933619+00', '2025-07-21 02:04:18.933619+00', NULL, 'transaction', 'f493b022-9c9b-4f7c-8a89-5c609bc2acf4', 'TA_f493b022-9c9b-4f7c-8a89-5c609bc2acf4_20250721020418000000000_0.jpeg', 'image', 568802),
+('db8e477b-9f0a-4600-8d2b-3326a5230ba1', '2025-07-29 03:44:16.58424+00', '2025-07-29 03:44:16.58424+00', NULL, 'transaction', '0ebe7bb8-63b5-46ba-98c6-1bacbd62e56f', 'TA_0ebe7bb8-63b5-46ba-98c6-1bacbd62e56f_20250729034416000000000_0.jpeg', 'image', 86623),
+('1a0fcc48-9164-42f6-b229-c5432f026c27', '2025-07-31 02:21:23.572689+00', '2025-07-31 02:21:23.572689+00', NULL, 'transaction', 'b58457dd-a3f5-40d5-a944-ca86d0c68890', 'TA_b58457dd-a3f5-40d5-a944-ca86d0c68890_20250731022123000000000_0.jpeg', 'image', 85777),
+('84c4f9c7-efdb-4b12-9f56-037c27350d69', '2025-07-31 02:23:26.207117+00', '2025-07-31 02:23:26.207117+00', NULL, 'transaction', '153e0dcb-4fb6-488a-af69-237a0ad080ff', 'TA_153e0dcb-4fb6-488a-af69-237a0ad080ff_20250731022326000000000_0.jpeg', 'image', 53484);
 -- +goose StatementEnd
 
 -- +goose Down
@@ -3,9 +3,15 @@ package env
 import (
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
+
+	"server/internal/utils/data"
 
 	"github.com/joho/godotenv"
+	"github.com/robfig/cron/v3"
 	"github.com/spf13/viper"
 )
 
@@ -89,6 +95,29 @@ type (
 		UseSSL      int    `env:"MINIO_USE_SSL"`
 	}
 
+	Storage struct {
+		Driver       string `env:"STORAGE_DRIVER"`
+		LocalPath    string `env:"STORAGE_LOCAL_PATH"`
+		LocalBaseURL string `env:"STORAGE_LOCAL_BASE_URL"`
+		// SigningKey signs the presigned URLs of the local driver, kept apart from the JWT secret
+		SigningKey string `env:"STORAGE_SIGNING_KEY"`
+	}
+
+	Pricing struct {
+		Provider string `env:"PRICE_PROVIDER"`
+	}
+
+	Report struct {
+		Cooldowns map[string]time.Duration `env:"REPORT_COOLDOWNS"`
+	}
+
+	Worker struct {
+		Concurrency map[string]int           `env:"WORKER_CONCURRENCY"`
+		Prefetch    map[string]int           `env:"WORKER_PREFETCH"`
+		Timeouts    map[string]time.Duration `env:"WORKER_TIMEOUTS"`
+		Schedules   map[string]string        `env:"WORKER_SCHEDULES"`
+	}
+
 	Config struct {
 		Server   Server
 		Client   Client
@@ -99,6 +128,10 @@ type (
 		ZSMTP    ZSMTP
 		RabbitMQ RabbitMQ
 		Minio    Minio
+		Storage  Storage
+		Pricing  Pricing
+		Report   Report
+		Worker   Worker
 	}
 )
 
@@ -279,6 +312,50 @@ func LoadNative() ([]string, error) {
 	}
 	// ! ______________________________________________________
 
+	// ! Load Storage configuration ___________________________
+	if Cfg.Storage.Driver, ok = os.LookupEnv("STORAGE_DRIVER"); !ok {
+		Cfg.Storage.Driver = "minio"
+	}
+	if Cfg.Storage.Driver == "local" {
+		if Cfg.Storage.LocalPath, ok = os.LookupEnv("STORAGE_LOCAL_PATH"); !ok {
+			missing = append(missing, "STORAGE_LOCAL_PATH env is not set")
+		}
+		if Cfg.Storage.LocalBaseURL, ok = os.LookupEnv("STORAGE_LOCAL_BASE_URL"); !ok {
+			missing = append(missing, "STORAGE_LOCAL_BASE_URL env is not set")
+		}
+		if Cfg.Storage.SigningKey, ok = os.LookupEnv("STORAGE_SIGNING_KEY"); !ok {
+			missing = append(missing, "STORAGE_SIGNING_KEY env is not set")
+		}
+	}
+	// ! ______________________________________________________
+
+	// ! Load Pricing configuration ___________________________
+	// Price sync is disabled when no provider is configured
+	Cfg.Pricing.Provider = os.Getenv("PRICE_PROVIDER")
+	// ! ______________________________________________________
+
+	// ! Load Report configuration ____________________________
+	// Formatted as type=duration pairs, e.g. financial=24h,summary=1h
+	missing = append(missing, loadReportCooldowns(parsePairs(os.Getenv("REPORT_COOLDOWNS")), "REPORT_COOLDOWNS")...)
+	// ! ______________________________________________________
+
+	// ! Load Worker configuration ____________________________
+	// Formatted as job=value pairs, e.g. user_report=2,outbox_relay=1, jobs left out use their defaults
+	missing = append(missing, loadWorkerJobs(
+		parsePairs(os.Getenv("WORKER_CONCURRENCY")), "WORKER_CONCURRENCY",
+		parsePairs(os.Getenv("WORKER_PREFETCH")), "WORKER_PREFETCH",
+		parsePairs(os.Getenv("WORKER_TIMEOUTS")), "WORKER_TIMEOUTS",
+	)...)
+	// Cron expressions contain commas, schedules are separated by semicolons, e.g. refresh_summaries=0 */6 * * *
+	schedules := map[string]string{}
+	for _, pair := range strings.Split(os.Getenv("WORKER_SCHEDULES"), ";") {
+		if job, schedule, ok := strings.Cut(pair, "="); ok {
+			schedules[strings.TrimSpace(job)] = strings.TrimSpace(schedule)
+		}
+	}
+	missing = append(missing, loadWorkerSchedules(schedules, "WORKER_SCHEDULES")...)
+	// ! ______________________________________________________
+
 	return missing, nil
 }
 
@@ -450,5 +527,132 @@ func LoadByViper() ([]string, error) {
 	
 	// ! ______________________________________________________
 
+	// ! Load Storage configuration ___________________________
+	if Cfg.Storage.Driver = config.GetString("OBJECT-STORAGE.DRIVER"); Cfg.Storage.Driver == "" {
+		Cfg.Storage.Driver = "minio"
+	}
+	if Cfg.Storage.Driver == "local" {
+		if Cfg.Storage.LocalPath = config.GetString("OBJECT-STORAGE.LOCAL.PATH"); Cfg.Storage.LocalPath == "" {
+			missing = append(missing, "OBJECT-STORAGE.LOCAL.PATH env is not set")
+		}
+		if Cfg.Storage.LocalBaseURL = config.GetString("OBJECT-STORAGE.LOCAL.BASE_URL"); Cfg.Storage.LocalBaseURL == "" {
+			missing = append(missing, "OBJECT-STORAGE.LOCAL.BASE_URL env is not set")
+		}
+		if Cfg.Storage.SigningKey = config.GetString("OBJECT-STORAGE.LOCAL.SIGNING_KEY"); Cfg.Storage.SigningKey == "" {
+			missing = append(missing, "OBJECT-STORAGE.LOCAL.SIGNING_KEY env is not set")
+		}
+	}
+	// ! ______________________________________________________
+
+	// ! Load Pricing configuration ___________________________
+	// Price sync is disabled when no provider is configured
+	Cfg.Pricing.Provider = config.GetString("INVESTMENT.PRICE_PROVIDER")
+	// ! ______________________________________________________
+
+	// ! Load Report configuration ____________________________
+	missing = append(missing, loadReportCooldowns(config.GetStringMapString("REPORT.COOLDOWNS"), "REPORT.COOLDOWNS")...)
+	// ! ______________________________________________________
+
+	// ! Load Worker configuration ____________________________
+	missing = append(missing, loadWorkerJobs(
+		config.GetStringMapString("WORKER.CONCURRENCY"), "WORKER.CONCURRENCY",
+		config.GetStringMapString("WORKER.PREFETCH"), "WORKER.PREFETCH",
+		config.GetStringMapString("WORKER.TIMEOUTS"), "WORKER.TIMEOUTS",
+	)...)
+	missing = append(missing, loadWorkerSchedules(config.GetStringMapString("WORKER.SCHEDULES"), "WORKER.SCHEDULES")...)
+	// ! ______________________________________________________
+
 	return missing, nil
 }
+
+// loadReportCooldowns parses the cooldown of each report type, types left out use the service default.
+// A type that is not in data.REPORT_TYPES is reported, it is most likely a typo that would silently keep the default.
+func loadReportCooldowns(cooldowns map[string]string, key string) []string {
+	var missing []string
+
+	Cfg.Report.Cooldowns = make(map[string]time.Duration, len(cooldowns))
+	for reportType, val := range cooldowns {
+		if !slices.Contains(data.REPORT_TYPES, strings.ToLower(reportType)) {
+			missing = append(missing, fmt.Sprintf("%s has unknown report type %s, known types are %s", key, reportType, strings.Join(data.REPORT_TYPES, ", ")))
+			continue
+		}
+
+		cooldown, err := time.ParseDuration(val)
+		if err != nil || cooldown < 0 {
+			missing = append(missing, fmt.Sprintf("%s of %s must be a duration, got %s", key, reportType, val))
+			continue
+		}
+		Cfg.Report.Cooldowns[strings.ToLower(reportType)] = cooldown
+	}
+
+	return missing
+}
+
+// loadWorkerJobs parses the concurrency, prefetch and timeout of each worker job
+func loadWorkerJobs(concurrency map[string]string, concurrencyKey string, prefetch map[string]string, prefetchKey string, timeouts map[string]string, timeoutsKey string) []string {
+	var missing []string
+
+	Cfg.Worker.Concurrency = make(map[string]int, len(concurrency))
+	for job, val := range concurrency {
+		count, err := strconv.Atoi(val)
+		if err != nil || count < 1 {
+			missing = append(missing, fmt.Sprintf("%s of %s must be a positive number, got %s", concurrencyKey, job, val))
+			continue
+		}
+		Cfg.Worker.Concurrency[strings.ToLower(job)] = count
+	}
+
+	Cfg.Worker.Prefetch = make(map[string]int, len(prefetch))
+	for job, val := range prefetch {
+		count, err := strconv.Atoi(val)
+		if err != nil || count < 1 {
+			missing = append(missing, fmt.Sprintf("%s of %s must be a positive number, got %s", prefetchKey, job, val))
+			continue
+		}
+		Cfg.Worker.Prefetch[strings.ToLower(job)] = count
+	}
+
+	Cfg.Worker.Timeouts = make(map[string]time.Duration, len(timeouts))
+	for job, val := range timeouts {
+		timeout, err := time.ParseDuration(val)
+		if err != nil || timeout <= 0 {
+			missing = append(missing, fmt.Sprintf("%s of %s must be a duration, got %s", timeoutsKey, job, val))
+			continue
+		}
+		Cfg.Worker.Timeouts[strings.ToLower(job)] = timeout
+	}
+
+	return missing
+}
+
+// loadWorkerSchedules validates the cron schedule of each scheduled job, "off" disables the schedule of a job
+func loadWorkerSchedules(schedules map[string]string, key string) []string {
+	var missing []string
+
+	Cfg.Worker.Schedules = make(map[string]string, len(schedules))
+	for job, val := range schedules {
+		if !strings.EqualFold(val, "off") {
+			if _, err := cron.ParseStandard(val); err != nil {
+				missing = append(missing, fmt.Sprintf("%s of %s must be a cron expression, got %s", key, job, val))
+				continue
+			}
+		}
+		Cfg.Worker.Schedules[strings.ToLower(job)] = val
+	}
+
+	return missing
+}
+
+// parsePairs splits comma separated key=value pairs of an environment variable
+func parsePairs(val string) map[string]string {
+	pairs := map[string]string{}
+	if val == "" {
+		return pairs
+	}
+
+	for _, pair := range strings.Split(val, ",") {
+		key, value, _ := strings.Cut(pair, "=")
+		pairs[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return pairs
+}
@@ -3,4 +3,19 @@ package miniofs
 const (
 	TRANSACTION_ATTACHMENT_BUCKET = "refina-transaction-attachments"
 	TRANSACTION_ATTACHMENT_PREFIX = "transaction_attachments"
-)
\ No newline at end of file
+	ATTACHMENT_BLOB_PREFIX        = "blob"
+
+	// Reports are private, they are downloaded through presigned URLs
+	REPORT_BUCKET = "refina-reports"
+)
+
+// BUCKETS lists every bucket used by the application, used when migrating between storage backends
+var BUCKETS = []string{
+	TRANSACTION_ATTACHMENT_BUCKET,
+	REPORT_BUCKET,
+}
+
+// PUBLIC_BUCKETS are readable without a presigned URL, their object URLs are stored and served directly
+var PUBLIC_BUCKETS = []string{
+	TRANSACTION_ATTACHMENT_BUCKET,
+}
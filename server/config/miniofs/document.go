@@ -0,0 +1,77 @@
+package miniofs
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net/mail"
+	"strings"
+)
+
+const (
+	DOCUMENT_KIND_IMAGE = "image"
+	DOCUMENT_KIND_PDF   = "pdf"
+	DOCUMENT_KIND_EMAIL = "email"
+	DOCUMENT_KIND_FILE  = "file"
+
+	// Only the headers are parsed while sniffing e-mails, the body can be arbitrarily large
+	emailSniffLength = 16 * 1024
+)
+
+// DocumentKind maps a content type to the document kind shown to the client
+func DocumentKind(contentType string) string {
+	switch {
+	case strings.HasPrefix(contentType, "image/"):
+		return DOCUMENT_KIND_IMAGE
+	case contentType == "application/pdf":
+		return DOCUMENT_KIND_PDF
+	case contentType == "message/rfc822":
+		return DOCUMENT_KIND_EMAIL
+	}
+	return DOCUMENT_KIND_FILE
+}
+
+// SniffContentType detects the content type from the file content instead of trusting the client.
+// The declared type is only used to reject uploads that pretend to be something else.
+func SniffContentType(data []byte, declared string) (string, error) {
+	detected := getContentTypeFromData(data)
+	if detected == "application/octet-stream" {
+		return "", fmt.Errorf("unable to detect file type from content")
+	}
+
+	switch declared {
+	case "", "application/octet-stream", detected:
+		return detected, nil
+	}
+
+	// image/jpg is a common alias sent by browsers
+	if declared == "image/jpg" && detected == "image/jpeg" {
+		return detected, nil
+	}
+
+	return "", fmt.Errorf("declared content type '%s' does not match detected content type '%s'", declared, detected)
+}
+
+// isEmail reports whether the data looks like an RFC 822 message (.eml)
+func isEmail(data []byte) bool {
+	head := data[:min(len(data), emailSniffLength)]
+	// Binary content is never an e-mail
+	if bytes.IndexByte(head, 0) != -1 {
+		return false
+	}
+
+	// Headers end at the first empty line, a message without body is still a message
+	if !bytes.Contains(head, []byte("\r\n\r\n")) && !bytes.Contains(head, []byte("\n\n")) {
+		head = append(append([]byte{}, head...), '\n', '\n')
+	}
+
+	msg, err := mail.ReadMessage(bufio.NewReader(bytes.NewReader(head)))
+	if err != nil {
+		return false
+	}
+
+	if msg.Header.Get("From") == "" {
+		return false
+	}
+	return msg.Header.Get("Date") != "" || msg.Header.Get("Subject") != "" || msg.Header.Get("Message-Id") != ""
+}
@@ -0,0 +1,162 @@
+package miniofs
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testEmail = "From: receipts@tokopedia.com\r\nTo: user@example.com\r\nSubject: Your receipt\r\nDate: Mon, 19 Oct 2026 10:00:00 +0700\r\n\r\nThank you for your order."
+
+func testImage(t *testing.T, encode func(buffer *bytes.Buffer, img image.Image) error) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 4; y++ {
+			img.Set(x, y, color.RGBA{R: 200, A: 255})
+		}
+	}
+
+	var buffer bytes.Buffer
+	if err := encode(&buffer, img); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
+
+func testJPEG(t *testing.T) []byte {
+	return testImage(t, func(buffer *bytes.Buffer, img image.Image) error { return jpeg.Encode(buffer, img, nil) })
+}
+
+func testPNG(t *testing.T) []byte {
+	return testImage(t, func(buffer *bytes.Buffer, img image.Image) error { return png.Encode(buffer, img) })
+}
+
+// testPDF builds a one page PDF, the page shows the JPEG as image when one is given
+func testPDF(jpegData []byte) []byte {
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.4\n")
+	pdf.WriteString("1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n")
+	pdf.WriteString("2 0 obj\n<< /Type /Pages /Kids [3 0 R] /Count 1 >>\nendobj\n")
+	if jpegData == nil {
+		pdf.WriteString("3 0 obj\n<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 4 0 R >> >> >>\nendobj\n")
+		pdf.WriteString("4 0 obj\n<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>\nendobj\n")
+	} else {
+		pdf.WriteString("3 0 obj\n<< /Type /Page /Parent 2 0 R /Resources 4 0 R >>\nendobj\n")
+		pdf.WriteString("4 0 obj\n<< /XObject << /Im1 5 0 R >> >>\nendobj\n")
+		fmt.Fprintf(&pdf, "5 0 obj\n<< /Type /XObject /Subtype /Image /Filter /DCTDecode /Length %d >>\nstream\n", len(jpegData))
+		pdf.Write(jpegData)
+		pdf.WriteString("\nendstream\nendobj\n")
+	}
+	pdf.WriteString("trailer\n<< /Root 1 0 R >>\n%%EOF\n")
+	return pdf.Bytes()
+}
+
+func TestDocumentKind(t *testing.T) {
+	tests := []struct {
+		contentType string
+		kind        string
+	}{
+		{"image/jpeg", DOCUMENT_KIND_IMAGE},
+		{"image/webp", DOCUMENT_KIND_IMAGE},
+		{"application/pdf", DOCUMENT_KIND_PDF},
+		{"message/rfc822", DOCUMENT_KIND_EMAIL},
+		{"application/zip", DOCUMENT_KIND_FILE},
+		{"", DOCUMENT_KIND_FILE},
+	}
+
+	for _, test := range tests {
+		t.Run(test.contentType, func(t *testing.T) {
+			assert.Equal(t, test.kind, DocumentKind(test.contentType))
+		})
+	}
+}
+
+func TestSniffContentType(t *testing.T) {
+	jpegData := testJPEG(t)
+	webpData := []byte("RIFF\x24\x00\x00\x00WEBPVP8 ")
+
+	tests := []struct {
+		name        string
+		data        []byte
+		declared    string
+		contentType string
+		fails       bool
+	}{
+		{name: "JPEG Without Declared Type", data: jpegData, contentType: "image/jpeg"},
+		{name: "JPEG Declared As image/jpg", data: jpegData, declared: "image/jpg", contentType: "image/jpeg"},
+		{name: "PNG Declared As Octet Stream", data: testPNG(t), declared: "application/octet-stream", contentType: "image/png"},
+		{name: "WebP", data: webpData, declared: "image/webp", contentType: "image/webp"},
+		{name: "PDF", data: testPDF(nil), declared: "application/pdf", contentType: "application/pdf"},
+		{name: "E-mail Receipt", data: []byte(testEmail), declared: "message/rfc822", contentType: "message/rfc822"},
+		{name: "Other RIFF Container", data: []byte("RIFF\x24\x00\x00\x00WAVEfmt "), fails: true},
+		{name: "Plain Text", data: []byte("just some text"), fails: true},
+		{name: "Text Without Sender", data: []byte("Subject: Hello\r\n\r\nbody"), fails: true},
+		{name: "PDF Declared As Image", data: testPDF(nil), declared: "image/png", fails: true},
+		{name: "Empty", data: nil, fails: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			contentType, err := SniffContentType(test.data, test.declared)
+			if test.fails {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, test.contentType, contentType)
+		})
+	}
+}
+
+func TestValidateAttachment(t *testing.T) {
+	config := CreateAttachmentValidationConfig()
+	const megabyte = 1024 * 1024
+
+	tests := []struct {
+		name        string
+		size        int
+		contentType string
+		fails       bool
+	}{
+		{"Image", 1 * megabyte, "image/jpeg", false},
+		{"Image Over The Default Limit", 6 * megabyte, "image/png", true},
+		{"PDF Over The Default Limit", 8 * megabyte, "application/pdf", false},
+		{"PDF Over Its Limit", 11 * megabyte, "application/pdf", true},
+		{"E-mail Over Its Limit", 3 * megabyte, "message/rfc822", true},
+		{"E-mail", 10 * 1024, "message/rfc822", false},
+		{"Not Allowed", 1024, "application/zip", true},
+		{"Empty", 0, "image/jpeg", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateFile(make([]byte, test.size), test.contentType, config)
+			assert.Equal(t, test.fails, err != nil, err)
+		})
+	}
+}
+
+func TestExtractPDFPreview(t *testing.T) {
+	t.Run("Image Of The First Page", func(t *testing.T) {
+		jpegData := testJPEG(t)
+		preview, ok := ExtractPDFPreview(testPDF(jpegData))
+		assert.True(t, ok)
+		assert.Equal(t, jpegData, preview)
+	})
+
+	t.Run("Text Only", func(t *testing.T) {
+		_, ok := ExtractPDFPreview(testPDF(nil))
+		assert.False(t, ok)
+	})
+
+	t.Run("Not A PDF", func(t *testing.T) {
+		_, ok := ExtractPDFPreview([]byte("not a pdf"))
+		assert.False(t, ok)
+	})
+}
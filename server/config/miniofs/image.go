@@ -0,0 +1,263 @@
+package miniofs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	THUMBNAIL_SMALL  = "small"
+	THUMBNAIL_MEDIUM = "medium"
+
+	// Guard against decompression bombs, 40 megapixel is larger than any phone camera we expect
+	maxImagePixels = 40_000_000
+
+	sanitizedJPEGQuality = 90
+	thumbnailJPEGQuality = 80
+)
+
+// ThumbnailSpec describes a thumbnail variant generated next to the original object
+type ThumbnailSpec struct {
+	Name         string
+	Suffix       string
+	MaxDimension int
+}
+
+// DefaultThumbnailSpecs are generated for every processed image upload
+var DefaultThumbnailSpecs = []ThumbnailSpec{
+	{Name: THUMBNAIL_SMALL, Suffix: "thumb_sm", MaxDimension: 160},
+	{Name: THUMBNAIL_MEDIUM, Suffix: "thumb_md", MaxDimension: 640},
+}
+
+// ProcessedImage holds a metadata-free image and its thumbnails
+type ProcessedImage struct {
+	Data        []byte
+	ContentType string
+	Thumbnails  map[string][]byte // keyed by ThumbnailSpec.Name, always JPEG
+}
+
+// IsProcessableImage reports whether the content type can be re-encoded and thumbnailed
+func IsProcessableImage(contentType string) bool {
+	switch contentType {
+	case "image/jpeg", "image/jpg", "image/png", "image/webp":
+		return true
+	}
+	return false
+}
+
+// ProcessImage re-encodes the image without any metadata (EXIF, GPS, XMP, ...) and builds thumbnails.
+// EXIF orientation is applied to the pixels before the metadata is dropped so photos keep their rotation.
+// PNG stays PNG, everything else is re-encoded as JPEG.
+func ProcessImage(data []byte, contentType string, specs []ThumbnailSpec) (*ProcessedImage, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image config: %v", err)
+	}
+	if cfg.Width*cfg.Height > maxImagePixels {
+		return nil, fmt.Errorf("image dimension %dx%d exceeds maximum allowed pixels", cfg.Width, cfg.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %v", err)
+	}
+
+	if contentType == "image/jpeg" || contentType == "image/jpg" {
+		img = applyOrientation(img, readJPEGOrientation(data))
+	}
+
+	result := &ProcessedImage{
+		Thumbnails: make(map[string][]byte, len(specs)),
+	}
+
+	var buf bytes.Buffer
+	if contentType == "image/png" {
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, fmt.Errorf("failed to encode png: %v", err)
+		}
+		result.ContentType = "image/png"
+	} else {
+		if err := jpeg.Encode(&buf, flatten(img), &jpeg.Options{Quality: sanitizedJPEGQuality}); err != nil {
+			return nil, fmt.Errorf("failed to encode jpeg: %v", err)
+		}
+		result.ContentType = "image/jpeg"
+	}
+	result.Data = buf.Bytes()
+
+	for _, spec := range specs {
+		thumb, err := encodeThumbnail(img, spec.MaxDimension)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate %s thumbnail: %v", spec.Name, err)
+		}
+		result.Thumbnails[spec.Name] = thumb
+	}
+
+	return result, nil
+}
+
+// ThumbnailObjectName builds the object name of a thumbnail stored next to the original
+func ThumbnailObjectName(objectName string, spec ThumbnailSpec) string {
+	base := objectName
+	for i := len(objectName) - 1; i >= 0 && objectName[i] != '/'; i-- {
+		if objectName[i] == '.' {
+			base = objectName[:i]
+			break
+		}
+	}
+	return fmt.Sprintf("%s_%s.jpg", base, spec.Suffix)
+}
+
+func encodeThumbnail(img image.Image, maxDimension int) ([]byte, error) {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	// Never upscale small images
+	if width > maxDimension || height > maxDimension {
+		if width >= height {
+			height = max(1, height*maxDimension/width)
+			width = maxDimension
+		} else {
+			width = max(1, width*maxDimension/height)
+			height = maxDimension
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Over, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: thumbnailJPEGQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// flatten draws the image on a white background, JPEG has no alpha channel
+func flatten(img image.Image) image.Image {
+	if _, ok := img.(*image.YCbCr); ok {
+		return img
+	}
+	dst := image.NewRGBA(img.Bounds())
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), img, img.Bounds().Min, draw.Over)
+	return dst
+}
+
+// readJPEGOrientation returns the EXIF orientation tag (1-8), defaults to 1 when missing or unreadable
+func readJPEGOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return 1
+		}
+		marker := data[pos+1]
+		// Start of scan, no more metadata segments
+		if marker == 0xDA || marker == 0xD9 {
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2 : pos+4]))
+		if length < 2 || pos+2+length > len(data) {
+			return 1
+		}
+		segment := data[pos+4 : pos+2+length]
+		if marker == 0xE1 && len(segment) > 6 && string(segment[:6]) == "Exif\x00\x00" {
+			return parseTIFFOrientation(segment[6:])
+		}
+		pos += 2 + length
+	}
+
+	return 1
+}
+
+func parseTIFFOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifdOffset := int(order.Uint32(tiff[4:8]))
+	if ifdOffset+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifdOffset : ifdOffset+2]))
+	for i := 0; i < entries; i++ {
+		entry := ifdOffset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:entry+2]) == 0x0112 {
+			orientation := int(order.Uint16(tiff[entry+8 : entry+10]))
+			if orientation < 1 || orientation > 8 {
+				return 1
+			}
+			return orientation
+		}
+	}
+
+	return 1
+}
+
+// applyOrientation transforms the pixels according to the EXIF orientation tag
+func applyOrientation(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	// Orientation 5-8 swap width and height
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // flip horizontal
+				dx, dy = width-1-x, y
+			case 3: // rotate 180
+				dx, dy = width-1-x, height-1-y
+			case 4: // flip vertical
+				dx, dy = x, height-1-y
+			case 5: // transpose
+				dx, dy = y, x
+			case 6: // rotate 90 CW
+				dx, dy = height-1-y, x
+			case 7: // transverse
+				dx, dy = height-1-y, width-1-x
+			case 8: // rotate 90 CCW
+				dx, dy = y, width-1-x
+			}
+			dst.Set(dx, dy, img.At(bounds.Min.X+x, bounds.Min.Y+y))
+		}
+	}
+
+	return dst
+}
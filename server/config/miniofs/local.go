@@ -0,0 +1,265 @@
+package miniofs
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	helper "server/internal/utils"
+)
+
+// LOCAL_STORAGE_PATH is the API route serving objects of the local storage backend
+const LOCAL_STORAGE_PATH = "/v1/storage"
+
+// LocalStorage stores objects on the local disk as <root>/<bucket>/<object>, meant for local development and tests
+type LocalStorage struct {
+	root       string
+	baseURL    string
+	signingKey []byte
+}
+
+// NewLocalStorage creates the local storage backend, baseURL is the public URL of the API serving LOCAL_STORAGE_PATH
+func NewLocalStorage(path, baseURL, signingKey string) (*LocalStorage, error) {
+	if path == "" {
+		return nil, fmt.Errorf("local storage path cannot be empty")
+	}
+	if signingKey == "" {
+		return nil, fmt.Errorf("local storage signing key cannot be empty")
+	}
+
+	root, err := helper.ExpandPathAndCreateDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create local storage directory: %v", err)
+	}
+
+	return &LocalStorage{
+		root:       root,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		signingKey: []byte(signingKey),
+	}, nil
+}
+
+func (l *LocalStorage) Upload(ctx context.Context, request UploadRequest) (*UploadResponse, error) {
+	if err := l.EnsureBucket(ctx, request.BucketName); err != nil {
+		return nil, err
+	}
+
+	return uploadFile(ctx, l, request)
+}
+
+func (l *LocalStorage) Put(ctx context.Context, bucketName, objectName string, data []byte, contentType string) (*UploadResponse, error) {
+	path, err := l.FilePath(bucketName, objectName)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := helper.ExpandPathAndCreateDir(filepath.Dir(path)); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %v", err)
+	}
+
+	// Write to a temporary file first so readers never see a partially written object
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("failed to write file: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write file: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return nil, fmt.Errorf("failed to store file: %v", err)
+	}
+
+	sum := md5.Sum(data)
+	return &UploadResponse{
+		BucketName: bucketName,
+		ObjectName: objectName,
+		Size:       int64(len(data)),
+		URL:        l.ObjectURL(bucketName, objectName),
+		Ext:        getExtensionFromContentType(contentType),
+		Kind:       DocumentKind(contentType),
+		ETag:       hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+func (l *LocalStorage) Get(ctx context.Context, bucketName, objectName string) (io.ReadCloser, error) {
+	path, err := l.FilePath(bucketName, objectName)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(path)
+}
+
+func (l *LocalStorage) Delete(ctx context.Context, bucketName, objectName string) error {
+	path, err := l.FilePath(bucketName, objectName)
+	if err != nil {
+		return err
+	}
+
+	// Deleting a missing object is not an error, same as S3
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// PresignedURL signs the object URL with an expiry, verified by VerifySignature when the object is served
+func (l *LocalStorage) PresignedURL(ctx context.Context, bucketName, objectName string, expires time.Duration) (string, error) {
+	if _, err := l.FilePath(bucketName, objectName); err != nil {
+		return "", err
+	}
+
+	expiresAt := strconv.FormatInt(time.Now().Add(expires).Unix(), 10)
+	query := url.Values{}
+	query.Set("expires", expiresAt)
+	query.Set("signature", l.sign(bucketName, objectName, expiresAt))
+
+	return l.ObjectURL(bucketName, objectName) + "?" + query.Encode(), nil
+}
+
+// VerifySignature checks a presigned URL created by PresignedURL
+func (l *LocalStorage) VerifySignature(bucketName, objectName, expiresAt, signature string) error {
+	expires, err := strconv.ParseInt(expiresAt, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid expiry")
+	}
+	if time.Now().Unix() > expires {
+		return fmt.Errorf("presigned URL has expired")
+	}
+	if !hmac.Equal([]byte(signature), []byte(l.sign(bucketName, objectName, expiresAt))) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+func (l *LocalStorage) List(ctx context.Context, bucketName, prefix string) ([]ObjectInfo, error) {
+	bucketPath, err := l.bucketPath(bucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []ObjectInfo
+	err = filepath.WalkDir(bucketPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".upload-") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(bucketPath, path)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if !strings.HasPrefix(name, prefix) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, ObjectInfo{
+			Name:         name,
+			Size:         info.Size(),
+			LastModified: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to list objects: %v", err)
+	}
+
+	return objects, nil
+}
+
+func (l *LocalStorage) EnsureBucket(ctx context.Context, bucketName string) error {
+	bucketPath, err := l.bucketPath(bucketName)
+	if err != nil {
+		return err
+	}
+
+	if _, err := helper.ExpandPathAndCreateDir(bucketPath); err != nil {
+		return fmt.Errorf("failed to create bucket: %v", err)
+	}
+	return nil
+}
+
+func (l *LocalStorage) ObjectURL(bucketName, objectName string) string {
+	return fmt.Sprintf("%s%s/%s/%s", l.baseURL, LOCAL_STORAGE_PATH, bucketName, objectName)
+}
+
+func (l *LocalStorage) ParseURL(rawURL string) (bucketName, objectName string, err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", err
+	}
+
+	path, ok := strings.CutPrefix(u.Path, LOCAL_STORAGE_PATH+"/")
+	if !ok {
+		return "", "", fmt.Errorf("URL is not a local storage URL: %s", rawURL)
+	}
+
+	parts := strings.SplitN(path, "/", 2)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("URL is not valid: %s", rawURL)
+	}
+
+	return parts[0], parts[1], nil
+}
+
+// IsPublicBucket reports whether objects of the bucket are served without a presigned URL
+func (l *LocalStorage) IsPublicBucket(bucketName string) bool {
+	return slices.Contains(PUBLIC_BUCKETS, bucketName)
+}
+
+// FilePath resolves the object to a path inside the storage root, rejecting path traversal
+func (l *LocalStorage) FilePath(bucketName, objectName string) (string, error) {
+	bucketPath, err := l.bucketPath(bucketName)
+	if err != nil {
+		return "", err
+	}
+
+	if objectName == "" {
+		return "", fmt.Errorf("object name cannot be empty")
+	}
+
+	path := filepath.Join(bucketPath, filepath.FromSlash(objectName))
+	if rel, err := filepath.Rel(bucketPath, path); err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("invalid object name '%s'", objectName)
+	}
+
+	return path, nil
+}
+
+func (l *LocalStorage) bucketPath(bucketName string) (string, error) {
+	if bucketName == "" || bucketName == "." || bucketName == ".." || strings.ContainsAny(bucketName, `/\`) {
+		return "", fmt.Errorf("invalid bucket name '%s'", bucketName)
+	}
+	return filepath.Join(l.root, bucketName), nil
+}
+
+func (l *LocalStorage) sign(bucketName, objectName, expiresAt string) string {
+	mac := hmac.New(sha256.New, l.signingKey)
+	mac.Write([]byte(bucketName + "/" + objectName + "\n" + expiresAt))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,204 @@
+package miniofs
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLocalStorage(t *testing.T) *LocalStorage {
+	storage, err := NewLocalStorage(t.TempDir(), "http://localhost:8080/", "signing-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return storage
+}
+
+func TestLocalStorageObjects(t *testing.T) {
+	ctx := context.Background()
+	storage := newTestLocalStorage(t)
+
+	response, err := storage.Put(ctx, "attachments", "receipts/receipt.pdf", []byte("%PDF-1.4"), "application/pdf")
+	assert.Nil(t, err)
+	assert.Equal(t, "http://localhost:8080/v1/storage/attachments/receipts/receipt.pdf", response.URL)
+	assert.Equal(t, int64(8), response.Size)
+	assert.Equal(t, DOCUMENT_KIND_PDF, response.Kind)
+
+	reader, err := storage.Get(ctx, "attachments", "receipts/receipt.pdf")
+	assert.Nil(t, err)
+	data, _ := io.ReadAll(reader)
+	reader.Close()
+	assert.Equal(t, "%PDF-1.4", string(data))
+
+	_, err = storage.Put(ctx, "attachments", "other.txt", []byte("other"), "text/plain")
+	assert.Nil(t, err)
+
+	objects, err := storage.List(ctx, "attachments", "receipts/")
+	assert.Nil(t, err)
+	assert.Len(t, objects, 1)
+	assert.Equal(t, "receipts/receipt.pdf", objects[0].Name)
+
+	assert.Nil(t, storage.Delete(ctx, "attachments", "receipts/receipt.pdf"))
+	_, err = storage.Get(ctx, "attachments", "receipts/receipt.pdf")
+	assert.NotNil(t, err)
+
+	// Deleting a missing object is not an error, same as S3
+	assert.Nil(t, storage.Delete(ctx, "attachments", "receipts/receipt.pdf"))
+
+	// Listing a bucket that was never written to is empty
+	objects, err = storage.List(ctx, "reports", "")
+	assert.Nil(t, err)
+	assert.Empty(t, objects)
+}
+
+func TestLocalStorageUpload(t *testing.T) {
+	storage := newTestLocalStorage(t)
+
+	response, err := storage.Upload(context.Background(), UploadRequest{
+		Base64Data: "data:application/pdf;base64," + base64.StdEncoding.EncodeToString(testPDF(nil)),
+		Prefix:     "receipt",
+		BucketName: "attachments",
+		Validation: CreateAttachmentValidationConfig(),
+		ObjectKey:  "abc",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "receipt_abc.pdf", response.ObjectName)
+	assert.Equal(t, ".pdf", response.Ext)
+
+	_, err = storage.Upload(context.Background(), UploadRequest{
+		Base64Data: "data:image/png;base64," + base64.StdEncoding.EncodeToString(testPDF(nil)),
+		BucketName: "attachments",
+		Validation: CreateAttachmentValidationConfig(),
+	})
+	assert.NotNil(t, err)
+}
+
+func TestLocalStorageFilePath(t *testing.T) {
+	storage := newTestLocalStorage(t)
+
+	tests := []struct {
+		name   string
+		bucket string
+		object string
+		fails  bool
+	}{
+		{"Object", "attachments", "receipt.pdf", false},
+		{"Nested Object", "attachments", "2026/10/receipt.pdf", false},
+		{"Empty Object", "attachments", "", true},
+		{"Object Escaping The Bucket", "attachments", "../reports/report.pdf", true},
+		{"Object Escaping The Root", "attachments", "../../etc/passwd", true},
+		{"Bucket Escaping The Root", "..", "etc/passwd", true},
+		{"Bucket With Separator", "attachments/../reports", "report.pdf", true},
+		{"Empty Bucket", "", "receipt.pdf", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := storage.FilePath(test.bucket, test.object)
+			assert.Equal(t, test.fails, err != nil, err)
+		})
+	}
+}
+
+func TestLocalStoragePresignedURL(t *testing.T) {
+	ctx := context.Background()
+	storage := newTestLocalStorage(t)
+
+	presigned, err := storage.PresignedURL(ctx, "reports", "report.pdf", time.Hour)
+	assert.Nil(t, err)
+
+	u, err := url.Parse(presigned)
+	assert.Nil(t, err)
+	bucketName, objectName, err := storage.ParseURL(presigned)
+	assert.Nil(t, err)
+	assert.Equal(t, "reports", bucketName)
+	assert.Equal(t, "report.pdf", objectName)
+
+	expires, signature := u.Query().Get("expires"), u.Query().Get("signature")
+	assert.Nil(t, storage.VerifySignature("reports", "report.pdf", expires, signature))
+	assert.NotNil(t, storage.VerifySignature("reports", "other.pdf", expires, signature))
+	assert.NotNil(t, storage.VerifySignature("reports", "report.pdf", expires+"0", signature))
+	assert.NotNil(t, storage.VerifySignature("reports", "report.pdf", "not-a-number", signature))
+
+	// Another key never accepts the signature
+	other, err := NewLocalStorage(t.TempDir(), "http://localhost:8080", "other-key")
+	assert.Nil(t, err)
+	assert.NotNil(t, other.VerifySignature("reports", "report.pdf", expires, signature))
+
+	// Presigned URLs are never signed with an empty key
+	_, err = NewLocalStorage(t.TempDir(), "http://localhost:8080", "")
+	assert.NotNil(t, err)
+
+	expired, err := storage.PresignedURL(ctx, "reports", "report.pdf", -time.Minute)
+	assert.Nil(t, err)
+	u, _ = url.Parse(expired)
+	assert.NotNil(t, storage.VerifySignature("reports", "report.pdf", u.Query().Get("expires"), u.Query().Get("signature")))
+}
+
+func TestReprocessImage(t *testing.T) {
+	ctx := context.Background()
+	storage := newTestLocalStorage(t)
+
+	t.Run("PNG Keeps Its Name", func(t *testing.T) {
+		_, err := storage.Put(ctx, "attachments", "photo.png", testPNG(t), "image/png")
+		assert.Nil(t, err)
+
+		response, err := ReprocessImage(ctx, storage, "attachments", "photo.png")
+		assert.Nil(t, err)
+		assert.Equal(t, "photo.png", response.ObjectName)
+		assert.Len(t, response.Thumbnails, len(DefaultThumbnailSpecs))
+	})
+
+	t.Run("WebP Is Written As JPEG", func(t *testing.T) {
+		// * A 1x1 lossless WebP
+		webp := []byte("RIFF\x1a\x00\x00\x00WEBPVP8L\x0d\x00\x00\x00\x2f\x00\x00\x00\x10\x07\x10\x11\x11\x88\x88\xfe\x07\x00")
+		_, err := storage.Put(ctx, "attachments", "photo.webp", webp, "image/webp")
+		assert.Nil(t, err)
+
+		response, err := ReprocessImage(ctx, storage, "attachments", "photo.webp")
+		assert.Nil(t, err)
+		assert.Equal(t, "photo.jpg", response.ObjectName)
+		assert.Equal(t, ".jpg", response.Ext)
+
+		reader, err := storage.Get(ctx, "attachments", "photo.jpg")
+		assert.Nil(t, err)
+		data, _ := io.ReadAll(reader)
+		reader.Close()
+		assert.Equal(t, "image/jpeg", getContentTypeFromData(data))
+	})
+}
+
+func TestLocalStorageParseURL(t *testing.T) {
+	storage := newTestLocalStorage(t)
+
+	tests := []struct {
+		name   string
+		url    string
+		bucket string
+		object string
+		fails  bool
+	}{
+		{"Object URL", storage.ObjectURL("attachments", "a/b.jpg"), "attachments", "a/b.jpg", false},
+		{"Presigned URL", "http://localhost:8080/v1/storage/reports/r.pdf?expires=1&signature=x", "reports", "r.pdf", false},
+		{"MinIO URL", "http://minio:9000/attachments/b.jpg", "", "", true},
+		{"Bucket Only", "http://localhost:8080/v1/storage/attachments/", "", "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bucket, object, err := storage.ParseURL(test.url)
+			if test.fails {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, test.bucket, bucket)
+			assert.Equal(t, test.object, object)
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"io"
 	"net/url"
 	"server/config/env"
 	"server/config/log"
@@ -21,6 +22,11 @@ type FileValidationConfig struct {
 	AllowedExtensions []string
 	MaxFileSize       int64 // in bytes
 	MinFileSize       int64 // in bytes
+
+	// MaxFileSizeByExtension overrides MaxFileSize for specific extensions (e.g. ".pdf")
+	MaxFileSizeByExtension map[string]int64
+	// StrictContentType detects the content type from the data and rejects files whose declared type differs
+	StrictContentType bool
 }
 
 // UploadRequest represents file upload request
@@ -29,6 +35,13 @@ type UploadRequest struct {
 	Prefix     string // prefix for filename, will be combined with timestamp and extension
 	BucketName string
 	Validation *FileValidationConfig
+
+	// ObjectKey names the object <prefix>_<key><ext> instead of using a timestamp, it must be unique per object
+	ObjectKey string
+
+	// ProcessImage strips metadata from images and stores thumbnails next to the original,
+	// for PDFs the thumbnails are generated from a first page preview when one can be extracted
+	ProcessImage bool
 }
 
 // UploadResponse represents upload result
@@ -37,8 +50,10 @@ type UploadResponse struct {
 	ObjectName string
 	Size       int64
 	URL        string
-	Ext       string
+	Ext        string
+	Kind       string // document kind, see DocumentKind
 	ETag       string
+	Thumbnails map[string]string // thumbnail URL keyed by ThumbnailSpec.Name
 }
 
 type MinIOConfig struct {
@@ -166,7 +181,7 @@ func (m *MinIOManager) validateBucket(ctx context.Context, bucketName string) er
 }
 
 // DecodeFile decodes base64 string to byte array and extracts content type
-func (m *MinIOManager) DecodeFile(base64Data string) ([]byte, string, error) {
+func DecodeFile(base64Data string) ([]byte, string, error) {
 	if base64Data == "" {
 		return nil, "", fmt.Errorf("base64 data cannot be empty")
 	}
@@ -194,13 +209,18 @@ func (m *MinIOManager) DecodeFile(base64Data string) ([]byte, string, error) {
 	return decoded, contentType, nil
 }
 
-// Validate validates file based on provided rules
-func (m *MinIOManager) Validate(data []byte, contentType string, config *FileValidationConfig) error {
+// ValidateFile validates file based on provided rules
+func ValidateFile(data []byte, contentType string, config *FileValidationConfig) error {
 	fileSize := int64(len(data))
 
-	if config.MaxFileSize > 0 && fileSize > config.MaxFileSize {
+	maxFileSize := config.MaxFileSize
+	if limit, ok := config.MaxFileSizeByExtension[getExtensionFromContentType(contentType)]; ok {
+		maxFileSize = limit
+	}
+
+	if maxFileSize > 0 && fileSize > maxFileSize {
 		return fmt.Errorf("file size (%d bytes) exceeds maximum allowed size (%d bytes)",
-			fileSize, config.MaxFileSize)
+			fileSize, maxFileSize)
 	}
 	if config.MinFileSize > 0 && fileSize < config.MinFileSize {
 		return fmt.Errorf("file size (%d bytes) is below minimum required size (%d bytes)",
@@ -238,8 +258,8 @@ func (m *MinIOManager) Validate(data []byte, contentType string, config *FileVal
 	return nil
 }
 
-// UploadFile uploads file to MinIO - main method yang digunakan
-func (m *MinIOManager) UploadFile(ctx context.Context, request UploadRequest) (*UploadResponse, error) {
+// Upload uploads file to MinIO - main method yang digunakan
+func (m *MinIOManager) Upload(ctx context.Context, request UploadRequest) (*UploadResponse, error) {
 	if !m.IsReady() {
 		return nil, fmt.Errorf("MinIO client not ready")
 	}
@@ -249,64 +269,39 @@ func (m *MinIOManager) UploadFile(ctx context.Context, request UploadRequest) (*
 		return nil, err
 	}
 
-	// Decode and validate file
-	data, contentType, err := m.DecodeFile(request.Base64Data)
-	if err != nil {
-		return nil, fmt.Errorf("decode error: %v", err)
-	}
-
-	if request.Validation == nil {
-		request.Validation = CreateDefaultValidationConfig()
-	}
-
-	if err := m.Validate(data, contentType, request.Validation); err != nil {
-		return nil, fmt.Errorf("validation error: %v", err)
-	}
+	return uploadFile(ctx, m, request)
+}
 
-	// Generate object name
-	ext := getExtensionFromContentType(contentType)
-	if ext == "" {
-		ext = ".bin"
+// Put stores raw data under the given object name, overwriting any existing object
+func (m *MinIOManager) Put(ctx context.Context, bucketName, objectName string, data []byte, contentType string) (*UploadResponse, error) {
+	if !m.IsReady() {
+		return nil, fmt.Errorf("MinIO client not ready")
 	}
 
-	prefix := request.Prefix
-	if prefix == "" {
-		prefix = "file"
+	if err := m.validateBucket(ctx, bucketName); err != nil {
+		return nil, err
 	}
 
-	timestamp := time.Now().Unix()
-	objectName := fmt.Sprintf("%s_%d%s", prefix, timestamp, ext)
-
-	// Upload file
-	reader := bytes.NewReader(data)
-	options := minio.PutObjectOptions{
+	info, err := m.client.PutObject(ctx, bucketName, objectName, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{
 		ContentType: contentType,
-	}
-
-	info, err := m.client.PutObject(ctx, request.BucketName, objectName, reader, int64(len(data)), options)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("upload failed: %v", err)
+		return nil, err
 	}
 
-	// Generate URL
-	url := fmt.Sprintf("%s://%s/%s/%s",
-		getProtocol(m.config.UseSSL),
-		m.config.Host,
-		request.BucketName,
-		objectName)
-		
 	return &UploadResponse{
-		BucketName: request.BucketName,
+		BucketName: bucketName,
 		ObjectName: objectName,
 		Size:       info.Size,
-		URL:        url,
-		Ext:        ext,
+		URL:        m.ObjectURL(bucketName, objectName),
+		Ext:        getExtensionFromContentType(contentType),
+		Kind:       DocumentKind(contentType),
 		ETag:       info.ETag,
 	}, nil
 }
 
-// GetFile retrieves file from MinIO
-func (m *MinIOManager) GetFile(ctx context.Context, bucketName, objectName string) (*minio.Object, error) {
+// Get retrieves file from MinIO
+func (m *MinIOManager) Get(ctx context.Context, bucketName, objectName string) (io.ReadCloser, error) {
 	if !m.IsReady() {
 		return nil, fmt.Errorf("MinIO client not ready")
 	}
@@ -318,8 +313,8 @@ func (m *MinIOManager) GetFile(ctx context.Context, bucketName, objectName strin
 	return m.client.GetObject(ctx, bucketName, objectName, minio.GetObjectOptions{})
 }
 
-// DeleteFile deletes file from MinIO
-func (m *MinIOManager) DeleteFile(ctx context.Context, bucketName, objectName string) error {
+// Delete deletes file from MinIO
+func (m *MinIOManager) Delete(ctx context.Context, bucketName, objectName string) error {
 	if !m.IsReady() {
 		return fmt.Errorf("MinIO client not ready")
 	}
@@ -331,8 +326,8 @@ func (m *MinIOManager) DeleteFile(ctx context.Context, bucketName, objectName st
 	return m.client.RemoveObject(ctx, bucketName, objectName, minio.RemoveObjectOptions{})
 }
 
-// GetPresignedURL generates presigned URL for direct access
-func (m *MinIOManager) GetPresignedURL(ctx context.Context, bucketName, objectName string, expires time.Duration) (string, error) {
+// PresignedURL generates presigned URL for direct access
+func (m *MinIOManager) PresignedURL(ctx context.Context, bucketName, objectName string, expires time.Duration) (string, error) {
 	if !m.IsReady() {
 		return "", fmt.Errorf("MinIO client not ready")
 	}
@@ -349,8 +344,8 @@ func (m *MinIOManager) GetPresignedURL(ctx context.Context, bucketName, objectNa
 	return url.String(), nil
 }
 
-// ListObjects lists objects in bucket with prefix
-func (m *MinIOManager) ListObjects(ctx context.Context, bucketName, prefix string) ([]minio.ObjectInfo, error) {
+// List lists objects in bucket with prefix
+func (m *MinIOManager) List(ctx context.Context, bucketName, prefix string) ([]ObjectInfo, error) {
 	if !m.IsReady() {
 		return nil, fmt.Errorf("MinIO client not ready")
 	}
@@ -359,7 +354,7 @@ func (m *MinIOManager) ListObjects(ctx context.Context, bucketName, prefix strin
 		return nil, err
 	}
 
-	var objects []minio.ObjectInfo
+	var objects []ObjectInfo
 	objectCh := m.client.ListObjects(ctx, bucketName, minio.ListObjectsOptions{
 		Prefix:    prefix,
 		Recursive: true,
@@ -369,12 +364,52 @@ func (m *MinIOManager) ListObjects(ctx context.Context, bucketName, prefix strin
 		if object.Err != nil {
 			return nil, object.Err
 		}
-		objects = append(objects, object)
+		objects = append(objects, ObjectInfo{
+			Name:         object.Key,
+			Size:         object.Size,
+			ContentType:  object.ContentType,
+			LastModified: object.LastModified,
+		})
 	}
 
 	return objects, nil
 }
 
+// EnsureBucket creates the bucket when it does not exist yet
+func (m *MinIOManager) EnsureBucket(ctx context.Context, bucketName string) error {
+	if !m.IsReady() {
+		return fmt.Errorf("MinIO client not ready")
+	}
+
+	exists, err := m.client.BucketExists(ctx, bucketName)
+	if err != nil {
+		return fmt.Errorf("failed to check bucket existence: %v", err)
+	}
+	if !exists {
+		if err := m.client.MakeBucket(ctx, bucketName, minio.MakeBucketOptions{}); err != nil {
+			return fmt.Errorf("failed to create bucket: %v", err)
+		}
+	}
+
+	m.mu.Lock()
+	m.bucketCache[bucketName] = true
+	m.mu.Unlock()
+
+	return nil
+}
+
+func (m *MinIOManager) ObjectURL(bucketName, objectName string) string {
+	return fmt.Sprintf("%s://%s/%s/%s",
+		getProtocol(m.config.UseSSL),
+		m.config.Host,
+		bucketName,
+		objectName)
+}
+
+func (m *MinIOManager) ParseURL(rawURL string) (bucketName, objectName string, err error) {
+	return ParseMinioURL(rawURL)
+}
+
 // Helper functions - tetap sama
 func getContentTypeFromData(data []byte) string {
 	if len(data) == 0 {
@@ -387,7 +422,6 @@ func getContentTypeFromData(data []byte) string {
 		"GIF87a":            "image/gif",
 		"GIF89a":            "image/gif",
 		"\x00\x00\x01\x00":  "image/x-icon",
-		"RIFF":              "image/webp",
 		"%PDF":              "application/pdf",
 		"PK\x03\x04":        "application/zip",
 		"PK\x05\x06":        "application/zip",
@@ -400,6 +434,15 @@ func getContentTypeFromData(data []byte) string {
 			return contentType
 		}
 	}
+
+	// RIFF is a container, only RIFF....WEBP is an image
+	if len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WEBP" {
+		return "image/webp"
+	}
+
+	if isEmail(data) {
+		return "message/rfc822"
+	}
 	return "application/octet-stream"
 }
 
@@ -413,6 +456,7 @@ func getExtensionFromContentType(contentType string) string {
 		"image/x-icon":             ".ico",
 		"image/vnd.microsoft.icon": ".ico",
 		"application/pdf":          ".pdf",
+		"message/rfc822":           ".eml",
 		"application/zip":          ".zip",
 		"application/json":         ".json",
 		"text/plain":               ".txt",
@@ -470,6 +514,20 @@ func CreateImageValidationConfig() *FileValidationConfig {
 	}
 }
 
+// CreateAttachmentValidationConfig accepts receipts as images, PDF invoices and e-mail receipts (.eml)
+func CreateAttachmentValidationConfig() *FileValidationConfig {
+	return &FileValidationConfig{
+		AllowedExtensions: []string{".jpg", ".jpeg", ".png", ".gif", ".webp", ".pdf", ".eml"},
+		MaxFileSize:       5 * 1024 * 1024, // 5MB
+		MinFileSize:       1,               // 1 byte
+		MaxFileSizeByExtension: map[string]int64{
+			".pdf": 10 * 1024 * 1024, // 10MB
+			".eml": 2 * 1024 * 1024,  // 2MB
+		},
+		StrictContentType: true,
+	}
+}
+
 // ParseMinioURL menerima URL MinIO/S3 dan mengembalikan bucket + objectKey
 func ParseMinioURL(rawURL string) (bucket, objectKey string, err error) {
 	u, err := url.Parse(rawURL)
@@ -497,4 +555,4 @@ func ExtractObjectNameFromURL(url string) string {
 		return parts[len(parts)-1]
 	}
 	return ""
-}
\ No newline at end of file
+}
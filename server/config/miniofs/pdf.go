@@ -0,0 +1,163 @@
+package miniofs
+
+import (
+	"bytes"
+	"image/jpeg"
+	"regexp"
+	"strconv"
+)
+
+// Rendering PDF content streams needs a full PDF engine, which is not available in pure Go.
+// Receipts are usually scans or contain a photo/logo on the first page, so the preview is taken from
+// the first JPEG (DCTDecode) image referenced by the first page, falling back to the first JPEG in the file.
+// Text-only PDFs have no preview, the client shows a document icon based on the kind instead.
+
+var (
+	pdfObjectPattern    = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+	pdfPagePattern      = regexp.MustCompile(`/Type\s*/Page\b`)
+	pdfResourcesPattern = regexp.MustCompile(`/Resources\s*(\d+)\s+\d+\s+R`)
+	pdfXObjectPattern   = regexp.MustCompile(`/XObject\s*(?:(\d+)\s+\d+\s+R|<<([^>]*)>>)`)
+	pdfReferencePattern = regexp.MustCompile(`/[^\s/<>\[\]()]+\s*(\d+)\s+\d+\s+R`)
+	pdfImagePattern     = regexp.MustCompile(`/Subtype\s*/Image\b`)
+	pdfDCTPattern       = regexp.MustCompile(`/Filter\s*(?:\[\s*)?/DCTDecode\s*\]?`)
+	pdfLengthPattern    = regexp.MustCompile(`/Length\s+(\d+)(\s+\d+\s+R)?`)
+)
+
+type pdfDocument struct {
+	data    []byte
+	objects map[int]int // object number -> offset of the object body
+	order   []int
+}
+
+// ExtractPDFPreview returns a JPEG image usable as preview of the first page, ok is false when none is found
+func ExtractPDFPreview(data []byte) ([]byte, bool) {
+	doc := parsePDF(data)
+	if len(doc.objects) == 0 {
+		return nil, false
+	}
+
+	if preview, ok := doc.firstPageImage(); ok {
+		return preview, true
+	}
+
+	for _, num := range doc.order {
+		if preview, ok := doc.jpegStream(num); ok {
+			return preview, true
+		}
+	}
+
+	return nil, false
+}
+
+func parsePDF(data []byte) *pdfDocument {
+	doc := &pdfDocument{data: data, objects: make(map[int]int)}
+	for _, match := range pdfObjectPattern.FindAllSubmatchIndex(data, -1) {
+		num, err := strconv.Atoi(string(data[match[2]:match[3]]))
+		if err != nil {
+			continue
+		}
+		// Incremental updates append newer versions of an object, the last one wins
+		if _, exists := doc.objects[num]; !exists {
+			doc.order = append(doc.order, num)
+		}
+		doc.objects[num] = match[1]
+	}
+	return doc
+}
+
+// dictionary returns the object content up to its stream or endobj keyword
+func (doc *pdfDocument) dictionary(num int) []byte {
+	offset, ok := doc.objects[num]
+	if !ok {
+		return nil
+	}
+
+	body := doc.data[offset:]
+	end := len(body)
+	if i := bytes.Index(body, []byte("endobj")); i != -1 {
+		end = i
+	}
+	if i := bytes.Index(body[:end], []byte("stream")); i != -1 {
+		end = i
+	}
+	return body[:end]
+}
+
+func (doc *pdfDocument) firstPageImage() ([]byte, bool) {
+	for _, num := range doc.order {
+		dict := doc.dictionary(num)
+		if !pdfPagePattern.Match(dict) {
+			continue
+		}
+
+		resources := dict
+		if match := pdfResourcesPattern.FindSubmatch(dict); match != nil {
+			resources = doc.dictionary(atoi(match[1]))
+		}
+
+		match := pdfXObjectPattern.FindSubmatch(resources)
+		if match == nil {
+			return nil, false
+		}
+		xobjects := match[2]
+		if len(match[1]) > 0 {
+			xobjects = doc.dictionary(atoi(match[1]))
+		}
+
+		for _, ref := range pdfReferencePattern.FindAllSubmatch(xobjects, -1) {
+			if preview, ok := doc.jpegStream(atoi(ref[1])); ok {
+				return preview, true
+			}
+		}
+		return nil, false
+	}
+
+	return nil, false
+}
+
+// jpegStream returns the raw stream of an image object when it is a plain DCTDecode (JPEG) image
+func (doc *pdfDocument) jpegStream(num int) ([]byte, bool) {
+	dict := doc.dictionary(num)
+	if !pdfImagePattern.Match(dict) || !pdfDCTPattern.Match(dict) {
+		return nil, false
+	}
+
+	offset := doc.objects[num] + len(dict)
+	body := doc.data[offset:]
+	if !bytes.HasPrefix(body, []byte("stream")) {
+		return nil, false
+	}
+	body = body[len("stream"):]
+	body = bytes.TrimPrefix(body, []byte("\r"))
+	body = bytes.TrimPrefix(body, []byte("\n"))
+
+	length := -1
+	if match := pdfLengthPattern.FindSubmatch(dict); match != nil {
+		if len(match[2]) == 0 {
+			length = atoi(match[1])
+		} else if value := bytes.TrimSpace(doc.dictionary(atoi(match[1]))); len(value) > 0 {
+			length = atoi(value)
+		}
+	}
+	if length <= 0 || length > len(body) {
+		length = bytes.Index(body, []byte("endstream"))
+		if length == -1 {
+			return nil, false
+		}
+	}
+
+	stream := body[:length]
+	if _, err := jpeg.DecodeConfig(bytes.NewReader(stream)); err != nil {
+		return nil, false
+	}
+
+	return stream, true
+}
+
+func atoi(b []byte) int {
+	n, err := strconv.Atoi(string(bytes.TrimSpace(b)))
+	if err != nil {
+		return -1
+	}
+	return n
+}
@@ -0,0 +1,266 @@
+package miniofs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"server/config/env"
+	"server/config/log"
+)
+
+const (
+	STORAGE_DRIVER_MINIO = "minio"
+	STORAGE_DRIVER_LOCAL = "local"
+)
+
+// Storage is implemented by every object storage backend, services only depend on this interface
+type Storage interface {
+	// Upload decodes, validates and stores a base64 upload under a generated object name
+	Upload(ctx context.Context, request UploadRequest) (*UploadResponse, error)
+	// Put stores raw data under the given object name, overwriting any existing object
+	Put(ctx context.Context, bucketName, objectName string, data []byte, contentType string) (*UploadResponse, error)
+	Get(ctx context.Context, bucketName, objectName string) (io.ReadCloser, error)
+	Delete(ctx context.Context, bucketName, objectName string) error
+	PresignedURL(ctx context.Context, bucketName, objectName string, expires time.Duration) (string, error)
+	List(ctx context.Context, bucketName, prefix string) ([]ObjectInfo, error)
+	EnsureBucket(ctx context.Context, bucketName string) error
+
+	// ObjectURL and ParseURL convert between stored references and bucket/object names
+	ObjectURL(bucketName, objectName string) string
+	ParseURL(rawURL string) (bucketName, objectName string, err error)
+}
+
+// ObjectInfo describes a stored object independent of the backend
+type ObjectInfo struct {
+	Name         string
+	Size         int64
+	ContentType  string
+	LastModified time.Time
+}
+
+// FileStorage is the storage backend selected by configuration
+var FileStorage Storage
+
+// SetupStorage initializes the global storage backend - dipanggil sekali di main.go
+func SetupStorage(cfg env.Config) {
+	storage, err := NewStorage(cfg.Storage.Driver, cfg)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to initialize storage: %v", err))
+	}
+
+	FileStorage = storage
+	log.Info(fmt.Sprintf("File storage initialized with %s driver", cfg.Storage.Driver))
+}
+
+// NewStorage creates the storage backend for the given driver, used directly when two backends are needed (migration)
+func NewStorage(driver string, cfg env.Config) (Storage, error) {
+	switch driver {
+	case STORAGE_DRIVER_LOCAL:
+		return NewLocalStorage(cfg.Storage.LocalPath, cfg.Storage.LocalBaseURL, cfg.Storage.SigningKey)
+	case STORAGE_DRIVER_MINIO, "":
+		SetupMinio(cfg.Minio)
+		return MinioClient, nil
+	}
+
+	return nil, fmt.Errorf("unknown storage driver '%s'", driver)
+}
+
+// uploadFile is the upload pipeline shared by all backends
+func uploadFile(ctx context.Context, storage Storage, request UploadRequest) (*UploadResponse, error) {
+	// Decode and validate file
+	data, contentType, err := DecodeFile(request.Base64Data)
+	if err != nil {
+		return nil, fmt.Errorf("decode error: %v", err)
+	}
+
+	if request.Validation == nil {
+		request.Validation = CreateDefaultValidationConfig()
+	}
+
+	if request.Validation.StrictContentType {
+		if contentType, err = SniffContentType(data, contentType); err != nil {
+			return nil, fmt.Errorf("validation error: %v", err)
+		}
+	}
+
+	if err := ValidateFile(data, contentType, request.Validation); err != nil {
+		return nil, fmt.Errorf("validation error: %v", err)
+	}
+
+	// Re-encode image without metadata, this may change the content type (e.g. webp -> jpeg)
+	var thumbnails map[string][]byte
+	if request.ProcessImage {
+		switch {
+		case IsProcessableImage(contentType):
+			processed, err := ProcessImage(data, contentType, DefaultThumbnailSpecs)
+			if err != nil {
+				return nil, fmt.Errorf("image processing error: %v", err)
+			}
+			data = processed.Data
+			contentType = processed.ContentType
+			thumbnails = processed.Thumbnails
+		case contentType == "application/pdf":
+			thumbnails = pdfThumbnails(data)
+		}
+	}
+
+	// Generate object name
+	ext := getExtensionFromContentType(contentType)
+	if ext == "" {
+		ext = ".bin"
+	}
+
+	prefix := request.Prefix
+	if prefix == "" {
+		prefix = "file"
+	}
+
+	objectName := fmt.Sprintf("%s_%d%s", prefix, time.Now().Unix(), ext)
+	if request.ObjectKey != "" {
+		objectName = fmt.Sprintf("%s_%s%s", prefix, request.ObjectKey, ext)
+	}
+
+	// Upload file
+	response, err := storage.Put(ctx, request.BucketName, objectName, data, contentType)
+	if err != nil {
+		return nil, fmt.Errorf("upload failed: %v", err)
+	}
+	response.Ext = ext
+
+	if len(thumbnails) > 0 {
+		if response.Thumbnails, err = putThumbnails(ctx, storage, request.BucketName, objectName, thumbnails); err != nil {
+			return nil, err
+		}
+	}
+
+	return response, nil
+}
+
+// ContentHash returns the hex encoded SHA-256 of the decoded file content
+func ContentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// ReprocessImage strips metadata from an already stored image, overwrites it and (re)creates its thumbnails.
+// An image re-encoded to another format (webp -> jpeg) is written next to the original under the extension of
+// the new format, the original is left for the caller to delete once nothing refers to it.
+func ReprocessImage(ctx context.Context, storage Storage, bucketName, objectName string) (*UploadResponse, error) {
+	object, err := storage.Get(ctx, bucketName, objectName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object: %v", err)
+	}
+	defer object.Close()
+
+	data, err := io.ReadAll(object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object: %v", err)
+	}
+
+	contentType := getContentTypeFromData(data)
+	if !IsProcessableImage(contentType) {
+		return nil, fmt.Errorf("object %s is not a processable image (%s)", objectName, contentType)
+	}
+
+	processed, err := ProcessImage(data, contentType, DefaultThumbnailSpecs)
+	if err != nil {
+		return nil, fmt.Errorf("image processing error: %v", err)
+	}
+
+	// Keep the original object name so existing references stay valid, unless its extension no longer matches
+	ext := getExtensionFromContentType(processed.ContentType)
+	if path.Ext(objectName) != ext {
+		objectName = strings.TrimSuffix(objectName, path.Ext(objectName)) + ext
+	}
+
+	response, err := storage.Put(ctx, bucketName, objectName, processed.Data, processed.ContentType)
+	if err != nil {
+		return nil, fmt.Errorf("upload failed: %v", err)
+	}
+	response.Ext = ext
+
+	if response.Thumbnails, err = putThumbnails(ctx, storage, bucketName, objectName, processed.Thumbnails); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// CopyObjects copies every object of a bucket from one backend to another, keeping the object names
+func CopyObjects(ctx context.Context, from, to Storage, bucketName string) (int, error) {
+	objects, err := from.List(ctx, bucketName, "")
+	if err != nil {
+		return 0, fmt.Errorf("failed to list objects: %v", err)
+	}
+
+	if err := to.EnsureBucket(ctx, bucketName); err != nil {
+		return 0, err
+	}
+
+	copied := 0
+	for _, object := range objects {
+		reader, err := from.Get(ctx, bucketName, object.Name)
+		if err != nil {
+			return copied, fmt.Errorf("failed to get object %s: %v", object.Name, err)
+		}
+
+		data, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			return copied, fmt.Errorf("failed to read object %s: %v", object.Name, err)
+		}
+
+		contentType := object.ContentType
+		if contentType == "" {
+			contentType = getContentTypeFromData(data)
+		}
+
+		if _, err := to.Put(ctx, bucketName, object.Name, data, contentType); err != nil {
+			return copied, fmt.Errorf("failed to put object %s: %v", object.Name, err)
+		}
+		copied++
+	}
+
+	return copied, nil
+}
+
+// putThumbnails uploads generated thumbnails next to the original object
+func putThumbnails(ctx context.Context, storage Storage, bucketName, objectName string, images map[string][]byte) (map[string]string, error) {
+	thumbnails := make(map[string]string, len(images))
+	for _, spec := range DefaultThumbnailSpecs {
+		data, ok := images[spec.Name]
+		if !ok {
+			continue
+		}
+
+		res, err := storage.Put(ctx, bucketName, ThumbnailObjectName(objectName, spec), data, "image/jpeg")
+		if err != nil {
+			return nil, fmt.Errorf("thumbnail upload failed: %v", err)
+		}
+		thumbnails[spec.Name] = res.URL
+	}
+
+	return thumbnails, nil
+}
+
+// pdfThumbnails builds thumbnails from the first page preview, a PDF without usable preview has no thumbnails
+func pdfThumbnails(data []byte) map[string][]byte {
+	preview, ok := ExtractPDFPreview(data)
+	if !ok {
+		return nil
+	}
+
+	processed, err := ProcessImage(preview, "image/jpeg", DefaultThumbnailSpecs)
+	if err != nil {
+		log.Warn(fmt.Sprintf("failed to generate pdf preview: %v", err))
+		return nil
+	}
+
+	return processed.Thumbnails
+}
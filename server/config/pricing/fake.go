@@ -0,0 +1,50 @@
+package pricing
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// FakeProvider serves prices from memory, used in tests and local development
+type FakeProvider struct {
+	mu     sync.RWMutex
+	prices map[string]float64
+	Err    error // returned by FetchQuotes when set
+}
+
+func NewFakeProvider(prices map[string]float64) *FakeProvider {
+	provider := &FakeProvider{prices: make(map[string]float64, len(prices))}
+	for instrument, price := range prices {
+		provider.prices[instrument] = price
+	}
+	return provider
+}
+
+func (provider *FakeProvider) Name() string {
+	return PROVIDER_FAKE
+}
+
+// SetPrice sets the price returned for the instrument on any date
+func (provider *FakeProvider) SetPrice(instrument string, price float64) {
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+	provider.prices[instrument] = price
+}
+
+func (provider *FakeProvider) FetchQuotes(ctx context.Context, instruments []string, date time.Time) ([]Quote, error) {
+	if provider.Err != nil {
+		return nil, provider.Err
+	}
+
+	provider.mu.RLock()
+	defer provider.mu.RUnlock()
+
+	var quotes []Quote
+	for _, instrument := range instruments {
+		if price, ok := provider.prices[instrument]; ok {
+			quotes = append(quotes, Quote{Instrument: instrument, Date: date, Price: price})
+		}
+	}
+	return quotes, nil
+}
@@ -0,0 +1,58 @@
+package pricing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"server/config/env"
+	"server/config/log"
+)
+
+const (
+	PROVIDER_NONE = ""
+	PROVIDER_FAKE = "fake"
+)
+
+// Quote is the closing price of an instrument on a date
+type Quote struct {
+	Instrument string
+	Date       time.Time
+	Price      float64
+}
+
+// Provider pushes market prices into investment_prices, implementations wrap an external price source
+type Provider interface {
+	// Name is stored as the source of the fetched prices
+	Name() string
+	// FetchQuotes returns the quotes of the given instruments for the date, unknown instruments are skipped
+	FetchQuotes(ctx context.Context, instruments []string, date time.Time) ([]Quote, error)
+}
+
+// PriceProvider is the provider selected by configuration, nil when price sync is disabled
+var PriceProvider Provider
+
+// SetupProvider initializes the global price provider - dipanggil sekali di main.go
+func SetupProvider(cfg env.Config) {
+	provider, err := NewProvider(cfg.Pricing.Provider)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to initialize price provider: %v", err))
+	}
+
+	PriceProvider = provider
+	if provider != nil {
+		log.Info(fmt.Sprintf("Price provider initialized with %s driver", provider.Name()))
+	}
+}
+
+// NewProvider creates the provider for the given name, PROVIDER_NONE disables price sync
+func NewProvider(name string) (Provider, error) {
+	switch name {
+	case PROVIDER_NONE:
+		return nil, nil
+	case PROVIDER_FAKE:
+		return NewFakeProvider(nil), nil
+	}
+
+	return nil, fmt.Errorf("unknown price provider '%s'", name)
+}
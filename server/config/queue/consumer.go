@@ -0,0 +1,234 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"server/config/log"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	// RETRY_COUNT_HEADER counts how many times a message has been retried
+	RETRY_COUNT_HEADER = "x-retry-count"
+	// ERROR_HEADER keeps the error of the last failed attempt
+	ERROR_HEADER = "x-error"
+
+	DEFAULT_MAX_RETRIES = 3
+	DEFAULT_RETRY_DELAY = 5 * time.Second
+	DEFAULT_PREFETCH    = 1
+
+	// Waits between reconnection attempts of a consumer, doubled up to the maximum
+	reconnectDelay    = time.Second
+	maxReconnectDelay = 30 * time.Second
+)
+
+// Handler processes a single delivery, a returned error retries the message unless it is Permanent
+type Handler func(ctx context.Context, delivery amqp091.Delivery) error
+
+// DeadLetterHandler is called after a message was moved to the dead-letter queue
+type DeadLetterHandler func(ctx context.Context, delivery amqp091.Delivery, err error)
+
+// Consumer consumes a queue of the Topology with manual acks. Failed messages are retried through the delay
+// queues with an exponential backoff and rejected to the dead-letter queue after the last retry.
+type Consumer struct {
+	Queue string
+	Tag   string
+
+	Handler      Handler
+	OnDeadLetter DeadLetterHandler
+
+	// Prefetch overrides the prefetch of the queue spec when set
+	Prefetch int
+
+	spec QueueSpec
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks an error that retrying cannot fix, the message goes to the dead-letter queue right away
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err}
+}
+
+// RetryQueueName is the delay queue holding messages before their given retry attempt
+func RetryQueueName(queue string, attempt int) string {
+	return fmt.Sprintf("%s.retry.%d", queue, attempt)
+}
+
+// DeadLetterQueueName is the queue collecting messages that ran out of retries
+func DeadLetterQueueName(queue string) string {
+	return queue + ".dlq"
+}
+
+// RetryDelay is the backoff before the given retry attempt, starting at base and doubling every attempt
+func RetryDelay(base time.Duration, attempt int) time.Duration {
+	return base << (attempt - 1)
+}
+
+// Run consumes until the context is cancelled, the channel is opened again whenever it or the connection is lost
+func (c *Consumer) Run(ctx context.Context) error {
+	spec, err := GetQueueSpec(c.Queue)
+	if err != nil {
+		return err
+	}
+	c.spec = spec
+	if c.Prefetch > 0 {
+		c.spec.Prefetch = c.Prefetch
+	}
+
+	delay := reconnectDelay
+	for {
+		err := c.consume(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err == nil {
+			// The broker cancelled the consumer, start over without backing off
+			delay = reconnectDelay
+		}
+		log.Warn(fmt.Sprintf("consumer %s stopped: %v, reconnecting in %s", c.Tag, err, delay))
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+		if err != nil {
+			delay = min(delay*2, maxReconnectDelay)
+		}
+	}
+}
+
+// consume handles deliveries until the channel closes, it returns nil when the broker cancelled the consumer
+func (c *Consumer) consume(ctx context.Context) error {
+	channel, err := GetChannel()
+	if err != nil {
+		return err
+	}
+	defer channel.Close()
+
+	if err := channel.Qos(c.spec.Prefetch, 0, false); err != nil {
+		return fmt.Errorf("failed to set prefetch: %w", err)
+	}
+
+	deliveries, err := channel.ConsumeWithContext(ctx, c.Queue, c.Tag, false, false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("failed to consume %s: %w", c.Queue, err)
+	}
+	closed := channel.NotifyClose(make(chan *amqp091.Error, 1))
+
+	log.Info(fmt.Sprintf("consumer %s started on %s", c.Tag, c.Queue))
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case amqpErr := <-closed:
+			if amqpErr != nil {
+				return amqpErr
+			}
+			return errors.New("channel closed")
+		case delivery, ok := <-deliveries:
+			if !ok {
+				return nil
+			}
+			c.handle(ctx, delivery)
+		}
+	}
+}
+
+func (c *Consumer) handle(ctx context.Context, delivery amqp091.Delivery) {
+	handlerErr := c.runHandler(ctx, delivery)
+	if handlerErr == nil {
+		if err := delivery.Ack(false); err != nil {
+			log.Error(fmt.Sprintf("failed to ack message of %s: %v", c.Queue, err))
+		}
+		return
+	}
+
+	retries := RetryCount(delivery)
+	var permanent *permanentError
+	if !errors.As(handlerErr, &permanent) && retries < c.spec.MaxRetries {
+		log.Warn(fmt.Sprintf("message of %s failed, retry %d of %d: %v", c.Queue, retries+1, c.spec.MaxRetries, handlerErr))
+		c.retry(ctx, delivery, retries+1, handlerErr)
+		return
+	}
+
+	// The queue dead-letters rejected messages through its dead-letter exchange
+	log.Error(fmt.Sprintf("message of %s moved to dead-letter queue after %d retries: %v", c.Queue, retries, handlerErr))
+	if err := delivery.Nack(false, false); err != nil {
+		log.Error(fmt.Sprintf("failed to reject message of %s: %v", c.Queue, err))
+		return
+	}
+	if c.OnDeadLetter != nil {
+		c.OnDeadLetter(ctx, delivery, handlerErr)
+	}
+}
+
+// runHandler calls the handler, a panic fails the message instead of stopping the consumer
+func (c *Consumer) runHandler(ctx context.Context, delivery amqp091.Delivery) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panicked: %v", r)
+		}
+	}()
+
+	return c.Handler(ctx, delivery)
+}
+
+// retry copies the message to the delay queue of the attempt and acks it once the broker confirmed the copy,
+// the message is requeued when the copy fails
+func (c *Consumer) retry(ctx context.Context, delivery amqp091.Delivery, attempt int, handlerErr error) {
+	target := RetryQueueName(c.Queue, attempt)
+
+	headers := amqp091.Table{}
+	for key, value := range delivery.Headers {
+		headers[key] = value
+	}
+	headers[RETRY_COUNT_HEADER] = int32(attempt)
+	headers[ERROR_HEADER] = handlerErr.Error()
+
+	// Publish confirms on a channel of its own, an unconfirmed copy could be lost while the ack removes the original
+	err := Publish(ctx, "", target, amqp091.Publishing{
+		Headers:     headers,
+		ContentType: delivery.ContentType,
+		MessageId:   delivery.MessageId,
+		Timestamp:   delivery.Timestamp,
+		Body:        delivery.Body,
+	})
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to move message of %s to %s: %v", c.Queue, target, err))
+		if err := delivery.Nack(false, true); err != nil {
+			log.Error(fmt.Sprintf("failed to nack message of %s: %v", c.Queue, err))
+		}
+		return
+	}
+
+	if err := delivery.Ack(false); err != nil {
+		log.Error(fmt.Sprintf("failed to ack message of %s: %v", c.Queue, err))
+	}
+}
+
+// RetryCount is how many times the delivered message has been retried, 0 on its first attempt
+func RetryCount(delivery amqp091.Delivery) int {
+	switch count := delivery.Headers[RETRY_COUNT_HEADER].(type) {
+	case int32:
+		return int(count)
+	case int64:
+		return int(count)
+	case int:
+		return count
+	}
+	return 0
+}
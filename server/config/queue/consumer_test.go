@@ -0,0 +1,45 @@
+package queue
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryDelay(t *testing.T) {
+	t.Run("Doubles Every Attempt", func(t *testing.T) {
+		assert.Equal(t, 5*time.Second, RetryDelay(5*time.Second, 1))
+		assert.Equal(t, 10*time.Second, RetryDelay(5*time.Second, 2))
+		assert.Equal(t, 20*time.Second, RetryDelay(5*time.Second, 3))
+	})
+}
+
+func TestRetryCount(t *testing.T) {
+	t.Run("Missing Header", func(t *testing.T) {
+		assert.Equal(t, 0, RetryCount(amqp091.Delivery{}))
+	})
+
+	t.Run("Header Types", func(t *testing.T) {
+		assert.Equal(t, 2, RetryCount(amqp091.Delivery{Headers: amqp091.Table{RETRY_COUNT_HEADER: int32(2)}}))
+		assert.Equal(t, 3, RetryCount(amqp091.Delivery{Headers: amqp091.Table{RETRY_COUNT_HEADER: int64(3)}}))
+	})
+}
+
+func TestPermanent(t *testing.T) {
+	t.Run("Wraps Error", func(t *testing.T) {
+		cause := errors.New("malformed message")
+		err := Permanent(cause)
+
+		var permanent *permanentError
+		assert.True(t, errors.As(err, &permanent))
+		assert.True(t, errors.Is(err, cause))
+		assert.Equal(t, cause.Error(), err.Error())
+	})
+
+	t.Run("Nil Error", func(t *testing.T) {
+		assert.Nil(t, Permanent(nil))
+	})
+}
@@ -0,0 +1,46 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// Publish sends a persistent message and waits for the broker to confirm it, a message to a mandatory
+// exchange that no queue accepted is returned as an error instead of being dropped
+func Publish(ctx context.Context, exchange, routingKey string, message amqp091.Publishing) error {
+	channel, err := GetChannel()
+	if err != nil {
+		return err
+	}
+	defer channel.Close()
+
+	if err := channel.Confirm(false); err != nil {
+		return fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+	returns := channel.NotifyReturn(make(chan amqp091.Return, 1))
+
+	message.DeliveryMode = amqp091.Persistent
+	confirmation, err := channel.PublishWithDeferredConfirmWithContext(ctx, exchange, routingKey, IsMandatory(exchange), false, message)
+	if err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", exchange, err)
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to confirm message to %s: %w", exchange, err)
+	}
+	if !acked {
+		return fmt.Errorf("message to %s was rejected by the broker", exchange)
+	}
+
+	// The broker returns unroutable messages before confirming them
+	select {
+	case returned := <-returns:
+		return fmt.Errorf("message to %s was not routed: %s", exchange, returned.ReplyText)
+	default:
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package queue
 
 import (
 	"fmt"
+	"sync"
 
 	"server/config/env"
 	"server/config/log"
@@ -9,12 +10,16 @@ import (
 	"github.com/rabbitmq/amqp091-go"
 )
 
-var connection *amqp091.Connection
+var (
+	connection       *amqp091.Connection
+	connectionString string
+	connectionMu     sync.Mutex
+)
 
 func SetupRabbitMQ(cfg env.RabbitMQ) {
-	connectionString := fmt.Sprintf("amqp://%s:%s@%s:%s/%s", cfg.RMQUser, cfg.RMQPassword, cfg.RMQHost, cfg.RMQPort, cfg.RMQVirtualHost)
+	connectionString = fmt.Sprintf("amqp://%s:%s@%s:%s/%s", cfg.RMQUser, cfg.RMQPassword, cfg.RMQHost, cfg.RMQPort, cfg.RMQVirtualHost)
 
-	conn, err := amqp091.Dial(connectionString)
+	conn, err := dial()
 	if err != nil {
 		log.Log.Fatalf("Failed to connect to RabbitMQ: %v", err)
 	}
@@ -22,23 +27,71 @@ func SetupRabbitMQ(cfg env.RabbitMQ) {
 	connection = conn
 }
 
+// dial connects to RabbitMQ and declares the topology, so a fresh broker works for both publishers and consumers
+func dial() (*amqp091.Connection, error) {
+	conn, err := amqp091.Dial(connectionString)
+	if err != nil {
+		return nil, err
+	}
+
+	channel, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to open a channel: %w", err)
+	}
+	defer channel.Close()
+
+	if err := DeclareTopology(channel); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return conn, nil
+}
+
 func Close() {
-	if connection != nil {
+	connectionMu.Lock()
+	defer connectionMu.Unlock()
+
+	if connection != nil && !connection.IsClosed() {
 		if err := connection.Close(); err != nil {
 			log.Error("Failed to close RabbitMQ connection: " + err.Error())
 		}
 	}
 }
 
+// GetChannel opens a channel on the shared connection, the connection is dialed again when it was lost
 func GetChannel() (*amqp091.Channel, error) {
-	if connection == nil {
-		return nil, fmt.Errorf("RabbitMQ connection is not initialized")
+	conn, err := getConnection()
+	if err != nil {
+		return nil, err
 	}
 
-	channel, err := connection.Channel()
+	channel, err := conn.Channel()
 	if err != nil {
 		return nil, fmt.Errorf("failed to open a channel: %w", err)
 	}
 
 	return channel, nil
 }
+
+func getConnection() (*amqp091.Connection, error) {
+	connectionMu.Lock()
+	defer connectionMu.Unlock()
+
+	if connectionString == "" {
+		return nil, fmt.Errorf("RabbitMQ connection is not initialized")
+	}
+
+	if connection == nil || connection.IsClosed() {
+		log.Warn("RabbitMQ connection lost, reconnecting")
+		conn, err := dial()
+		if err != nil {
+			return nil, fmt.Errorf("failed to reconnect to RabbitMQ: %w", err)
+		}
+		connection = conn
+		log.Info("RabbitMQ connection restored")
+	}
+
+	return connection, nil
+}
@@ -0,0 +1,197 @@
+package queue
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	USER_REPORT_EXCHANGE    = "user_report"
+	USER_REPORT_QUEUE       = "user_report"
+	USER_REPORT_ROUTING_KEY = "user_report"
+
+	SCHEDULED_JOB_EXCHANGE    = "scheduled_job"
+	SCHEDULED_JOB_QUEUE       = "scheduled_job"
+	SCHEDULED_JOB_ROUTING_KEY = "scheduled_job"
+
+	EMAIL_EXCHANGE    = "email"
+	EMAIL_QUEUE       = "email"
+	EMAIL_ROUTING_KEY = "email"
+
+	USER_SUMMARIES_QUEUE = "user_summaries"
+
+	// EVENTS_EXCHANGE is the topic exchange of the domain events, routed by event type
+	EVENTS_EXCHANGE = "domain_events"
+)
+
+// ExchangeSpec describes an exchange queues are bound to, messages to a Mandatory exchange must reach a queue
+type ExchangeSpec struct {
+	Name      string
+	Kind      string
+	Mandatory bool
+}
+
+// QueueSpec describes a work queue with everything declared around it: its bindings, its delay queues
+// for retries and its dead-letter exchange and queue. Without BindingKeys the queue is bound by RoutingKey.
+type QueueSpec struct {
+	Name        string
+	Exchange    string
+	RoutingKey  string
+	BindingKeys []string
+
+	MaxRetries int
+	RetryDelay time.Duration
+	Prefetch   int
+}
+
+// Exchanges are declared before the queues of the Topology
+var Exchanges = []ExchangeSpec{
+	{Name: USER_REPORT_EXCHANGE, Kind: amqp091.ExchangeDirect, Mandatory: true},
+	{Name: SCHEDULED_JOB_EXCHANGE, Kind: amqp091.ExchangeDirect, Mandatory: true},
+	{Name: EMAIL_EXCHANGE, Kind: amqp091.ExchangeDirect, Mandatory: true},
+	// Events nobody subscribed to are dropped
+	{Name: EVENTS_EXCHANGE, Kind: amqp091.ExchangeTopic},
+}
+
+// Topology is declared on every connection, consumers and publishers only refer to it by name
+var Topology = []QueueSpec{
+	{
+		Name:       USER_REPORT_QUEUE,
+		Exchange:   USER_REPORT_EXCHANGE,
+		RoutingKey: USER_REPORT_ROUTING_KEY,
+		MaxRetries: DEFAULT_MAX_RETRIES,
+		RetryDelay: DEFAULT_RETRY_DELAY,
+		Prefetch:   DEFAULT_PREFETCH,
+	},
+	{
+		// Jobs triggered by an admin, runs of the schedule start in the worker itself
+		Name:       SCHEDULED_JOB_QUEUE,
+		Exchange:   SCHEDULED_JOB_EXCHANGE,
+		RoutingKey: SCHEDULED_JOB_ROUTING_KEY,
+		MaxRetries: DEFAULT_MAX_RETRIES,
+		RetryDelay: DEFAULT_RETRY_DELAY,
+		Prefetch:   DEFAULT_PREFETCH,
+	},
+	{
+		// Emails rendered by the API and the worker, sent by the email job
+		Name:       EMAIL_QUEUE,
+		Exchange:   EMAIL_EXCHANGE,
+		RoutingKey: EMAIL_ROUTING_KEY,
+		MaxRetries: DEFAULT_MAX_RETRIES,
+		RetryDelay: DEFAULT_RETRY_DELAY,
+		Prefetch:   DEFAULT_PREFETCH,
+	},
+	// Keeps the dashboard summaries of a user up to date with their transactions
+	EventQueue(USER_SUMMARIES_QUEUE, "transaction.*.v1"),
+}
+
+// EventQueue is the spec of a queue subscribed to the domain events matching the binding keys, e.g. "transaction.*.v1"
+func EventQueue(name string, bindingKeys ...string) QueueSpec {
+	return QueueSpec{
+		Name:        name,
+		Exchange:    EVENTS_EXCHANGE,
+		RoutingKey:  name,
+		BindingKeys: bindingKeys,
+		MaxRetries:  DEFAULT_MAX_RETRIES,
+		RetryDelay:  DEFAULT_RETRY_DELAY,
+		Prefetch:    DEFAULT_PREFETCH,
+	}
+}
+
+// IsMandatory reports whether messages to the exchange must be routed to a queue
+func IsMandatory(exchange string) bool {
+	for _, spec := range Exchanges {
+		if spec.Name == exchange {
+			return spec.Mandatory
+		}
+	}
+	return true
+}
+
+// DeadLetterExchangeName is the exchange rejected messages of the exchange are routed to
+func DeadLetterExchangeName(exchange string) string {
+	return exchange + ".dlx"
+}
+
+// GetQueueSpec returns the topology entry of the queue
+func GetQueueSpec(name string) (QueueSpec, error) {
+	for _, spec := range Topology {
+		if spec.Name == name {
+			return spec, nil
+		}
+	}
+	return QueueSpec{}, fmt.Errorf("queue %s is not part of the topology", name)
+}
+
+// DeclareTopology declares all exchanges, queues and bindings, declaring is idempotent as long as the arguments
+// match. A queue created earlier with other arguments has to be deleted once before it can be declared.
+func DeclareTopology(channel *amqp091.Channel) error {
+	for _, exchange := range Exchanges {
+		if err := declareExchange(channel, exchange.Name, exchange.Kind); err != nil {
+			return err
+		}
+		if err := declareExchange(channel, DeadLetterExchangeName(exchange.Name), amqp091.ExchangeDirect); err != nil {
+			return err
+		}
+	}
+
+	for _, spec := range Topology {
+		if err := declareQueueSpec(channel, spec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func declareQueueSpec(channel *amqp091.Channel, spec QueueSpec) error {
+	dlx := DeadLetterExchangeName(spec.Exchange)
+	dlq := DeadLetterQueueName(spec.Name)
+
+	// Rejected messages go to the dead-letter queue through the dead-letter exchange
+	if _, err := channel.QueueDeclare(spec.Name, true, false, false, false, amqp091.Table{
+		"x-dead-letter-exchange":    dlx,
+		"x-dead-letter-routing-key": spec.RoutingKey,
+	}); err != nil {
+		return fmt.Errorf("failed to declare queue %s: %w", spec.Name, err)
+	}
+	bindingKeys := spec.BindingKeys
+	if len(bindingKeys) == 0 {
+		bindingKeys = []string{spec.RoutingKey}
+	}
+	for _, bindingKey := range bindingKeys {
+		if err := channel.QueueBind(spec.Name, bindingKey, spec.Exchange, false, nil); err != nil {
+			return fmt.Errorf("failed to bind queue %s: %w", spec.Name, err)
+		}
+	}
+
+	if _, err := channel.QueueDeclare(dlq, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare queue %s: %w", dlq, err)
+	}
+	if err := channel.QueueBind(dlq, spec.RoutingKey, dlx, false, nil); err != nil {
+		return fmt.Errorf("failed to bind queue %s: %w", dlq, err)
+	}
+
+	// Expired messages of the delay queues return straight to the work queue through the default exchange,
+	// so a retry never reaches the other queues bound to the same exchange
+	for attempt := 1; attempt <= spec.MaxRetries; attempt++ {
+		retryQueue := RetryQueueName(spec.Name, attempt)
+		if _, err := channel.QueueDeclare(retryQueue, true, false, false, false, amqp091.Table{
+			"x-message-ttl":             RetryDelay(spec.RetryDelay, attempt).Milliseconds(),
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": spec.Name,
+		}); err != nil {
+			return fmt.Errorf("failed to declare queue %s: %w", retryQueue, err)
+		}
+	}
+
+	return nil
+}
+
+func declareExchange(channel *amqp091.Channel, name, kind string) error {
+	if err := channel.ExchangeDeclare(name, kind, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare exchange %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"net/http"
+
+	"server/internal/service"
+	"server/internal/types/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+type analyticsHandler struct {
+	analyticsService service.AnalyticsService
+}
+
+func NewAnalyticsHandler(analyticsService service.AnalyticsService) *analyticsHandler {
+	return &analyticsHandler{analyticsService}
+}
+
+func (analytics_handler *analyticsHandler) GetCashflow(c *gin.Context) {
+	ctx := c.Request.Context()
+	token := c.GetHeader("Authorization")
+
+	var query dto.CashflowQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	cashflow, err := analytics_handler.analyticsService.GetCashflow(ctx, token, query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get cash flow",
+		"data":       cashflow,
+	})
+}
+
+func (analytics_handler *analyticsHandler) GetBalances(c *gin.Context) {
+	ctx := c.Request.Context()
+	token := c.GetHeader("Authorization")
+
+	var query dto.BalancesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	balances, err := analytics_handler.analyticsService.GetBalances(ctx, token, query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get wallet balances",
+		"data":       balances,
+	})
+}
+
+func (analytics_handler *analyticsHandler) GetForecast(c *gin.Context) {
+	ctx := c.Request.Context()
+	token := c.GetHeader("Authorization")
+
+	var query dto.ForecastQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	forecast, err := analytics_handler.analyticsService.GetForecast(ctx, token, query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get balance forecast",
+		"data":       forecast,
+	})
+}
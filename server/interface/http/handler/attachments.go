@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"net/http"
+
+	"server/internal/service"
+	"server/internal/types/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AttachmentHandler serves the attachments of any resource, the owner type is bound when registering the route
+type AttachmentHandler struct {
+	attachmentServ service.AttachmentsService
+}
+
+func NewAttachmentHandler(attachmentServ service.AttachmentsService) *AttachmentHandler {
+	return &AttachmentHandler{attachmentServ}
+}
+
+func (attachmentHandler *AttachmentHandler) GetAttachments(ownerType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		token := c.GetHeader("Authorization")
+		ID := c.Param("id")
+
+		attachments, err := attachmentHandler.attachmentServ.GetOwnerAttachments(ctx, token, ownerType, ID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":     false,
+				"statusCode": 400,
+				"message":    err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"status":     true,
+			"statusCode": 200,
+			"message":    "Get attachments data",
+			"data":       attachments,
+		})
+	}
+}
+
+func (attachmentHandler *AttachmentHandler) UploadAttachments(ownerType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.GetHeader("Authorization")
+		ID := c.Param("id")
+		var payload dto.Attachments
+		if err := c.Bind(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":     false,
+				"statusCode": 400,
+				"message":    err.Error(),
+			})
+			return
+		}
+
+		ctx := c.Request.Context()
+
+		attachments, err := attachmentHandler.attachmentServ.UploadOwnerAttachments(ctx, token, ownerType, ID, payload.Files)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"status":     false,
+				"statusCode": 500,
+				"message":    err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"status":     true,
+			"statusCode": 200,
+			"message":    "Upload attachment success",
+			"data":       attachments,
+		})
+	}
+}
+
+func (attachmentHandler *AttachmentHandler) DeleteAttachment(ownerType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		token := c.GetHeader("Authorization")
+		ID := c.Param("id")
+		attachmentID := c.Param("attachmentId")
+
+		attachment, err := attachmentHandler.attachmentServ.DeleteOwnerAttachment(ctx, token, ownerType, ID, attachmentID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":     false,
+				"statusCode": 400,
+				"message":    err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"status":     true,
+			"statusCode": 200,
+			"message":    "Delete attachment success",
+			"data":       attachment,
+		})
+	}
+}
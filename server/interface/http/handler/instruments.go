@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"net/http"
+
+	"server/internal/service"
+	"server/internal/types/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+type instrumentHandler struct {
+	instrumentService service.InstrumentsService
+}
+
+func NewInstrumentHandler(instrumentService service.InstrumentsService) *instrumentHandler {
+	return &instrumentHandler{instrumentService}
+}
+
+func (instrument_handler *instrumentHandler) GetInstruments(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var query dto.InstrumentsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	instruments, err := instrument_handler.instrumentService.GetInstruments(ctx, query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"statusCode": 500,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get Instruments",
+		"data":       instruments,
+	})
+}
+
+func (instrument_handler *instrumentHandler) GetInstrumentByID(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	instrument, err := instrument_handler.instrumentService.GetInstrumentByID(ctx, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"statusCode": 404,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get Instrument",
+		"data":       instrument,
+	})
+}
+
+func (instrument_handler *instrumentHandler) CreateInstrument(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var instrumentRequest dto.InstrumentsRequest
+	if err := c.ShouldBindJSON(&instrumentRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	instrument, err := instrument_handler.instrumentService.CreateInstrument(ctx, instrumentRequest)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"statusCode": 201,
+		"status":     true,
+		"message":    "Create Instrument",
+		"data":       instrument,
+	})
+}
+
+func (instrument_handler *instrumentHandler) UpdateInstrument(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	var instrumentRequest dto.InstrumentsRequest
+	if err := c.ShouldBindJSON(&instrumentRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	instrument, err := instrument_handler.instrumentService.UpdateInstrument(ctx, id, instrumentRequest)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Update Instrument",
+		"data":       instrument,
+	})
+}
+
+func (instrument_handler *instrumentHandler) DeleteInstrument(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	instrument, err := instrument_handler.instrumentService.DeleteInstrument(ctx, id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Delete Instrument",
+		"data":       instrument,
+	})
+}
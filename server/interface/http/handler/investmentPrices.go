@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"server/internal/service"
+	"server/internal/types/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+type investmentPriceHandler struct {
+	investmentPriceService service.InvestmentPricesService
+}
+
+func NewInvestmentPriceHandler(investmentPriceService service.InvestmentPricesService) *investmentPriceHandler {
+	return &investmentPriceHandler{investmentPriceService}
+}
+
+func (price_handler *investmentPriceHandler) GetPrices(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var query dto.InvestmentPricesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	prices, err := price_handler.investmentPriceService.GetPrices(ctx, query.Instrument, query.From, query.To)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get Investment Prices",
+		"data":       prices,
+	})
+}
+
+func (price_handler *investmentPriceHandler) CreatePrices(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var pricesRequest []dto.InvestmentPricesRequest
+	if err := c.ShouldBindJSON(&pricesRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	prices, err := price_handler.investmentPriceService.CreatePrices(ctx, pricesRequest)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Create Investment Prices",
+		"data":       prices,
+	})
+}
+
+// ImportPrices accepts the CSV as multipart "file" field or as raw text/csv body
+func (price_handler *investmentPriceHandler) ImportPrices(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	body := c.Request.Body
+	if file, err := c.FormFile("file"); err == nil {
+		opened, err := file.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"statusCode": 400,
+				"status":     false,
+				"message":    err.Error(),
+			})
+			return
+		}
+		defer opened.Close()
+		body = opened
+	}
+
+	imported, err := price_handler.investmentPriceService.ImportPricesCSV(ctx, body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Import Investment Prices",
+		"data":       dto.InvestmentPricesImportResponse{Imported: imported},
+	})
+}
+
+func (price_handler *investmentPriceHandler) SyncPrices(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	synced, err := price_handler.investmentPriceService.SyncPrices(ctx, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Sync Investment Prices",
+		"data":       dto.InvestmentPricesImportResponse{Imported: synced},
+	})
+}
+
+func (price_handler *investmentPriceHandler) DeletePrice(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id := c.Param("id")
+
+	price, err := price_handler.investmentPriceService.DeletePrice(ctx, id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Delete Investment Price",
+		"data":       price,
+	})
+}
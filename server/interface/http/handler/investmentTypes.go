@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"net/http"
+
+	"server/internal/service"
+	"server/internal/types/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+type investmentTypeHandler struct {
+	investmentTypeService service.InvestmentTypesService
+}
+
+func NewInvestmentTypeHandler(investmentTypeService service.InvestmentTypesService) *investmentTypeHandler {
+	return &investmentTypeHandler{investmentTypeService}
+}
+
+func (investment_type_handler *investmentTypeHandler) GetAllInvestmentTypes(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	investmentTypes, err := investment_type_handler.investmentTypeService.GetAllInvestmentTypes(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"statusCode": 500,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get All Investment Types",
+		"data":       investmentTypes,
+	})
+}
+
+func (investment_type_handler *investmentTypeHandler) GetInvestmentTypeByID(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	investmentType, err := investment_type_handler.investmentTypeService.GetInvestmentTypeByID(ctx, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"statusCode": 404,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get Investment Type",
+		"data":       investmentType,
+	})
+}
+
+func (investment_type_handler *investmentTypeHandler) CreateInvestmentType(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var investmentTypeRequest dto.InvestmentTypesRequest
+	if err := c.ShouldBindJSON(&investmentTypeRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	investmentType, err := investment_type_handler.investmentTypeService.CreateInvestmentType(ctx, investmentTypeRequest)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"statusCode": 201,
+		"status":     true,
+		"message":    "Create Investment Type",
+		"data":       investmentType,
+	})
+}
+
+func (investment_type_handler *investmentTypeHandler) UpdateInvestmentType(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	var investmentTypeRequest dto.InvestmentTypesRequest
+	if err := c.ShouldBindJSON(&investmentTypeRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	investmentType, err := investment_type_handler.investmentTypeService.UpdateInvestmentType(ctx, id, investmentTypeRequest)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Update Investment Type",
+		"data":       investmentType,
+	})
+}
+
+func (investment_type_handler *investmentTypeHandler) DeleteInvestmentType(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	investmentType, err := investment_type_handler.investmentTypeService.DeleteInvestmentType(ctx, id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Delete Investment Type",
+		"data":       investmentType,
+	})
+}
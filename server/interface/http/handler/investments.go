@@ -169,3 +169,345 @@ func (investment_handler *investmentHandler) DeleteInvestment(c *gin.Context) {
 		"data":       investment,
 	})
 }
+
+func (investment_handler *investmentHandler) GetInvestmentTrades(c *gin.Context) {
+	ctx := c.Request.Context()
+	token := c.GetHeader("Authorization")
+
+	id := c.Param("id")
+
+	trades, err := investment_handler.investmentService.GetInvestmentTrades(ctx, token, id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get Investment Trades",
+		"data":       trades,
+	})
+}
+
+func (investment_handler *investmentHandler) CreateInvestmentTrade(c *gin.Context) {
+	ctx := c.Request.Context()
+	token := c.GetHeader("Authorization")
+
+	id := c.Param("id")
+
+	var tradeRequest dto.InvestmentTradesRequest
+	if err := c.ShouldBindJSON(&tradeRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	trade, err := investment_handler.investmentService.CreateInvestmentTrade(ctx, token, id, tradeRequest)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Create Investment Trade",
+		"data":       trade,
+	})
+}
+
+func (investment_handler *investmentHandler) DeleteInvestmentTrade(c *gin.Context) {
+	ctx := c.Request.Context()
+	token := c.GetHeader("Authorization")
+
+	id := c.Param("id")
+	tradeID := c.Param("tradeId")
+
+	trade, err := investment_handler.investmentService.DeleteInvestmentTrade(ctx, token, id, tradeID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Delete Investment Trade",
+		"data":       trade,
+	})
+}
+
+func (investment_handler *investmentHandler) GetInvestmentPosition(c *gin.Context) {
+	ctx := c.Request.Context()
+	token := c.GetHeader("Authorization")
+
+	id := c.Param("id")
+	method := c.Query("method")
+
+	position, err := investment_handler.investmentService.GetInvestmentPosition(ctx, token, id, method)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get Investment Position",
+		"data":       position,
+	})
+}
+
+func (investment_handler *investmentHandler) GetPortfolioValuation(c *gin.Context) {
+	ctx := c.Request.Context()
+	token := c.GetHeader("Authorization")
+
+	valuation, err := investment_handler.investmentService.GetPortfolioValuation(ctx, token)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get Portfolio Valuation",
+		"data":       valuation,
+	})
+}
+
+func (investment_handler *investmentHandler) GetPortfolioHistory(c *gin.Context) {
+	ctx := c.Request.Context()
+	token := c.GetHeader("Authorization")
+
+	var query dto.PortfolioHistoryQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	history, err := investment_handler.investmentService.GetPortfolioHistory(ctx, token, query.From, query.To, query.Interval)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get Portfolio History",
+		"data":       history,
+	})
+}
+
+func (investment_handler *investmentHandler) GetInvestmentIncomes(c *gin.Context) {
+	ctx := c.Request.Context()
+	token := c.GetHeader("Authorization")
+
+	id := c.Param("id")
+
+	incomes, err := investment_handler.investmentService.GetInvestmentIncomes(ctx, token, id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get Investment Incomes",
+		"data":       incomes,
+	})
+}
+
+func (investment_handler *investmentHandler) CreateInvestmentIncome(c *gin.Context) {
+	ctx := c.Request.Context()
+	token := c.GetHeader("Authorization")
+
+	id := c.Param("id")
+
+	var incomeRequest dto.InvestmentIncomesRequest
+	if err := c.ShouldBindJSON(&incomeRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	income, err := investment_handler.investmentService.CreateInvestmentIncome(ctx, token, id, incomeRequest)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Create Investment Income",
+		"data":       income,
+	})
+}
+
+func (investment_handler *investmentHandler) DeleteInvestmentIncome(c *gin.Context) {
+	ctx := c.Request.Context()
+	token := c.GetHeader("Authorization")
+
+	id := c.Param("id")
+	incomeID := c.Param("incomeId")
+
+	income, err := investment_handler.investmentService.DeleteInvestmentIncome(ctx, token, id, incomeID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Delete Investment Income",
+		"data":       income,
+	})
+}
+
+func (investment_handler *investmentHandler) GetInvestmentYield(c *gin.Context) {
+	ctx := c.Request.Context()
+	token := c.GetHeader("Authorization")
+
+	id := c.Param("id")
+
+	yield, err := investment_handler.investmentService.GetInvestmentYield(ctx, token, id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get Investment Yield",
+		"data":       yield,
+	})
+}
+
+func (investment_handler *investmentHandler) GetCouponSchedule(c *gin.Context) {
+	ctx := c.Request.Context()
+	token := c.GetHeader("Authorization")
+
+	id := c.Param("id")
+
+	schedule, err := investment_handler.investmentService.GetCouponSchedule(ctx, token, id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get Coupon Schedule",
+		"data":       schedule,
+	})
+}
+
+func (investment_handler *investmentHandler) GetInvestmentPerformance(c *gin.Context) {
+	ctx := c.Request.Context()
+	token := c.GetHeader("Authorization")
+
+	id := c.Param("id")
+	period := c.Query("period")
+
+	performance, err := investment_handler.investmentService.GetInvestmentPerformance(ctx, token, id, period)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get Investment Performance",
+		"data":       performance,
+	})
+}
+
+func (investment_handler *investmentHandler) GetPortfolioPerformance(c *gin.Context) {
+	ctx := c.Request.Context()
+	token := c.GetHeader("Authorization")
+
+	period := c.Query("period")
+
+	performance, err := investment_handler.investmentService.GetPortfolioPerformance(ctx, token, period)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get Portfolio Performance",
+		"data":       performance,
+	})
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"net/http"
+
+	"server/internal/service"
+	"server/internal/types/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+type recurringTransactionHandler struct {
+	recurringTransactionService service.RecurringTransactionsService
+}
+
+func NewRecurringTransactionHandler(recurringTransactionService service.RecurringTransactionsService) *recurringTransactionHandler {
+	return &recurringTransactionHandler{recurringTransactionService}
+}
+
+func (recurring_handler *recurringTransactionHandler) GetRecurringTransactions(c *gin.Context) {
+	ctx := c.Request.Context()
+	token := c.GetHeader("Authorization")
+
+	recurring, err := recurring_handler.recurringTransactionService.GetRecurringTransactions(ctx, token)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get Recurring Transactions",
+		"data":       recurring,
+	})
+}
+
+func (recurring_handler *recurringTransactionHandler) CreateRecurringTransaction(c *gin.Context) {
+	ctx := c.Request.Context()
+	token := c.GetHeader("Authorization")
+
+	var recurringRequest dto.RecurringTransactionsRequest
+	if err := c.ShouldBindJSON(&recurringRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	recurring, err := recurring_handler.recurringTransactionService.CreateRecurringTransaction(ctx, token, recurringRequest)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Create Recurring Transaction",
+		"data":       recurring,
+	})
+}
+
+func (recurring_handler *recurringTransactionHandler) DeleteRecurringTransaction(c *gin.Context) {
+	ctx := c.Request.Context()
+	token := c.GetHeader("Authorization")
+	ID := c.Param("id")
+
+	recurring, err := recurring_handler.recurringTransactionService.DeleteRecurringTransaction(ctx, token, ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Delete Recurring Transaction",
+		"data":       recurring,
+	})
+}
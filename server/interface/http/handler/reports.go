@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"server/internal/service"
@@ -19,7 +20,76 @@ func NewReportHandler(reportService service.ReportsService) *reportHandler {
 	}
 }
 
+func (h *reportHandler) GetReports(c *gin.Context) {
+	ctx := c.Request.Context()
+	token := c.GetHeader("Authorization")
+
+	reports, err := h.reportService.GetReports(ctx, token)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     true,
+		"statusCode": 200,
+		"message":    "Get reports data",
+		"data":       reports,
+	})
+}
+
+func (h *reportHandler) GetReportByID(c *gin.Context) {
+	ctx := c.Request.Context()
+	token := c.GetHeader("Authorization")
+	ID := c.Param("id")
+
+	report, err := h.reportService.GetReportByID(ctx, token, ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     true,
+		"statusCode": 200,
+		"message":    "Get report data",
+		"data":       report,
+	})
+}
+
+func (h *reportHandler) DownloadReport(c *gin.Context) {
+	ctx := c.Request.Context()
+	token := c.GetHeader("Authorization")
+	ID := c.Param("id")
+
+	download, err := h.reportService.GetReportDownload(ctx, token, ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     true,
+		"statusCode": 200,
+		"message":    "Get report download link",
+		"data":       download,
+	})
+}
+
 func (h *reportHandler) RequestReports(c *gin.Context) {
+	token := c.GetHeader("Authorization")
 	var request dto.ReportRequest
 	err := c.BindJSON(&request)
 	if err != nil {
@@ -32,7 +102,20 @@ func (h *reportHandler) RequestReports(c *gin.Context) {
 	}
 
 	ctx := c.Request.Context()
-	if err := h.reportService.RequestReport(ctx, request.UserID, request.FromDate, request.ToDate); err != nil {
+	if err := h.reportService.RequestReport(ctx, token, request.Type, request.FromDate, request.ToDate); err != nil {
+		var cooldownErr *service.ReportCooldownError
+		if errors.As(err, &cooldownErr) {
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"status":     false,
+				"statusCode": 429,
+				"message":    err.Error(),
+				"data": gin.H{
+					"next_request_at": cooldownErr.NextRequestAt,
+				},
+			})
+			return
+		}
+
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":     false,
 			"statusCode": 500,
@@ -47,3 +130,26 @@ func (h *reportHandler) RequestReports(c *gin.Context) {
 		"message":    "Request reports sent successfully",
 	})
 }
+
+func (h *reportHandler) CancelReport(c *gin.Context) {
+	ctx := c.Request.Context()
+	token := c.GetHeader("Authorization")
+	ID := c.Param("id")
+
+	report, err := h.reportService.CancelReport(ctx, token, ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     true,
+		"statusCode": 200,
+		"message":    "Report cancelled",
+		"data":       report,
+	})
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"server/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// requestReportService answers RequestReport with err, the other methods are not used by the test
+type requestReportService struct {
+	service.ReportsService
+	err error
+}
+
+func (s *requestReportService) RequestReport(ctx context.Context, token, reportType, fromDate, toDate string) error {
+	return s.err
+}
+
+func TestRequestReports(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	nextRequestAt := time.Date(2026, time.October, 20, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		err           error
+		statusCode    int
+		nextRequestAt string
+	}{
+		{"Requested", nil, http.StatusOK, ""},
+		{"Inside Cooldown", &service.ReportCooldownError{ReportType: "financial", NextRequestAt: nextRequestAt}, http.StatusTooManyRequests, "2026-10-20T08:00:00Z"},
+		{"Failed", errors.New("invalid report type tax"), http.StatusInternalServerError, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := gin.New()
+			router.POST("/reports", NewReportHandler(&requestReportService{err: test.err}).RequestReports)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/reports", strings.NewReader(`{"type":"financial","from_date":"2026-09-01T00:00:00Z","to_date":"2026-09-30T23:59:59Z"}`))
+			router.ServeHTTP(recorder, request)
+
+			var body struct {
+				StatusCode int `json:"statusCode"`
+				Data       struct {
+					NextRequestAt string `json:"next_request_at"`
+				} `json:"data"`
+			}
+			assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+			assert.Equal(t, test.statusCode, recorder.Code)
+			assert.Equal(t, test.statusCode, body.StatusCode)
+			assert.Equal(t, test.nextRequestAt, body.Data.NextRequestAt)
+		})
+	}
+}
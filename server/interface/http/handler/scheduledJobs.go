@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"net/http"
+
+	"server/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type scheduledJobHandler struct {
+	scheduledJobService service.ScheduledJobsService
+}
+
+func NewScheduledJobHandler(scheduledJobService service.ScheduledJobsService) *scheduledJobHandler {
+	return &scheduledJobHandler{scheduledJobService}
+}
+
+func (job_handler *scheduledJobHandler) GetJobs(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	jobs, err := job_handler.scheduledJobService.GetJobs(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"statusCode": 500,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get Scheduled Jobs",
+		"data":       jobs,
+	})
+}
+
+func (job_handler *scheduledJobHandler) GetJobRuns(c *gin.Context) {
+	ctx := c.Request.Context()
+	name := c.Param("name")
+
+	runs, err := job_handler.scheduledJobService.GetJobRuns(ctx, name)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"statusCode": 404,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get Scheduled Job Runs",
+		"data":       runs,
+	})
+}
+
+func (job_handler *scheduledJobHandler) TriggerJob(c *gin.Context) {
+	ctx := c.Request.Context()
+	token := c.GetHeader("Authorization")
+	name := c.Param("name")
+
+	if err := job_handler.scheduledJobService.TriggerJob(ctx, token, name); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"statusCode": 202,
+		"status":     true,
+		"message":    "Scheduled Job Triggered",
+	})
+}
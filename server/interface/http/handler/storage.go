@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"server/config/miniofs"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StorageHandler struct {
+	storage *miniofs.LocalStorage
+}
+
+func NewStorageHandler(storage *miniofs.LocalStorage) *StorageHandler {
+	return &StorageHandler{storage}
+}
+
+// ServeObject serves objects of the local storage backend, private buckets require a presigned URL
+func (storageHandler *StorageHandler) ServeObject(c *gin.Context) {
+	bucket := c.Param("bucket")
+	object := strings.TrimPrefix(c.Param("object"), "/")
+
+	if !storageHandler.storage.IsPublicBucket(bucket) {
+		if err := storageHandler.storage.VerifySignature(bucket, object, c.Query("expires"), c.Query("signature")); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{
+				"status":     false,
+				"statusCode": 403,
+				"message":    err.Error(),
+			})
+			return
+		}
+	}
+
+	path, err := storageHandler.storage.FilePath(bucket, object)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.File(path)
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"net/http"
+
+	"server/internal/service"
+	"server/internal/types/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+type targetAllocationHandler struct {
+	targetAllocationService service.TargetAllocationsService
+}
+
+func NewTargetAllocationHandler(targetAllocationService service.TargetAllocationsService) *targetAllocationHandler {
+	return &targetAllocationHandler{targetAllocationService}
+}
+
+func (allocation_handler *targetAllocationHandler) GetTargetAllocations(c *gin.Context) {
+	ctx := c.Request.Context()
+	token := c.GetHeader("Authorization")
+
+	targets, err := allocation_handler.targetAllocationService.GetTargetAllocations(ctx, token)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get Target Allocations",
+		"data":       targets,
+	})
+}
+
+func (allocation_handler *targetAllocationHandler) SetTargetAllocations(c *gin.Context) {
+	ctx := c.Request.Context()
+	token := c.GetHeader("Authorization")
+
+	var targetsRequest []dto.TargetAllocationsRequest
+	if err := c.ShouldBindJSON(&targetsRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	targets, err := allocation_handler.targetAllocationService.SetTargetAllocations(ctx, token, targetsRequest)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Set Target Allocations",
+		"data":       targets,
+	})
+}
+
+func (allocation_handler *targetAllocationHandler) GetRebalance(c *gin.Context) {
+	ctx := c.Request.Context()
+	token := c.GetHeader("Authorization")
+
+	rebalance, err := allocation_handler.targetAllocationService.GetRebalance(ctx, token)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"statusCode": 400,
+			"status":     false,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statusCode": 200,
+		"status":     true,
+		"message":    "Get Rebalance Suggestions",
+		"data":       rebalance,
+	})
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+
+	"server/internal/types/dto"
+	"server/internal/types/entity"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminMiddleware only lets admins through, it has to run after AuthMiddleware
+func AdminMiddleware() gin.HandlerFunc {
+	return gin.HandlerFunc(func(ctx *gin.Context) {
+		userData, ok := ctx.Get("user_data")
+		if !ok {
+			ctx.JSON(http.StatusUnauthorized, gin.H{
+				"statusCode": 401,
+				"status":     false,
+				"error":      "Unauthorized",
+			})
+			ctx.Abort()
+			return
+		}
+
+		if user, ok := userData.(dto.UserData); !ok || user.Role != string(entity.Admin) {
+			ctx.JSON(http.StatusForbidden, gin.H{
+				"statusCode": 403,
+				"status":     false,
+				"error":      "Forbidden",
+			})
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	})
+}
@@ -3,6 +3,7 @@ package router
 import (
 	"server/config/db"
 	"server/config/miniofs"
+	"server/config/pricing"
 	"server/config/redis"
 	"server/interface/http/middleware"
 	"server/interface/http/routes"
@@ -23,12 +24,24 @@ func SetupRouter() *gin.Engine {
 
 	v1 := router.Group("/v1")
 	routes.UserRoutes(v1, db.DB, redis.RDB)
-	routes.TransactionRoutes(v1, db.DB, miniofs.MinioClient)
-	routes.WalletRoutes(v1, db.DB)
-	routes.InvestmentRoute(v1, db.DB)
+	routes.TransactionRoutes(v1, db.DB, miniofs.FileStorage)
+	routes.WalletRoutes(v1, db.DB, miniofs.FileStorage)
+	routes.InvestmentRoute(v1, db.DB, miniofs.FileStorage)
+	routes.InvestmentPriceRoutes(v1, db.DB, pricing.PriceProvider)
+	routes.TargetAllocationRoutes(v1, db.DB)
+	routes.InvestmentTypeRoutes(v1, db.DB)
+	routes.InstrumentRoutes(v1, db.DB)
 	routes.WalletTypesRoutes(v1, db.DB)
 	routes.CategoryRoutes(v1, db.DB)
-	routes.ReportRoutes(v1, db.DB)
+	routes.ReportRoutes(v1, db.DB, miniofs.FileStorage)
+	routes.RecurringTransactionRoutes(v1, db.DB)
+	routes.ScheduledJobRoutes(v1, db.DB, miniofs.FileStorage)
+	routes.AnalyticsRoutes(v1, db.DB)
+
+	// Objects of the local storage backend are served by the API itself
+	if local, ok := miniofs.FileStorage.(*miniofs.LocalStorage); ok {
+		routes.StorageRoutes(v1, local)
+	}
 
 	return router
 }
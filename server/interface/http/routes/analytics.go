@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"server/interface/http/handler"
+	"server/interface/http/middleware"
+	"server/internal/repository"
+	"server/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func AnalyticsRoutes(version *gin.RouterGroup, db *gorm.DB) {
+	Analytics_repo := repository.NewAnalyticsRepository(db)
+	Recurring_repo := repository.NewRecurringTransactionsRepository(db)
+	Analytics_serv := service.NewAnalyticsService(Analytics_repo, Recurring_repo)
+	Analytics_handler := handler.NewAnalyticsHandler(Analytics_serv)
+
+	analytics := version.Group("/analytics")
+	analytics.Use(middleware.AuthMiddleware())
+
+	analytics.GET("cashflow", Analytics_handler.GetCashflow)
+	analytics.GET("balances", Analytics_handler.GetBalances)
+	analytics.GET("forecast", Analytics_handler.GetForecast)
+}
@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"server/interface/http/handler"
+	"server/interface/http/middleware"
+	"server/internal/repository"
+	"server/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func InstrumentRoutes(version *gin.RouterGroup, db *gorm.DB) {
+	txManager := repository.NewTxManager(db)
+	Instrument_repo := repository.NewInstrumentsRepository(db)
+	Investment_type_repo := repository.NewInvestmentTypesRepository(db)
+	Instrument_serv := service.NewInstrumentsService(txManager, Instrument_repo, Investment_type_repo)
+	Instrument_handler := handler.NewInstrumentHandler(Instrument_serv)
+
+	instruments := version.Group("/instruments")
+	instruments.Use(middleware.AuthMiddleware())
+
+	instruments.GET("", Instrument_handler.GetInstruments)
+	instruments.GET(":id", Instrument_handler.GetInstrumentByID)
+
+	admin := instruments.Group("", middleware.AdminMiddleware())
+	admin.POST("", Instrument_handler.CreateInstrument)
+	admin.PUT(":id", Instrument_handler.UpdateInstrument)
+	admin.DELETE(":id", Instrument_handler.DeleteInstrument)
+}
@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"server/config/pricing"
+	"server/interface/http/handler"
+	"server/interface/http/middleware"
+	"server/internal/repository"
+	"server/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func InvestmentPriceRoutes(version *gin.RouterGroup, db *gorm.DB, provider pricing.Provider) {
+	txManager := repository.NewTxManager(db)
+	Investment_price_repo := repository.NewInvestmentPricesRepository(db)
+	Investment_repo := repository.NewInvestmentRepository(db)
+	Instrument_repo := repository.NewInstrumentsRepository(db)
+	Investment_price_serv := service.NewInvestmentPricesService(txManager, Investment_price_repo, Investment_repo, Instrument_repo, provider)
+	Investment_price_handler := handler.NewInvestmentPriceHandler(Investment_price_serv)
+
+	prices := version.Group("/investment-prices")
+	prices.Use(middleware.AuthMiddleware())
+
+	prices.GET("", Investment_price_handler.GetPrices)
+
+	// Prices are shared by every user, only admins maintain them
+	admin := prices.Group("", middleware.AdminMiddleware())
+	admin.POST("", Investment_price_handler.CreatePrices)
+	admin.POST("import", Investment_price_handler.ImportPrices)
+	admin.POST("sync", Investment_price_handler.SyncPrices)
+	admin.DELETE(":id", Investment_price_handler.DeletePrice)
+}
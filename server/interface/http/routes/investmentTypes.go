@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"server/interface/http/handler"
+	"server/interface/http/middleware"
+	"server/internal/repository"
+	"server/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func InvestmentTypeRoutes(version *gin.RouterGroup, db *gorm.DB) {
+	txManager := repository.NewTxManager(db)
+	Investment_type_repo := repository.NewInvestmentTypesRepository(db)
+	Investment_type_serv := service.NewInvestmentTypesService(txManager, Investment_type_repo)
+	Investment_type_handler := handler.NewInvestmentTypeHandler(Investment_type_serv)
+
+	investmentTypes := version.Group("/investment-types")
+	investmentTypes.Use(middleware.AuthMiddleware())
+
+	investmentTypes.GET("", Investment_type_handler.GetAllInvestmentTypes)
+	investmentTypes.GET(":id", Investment_type_handler.GetInvestmentTypeByID)
+
+	admin := investmentTypes.Group("", middleware.AdminMiddleware())
+	admin.POST("", Investment_type_handler.CreateInvestmentType)
+	admin.PUT(":id", Investment_type_handler.UpdateInvestmentType)
+	admin.DELETE(":id", Investment_type_handler.DeleteInvestmentType)
+}
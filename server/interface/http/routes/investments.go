@@ -1,19 +1,35 @@
 package routes
 
 import (
+	"server/config/miniofs"
 	"server/interface/http/handler"
 	"server/interface/http/middleware"
 	"server/internal/repository"
 	"server/internal/service"
+	"server/internal/utils/data"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-func InvestmentRoute(version *gin.RouterGroup, db *gorm.DB) {
+func InvestmentRoute(version *gin.RouterGroup, db *gorm.DB, storage miniofs.Storage) {
 	txManager := repository.NewTxManager(db)
 	Investment_repo := repository.NewInvestmentRepository(db)
-	Investment_serv := service.NewInvestmentService(txManager, Investment_repo)
+	Investment_trade_repo := repository.NewInvestmentTradesRepository(db)
+	Investment_income_repo := repository.NewInvestmentIncomesRepository(db)
+	Investment_price_repo := repository.NewInvestmentPricesRepository(db)
+	Instrument_repo := repository.NewInstrumentsRepository(db)
+	Wallet_repo := repository.NewWalletRepository(db)
+	Transaction_repo := repository.NewTransactionRepository(db)
+	Category_repo := repository.NewCategoryRepository(db)
+	Outbox_repo := repository.NewOutboxEventsRepository(db)
+	Outbox_serv := service.NewOutboxService(txManager, Outbox_repo)
+	attachmentRepo := repository.NewAttachmentsRepository(db)
+	blobRepo := repository.NewAttachmentBlobsRepository(db)
+	Attachment_serv := service.NewAttachmentsService(txManager, attachmentRepo, blobRepo, storage)
+	Attachment_handler := handler.NewAttachmentHandler(Attachment_serv)
+
+	Investment_serv := service.NewInvestmentService(txManager, Investment_repo, Investment_trade_repo, Investment_income_repo, Investment_price_repo, Instrument_repo, Wallet_repo, Transaction_repo, Category_repo, Attachment_serv, Outbox_serv)
 	Investment_handler := handler.NewInvestmentHandler(Investment_serv)
 
 	investments := version.Group("/investments")
@@ -22,7 +38,24 @@ func InvestmentRoute(version *gin.RouterGroup, db *gorm.DB) {
 	investments.GET("", Investment_handler.GetAllInvestments)
 	investments.GET(":id", Investment_handler.GetInvestmentByID)
 	investments.GET("user", Investment_handler.GetInvestmentsByUserID)
+	investments.GET("portfolio", Investment_handler.GetPortfolioValuation)
+	investments.GET("portfolio/history", Investment_handler.GetPortfolioHistory)
+	investments.GET("portfolio/performance", Investment_handler.GetPortfolioPerformance)
 	investments.POST("", Investment_handler.CreateInvestment)
 	investments.PUT(":id", Investment_handler.UpdateInvestment)
 	investments.DELETE(":id", Investment_handler.DeleteInvestment)
+	investments.GET(":id/trades", Investment_handler.GetInvestmentTrades)
+	investments.POST(":id/trades", Investment_handler.CreateInvestmentTrade)
+	investments.DELETE(":id/trades/:tradeId", Investment_handler.DeleteInvestmentTrade)
+	investments.GET(":id/position", Investment_handler.GetInvestmentPosition)
+	investments.GET(":id/incomes", Investment_handler.GetInvestmentIncomes)
+	investments.POST(":id/incomes", Investment_handler.CreateInvestmentIncome)
+	investments.DELETE(":id/incomes/:incomeId", Investment_handler.DeleteInvestmentIncome)
+	investments.GET(":id/yield", Investment_handler.GetInvestmentYield)
+	investments.GET(":id/coupons", Investment_handler.GetCouponSchedule)
+	investments.GET(":id/performance", Investment_handler.GetInvestmentPerformance)
+
+	investments.GET(":id/attachments", Attachment_handler.GetAttachments(data.ATTACHMENT_OWNER_INVESTMENT))
+	investments.POST(":id/attachments", Attachment_handler.UploadAttachments(data.ATTACHMENT_OWNER_INVESTMENT))
+	investments.DELETE(":id/attachments/:attachmentId", Attachment_handler.DeleteAttachment(data.ATTACHMENT_OWNER_INVESTMENT))
 }
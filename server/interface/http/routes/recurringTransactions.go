@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"server/interface/http/handler"
+	"server/interface/http/middleware"
+	"server/internal/repository"
+	"server/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func RecurringTransactionRoutes(version *gin.RouterGroup, db *gorm.DB) {
+	txManager := repository.NewTxManager(db)
+	Recurring_repo := repository.NewRecurringTransactionsRepository(db)
+	Transaction_repo := repository.NewTransactionRepository(db)
+	Wallet_repo := repository.NewWalletRepository(db)
+	Category_repo := repository.NewCategoryRepository(db)
+	Outbox_repo := repository.NewOutboxEventsRepository(db)
+	Outbox_serv := service.NewOutboxService(txManager, Outbox_repo)
+	Recurring_serv := service.NewRecurringTransactionsService(txManager, Recurring_repo, Transaction_repo, Wallet_repo, Category_repo, Outbox_serv)
+	Recurring_handler := handler.NewRecurringTransactionHandler(Recurring_serv)
+
+	recurring := version.Group("/recurring-transactions")
+	recurring.Use(middleware.AuthMiddleware())
+
+	recurring.GET("", Recurring_handler.GetRecurringTransactions)
+	recurring.POST("", Recurring_handler.CreateRecurringTransaction)
+	recurring.DELETE(":id", Recurring_handler.DeleteRecurringTransaction)
+}
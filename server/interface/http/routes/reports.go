@@ -1,7 +1,9 @@
 package routes
 
 import (
+	"server/config/miniofs"
 	"server/interface/http/handler"
+	"server/interface/http/middleware"
 	"server/internal/repository"
 	"server/internal/service"
 
@@ -9,13 +11,24 @@ import (
 	"gorm.io/gorm"
 )
 
-func ReportRoutes(version *gin.RouterGroup, db *gorm.DB) {
+func ReportRoutes(version *gin.RouterGroup, db *gorm.DB, storage miniofs.Storage) {
+	txManager := repository.NewTxManager(db)
 	userRepo := repository.NewUsersRepository(db)
 	reportsRepo := repository.NewReportsRepository(db)
-	reportsService := service.NewReportsService(reportsRepo, userRepo)
+	transactionRepo := repository.NewTransactionRepository(db)
+	walletRepo := repository.NewWalletRepository(db)
+	outboxRepo := repository.NewOutboxEventsRepository(db)
+	outboxService := service.NewOutboxService(txManager, outboxRepo)
+	emailsService := service.NewZohoEmailsService(outboxService)
+	reportsService := service.NewReportsService(txManager, reportsRepo, userRepo, transactionRepo, walletRepo, outboxService, emailsService, storage)
 	reportHandler := handler.NewReportHandler(reportsService)
 
 	reportGroup := version.Group("/reports")
+	reportGroup.Use(middleware.AuthMiddleware())
 
+	reportGroup.GET("", reportHandler.GetReports)
+	reportGroup.GET(":id", reportHandler.GetReportByID)
+	reportGroup.GET(":id/download", reportHandler.DownloadReport)
 	reportGroup.POST("request", reportHandler.RequestReports)
+	reportGroup.POST(":id/cancel", reportHandler.CancelReport)
 }
@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"server/config/miniofs"
+	"server/interface/http/handler"
+	"server/interface/http/middleware"
+	"server/internal/repository"
+	"server/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func ScheduledJobRoutes(version *gin.RouterGroup, db *gorm.DB, storage miniofs.Storage) {
+	txManager := repository.NewTxManager(db)
+	Job_run_repo := repository.NewScheduledJobRunsRepository(db)
+	Outbox_repo := repository.NewOutboxEventsRepository(db)
+	Outbox_serv := service.NewOutboxService(txManager, Outbox_repo)
+	Summary_repo := repository.NewUserSummariesRepository(db)
+	Summary_serv := service.NewUserSummariesService(txManager, Summary_repo)
+	Report_repo := repository.NewReportsRepository(db)
+	Cleanup_serv := service.NewCleanupService(Outbox_repo, Report_repo, storage)
+	Recurring_repo := repository.NewRecurringTransactionsRepository(db)
+	Recurring_serv := service.NewRecurringTransactionsService(txManager, Recurring_repo, repository.NewTransactionRepository(db), repository.NewWalletRepository(db), repository.NewCategoryRepository(db), Outbox_serv)
+	Job_serv := service.NewScheduledJobsService(txManager, Job_run_repo, Outbox_serv, Summary_serv, Cleanup_serv, Recurring_serv)
+	Job_handler := handler.NewScheduledJobHandler(Job_serv)
+
+	jobs := version.Group("/admin/jobs")
+	jobs.Use(middleware.AuthMiddleware(), middleware.AdminMiddleware())
+
+	jobs.GET("", Job_handler.GetJobs)
+	jobs.GET(":name/runs", Job_handler.GetJobRuns)
+	jobs.POST(":name/run", Job_handler.TriggerJob)
+}
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"server/config/miniofs"
+	"server/interface/http/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func StorageRoutes(version *gin.RouterGroup, storage *miniofs.LocalStorage) {
+	storageHandler := handler.NewStorageHandler(storage)
+
+	storageGroup := version.Group("/storage")
+
+	storageGroup.GET(":bucket/*object", storageHandler.ServeObject)
+}
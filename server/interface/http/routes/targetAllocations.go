@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"server/interface/http/handler"
+	"server/interface/http/middleware"
+	"server/internal/repository"
+	"server/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func TargetAllocationRoutes(version *gin.RouterGroup, db *gorm.DB) {
+	txManager := repository.NewTxManager(db)
+	Target_allocation_repo := repository.NewTargetAllocationsRepository(db)
+	Investment_type_repo := repository.NewInvestmentTypesRepository(db)
+	Target_allocation_serv := service.NewTargetAllocationsService(txManager, Target_allocation_repo, Investment_type_repo)
+	Target_allocation_handler := handler.NewTargetAllocationHandler(Target_allocation_serv)
+
+	allocations := version.Group("/allocations")
+	allocations.Use(middleware.AuthMiddleware())
+
+	allocations.GET("targets", Target_allocation_handler.GetTargetAllocations)
+	allocations.PUT("targets", Target_allocation_handler.SetTargetAllocations)
+	allocations.GET("rebalance", Target_allocation_handler.GetRebalance)
+}
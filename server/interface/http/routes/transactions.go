@@ -11,14 +11,18 @@ import (
 	"gorm.io/gorm"
 )
 
-func TransactionRoutes(version *gin.RouterGroup, db *gorm.DB, minio *miniofs.MinIOManager) {
+func TransactionRoutes(version *gin.RouterGroup, db *gorm.DB, storage miniofs.Storage) {
 	txManager := repository.NewTxManager(db)
 	transactionRepo := repository.NewTransactionRepository(db)
 	walletRepo := repository.NewWalletRepository(db)
 	categoryRepo := repository.NewCategoryRepository(db)
 	attachmentRepo := repository.NewAttachmentsRepository(db)
+	blobRepo := repository.NewAttachmentBlobsRepository(db)
+	outboxRepo := repository.NewOutboxEventsRepository(db)
 
-	Transaction_serv := service.NewTransactionService(txManager, transactionRepo, walletRepo, categoryRepo, attachmentRepo, minio)
+	Attachment_serv := service.NewAttachmentsService(txManager, attachmentRepo, blobRepo, storage)
+	Outbox_serv := service.NewOutboxService(txManager, outboxRepo)
+	Transaction_serv := service.NewTransactionService(txManager, transactionRepo, walletRepo, categoryRepo, attachmentRepo, Attachment_serv, Outbox_serv)
 	Transaction_handler := handler.NewTransactionHandler(Transaction_serv)
 
 	transaction := version.Group("/transactions")
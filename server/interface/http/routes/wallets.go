@@ -1,19 +1,28 @@
 package routes
 
 import (
+	"server/config/miniofs"
 	"server/interface/http/handler"
 	"server/interface/http/middleware"
 	"server/internal/repository"
 	"server/internal/service"
+	"server/internal/utils/data"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-func WalletRoutes(version *gin.RouterGroup, db *gorm.DB) {
+func WalletRoutes(version *gin.RouterGroup, db *gorm.DB, storage miniofs.Storage) {
 	txManager := repository.NewTxManager(db)
 	Wallet_repo := repository.NewWalletRepository(db)
-	Wallet_serv := service.NewWalletService(txManager, Wallet_repo)
+	Outbox_repo := repository.NewOutboxEventsRepository(db)
+	Outbox_serv := service.NewOutboxService(txManager, Outbox_repo)
+	attachmentRepo := repository.NewAttachmentsRepository(db)
+	blobRepo := repository.NewAttachmentBlobsRepository(db)
+	Attachment_serv := service.NewAttachmentsService(txManager, attachmentRepo, blobRepo, storage)
+	Attachment_handler := handler.NewAttachmentHandler(Attachment_serv)
+
+	Wallet_serv := service.NewWalletService(txManager, Wallet_repo, Attachment_serv, Outbox_serv)
 	Wallet_handler := handler.NewWalletHandler(Wallet_serv)
 
 	wallets := version.Group("/wallets")
@@ -26,4 +35,8 @@ func WalletRoutes(version *gin.RouterGroup, db *gorm.DB) {
 	wallets.POST("", Wallet_handler.CreateWallet)
 	wallets.PUT(":id", Wallet_handler.UpdateWallet)
 	wallets.DELETE(":id", Wallet_handler.DeleteWallet)
+
+	wallets.GET(":id/attachments", Attachment_handler.GetAttachments(data.ATTACHMENT_OWNER_WALLET))
+	wallets.POST(":id/attachments", Attachment_handler.UploadAttachments(data.ATTACHMENT_OWNER_WALLET))
+	wallets.DELETE(":id/attachments/:attachmentId", Attachment_handler.DeleteAttachment(data.ATTACHMENT_OWNER_WALLET))
 }
@@ -0,0 +1,36 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// RunCron calls run at every time of the cron schedule until the context is cancelled, times missed while
+// a run takes longer than the interval are skipped. The schedule may set its time zone with a CRON_TZ= prefix.
+func RunCron(ctx context.Context, spec string, run func(ctx context.Context, at time.Time)) error {
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return err
+	}
+
+	for {
+		next := schedule.Next(time.Now())
+		if next.IsZero() {
+			// The schedule never fires
+			<-ctx.Done()
+			return nil
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+
+		run(ctx, next)
+	}
+}
@@ -0,0 +1,27 @@
+package jobs
+
+import (
+	"time"
+
+	"server/config/queue"
+	"server/interface/worker"
+	"server/internal/repository"
+	"server/internal/service"
+)
+
+const EMAIL_JOB = "email"
+
+func init() {
+	worker.Register(EMAIL_JOB, func(deps worker.Dependencies) worker.Job {
+		txManager := repository.NewTxManager(deps.DB)
+		outboxRepo := repository.NewOutboxEventsRepository(deps.DB)
+		outboxService := service.NewOutboxService(txManager, outboxRepo)
+		emailsService := service.NewZohoEmailsService(outboxService)
+
+		return worker.Job{
+			Queue:   queue.EMAIL_QUEUE,
+			Handler: emailsService.ProcessEmail,
+			Timeout: time.Minute,
+		}
+	})
+}
@@ -0,0 +1,27 @@
+package jobs
+
+import (
+	"context"
+
+	"server/interface/worker"
+	"server/internal/repository"
+	"server/internal/service"
+)
+
+const OUTBOX_RELAY_JOB = "outbox_relay"
+
+func init() {
+	// Events stored by the API are published from here, relays running side by side lock different rows
+	worker.Register(OUTBOX_RELAY_JOB, func(deps worker.Dependencies) worker.Job {
+		txManager := repository.NewTxManager(deps.DB)
+		outboxRepo := repository.NewOutboxEventsRepository(deps.DB)
+		outboxService := service.NewOutboxService(txManager, outboxRepo)
+
+		return worker.Job{
+			Run: func(ctx context.Context) error {
+				outboxService.RunRelay(ctx, service.OUTBOX_RELAY_INTERVAL)
+				return nil
+			},
+		}
+	})
+}
@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"time"
+
+	"server/config/queue"
+	"server/interface/worker"
+	"server/internal/repository"
+	"server/internal/service"
+)
+
+const USER_REPORT_JOB = "user_report"
+
+func init() {
+	worker.Register(USER_REPORT_JOB, func(deps worker.Dependencies) worker.Job {
+		txManager := repository.NewTxManager(deps.DB)
+		userRepo := repository.NewUsersRepository(deps.DB)
+		reportsRepo := repository.NewReportsRepository(deps.DB)
+		transactionRepo := repository.NewTransactionRepository(deps.DB)
+		walletRepo := repository.NewWalletRepository(deps.DB)
+		outboxRepo := repository.NewOutboxEventsRepository(deps.DB)
+		outboxService := service.NewOutboxService(txManager, outboxRepo)
+		emailsService := service.NewZohoEmailsService(outboxService)
+		reportsService := service.NewReportsService(txManager, reportsRepo, userRepo, transactionRepo, walletRepo, outboxService, emailsService, deps.Storage)
+
+		return worker.Job{
+			Queue:        queue.USER_REPORT_QUEUE,
+			Handler:      reportsService.ProcessReport,
+			OnDeadLetter: reportsService.FailReport,
+			// Generating the PDF and sending the email can take a while for long date ranges
+			Timeout: 10 * time.Minute,
+		}
+	})
+}
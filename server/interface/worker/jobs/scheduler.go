@@ -0,0 +1,94 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"server/config/log"
+	"server/config/queue"
+	"server/interface/worker"
+	"server/internal/repository"
+	"server/internal/service"
+	"server/internal/utils/data"
+)
+
+const (
+	SCHEDULER_JOB     = "scheduler"
+	SCHEDULED_JOB_JOB = "scheduled_job"
+
+	// SCHEDULED_JOB_TIMEOUT bounds a run started by the schedule, runs triggered by hand use the timeout of SCHEDULED_JOB_JOB
+	SCHEDULED_JOB_TIMEOUT = 30 * time.Minute
+)
+
+func newScheduledJobsService(deps worker.Dependencies) service.ScheduledJobsService {
+	txManager := repository.NewTxManager(deps.DB)
+	jobRunsRepo := repository.NewScheduledJobRunsRepository(deps.DB)
+	outboxRepo := repository.NewOutboxEventsRepository(deps.DB)
+	outboxService := service.NewOutboxService(txManager, outboxRepo)
+	summaryService := service.NewUserSummariesService(txManager, repository.NewUserSummariesRepository(deps.DB))
+	cleanupService := service.NewCleanupService(outboxRepo, repository.NewReportsRepository(deps.DB), deps.Storage)
+	recurringService := service.NewRecurringTransactionsService(txManager, repository.NewRecurringTransactionsRepository(deps.DB), repository.NewTransactionRepository(deps.DB), repository.NewWalletRepository(deps.DB), repository.NewCategoryRepository(deps.DB), outboxService)
+	return service.NewScheduledJobsService(txManager, jobRunsRepo, outboxService, summaryService, cleanupService, recurringService)
+}
+
+func init() {
+	// Every worker fires the schedules, the run history makes sure each time only runs once
+	worker.Register(SCHEDULER_JOB, func(deps worker.Dependencies) worker.Job {
+		jobsService := newScheduledJobsService(deps)
+
+		return worker.Job{
+			Run: func(ctx context.Context) error {
+				names := make([]string, 0, len(data.SCHEDULED_JOB_SCHEDULES))
+				for name := range data.SCHEDULED_JOB_SCHEDULES {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+
+				var wg sync.WaitGroup
+				for _, name := range names {
+					schedule := service.JobSchedule(name)
+					if strings.EqualFold(schedule, data.SCHEDULE_OFF) {
+						log.Info(fmt.Sprintf("scheduled job %s is off, it only runs when triggered", name))
+						continue
+					}
+
+					log.Info(fmt.Sprintf("scheduled job %s runs at %s", name, schedule))
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						err := worker.RunCron(ctx, schedule, func(ctx context.Context, at time.Time) {
+							ctx, cancel := context.WithTimeout(ctx, SCHEDULED_JOB_TIMEOUT)
+							defer cancel()
+
+							if err := jobsService.RunScheduledJob(ctx, name, at); err != nil {
+								log.Error(err.Error(), map[string]interface{}{"job": name, "scheduled_at": at.Format(time.RFC3339)})
+							}
+						})
+						if err != nil {
+							log.Error(fmt.Sprintf("invalid schedule %s of job %s: %v", schedule, name, err))
+						}
+					}()
+				}
+
+				// Keep running without schedules instead of being restarted
+				wg.Wait()
+				<-ctx.Done()
+				return nil
+			},
+		}
+	})
+
+	worker.Register(SCHEDULED_JOB_JOB, func(deps worker.Dependencies) worker.Job {
+		jobsService := newScheduledJobsService(deps)
+
+		return worker.Job{
+			Queue:   queue.SCHEDULED_JOB_QUEUE,
+			Handler: jobsService.ProcessJobTrigger,
+			Timeout: SCHEDULED_JOB_TIMEOUT,
+		}
+	})
+}
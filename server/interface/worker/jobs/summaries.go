@@ -0,0 +1,24 @@
+package jobs
+
+import (
+	"server/config/queue"
+	"server/interface/worker"
+	"server/internal/repository"
+	"server/internal/service"
+)
+
+const USER_SUMMARIES_JOB = "user_summaries"
+
+func init() {
+	// Every transaction event recomputes the months it touched of the user's dashboard summaries
+	worker.Register(USER_SUMMARIES_JOB, func(deps worker.Dependencies) worker.Job {
+		txManager := repository.NewTxManager(deps.DB)
+		summaryRepo := repository.NewUserSummariesRepository(deps.DB)
+		summaryService := service.NewUserSummariesService(txManager, summaryRepo)
+
+		return worker.Job{
+			Queue:   queue.USER_SUMMARIES_QUEUE,
+			Handler: service.NewEventHandler(summaryService.ApplyTransactionEvent),
+		}
+	})
+}
@@ -0,0 +1,239 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"server/config/env"
+	"server/config/log"
+	"server/config/miniofs"
+	"server/config/queue"
+
+	"github.com/google/uuid"
+	"github.com/rabbitmq/amqp091-go"
+	"gorm.io/gorm"
+)
+
+const (
+	DEFAULT_CONCURRENCY = 1
+	DEFAULT_TIMEOUT     = 5 * time.Minute
+
+	// Waits before a runner that stopped with an error is started again, doubled up to the maximum
+	restartDelay    = time.Second
+	maxRestartDelay = time.Minute
+)
+
+// Dependencies are shared by all jobs, each job builds its own repositories and services from them
+type Dependencies struct {
+	DB      *gorm.DB
+	Storage miniofs.Storage
+}
+
+// Job is a background job type of the worker. A job either consumes a queue of the topology with Handler,
+// or runs until the context is cancelled with Run, e.g. a relay polling the database.
+type Job struct {
+	Queue        string
+	Handler      queue.Handler
+	OnDeadLetter queue.DeadLetterHandler
+
+	Run func(ctx context.Context) error
+
+	// Concurrency is the number of consumers or runners started, Prefetch the unacked messages of each
+	// consumer and Timeout the deadline of a single message. The worker configuration overrides them by job name.
+	Concurrency int
+	Prefetch    int
+	Timeout     time.Duration
+}
+
+// Factory builds a job from the shared dependencies when the worker starts
+type Factory func(deps Dependencies) Job
+
+var (
+	registryMu sync.Mutex
+	registry   = map[string]Factory{}
+)
+
+// Register adds a job type to the worker, it is meant to be called from the init function of the job's file
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	name = strings.ToLower(name)
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("worker job %s is already registered", name))
+	}
+	registry[name] = factory
+}
+
+// Jobs returns the names of the registered jobs in order
+func Jobs() []string {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Run starts every registered job and blocks until the context is cancelled and all of them stopped
+func Run(ctx context.Context, deps Dependencies) {
+	var wg sync.WaitGroup
+	for _, name := range Jobs() {
+		registryMu.Lock()
+		factory := registry[name]
+		registryMu.Unlock()
+
+		job := configure(name, factory(deps))
+		if job.Handler == nil && job.Run == nil {
+			log.Error(fmt.Sprintf("worker job %s has neither a handler nor a runner, skipping", name))
+			continue
+		}
+
+		log.Info(fmt.Sprintf("starting worker job %s", name), map[string]interface{}{
+			"job":         name,
+			"concurrency": job.Concurrency,
+			"prefetch":    job.Prefetch,
+			"timeout":     job.Timeout.String(),
+		})
+		for i := 1; i <= job.Concurrency; i++ {
+			wg.Add(1)
+			go func(instance int) {
+				defer wg.Done()
+				if job.Handler != nil {
+					consume(ctx, name, instance, job)
+					return
+				}
+				run(ctx, name, instance, job)
+			}(i)
+		}
+	}
+
+	wg.Wait()
+}
+
+// configure applies the defaults and the worker configuration to the job
+func configure(name string, job Job) Job {
+	if concurrency, ok := env.Cfg.Worker.Concurrency[name]; ok {
+		job.Concurrency = concurrency
+	}
+	if prefetch, ok := env.Cfg.Worker.Prefetch[name]; ok {
+		job.Prefetch = prefetch
+	}
+	if timeout, ok := env.Cfg.Worker.Timeouts[name]; ok {
+		job.Timeout = timeout
+	}
+
+	if job.Concurrency < 1 {
+		job.Concurrency = DEFAULT_CONCURRENCY
+	}
+	if job.Timeout <= 0 {
+		job.Timeout = DEFAULT_TIMEOUT
+	}
+	return job
+}
+
+func consume(ctx context.Context, name string, instance int, job Job) {
+	consumer := queue.Consumer{
+		Queue:        job.Queue,
+		Tag:          fmt.Sprintf("worker_%s_%d", name, instance),
+		Handler:      handle(name, job),
+		OnDeadLetter: job.OnDeadLetter,
+		Prefetch:     job.Prefetch,
+	}
+
+	if err := consumer.Run(ctx); err != nil {
+		log.Error(fmt.Sprintf("worker job %s stopped: %v", name, err), map[string]interface{}{"job": name})
+	}
+}
+
+// handle wraps the handler of the job with its timeout, panic recovery and logging
+func handle(name string, job Job) queue.Handler {
+	return func(ctx context.Context, delivery amqp091.Delivery) (err error) {
+		jobID := delivery.MessageId
+		if jobID == "" {
+			jobID = uuid.NewString()
+		}
+		fields := map[string]interface{}{
+			"job":     name,
+			"job_id":  jobID,
+			"attempt": queue.RetryCount(delivery) + 1,
+		}
+
+		ctx, cancel := context.WithTimeout(withJobID(ctx, jobID), job.Timeout)
+		defer cancel()
+
+		start := time.Now()
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("job panicked: %v", r)
+				fields["stack"] = string(debug.Stack())
+			}
+
+			fields["duration"] = time.Since(start).String()
+			if err != nil {
+				log.Error(fmt.Sprintf("job %s failed: %v", name, err), fields)
+				return
+			}
+			log.Info(fmt.Sprintf("job %s completed", name), fields)
+		}()
+
+		log.Debug(fmt.Sprintf("job %s started", name), fields)
+		return job.Handler(ctx, delivery)
+	}
+}
+
+// run keeps a runner going until the context is cancelled, it is restarted with a backoff when it fails or panics
+func run(ctx context.Context, name string, instance int, job Job) {
+	delay := restartDelay
+	for {
+		jobID := fmt.Sprintf("%s_%d", name, instance)
+		err := runOnce(withJobID(ctx, jobID), job)
+		if ctx.Err() != nil {
+			return
+		}
+
+		fields := map[string]interface{}{"job": name, "job_id": jobID}
+		if err != nil {
+			log.Error(fmt.Sprintf("worker job %s stopped: %v, restarting in %s", name, err, delay), fields)
+		} else {
+			log.Warn(fmt.Sprintf("worker job %s returned, restarting in %s", name, delay), fields)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxRestartDelay)
+	}
+}
+
+func runOnce(ctx context.Context, job Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v\n%s", r, debug.Stack())
+		}
+	}()
+
+	return job.Run(ctx)
+}
+
+type jobIDKey struct{}
+
+func withJobID(ctx context.Context, jobID string) context.Context {
+	return context.WithValue(ctx, jobIDKey{}, jobID)
+}
+
+// JobID returns the ID of the job being handled, handlers add it to their own logs
+func JobID(ctx context.Context) string {
+	jobID, _ := ctx.Value(jobIDKey{}).(string)
+	return jobID
+}
@@ -0,0 +1,95 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"server/config/env"
+	"server/config/log"
+
+	"github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigure(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		env.Cfg.Worker = env.Worker{}
+
+		job := configure("test_job", Job{})
+		assert.Equal(t, DEFAULT_CONCURRENCY, job.Concurrency)
+		assert.Equal(t, DEFAULT_TIMEOUT, job.Timeout)
+		assert.Equal(t, 0, job.Prefetch)
+	})
+
+	t.Run("Config Overrides Job", func(t *testing.T) {
+		env.Cfg.Worker = env.Worker{
+			Concurrency: map[string]int{"test_job": 4},
+			Prefetch:    map[string]int{"test_job": 8},
+			Timeouts:    map[string]time.Duration{"test_job": time.Minute},
+		}
+		defer func() { env.Cfg.Worker = env.Worker{} }()
+
+		job := configure("test_job", Job{Concurrency: 2, Prefetch: 1, Timeout: time.Hour})
+		assert.Equal(t, 4, job.Concurrency)
+		assert.Equal(t, 8, job.Prefetch)
+		assert.Equal(t, time.Minute, job.Timeout)
+	})
+}
+
+func TestHandle(t *testing.T) {
+	log.SetupLogger()
+
+	t.Run("Job ID And Timeout", func(t *testing.T) {
+		handler := handle("test_job", Job{
+			Timeout: time.Minute,
+			Handler: func(ctx context.Context, delivery amqp091.Delivery) error {
+				assert.Equal(t, "message-1", JobID(ctx))
+				_, ok := ctx.Deadline()
+				assert.True(t, ok)
+				return nil
+			},
+		})
+
+		assert.Nil(t, handler(context.Background(), amqp091.Delivery{MessageId: "message-1"}))
+	})
+
+	t.Run("Returns Error", func(t *testing.T) {
+		cause := errors.New("failed")
+		handler := handle("test_job", Job{
+			Timeout: time.Minute,
+			Handler: func(ctx context.Context, delivery amqp091.Delivery) error { return cause },
+		})
+
+		assert.ErrorIs(t, handler(context.Background(), amqp091.Delivery{}), cause)
+	})
+
+	t.Run("Recovers Panic", func(t *testing.T) {
+		handler := handle("test_job", Job{
+			Timeout: time.Minute,
+			Handler: func(ctx context.Context, delivery amqp091.Delivery) error { panic("boom") },
+		})
+
+		err := handler(context.Background(), amqp091.Delivery{})
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "boom")
+	})
+}
+
+func TestRunCron(t *testing.T) {
+	t.Run("Invalid Schedule", func(t *testing.T) {
+		err := RunCron(context.Background(), "not a schedule", func(ctx context.Context, at time.Time) {})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Stops With Context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := RunCron(ctx, "CRON_TZ=UTC 0 18 * * *", func(ctx context.Context, at time.Time) {
+			t.Error("run must not be called")
+		})
+		assert.Nil(t, err)
+	})
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"server/internal/types/view"
+	"server/internal/utils/data"
+
+	"gorm.io/gorm"
+)
+
+type AnalyticsRepository interface {
+	GetCashflow(ctx context.Context, tx Transaction, userID string, from, to time.Time, granularity, groupBy string) ([]view.Cashflow, error)
+	GetWalletBalances(ctx context.Context, tx Transaction, userID string) ([]view.WalletBalance, error)
+	GetWalletDailyFlows(ctx context.Context, tx Transaction, userID string, from time.Time) ([]view.WalletDailyFlow, error)
+	GetWalletTransactions(ctx context.Context, tx Transaction, userID string, from time.Time) ([]view.WalletTransaction, error)
+}
+
+type analyticsRepository struct {
+	db *gorm.DB
+}
+
+func NewAnalyticsRepository(db *gorm.DB) AnalyticsRepository {
+	return &analyticsRepository{db}
+}
+
+// Helper untuk mendapatkan DB instance (transaksi atau biasa)
+func (analytics_repo *analyticsRepository) getDB(ctx context.Context, tx Transaction) (*gorm.DB, error) {
+	if tx != nil {
+		gormTx, ok := tx.(*GormTx) // Type assertion ke GORM transaction
+		if !ok {
+			return nil, errors.New("invalid transaction type")
+		}
+		return gormTx.db.WithContext(ctx), nil
+	}
+	return analytics_repo.db.WithContext(ctx), nil
+}
+
+// cashflowGroups are the key and label columns of each grouping, an empty grouping sums everything into one group
+var cashflowGroups = map[string][2]string{
+	"":                                     {"''", "''"},
+	data.CASHFLOW_GROUP_BY_CATEGORY:        {"c.id::text", "c.name"},
+	data.CASHFLOW_GROUP_BY_PARENT_CATEGORY: {"COALESCE(parent.id, c.id)::text", "COALESCE(parent.name, c.name)"},
+	data.CASHFLOW_GROUP_BY_WALLET:          {"w.id::text", "w.name"},
+	data.CASHFLOW_GROUP_BY_WALLET_TYPE:     {"wt.type", "wt.type"},
+}
+
+// GetCashflow sums the income and expense of the user's transactions dated from..to (inclusive) per period of
+// the granularity and per group. Transfers between the user's wallets are not income or expense and are left out.
+func (analytics_repo *analyticsRepository) GetCashflow(ctx context.Context, tx Transaction, userID string, from, to time.Time, granularity, groupBy string) ([]view.Cashflow, error) {
+	db, err := analytics_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	group, ok := cashflowGroups[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("unknown cash flow grouping '%s'", groupBy)
+	}
+
+	query := fmt.Sprintf(`
+	SELECT
+		date_trunc(@granularity, t.transaction_date) AS period,
+		%[1]s AS group_key,
+		%[2]s AS group_label,
+		COALESCE(SUM(CASE WHEN c.type = 'income' THEN t.amount END), 0) AS income,
+		COALESCE(SUM(CASE WHEN c.type = 'expense' THEN t.amount END), 0) AS expense
+	FROM transactions t
+	JOIN wallets w ON w.id = t.wallet_id
+	JOIN wallet_types wt ON wt.id = w.wallet_type_id
+	JOIN categories c ON c.id = t.category_id
+	LEFT JOIN categories parent ON parent.id = c.parent_id
+	WHERE w.user_id = @user_id
+		AND w.deleted_at IS NULL
+		AND t.deleted_at IS NULL
+		AND c.type IN ('income', 'expense')
+		AND t.transaction_date >= @from::date
+		AND t.transaction_date < @to::date + 1
+	GROUP BY 1, 2, 3
+	ORDER BY 1, 3`, group[0], group[1])
+
+	var cashflow []view.Cashflow
+	err = db.Raw(query, map[string]interface{}{
+		"granularity": granularity,
+		"user_id":     userID,
+		"from":        from.Format("2006-01-02"),
+		"to":          to.Format("2006-01-02"),
+	}).Scan(&cashflow).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return cashflow, nil
+}
+
+func (analytics_repo *analyticsRepository) GetWalletBalances(ctx context.Context, tx Transaction, userID string) ([]view.WalletBalance, error) {
+	db, err := analytics_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var wallets []view.WalletBalance
+	err = db.Raw(`
+	SELECT w.id AS wallet_id, w.name, wt.type AS wallet_type, w.balance
+	FROM wallets w
+	JOIN wallet_types wt ON wt.id = w.wallet_type_id
+	WHERE w.user_id = ? AND w.deleted_at IS NULL
+	ORDER BY w.name`, userID).Scan(&wallets).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return wallets, nil
+}
+
+// GetWalletDailyFlows sums what the transactions dated from the date on changed each wallet's balance per day.
+// It reads the transactions rather than user_wallet_daily_flows so the history matches the balances right away.
+func (analytics_repo *analyticsRepository) GetWalletDailyFlows(ctx context.Context, tx Transaction, userID string, from time.Time) ([]view.WalletDailyFlow, error) {
+	db, err := analytics_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var flows []view.WalletDailyFlow
+	err = db.Raw(`
+	SELECT
+		t.wallet_id,
+		DATE(t.transaction_date)::timestamp AS date,
+		SUM(
+		CASE
+			WHEN c.type = 'expense' OR (c.type = 'fund_transfer' AND c.name = 'Cash Out') THEN -1 * t.amount
+			WHEN c.type = 'income' OR (c.type = 'fund_transfer' AND c.name = 'Cash In') THEN t.amount
+			ELSE 0
+		END
+		) AS amount
+	FROM transactions t
+	JOIN wallets w ON w.id = t.wallet_id
+	JOIN categories c ON c.id = t.category_id
+	WHERE w.user_id = ?
+		AND w.deleted_at IS NULL
+		AND t.deleted_at IS NULL
+		AND t.transaction_date >= ?::date
+	GROUP BY t.wallet_id, DATE(t.transaction_date)`, userID, from.Format("2006-01-02")).Scan(&flows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return flows, nil
+}
+
+// GetWalletTransactions returns the transactions of the user's wallets dated from the date on, signed like the
+// balance change they made
+func (analytics_repo *analyticsRepository) GetWalletTransactions(ctx context.Context, tx Transaction, userID string, from time.Time) ([]view.WalletTransaction, error) {
+	db, err := analytics_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var transactions []view.WalletTransaction
+	err = db.Raw(`
+	SELECT
+		t.wallet_id,
+		t.category_id,
+		c.name AS category_name,
+		c.type AS category_type,
+		CASE
+			WHEN c.type = 'expense' OR (c.type = 'fund_transfer' AND c.name = 'Cash Out') THEN -1 * t.amount
+			WHEN c.type = 'income' OR (c.type = 'fund_transfer' AND c.name = 'Cash In') THEN t.amount
+			ELSE 0
+		END AS amount,
+		DATE(t.transaction_date)::timestamp AS transaction_date
+	FROM transactions t
+	JOIN wallets w ON w.id = t.wallet_id
+	JOIN categories c ON c.id = t.category_id
+	WHERE w.user_id = ?
+		AND w.deleted_at IS NULL
+		AND t.deleted_at IS NULL
+		AND t.transaction_date >= ?::date
+	ORDER BY t.transaction_date`, userID, from.Format("2006-01-02")).Scan(&transactions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"server/internal/types/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AttachmentBlobsRepository interface {
+	GetAllBlobs(ctx context.Context, tx Transaction) ([]entity.AttachmentBlobs, error)
+	FindBlobByHash(ctx context.Context, tx Transaction, hash string) (*entity.AttachmentBlobs, error)
+	LockBlobByHash(ctx context.Context, tx Transaction, hash string) (*entity.AttachmentBlobs, error)
+	AcquireBlob(ctx context.Context, tx Transaction, blob entity.AttachmentBlobs) (entity.AttachmentBlobs, error)
+	ReleaseBlob(ctx context.Context, tx Transaction, id uuid.UUID) (entity.AttachmentBlobs, error)
+	UpdateBlob(ctx context.Context, tx Transaction, blob entity.AttachmentBlobs) (entity.AttachmentBlobs, error)
+	DeleteUnreferencedBlob(ctx context.Context, tx Transaction, id uuid.UUID) (bool, error)
+}
+
+type attachmentBlobsRepository struct {
+	db *gorm.DB
+}
+
+func NewAttachmentBlobsRepository(db *gorm.DB) AttachmentBlobsRepository {
+	return &attachmentBlobsRepository{db}
+}
+
+// Helper untuk mendapatkan DB instance (transaksi atau biasa)
+func (blob_repo *attachmentBlobsRepository) getDB(ctx context.Context, tx Transaction) (*gorm.DB, error) {
+	if tx != nil {
+		gormTx, ok := tx.(*GormTx) // Type assertion ke GORM transaction
+		if !ok {
+			return nil, errors.New("invalid transaction type")
+		}
+		return gormTx.db.WithContext(ctx), nil
+	}
+	return blob_repo.db.WithContext(ctx), nil
+}
+
+func (blob_repo *attachmentBlobsRepository) GetAllBlobs(ctx context.Context, tx Transaction) ([]entity.AttachmentBlobs, error) {
+	db, err := blob_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var blobs []entity.AttachmentBlobs
+	if err := db.Find(&blobs).Error; err != nil {
+		return nil, err
+	}
+
+	return blobs, nil
+}
+
+// FindBlobByHash returns nil without error when no blob with the hash exists
+func (blob_repo *attachmentBlobsRepository) FindBlobByHash(ctx context.Context, tx Transaction, hash string) (*entity.AttachmentBlobs, error) {
+	db, err := blob_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var blobs []entity.AttachmentBlobs
+	if err := db.Where("hash = ?", hash).Limit(1).Find(&blobs).Error; err != nil {
+		return nil, err
+	}
+	if len(blobs) == 0 {
+		return nil, nil
+	}
+
+	return &blobs[0], nil
+}
+
+// LockBlobByHash is FindBlobByHash holding a row lock until the transaction ends, so the blob cannot lose its
+// last reference and be deleted before the caller acquires it. A blob deleted while waiting is not returned.
+func (blob_repo *attachmentBlobsRepository) LockBlobByHash(ctx context.Context, tx Transaction, hash string) (*entity.AttachmentBlobs, error) {
+	db, err := blob_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var blobs []entity.AttachmentBlobs
+	if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("hash = ?", hash).Limit(1).Find(&blobs).Error; err != nil {
+		return nil, err
+	}
+	if len(blobs) == 0 {
+		return nil, nil
+	}
+
+	return &blobs[0], nil
+}
+
+// AcquireBlob inserts the blob or, when the hash already exists, takes another reference on it
+func (blob_repo *attachmentBlobsRepository) AcquireBlob(ctx context.Context, tx Transaction, blob entity.AttachmentBlobs) (entity.AttachmentBlobs, error) {
+	db, err := blob_repo.getDB(ctx, tx)
+	if err != nil {
+		return entity.AttachmentBlobs{}, err
+	}
+
+	blob.RefCount = 1
+	err = db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "hash"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"ref_count":  gorm.Expr("attachment_blobs.ref_count + 1"),
+			"updated_at": time.Now(),
+		}),
+	}).Create(&blob).Error
+	if err != nil {
+		return entity.AttachmentBlobs{}, err
+	}
+
+	var acquired entity.AttachmentBlobs
+	if err := db.First(&acquired, "hash = ?", blob.Hash).Error; err != nil {
+		return entity.AttachmentBlobs{}, err
+	}
+
+	return acquired, nil
+}
+
+// ReleaseBlob drops one reference and returns the blob with its remaining reference count
+func (blob_repo *attachmentBlobsRepository) ReleaseBlob(ctx context.Context, tx Transaction, id uuid.UUID) (entity.AttachmentBlobs, error) {
+	db, err := blob_repo.getDB(ctx, tx)
+	if err != nil {
+		return entity.AttachmentBlobs{}, err
+	}
+
+	var blob entity.AttachmentBlobs
+	result := db.Model(&blob).Clauses(clause.Returning{}).Where("id = ?", id).UpdateColumn("ref_count", gorm.Expr("GREATEST(ref_count - 1, 0)"))
+	if result.Error != nil {
+		return entity.AttachmentBlobs{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entity.AttachmentBlobs{}, gorm.ErrRecordNotFound
+	}
+
+	return blob, nil
+}
+
+// UpdateBlob saves the blob metadata, the reference count is only changed by AcquireBlob and ReleaseBlob
+func (blob_repo *attachmentBlobsRepository) UpdateBlob(ctx context.Context, tx Transaction, blob entity.AttachmentBlobs) (entity.AttachmentBlobs, error) {
+	db, err := blob_repo.getDB(ctx, tx)
+	if err != nil {
+		return entity.AttachmentBlobs{}, err
+	}
+
+	if err := db.Omit("ref_count").Save(&blob).Error; err != nil {
+		return entity.AttachmentBlobs{}, err
+	}
+
+	return blob, nil
+}
+
+// DeleteUnreferencedBlob removes the blob only if nobody took a new reference in the meantime
+func (blob_repo *attachmentBlobsRepository) DeleteUnreferencedBlob(ctx context.Context, tx Transaction, id uuid.UUID) (bool, error) {
+	db, err := blob_repo.getDB(ctx, tx)
+	if err != nil {
+		return false, err
+	}
+
+	result := db.Unscoped().Where("id = ? AND ref_count <= 0", id).Delete(&entity.AttachmentBlobs{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"server/internal/types/entity"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type attachmentBlobsRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func NewAttachmentBlobsRepositoryMock() *attachmentBlobsRepositoryMock {
+	return &attachmentBlobsRepositoryMock{Mock: mock.Mock{}}
+}
+
+func (blob_repo *attachmentBlobsRepositoryMock) GetAllBlobs(ctx context.Context, tx Transaction) ([]entity.AttachmentBlobs, error) {
+	arguments := blob_repo.Mock.Called(ctx, tx)
+
+	result, ok := arguments.Get(0).([]entity.AttachmentBlobs)
+	if !ok {
+		return nil, errors.New("error getting all blobs")
+	}
+
+	return result, nil
+}
+
+func (blob_repo *attachmentBlobsRepositoryMock) FindBlobByHash(ctx context.Context, tx Transaction, hash string) (*entity.AttachmentBlobs, error) {
+	arguments := blob_repo.Mock.Called(ctx, tx, hash)
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).(*entity.AttachmentBlobs)
+	return result, nil
+}
+
+func (blob_repo *attachmentBlobsRepositoryMock) LockBlobByHash(ctx context.Context, tx Transaction, hash string) (*entity.AttachmentBlobs, error) {
+	arguments := blob_repo.Mock.Called(ctx, tx, hash)
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).(*entity.AttachmentBlobs)
+	return result, nil
+}
+
+func (blob_repo *attachmentBlobsRepositoryMock) AcquireBlob(ctx context.Context, tx Transaction, blob entity.AttachmentBlobs) (entity.AttachmentBlobs, error) {
+	arguments := blob_repo.Mock.Called(ctx, tx, blob)
+	if arguments.Get(1) != nil {
+		return entity.AttachmentBlobs{}, arguments.Error(1)
+	}
+
+	// * Without a return value the blob is acquired as given, like a new hash
+	result, ok := arguments.Get(0).(entity.AttachmentBlobs)
+	if !ok {
+		blob.RefCount = 1
+		return blob, nil
+	}
+
+	return result, nil
+}
+
+func (blob_repo *attachmentBlobsRepositoryMock) ReleaseBlob(ctx context.Context, tx Transaction, id uuid.UUID) (entity.AttachmentBlobs, error) {
+	arguments := blob_repo.Mock.Called(ctx, tx, id)
+	if arguments.Get(1) != nil {
+		return entity.AttachmentBlobs{}, arguments.Error(1)
+	}
+
+	result, ok := arguments.Get(0).(entity.AttachmentBlobs)
+	if !ok {
+		return entity.AttachmentBlobs{}, errors.New("error releasing blob")
+	}
+
+	return result, nil
+}
+
+func (blob_repo *attachmentBlobsRepositoryMock) UpdateBlob(ctx context.Context, tx Transaction, blob entity.AttachmentBlobs) (entity.AttachmentBlobs, error) {
+	arguments := blob_repo.Mock.Called(ctx, tx, blob)
+
+	result, ok := arguments.Get(0).(entity.AttachmentBlobs)
+	if !ok {
+		return entity.AttachmentBlobs{}, errors.New("error updating blob")
+	}
+
+	return result, nil
+}
+
+func (blob_repo *attachmentBlobsRepositoryMock) DeleteUnreferencedBlob(ctx context.Context, tx Transaction, id uuid.UUID) (bool, error) {
+	arguments := blob_repo.Mock.Called(ctx, tx, id)
+	if arguments.Get(1) != nil {
+		return false, arguments.Error(1)
+	}
+
+	return arguments.Bool(0), nil
+}
@@ -3,16 +3,22 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"server/internal/types/entity"
+	"server/internal/utils/data"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type AttachmentsRepository interface {
 	GetAllAttachments(ctx context.Context, tx Transaction) ([]entity.Attachments, error)
 	GetAttachmentByID(ctx context.Context, tx Transaction, id string) (entity.Attachments, error)
-	GetAttachmentsByTransactionID(ctx context.Context, tx Transaction, transactionID string) ([]entity.Attachments, error)
+	GetAttachmentsByOwner(ctx context.Context, tx Transaction, ownerType, ownerID string) ([]entity.Attachments, error)
+	GetAttachmentOwnerUserID(ctx context.Context, tx Transaction, ownerType, ownerID string) (string, error)
+	GetAttachmentsWithoutThumbnails(ctx context.Context, tx Transaction, limit int) ([]entity.Attachments, error)
+	GetUserAttachmentsByBlobID(ctx context.Context, tx Transaction, blobID uuid.UUID, userID string) ([]entity.Attachments, error)
 	CreateAttachment(ctx context.Context, tx Transaction, attachment entity.Attachments) (entity.Attachments, error)
 	UpdateAttachment(ctx context.Context, tx Transaction, attachment entity.Attachments) (entity.Attachments, error)
 	DeleteAttachment(ctx context.Context, tx Transaction, attachment entity.Attachments) (entity.Attachments, error)
@@ -66,14 +72,84 @@ func (attachments_repo *attachmentsRepository) GetAttachmentByID(ctx context.Con
 	return attachment, nil
 }
 
-func (attachments_repo *attachmentsRepository) GetAttachmentsByTransactionID(ctx context.Context, tx Transaction, transactionID string) ([]entity.Attachments, error) {
+func (attachments_repo *attachmentsRepository) GetAttachmentsByOwner(ctx context.Context, tx Transaction, ownerType, ownerID string) ([]entity.Attachments, error) {
 	db, err := attachments_repo.getDB(ctx, tx)
 	if err != nil {
 		return nil, err
 	}
 
 	var attachments []entity.Attachments
-	if err := db.Where("transaction_id = ?", transactionID).Find(&attachments).Error; err != nil {
+	if err := db.Where("owner_type = ? AND owner_id = ?", ownerType, ownerID).Order("created_at").Find(&attachments).Error; err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+// GetAttachmentOwnerUserID returns the user owning the resource, gorm.ErrRecordNotFound when the resource does not exist
+func (attachments_repo *attachmentsRepository) GetAttachmentOwnerUserID(ctx context.Context, tx Transaction, ownerType, ownerID string) (string, error) {
+	db, err := attachments_repo.getDB(ctx, tx)
+	if err != nil {
+		return "", err
+	}
+
+	var query *gorm.DB
+	switch ownerType {
+	case data.ATTACHMENT_OWNER_TRANSACTION:
+		query = db.Table("transactions").
+			Select("wallets.user_id").
+			Joins("JOIN wallets ON wallets.id = transactions.wallet_id").
+			Where("transactions.id = ? AND transactions.deleted_at IS NULL", ownerID)
+	case data.ATTACHMENT_OWNER_WALLET:
+		query = db.Table("wallets").Select("user_id").Where("id = ? AND deleted_at IS NULL", ownerID)
+	case data.ATTACHMENT_OWNER_INVESTMENT:
+		query = db.Table("investments").Select("user_id").Where("id = ? AND deleted_at IS NULL", ownerID)
+	default:
+		return "", fmt.Errorf("unknown attachment owner type '%s'", ownerType)
+	}
+
+	var userIDs []string
+	if err := query.Limit(1).Pluck("user_id", &userIDs).Error; err != nil {
+		return "", err
+	}
+	if len(userIDs) == 0 {
+		return "", gorm.ErrRecordNotFound
+	}
+
+	return userIDs[0], nil
+}
+
+func (attachments_repo *attachmentsRepository) GetAttachmentsWithoutThumbnails(ctx context.Context, tx Transaction, limit int) ([]entity.Attachments, error) {
+	db, err := attachments_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var attachments []entity.Attachments
+	if err := db.Where("image <> '' AND format = 'image' AND (thumbnail_small IS NULL OR thumbnail_small = '')").Order("created_at").Limit(limit).Find(&attachments).Error; err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+// GetUserAttachmentsByBlobID returns attachments of the same blob on any resource owned by the user
+func (attachments_repo *attachmentsRepository) GetUserAttachmentsByBlobID(ctx context.Context, tx Transaction, blobID uuid.UUID, userID string) ([]entity.Attachments, error) {
+	db, err := attachments_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var attachments []entity.Attachments
+	err = db.Joins("LEFT JOIN transactions ON attachments.owner_type = ? AND transactions.id = attachments.owner_id AND transactions.deleted_at IS NULL", data.ATTACHMENT_OWNER_TRANSACTION).
+		Joins("LEFT JOIN wallets transaction_wallets ON transaction_wallets.id = transactions.wallet_id").
+		Joins("LEFT JOIN wallets ON attachments.owner_type = ? AND wallets.id = attachments.owner_id AND wallets.deleted_at IS NULL", data.ATTACHMENT_OWNER_WALLET).
+		Joins("LEFT JOIN investments ON attachments.owner_type = ? AND investments.id = attachments.owner_id AND investments.deleted_at IS NULL", data.ATTACHMENT_OWNER_INVESTMENT).
+		Where("attachments.blob_id = ?", blobID).
+		Where("COALESCE(transaction_wallets.user_id, wallets.user_id, investments.user_id) = ?", userID).
+		Order("attachments.created_at").
+		Find(&attachments).Error
+	if err != nil {
 		return nil, err
 	}
 
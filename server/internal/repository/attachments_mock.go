@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"server/internal/types/entity"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type attachmentsRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func NewAttachmentsRepositoryMock() *attachmentsRepositoryMock {
+	return &attachmentsRepositoryMock{Mock: mock.Mock{}}
+}
+
+func (attachments_repo *attachmentsRepositoryMock) GetAllAttachments(ctx context.Context, tx Transaction) ([]entity.Attachments, error) {
+	arguments := attachments_repo.Mock.Called(ctx, tx)
+
+	result, ok := arguments.Get(0).([]entity.Attachments)
+	if !ok {
+		return nil, errors.New("error getting all attachments")
+	}
+
+	return result, nil
+}
+
+func (attachments_repo *attachmentsRepositoryMock) GetAttachmentByID(ctx context.Context, tx Transaction, id string) (entity.Attachments, error) {
+	arguments := attachments_repo.Mock.Called(ctx, tx, id)
+
+	result, ok := arguments.Get(0).(entity.Attachments)
+	if !ok || arguments.Get(1) != nil {
+		return entity.Attachments{}, errors.New("error getting attachment by ID")
+	}
+
+	return result, nil
+}
+
+func (attachments_repo *attachmentsRepositoryMock) GetAttachmentsByOwner(ctx context.Context, tx Transaction, ownerType, ownerID string) ([]entity.Attachments, error) {
+	arguments := attachments_repo.Mock.Called(ctx, tx, ownerType, ownerID)
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).([]entity.Attachments)
+	return result, nil
+}
+
+func (attachments_repo *attachmentsRepositoryMock) GetAttachmentOwnerUserID(ctx context.Context, tx Transaction, ownerType, ownerID string) (string, error) {
+	arguments := attachments_repo.Mock.Called(ctx, tx, ownerType, ownerID)
+	if arguments.Get(1) != nil {
+		return "", arguments.Error(1)
+	}
+
+	return arguments.String(0), nil
+}
+
+func (attachments_repo *attachmentsRepositoryMock) GetAttachmentsWithoutThumbnails(ctx context.Context, tx Transaction, limit int) ([]entity.Attachments, error) {
+	arguments := attachments_repo.Mock.Called(ctx, tx, limit)
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).([]entity.Attachments)
+	return result, nil
+}
+
+func (attachments_repo *attachmentsRepositoryMock) GetUserAttachmentsByBlobID(ctx context.Context, tx Transaction, blobID uuid.UUID, userID string) ([]entity.Attachments, error) {
+	arguments := attachments_repo.Mock.Called(ctx, tx, blobID, userID)
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).([]entity.Attachments)
+	return result, nil
+}
+
+func (attachments_repo *attachmentsRepositoryMock) CreateAttachment(ctx context.Context, tx Transaction, attachment entity.Attachments) (entity.Attachments, error) {
+	arguments := attachments_repo.Mock.Called(ctx, tx, attachment)
+	if arguments.Get(1) != nil {
+		return entity.Attachments{}, arguments.Error(1)
+	}
+
+	// * Without a return value the attachment is created as given
+	result, ok := arguments.Get(0).(entity.Attachments)
+	if !ok {
+		attachment.ID = uuid.New()
+		return attachment, nil
+	}
+
+	return result, nil
+}
+
+func (attachments_repo *attachmentsRepositoryMock) UpdateAttachment(ctx context.Context, tx Transaction, attachment entity.Attachments) (entity.Attachments, error) {
+	arguments := attachments_repo.Mock.Called(ctx, tx, attachment)
+
+	result, ok := arguments.Get(0).(entity.Attachments)
+	if !ok {
+		return entity.Attachments{}, errors.New("error updating attachment")
+	}
+
+	return result, nil
+}
+
+func (attachments_repo *attachmentsRepositoryMock) DeleteAttachment(ctx context.Context, tx Transaction, attachment entity.Attachments) (entity.Attachments, error) {
+	arguments := attachments_repo.Mock.Called(ctx, tx, attachment)
+	if arguments.Get(1) != nil {
+		return entity.Attachments{}, arguments.Error(1)
+	}
+
+	return attachment, nil
+}
@@ -10,6 +10,7 @@ import (
 	"server/internal/types/entity"
 	"server/internal/types/view"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -17,6 +18,7 @@ type CategoriesRepository interface {
 	GetAllCategories(ctx context.Context, tx Transaction) ([]entity.Categories, error)
 	GetCategoryByID(ctx context.Context, tx Transaction, id string) (entity.Categories, error)
 	GetCategoriesByType(ctx context.Context, tx Transaction, typeCategory string) ([]view.ViewCategoriesGroupByType, error)
+	GetCategoryByName(ctx context.Context, tx Transaction, name, typeCategory string, parentID *uuid.UUID) (entity.Categories, error)
 	CreateCategory(ctx context.Context, tx Transaction, category entity.Categories) (entity.Categories, error)
 	UpdateCategory(ctx context.Context, tx Transaction, category entity.Categories) (entity.Categories, error)
 	DeleteCategory(ctx context.Context, tx Transaction, category entity.Categories) (entity.Categories, error)
@@ -70,6 +72,28 @@ func (category_repo *categoryRepository) GetCategoryByID(ctx context.Context, tx
 	return category, nil
 }
 
+// GetCategoryByName finds a category by name and type, parentID nil looks for a top level category
+func (category_repo *categoryRepository) GetCategoryByName(ctx context.Context, tx Transaction, name, typeCategory string, parentID *uuid.UUID) (entity.Categories, error) {
+	db, err := category_repo.getDB(ctx, tx)
+	if err != nil {
+		return entity.Categories{}, err
+	}
+
+	query := db.Where("name = ? AND type = ?", name, typeCategory)
+	if parentID == nil {
+		query = query.Where("parent_id IS NULL")
+	} else {
+		query = query.Where("parent_id = ?", *parentID)
+	}
+
+	var category entity.Categories
+	if err := query.First(&category).Error; err != nil {
+		return entity.Categories{}, err
+	}
+
+	return category, nil
+}
+
 func (category_repo *categoryRepository) GetCategoriesByType(ctx context.Context, tx Transaction, typeCategory string) ([]view.ViewCategoriesGroupByType, error) {
 	db, err := category_repo.getDB(ctx, tx)
 	if err != nil {
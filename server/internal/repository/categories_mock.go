@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+
+	"server/internal/types/entity"
+	"server/internal/types/view"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type categoriesRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func NewCategoriesRepositoryMock() *categoriesRepositoryMock {
+	return &categoriesRepositoryMock{Mock: mock.Mock{}}
+}
+
+func (category_repo *categoriesRepositoryMock) GetAllCategories(ctx context.Context, tx Transaction) ([]entity.Categories, error) {
+	arguments := category_repo.Mock.Called(ctx, tx)
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).([]entity.Categories)
+	return result, nil
+}
+
+func (category_repo *categoriesRepositoryMock) GetCategoryByID(ctx context.Context, tx Transaction, id string) (entity.Categories, error) {
+	arguments := category_repo.Mock.Called(ctx, tx, id)
+	if arguments.Get(1) != nil {
+		return entity.Categories{}, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).(entity.Categories)
+	return result, nil
+}
+
+func (category_repo *categoriesRepositoryMock) GetCategoriesByType(ctx context.Context, tx Transaction, typeCategory string) ([]view.ViewCategoriesGroupByType, error) {
+	arguments := category_repo.Mock.Called(ctx, tx, typeCategory)
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).([]view.ViewCategoriesGroupByType)
+	return result, nil
+}
+
+func (category_repo *categoriesRepositoryMock) GetCategoryByName(ctx context.Context, tx Transaction, name, typeCategory string, parentID *uuid.UUID) (entity.Categories, error) {
+	arguments := category_repo.Mock.Called(ctx, tx, name, typeCategory, parentID)
+	if arguments.Get(1) != nil {
+		return entity.Categories{}, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).(entity.Categories)
+	return result, nil
+}
+
+func (category_repo *categoriesRepositoryMock) CreateCategory(ctx context.Context, tx Transaction, category entity.Categories) (entity.Categories, error) {
+	arguments := category_repo.Mock.Called(ctx, tx, category)
+	if arguments.Get(1) != nil {
+		return entity.Categories{}, arguments.Error(1)
+	}
+
+	// * Without a return value the category is created as given
+	result, ok := arguments.Get(0).(entity.Categories)
+	if !ok {
+		if category.ID == uuid.Nil {
+			category.ID = uuid.New()
+		}
+		return category, nil
+	}
+
+	return result, nil
+}
+
+func (category_repo *categoriesRepositoryMock) UpdateCategory(ctx context.Context, tx Transaction, category entity.Categories) (entity.Categories, error) {
+	arguments := category_repo.Mock.Called(ctx, tx, category)
+	if arguments.Get(1) != nil {
+		return entity.Categories{}, arguments.Error(1)
+	}
+
+	// * Without a return value the category is returned as given
+	result, ok := arguments.Get(0).(entity.Categories)
+	if !ok {
+		return category, nil
+	}
+
+	return result, nil
+}
+
+func (category_repo *categoriesRepositoryMock) DeleteCategory(ctx context.Context, tx Transaction, category entity.Categories) (entity.Categories, error) {
+	arguments := category_repo.Mock.Called(ctx, tx, category)
+	if arguments.Get(1) != nil {
+		return entity.Categories{}, arguments.Error(1)
+	}
+
+	// * Without a return value the category is returned as given
+	result, ok := arguments.Get(0).(entity.Categories)
+	if !ok {
+		return category, nil
+	}
+
+	return result, nil
+}
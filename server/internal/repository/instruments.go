@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"server/internal/types/entity"
+
+	"gorm.io/gorm"
+)
+
+type InstrumentsRepository interface {
+	GetInstruments(ctx context.Context, tx Transaction, search, kind, investmentTypeID string) ([]entity.Instruments, error)
+	GetInstrumentByID(ctx context.Context, tx Transaction, id string) (entity.Instruments, error)
+	GetInstrumentByCode(ctx context.Context, tx Transaction, code string) (entity.Instruments, error)
+	GetInstrumentsByCodes(ctx context.Context, tx Transaction, codes []string) ([]entity.Instruments, error)
+	GetInstrumentsByIDs(ctx context.Context, tx Transaction, ids []string) ([]entity.Instruments, error)
+	CreateInstrument(ctx context.Context, tx Transaction, instrument entity.Instruments) (entity.Instruments, error)
+	UpdateInstrument(ctx context.Context, tx Transaction, instrument entity.Instruments) (entity.Instruments, error)
+	DeleteInstrument(ctx context.Context, tx Transaction, instrument entity.Instruments) (entity.Instruments, error)
+	CountInvestmentsByInstrument(ctx context.Context, tx Transaction, id string) (int64, error)
+}
+
+type instrumentsRepository struct {
+	db *gorm.DB
+}
+
+func NewInstrumentsRepository(db *gorm.DB) InstrumentsRepository {
+	return &instrumentsRepository{db}
+}
+
+// Helper untuk mendapatkan DB instance (transaksi atau biasa)
+func (instrument_repo *instrumentsRepository) getDB(ctx context.Context, tx Transaction) (*gorm.DB, error) {
+	if tx != nil {
+		gormTx, ok := tx.(*GormTx) // Type assertion ke GORM transaction
+		if !ok {
+			return nil, errors.New("invalid transaction type")
+		}
+		return gormTx.db.WithContext(ctx), nil
+	}
+	return instrument_repo.db.WithContext(ctx), nil
+}
+
+func (instrument_repo *instrumentsRepository) GetInstruments(ctx context.Context, tx Transaction, search, kind, investmentTypeID string) ([]entity.Instruments, error) {
+	db, err := instrument_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := db.Preload("InvestmentTypes")
+	if search != "" {
+		pattern := "%" + strings.ToUpper(strings.TrimSpace(search)) + "%"
+		query = query.Where("code LIKE ? OR UPPER(name) LIKE ?", pattern, pattern)
+	}
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	if investmentTypeID != "" {
+		query = query.Where("investment_type_id = ?", investmentTypeID)
+	}
+
+	var instruments []entity.Instruments
+	if err := query.Order("code").Find(&instruments).Error; err != nil {
+		return nil, err
+	}
+	return instruments, nil
+}
+
+func (instrument_repo *instrumentsRepository) GetInstrumentByID(ctx context.Context, tx Transaction, id string) (entity.Instruments, error) {
+	db, err := instrument_repo.getDB(ctx, tx)
+	if err != nil {
+		return entity.Instruments{}, err
+	}
+
+	var instrument entity.Instruments
+	if err := db.Preload("InvestmentTypes").First(&instrument, "id = ?", id).Error; err != nil {
+		return entity.Instruments{}, err
+	}
+	return instrument, nil
+}
+
+// GetInstrumentByCode expects a normalized code, see portfolio.InstrumentKey
+func (instrument_repo *instrumentsRepository) GetInstrumentByCode(ctx context.Context, tx Transaction, code string) (entity.Instruments, error) {
+	db, err := instrument_repo.getDB(ctx, tx)
+	if err != nil {
+		return entity.Instruments{}, err
+	}
+
+	var instrument entity.Instruments
+	if err := db.First(&instrument, "code = ?", code).Error; err != nil {
+		return entity.Instruments{}, err
+	}
+	return instrument, nil
+}
+
+func (instrument_repo *instrumentsRepository) GetInstrumentsByCodes(ctx context.Context, tx Transaction, codes []string) ([]entity.Instruments, error) {
+	db, err := instrument_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var instruments []entity.Instruments
+	if len(codes) == 0 {
+		return instruments, nil
+	}
+
+	if err := db.Where("code IN ?", codes).Find(&instruments).Error; err != nil {
+		return nil, err
+	}
+	return instruments, nil
+}
+
+func (instrument_repo *instrumentsRepository) GetInstrumentsByIDs(ctx context.Context, tx Transaction, ids []string) ([]entity.Instruments, error) {
+	db, err := instrument_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var instruments []entity.Instruments
+	if len(ids) == 0 {
+		return instruments, nil
+	}
+
+	if err := db.Where("id IN ?", ids).Find(&instruments).Error; err != nil {
+		return nil, err
+	}
+	return instruments, nil
+}
+
+func (instrument_repo *instrumentsRepository) CreateInstrument(ctx context.Context, tx Transaction, instrument entity.Instruments) (entity.Instruments, error) {
+	db, err := instrument_repo.getDB(ctx, tx)
+	if err != nil {
+		return entity.Instruments{}, err
+	}
+
+	if err := db.Omit("InvestmentTypes").Create(&instrument).Error; err != nil {
+		return entity.Instruments{}, err
+	}
+	return instrument, nil
+}
+
+func (instrument_repo *instrumentsRepository) UpdateInstrument(ctx context.Context, tx Transaction, instrument entity.Instruments) (entity.Instruments, error) {
+	db, err := instrument_repo.getDB(ctx, tx)
+	if err != nil {
+		return entity.Instruments{}, err
+	}
+
+	if err := db.Omit("InvestmentTypes").Save(&instrument).Error; err != nil {
+		return entity.Instruments{}, err
+	}
+	return instrument, nil
+}
+
+func (instrument_repo *instrumentsRepository) DeleteInstrument(ctx context.Context, tx Transaction, instrument entity.Instruments) (entity.Instruments, error) {
+	db, err := instrument_repo.getDB(ctx, tx)
+	if err != nil {
+		return entity.Instruments{}, err
+	}
+
+	if err := db.Delete(&instrument).Error; err != nil {
+		return entity.Instruments{}, err
+	}
+	return instrument, nil
+}
+
+// CountInvestmentsByInstrument counts the holdings of the instrument, an instrument still held must not be deleted
+func (instrument_repo *instrumentsRepository) CountInvestmentsByInstrument(ctx context.Context, tx Transaction, id string) (int64, error) {
+	db, err := instrument_repo.getDB(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	if err := db.Model(&entity.Investments{}).Where("instrument_id = ?", id).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
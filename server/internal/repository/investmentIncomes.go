@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"server/internal/types/entity"
+
+	"gorm.io/gorm"
+)
+
+type InvestmentIncomesRepository interface {
+	GetIncomesByInvestmentID(ctx context.Context, tx Transaction, investmentID string) ([]entity.InvestmentIncomes, error)
+	GetIncomesByInvestmentIDs(ctx context.Context, tx Transaction, investmentIDs []string) ([]entity.InvestmentIncomes, error)
+	GetIncomeByID(ctx context.Context, tx Transaction, id string) (entity.InvestmentIncomes, error)
+	CreateIncome(ctx context.Context, tx Transaction, income entity.InvestmentIncomes) (entity.InvestmentIncomes, error)
+	DeleteIncome(ctx context.Context, tx Transaction, income entity.InvestmentIncomes) (entity.InvestmentIncomes, error)
+}
+
+type investmentIncomesRepository struct {
+	db *gorm.DB
+}
+
+func NewInvestmentIncomesRepository(db *gorm.DB) InvestmentIncomesRepository {
+	return &investmentIncomesRepository{db}
+}
+
+// Helper untuk mendapatkan DB instance (transaksi atau biasa)
+func (income_repo *investmentIncomesRepository) getDB(ctx context.Context, tx Transaction) (*gorm.DB, error) {
+	if tx != nil {
+		gormTx, ok := tx.(*GormTx) // Type assertion ke GORM transaction
+		if !ok {
+			return nil, errors.New("invalid transaction type")
+		}
+		return gormTx.db.WithContext(ctx), nil
+	}
+	return income_repo.db.WithContext(ctx), nil
+}
+
+func (income_repo *investmentIncomesRepository) GetIncomesByInvestmentID(ctx context.Context, tx Transaction, investmentID string) ([]entity.InvestmentIncomes, error) {
+	db, err := income_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var incomes []entity.InvestmentIncomes
+	if err := db.Where("investment_id = ?", investmentID).Order("payment_date, created_at").Find(&incomes).Error; err != nil {
+		return nil, err
+	}
+
+	return incomes, nil
+}
+
+func (income_repo *investmentIncomesRepository) GetIncomesByInvestmentIDs(ctx context.Context, tx Transaction, investmentIDs []string) ([]entity.InvestmentIncomes, error) {
+	db, err := income_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var incomes []entity.InvestmentIncomes
+	if len(investmentIDs) == 0 {
+		return incomes, nil
+	}
+
+	if err := db.Where("investment_id IN ?", investmentIDs).Order("payment_date, created_at").Find(&incomes).Error; err != nil {
+		return nil, err
+	}
+
+	return incomes, nil
+}
+
+func (income_repo *investmentIncomesRepository) GetIncomeByID(ctx context.Context, tx Transaction, id string) (entity.InvestmentIncomes, error) {
+	db, err := income_repo.getDB(ctx, tx)
+	if err != nil {
+		return entity.InvestmentIncomes{}, err
+	}
+
+	var income entity.InvestmentIncomes
+	if err := db.First(&income, "id = ?", id).Error; err != nil {
+		return entity.InvestmentIncomes{}, err
+	}
+
+	return income, nil
+}
+
+func (income_repo *investmentIncomesRepository) CreateIncome(ctx context.Context, tx Transaction, income entity.InvestmentIncomes) (entity.InvestmentIncomes, error) {
+	db, err := income_repo.getDB(ctx, tx)
+	if err != nil {
+		return entity.InvestmentIncomes{}, err
+	}
+
+	if err := db.Omit("Investment", "Wallet").Create(&income).Error; err != nil {
+		return entity.InvestmentIncomes{}, err
+	}
+
+	return income, nil
+}
+
+func (income_repo *investmentIncomesRepository) DeleteIncome(ctx context.Context, tx Transaction, income entity.InvestmentIncomes) (entity.InvestmentIncomes, error) {
+	db, err := income_repo.getDB(ctx, tx)
+	if err != nil {
+		return entity.InvestmentIncomes{}, err
+	}
+
+	if err := db.Delete(&income).Error; err != nil {
+		return entity.InvestmentIncomes{}, err
+	}
+
+	return income, nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+
+	"server/internal/types/entity"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type investmentIncomesRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func NewInvestmentIncomesRepositoryMock() *investmentIncomesRepositoryMock {
+	return &investmentIncomesRepositoryMock{Mock: mock.Mock{}}
+}
+
+func (income_repo *investmentIncomesRepositoryMock) GetIncomesByInvestmentID(ctx context.Context, tx Transaction, investmentID string) ([]entity.InvestmentIncomes, error) {
+	arguments := income_repo.Mock.Called(ctx, tx, investmentID)
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).([]entity.InvestmentIncomes)
+	return result, nil
+}
+
+func (income_repo *investmentIncomesRepositoryMock) GetIncomesByInvestmentIDs(ctx context.Context, tx Transaction, investmentIDs []string) ([]entity.InvestmentIncomes, error) {
+	arguments := income_repo.Mock.Called(ctx, tx, investmentIDs)
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).([]entity.InvestmentIncomes)
+	return result, nil
+}
+
+func (income_repo *investmentIncomesRepositoryMock) GetIncomeByID(ctx context.Context, tx Transaction, id string) (entity.InvestmentIncomes, error) {
+	arguments := income_repo.Mock.Called(ctx, tx, id)
+	if arguments.Get(1) != nil {
+		return entity.InvestmentIncomes{}, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).(entity.InvestmentIncomes)
+	return result, nil
+}
+
+func (income_repo *investmentIncomesRepositoryMock) CreateIncome(ctx context.Context, tx Transaction, income entity.InvestmentIncomes) (entity.InvestmentIncomes, error) {
+	arguments := income_repo.Mock.Called(ctx, tx, income)
+	if arguments.Get(1) != nil {
+		return entity.InvestmentIncomes{}, arguments.Error(1)
+	}
+
+	// * Without a return value the income is created as given
+	result, ok := arguments.Get(0).(entity.InvestmentIncomes)
+	if !ok {
+		if income.ID == uuid.Nil {
+			income.ID = uuid.New()
+		}
+		return income, nil
+	}
+
+	return result, nil
+}
+
+func (income_repo *investmentIncomesRepositoryMock) DeleteIncome(ctx context.Context, tx Transaction, income entity.InvestmentIncomes) (entity.InvestmentIncomes, error) {
+	arguments := income_repo.Mock.Called(ctx, tx, income)
+	if arguments.Get(1) != nil {
+		return entity.InvestmentIncomes{}, arguments.Error(1)
+	}
+
+	// * Without a return value the income is returned as given
+	result, ok := arguments.Get(0).(entity.InvestmentIncomes)
+	if !ok {
+		return income, nil
+	}
+
+	return result, nil
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"server/internal/types/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type InvestmentPricesRepository interface {
+	GetPrices(ctx context.Context, tx Transaction, instrumentID string, from, to time.Time) ([]entity.InvestmentPrices, error)
+	GetPricesUntil(ctx context.Context, tx Transaction, instrumentIDs []string, to time.Time) ([]entity.InvestmentPrices, error)
+	GetLatestPrice(ctx context.Context, tx Transaction, instrumentID string, asOf time.Time) (*entity.InvestmentPrices, error)
+	UpsertPrices(ctx context.Context, tx Transaction, prices []entity.InvestmentPrices) (int64, error)
+	DeletePrice(ctx context.Context, tx Transaction, price entity.InvestmentPrices) (entity.InvestmentPrices, error)
+	GetPriceByID(ctx context.Context, tx Transaction, id string) (entity.InvestmentPrices, error)
+}
+
+type investmentPricesRepository struct {
+	db *gorm.DB
+}
+
+func NewInvestmentPricesRepository(db *gorm.DB) InvestmentPricesRepository {
+	return &investmentPricesRepository{db}
+}
+
+// Helper untuk mendapatkan DB instance (transaksi atau biasa)
+func (price_repo *investmentPricesRepository) getDB(ctx context.Context, tx Transaction) (*gorm.DB, error) {
+	if tx != nil {
+		gormTx, ok := tx.(*GormTx) // Type assertion ke GORM transaction
+		if !ok {
+			return nil, errors.New("invalid transaction type")
+		}
+		return gormTx.db.WithContext(ctx), nil
+	}
+	return price_repo.db.WithContext(ctx), nil
+}
+
+func (price_repo *investmentPricesRepository) GetPrices(ctx context.Context, tx Transaction, instrumentID string, from, to time.Time) ([]entity.InvestmentPrices, error) {
+	db, err := price_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var prices []entity.InvestmentPrices
+	err = db.Preload("Instrument").Where("instrument_id = ? AND price_date BETWEEN ? AND ?", instrumentID, from, to).
+		Order("price_date").
+		Find(&prices).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return prices, nil
+}
+
+// GetPricesUntil returns every price of the instruments up to the date, ordered by instrument and date
+func (price_repo *investmentPricesRepository) GetPricesUntil(ctx context.Context, tx Transaction, instrumentIDs []string, to time.Time) ([]entity.InvestmentPrices, error) {
+	db, err := price_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var prices []entity.InvestmentPrices
+	if len(instrumentIDs) == 0 {
+		return prices, nil
+	}
+
+	err = db.Where("instrument_id IN ? AND price_date <= ?", instrumentIDs, to).
+		Order("instrument_id, price_date").
+		Find(&prices).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return prices, nil
+}
+
+// GetLatestPrice returns the most recent price at or before asOf, nil when the instrument has no price yet
+func (price_repo *investmentPricesRepository) GetLatestPrice(ctx context.Context, tx Transaction, instrumentID string, asOf time.Time) (*entity.InvestmentPrices, error) {
+	db, err := price_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var price entity.InvestmentPrices
+	err = db.Where("instrument_id = ? AND price_date <= ?", instrumentID, asOf).
+		Order("price_date DESC").
+		First(&price).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &price, nil
+}
+
+// UpsertPrices inserts the prices, a price for an existing instrument and date replaces the stored one
+func (price_repo *investmentPricesRepository) UpsertPrices(ctx context.Context, tx Transaction, prices []entity.InvestmentPrices) (int64, error) {
+	db, err := price_repo.getDB(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(prices) == 0 {
+		return 0, nil
+	}
+
+	result := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "instrument_id"}, {Name: "price_date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"price", "source", "updated_at"}),
+	}).Omit("Instrument").Create(&prices)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (price_repo *investmentPricesRepository) GetPriceByID(ctx context.Context, tx Transaction, id string) (entity.InvestmentPrices, error) {
+	db, err := price_repo.getDB(ctx, tx)
+	if err != nil {
+		return entity.InvestmentPrices{}, err
+	}
+
+	var price entity.InvestmentPrices
+	if err := db.Preload("Instrument").First(&price, "id = ?", id).Error; err != nil {
+		return entity.InvestmentPrices{}, err
+	}
+
+	return price, nil
+}
+
+// DeletePrice removes the price permanently so the same instrument and date can be entered again
+func (price_repo *investmentPricesRepository) DeletePrice(ctx context.Context, tx Transaction, price entity.InvestmentPrices) (entity.InvestmentPrices, error) {
+	db, err := price_repo.getDB(ctx, tx)
+	if err != nil {
+		return entity.InvestmentPrices{}, err
+	}
+
+	if err := db.Unscoped().Delete(&price).Error; err != nil {
+		return entity.InvestmentPrices{}, err
+	}
+
+	return price, nil
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"server/internal/types/entity"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type investmentPricesRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func NewInvestmentPricesRepositoryMock() *investmentPricesRepositoryMock {
+	return &investmentPricesRepositoryMock{Mock: mock.Mock{}}
+}
+
+func (price_repo *investmentPricesRepositoryMock) GetPrices(ctx context.Context, tx Transaction, instrumentID string, from, to time.Time) ([]entity.InvestmentPrices, error) {
+	arguments := price_repo.Mock.Called(ctx, tx, instrumentID, from, to)
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).([]entity.InvestmentPrices)
+	return result, nil
+}
+
+func (price_repo *investmentPricesRepositoryMock) GetPricesUntil(ctx context.Context, tx Transaction, instrumentIDs []string, to time.Time) ([]entity.InvestmentPrices, error) {
+	arguments := price_repo.Mock.Called(ctx, tx, instrumentIDs, to)
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).([]entity.InvestmentPrices)
+	return result, nil
+}
+
+func (price_repo *investmentPricesRepositoryMock) GetLatestPrice(ctx context.Context, tx Transaction, instrumentID string, asOf time.Time) (*entity.InvestmentPrices, error) {
+	arguments := price_repo.Mock.Called(ctx, tx, instrumentID, asOf)
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).(*entity.InvestmentPrices)
+	return result, nil
+}
+
+func (price_repo *investmentPricesRepositoryMock) UpsertPrices(ctx context.Context, tx Transaction, prices []entity.InvestmentPrices) (int64, error) {
+	arguments := price_repo.Mock.Called(ctx, tx, prices)
+	if arguments.Get(1) != nil {
+		return 0, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).(int64)
+	return result, nil
+}
+
+func (price_repo *investmentPricesRepositoryMock) DeletePrice(ctx context.Context, tx Transaction, price entity.InvestmentPrices) (entity.InvestmentPrices, error) {
+	arguments := price_repo.Mock.Called(ctx, tx, price)
+	if arguments.Get(1) != nil {
+		return entity.InvestmentPrices{}, arguments.Error(1)
+	}
+
+	// * Without a return value the price is returned as given
+	result, ok := arguments.Get(0).(entity.InvestmentPrices)
+	if !ok {
+		return price, nil
+	}
+
+	return result, nil
+}
+
+func (price_repo *investmentPricesRepositoryMock) GetPriceByID(ctx context.Context, tx Transaction, id string) (entity.InvestmentPrices, error) {
+	arguments := price_repo.Mock.Called(ctx, tx, id)
+	if arguments.Get(1) != nil {
+		return entity.InvestmentPrices{}, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).(entity.InvestmentPrices)
+	return result, nil
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"server/internal/types/entity"
+
+	"gorm.io/gorm"
+)
+
+type InvestmentTradesRepository interface {
+	GetTradesByInvestmentID(ctx context.Context, tx Transaction, investmentID string) ([]entity.InvestmentTrades, error)
+	GetTradesByInvestmentIDs(ctx context.Context, tx Transaction, investmentIDs []string) ([]entity.InvestmentTrades, error)
+	GetTradeByID(ctx context.Context, tx Transaction, id string) (entity.InvestmentTrades, error)
+	CreateTrade(ctx context.Context, tx Transaction, trade entity.InvestmentTrades) (entity.InvestmentTrades, error)
+	DeleteTrade(ctx context.Context, tx Transaction, trade entity.InvestmentTrades) (entity.InvestmentTrades, error)
+}
+
+type investmentTradesRepository struct {
+	db *gorm.DB
+}
+
+func NewInvestmentTradesRepository(db *gorm.DB) InvestmentTradesRepository {
+	return &investmentTradesRepository{db}
+}
+
+// Helper untuk mendapatkan DB instance (transaksi atau biasa)
+func (trade_repo *investmentTradesRepository) getDB(ctx context.Context, tx Transaction) (*gorm.DB, error) {
+	if tx != nil {
+		gormTx, ok := tx.(*GormTx) // Type assertion ke GORM transaction
+		if !ok {
+			return nil, errors.New("invalid transaction type")
+		}
+		return gormTx.db.WithContext(ctx), nil
+	}
+	return trade_repo.db.WithContext(ctx), nil
+}
+
+// GetTradesByInvestmentID returns the trades in the order they have to be replayed
+func (trade_repo *investmentTradesRepository) GetTradesByInvestmentID(ctx context.Context, tx Transaction, investmentID string) ([]entity.InvestmentTrades, error) {
+	db, err := trade_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var trades []entity.InvestmentTrades
+	if err := db.Where("investment_id = ?", investmentID).Order("trade_date, created_at").Find(&trades).Error; err != nil {
+		return nil, err
+	}
+
+	return trades, nil
+}
+
+func (trade_repo *investmentTradesRepository) GetTradesByInvestmentIDs(ctx context.Context, tx Transaction, investmentIDs []string) ([]entity.InvestmentTrades, error) {
+	db, err := trade_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var trades []entity.InvestmentTrades
+	if len(investmentIDs) == 0 {
+		return trades, nil
+	}
+
+	if err := db.Where("investment_id IN ?", investmentIDs).Order("trade_date, created_at").Find(&trades).Error; err != nil {
+		return nil, err
+	}
+
+	return trades, nil
+}
+
+func (trade_repo *investmentTradesRepository) GetTradeByID(ctx context.Context, tx Transaction, id string) (entity.InvestmentTrades, error) {
+	db, err := trade_repo.getDB(ctx, tx)
+	if err != nil {
+		return entity.InvestmentTrades{}, err
+	}
+
+	var trade entity.InvestmentTrades
+	if err := db.First(&trade, "id = ?", id).Error; err != nil {
+		return entity.InvestmentTrades{}, err
+	}
+
+	return trade, nil
+}
+
+func (trade_repo *investmentTradesRepository) CreateTrade(ctx context.Context, tx Transaction, trade entity.InvestmentTrades) (entity.InvestmentTrades, error) {
+	db, err := trade_repo.getDB(ctx, tx)
+	if err != nil {
+		return entity.InvestmentTrades{}, err
+	}
+
+	if err := db.Omit("Investment").Create(&trade).Error; err != nil {
+		return entity.InvestmentTrades{}, err
+	}
+
+	return trade, nil
+}
+
+func (trade_repo *investmentTradesRepository) DeleteTrade(ctx context.Context, tx Transaction, trade entity.InvestmentTrades) (entity.InvestmentTrades, error) {
+	db, err := trade_repo.getDB(ctx, tx)
+	if err != nil {
+		return entity.InvestmentTrades{}, err
+	}
+
+	if err := db.Delete(&trade).Error; err != nil {
+		return entity.InvestmentTrades{}, err
+	}
+
+	return trade, nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+
+	"server/internal/types/entity"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type investmentTradesRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func NewInvestmentTradesRepositoryMock() *investmentTradesRepositoryMock {
+	return &investmentTradesRepositoryMock{Mock: mock.Mock{}}
+}
+
+func (trade_repo *investmentTradesRepositoryMock) GetTradesByInvestmentID(ctx context.Context, tx Transaction, investmentID string) ([]entity.InvestmentTrades, error) {
+	arguments := trade_repo.Mock.Called(ctx, tx, investmentID)
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).([]entity.InvestmentTrades)
+	return result, nil
+}
+
+func (trade_repo *investmentTradesRepositoryMock) GetTradesByInvestmentIDs(ctx context.Context, tx Transaction, investmentIDs []string) ([]entity.InvestmentTrades, error) {
+	arguments := trade_repo.Mock.Called(ctx, tx, investmentIDs)
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).([]entity.InvestmentTrades)
+	return result, nil
+}
+
+func (trade_repo *investmentTradesRepositoryMock) GetTradeByID(ctx context.Context, tx Transaction, id string) (entity.InvestmentTrades, error) {
+	arguments := trade_repo.Mock.Called(ctx, tx, id)
+	if arguments.Get(1) != nil {
+		return entity.InvestmentTrades{}, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).(entity.InvestmentTrades)
+	return result, nil
+}
+
+func (trade_repo *investmentTradesRepositoryMock) CreateTrade(ctx context.Context, tx Transaction, trade entity.InvestmentTrades) (entity.InvestmentTrades, error) {
+	arguments := trade_repo.Mock.Called(ctx, tx, trade)
+	if arguments.Get(1) != nil {
+		return entity.InvestmentTrades{}, arguments.Error(1)
+	}
+
+	// * Without a return value the trade is created as given
+	result, ok := arguments.Get(0).(entity.InvestmentTrades)
+	if !ok {
+		if trade.ID == uuid.Nil {
+			trade.ID = uuid.New()
+		}
+		return trade, nil
+	}
+
+	return result, nil
+}
+
+func (trade_repo *investmentTradesRepositoryMock) DeleteTrade(ctx context.Context, tx Transaction, trade entity.InvestmentTrades) (entity.InvestmentTrades, error) {
+	arguments := trade_repo.Mock.Called(ctx, tx, trade)
+	if arguments.Get(1) != nil {
+		return entity.InvestmentTrades{}, arguments.Error(1)
+	}
+
+	// * Without a return value the trade is returned as given
+	result, ok := arguments.Get(0).(entity.InvestmentTrades)
+	if !ok {
+		return trade, nil
+	}
+
+	return result, nil
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"server/internal/types/entity"
+
+	"gorm.io/gorm"
+)
+
+type InvestmentTypesRepository interface {
+	GetAllInvestmentTypes(ctx context.Context, tx Transaction) ([]entity.InvestmentTypes, error)
+	GetInvestmentTypeByID(ctx context.Context, tx Transaction, id string) (entity.InvestmentTypes, error)
+	CreateInvestmentType(ctx context.Context, tx Transaction, investmentType entity.InvestmentTypes) (entity.InvestmentTypes, error)
+	UpdateInvestmentType(ctx context.Context, tx Transaction, investmentType entity.InvestmentTypes) (entity.InvestmentTypes, error)
+	DeleteInvestmentType(ctx context.Context, tx Transaction, investmentType entity.InvestmentTypes) (entity.InvestmentTypes, error)
+	CountInvestmentsByType(ctx context.Context, tx Transaction, id string) (int64, error)
+}
+
+type investmentTypesRepository struct {
+	db *gorm.DB
+}
+
+func NewInvestmentTypesRepository(db *gorm.DB) InvestmentTypesRepository {
+	return &investmentTypesRepository{db}
+}
+
+// Helper untuk mendapatkan DB instance (transaksi atau biasa)
+func (investment_type_repo *investmentTypesRepository) getDB(ctx context.Context, tx Transaction) (*gorm.DB, error) {
+	if tx != nil {
+		gormTx, ok := tx.(*GormTx) // Type assertion ke GORM transaction
+		if !ok {
+			return nil, errors.New("invalid transaction type")
+		}
+		return gormTx.db.WithContext(ctx), nil
+	}
+	return investment_type_repo.db.WithContext(ctx), nil
+}
+
+func (investment_type_repo *investmentTypesRepository) GetAllInvestmentTypes(ctx context.Context, tx Transaction) ([]entity.InvestmentTypes, error) {
+	db, err := investment_type_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var investmentTypes []entity.InvestmentTypes
+	if err := db.Order("name").Find(&investmentTypes).Error; err != nil {
+		return nil, err
+	}
+	return investmentTypes, nil
+}
+
+func (investment_type_repo *investmentTypesRepository) GetInvestmentTypeByID(ctx context.Context, tx Transaction, id string) (entity.InvestmentTypes, error) {
+	db, err := investment_type_repo.getDB(ctx, tx)
+	if err != nil {
+		return entity.InvestmentTypes{}, err
+	}
+
+	var investmentType entity.InvestmentTypes
+	if err := db.Where("id = ?", id).First(&investmentType).Error; err != nil {
+		return entity.InvestmentTypes{}, err
+	}
+	return investmentType, nil
+}
+
+func (investment_type_repo *investmentTypesRepository) CreateInvestmentType(ctx context.Context, tx Transaction, investmentType entity.InvestmentTypes) (entity.InvestmentTypes, error) {
+	db, err := investment_type_repo.getDB(ctx, tx)
+	if err != nil {
+		return entity.InvestmentTypes{}, err
+	}
+
+	if err := db.Create(&investmentType).Error; err != nil {
+		return entity.InvestmentTypes{}, err
+	}
+	return investmentType, nil
+}
+
+func (investment_type_repo *investmentTypesRepository) UpdateInvestmentType(ctx context.Context, tx Transaction, investmentType entity.InvestmentTypes) (entity.InvestmentTypes, error) {
+	db, err := investment_type_repo.getDB(ctx, tx)
+	if err != nil {
+		return entity.InvestmentTypes{}, err
+	}
+
+	if err := db.Save(&investmentType).Error; err != nil {
+		return entity.InvestmentTypes{}, err
+	}
+	return investmentType, nil
+}
+
+func (investment_type_repo *investmentTypesRepository) DeleteInvestmentType(ctx context.Context, tx Transaction, investmentType entity.InvestmentTypes) (entity.InvestmentTypes, error) {
+	db, err := investment_type_repo.getDB(ctx, tx)
+	if err != nil {
+		return entity.InvestmentTypes{}, err
+	}
+
+	if err := db.Delete(&investmentType).Error; err != nil {
+		return entity.InvestmentTypes{}, err
+	}
+	return investmentType, nil
+}
+
+// CountInvestmentsByType counts the holdings of the type, a type still in use must not be deleted
+func (investment_type_repo *investmentTypesRepository) CountInvestmentsByType(ctx context.Context, tx Transaction, id string) (int64, error) {
+	db, err := investment_type_repo.getDB(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	if err := db.Model(&entity.Investments{}).Where("investment_type_id = ?", id).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -14,6 +14,8 @@ type InvestmentsRepository interface {
 	GetAllInvestments(ctx context.Context, tx Transaction) ([]entity.Investments, error)
 	GetInvestmentByID(ctx context.Context, tx Transaction, id string) (entity.Investments, error)
 	GetInvestmentsByUserID(ctx context.Context, tx Transaction, id string) ([]view.ViewUserInvestments, error)
+	GetUserPositions(ctx context.Context, tx Transaction, userID string) ([]entity.Investments, error)
+	GetHeldInstruments(ctx context.Context, tx Transaction) ([]entity.Instruments, error)
 	CreateInvestment(ctx context.Context, tx Transaction, investment entity.Investments) (entity.Investments, error)
 	UpdateInvestment(ctx context.Context, tx Transaction, investment entity.Investments) (entity.Investments, error)
 	DeleteInvestment(ctx context.Context, tx Transaction, investment entity.Investments) (entity.Investments, error)
@@ -46,7 +48,7 @@ func (investment_repo *investmentsRepository) GetAllInvestments(ctx context.Cont
 	}
 
 	var investments []entity.Investments
-	if err := db.Find(&investments).Error; err != nil {
+	if err := db.Preload("Instrument").Find(&investments).Error; err != nil {
 		return nil, err
 	}
 
@@ -60,7 +62,7 @@ func (investment_repo *investmentsRepository) GetInvestmentByID(ctx context.Cont
 	}
 
 	var investment entity.Investments
-	if err := db.First(&investment, "id = ?", id).Error; err != nil {
+	if err := db.Preload("InvestmentTypes").Preload("Instrument").First(&investment, "id = ?", id).Error; err != nil {
 		return entity.Investments{}, err
 	}
 
@@ -82,13 +84,46 @@ func (investment_repo *investmentsRepository) GetInvestmentsByUserID(ctx context
 	return userInvestments, nil
 }
 
+// GetUserPositions returns every investment of the user, including closed positions which still carry realized P&L
+func (investment_repo *investmentsRepository) GetUserPositions(ctx context.Context, tx Transaction, userID string) ([]entity.Investments, error) {
+	db, err := investment_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var investments []entity.Investments
+	if err := db.Preload("InvestmentTypes").Preload("Instrument").Where("user_id = ?", userID).Order("investment_date").Find(&investments).Error; err != nil {
+		return nil, err
+	}
+
+	return investments, nil
+}
+
+// GetHeldInstruments returns the instruments of all open positions
+func (investment_repo *investmentsRepository) GetHeldInstruments(ctx context.Context, tx Transaction) ([]entity.Instruments, error) {
+	db, err := investment_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var instruments []entity.Instruments
+	err = db.Where("id IN (?)", db.Model(&entity.Investments{}).Where("quantity > 0").Select("instrument_id")).
+		Order("code").
+		Find(&instruments).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return instruments, nil
+}
+
 func (investment_repo *investmentsRepository) CreateInvestment(ctx context.Context, tx Transaction, investment entity.Investments) (entity.Investments, error) {
 	db, err := investment_repo.getDB(ctx, tx)
 	if err != nil {
 		return entity.Investments{}, err
 	}
 
-	if err := db.Create(&investment).Error; err != nil {
+	if err := db.Omit("Instrument").Create(&investment).Error; err != nil {
 		return entity.Investments{}, err
 	}
 
@@ -101,7 +136,7 @@ func (investment_repo *investmentsRepository) UpdateInvestment(ctx context.Conte
 		return entity.Investments{}, err
 	}
 
-	if err := db.Omit("InvestmentTypes", "User").Save(&investment).Error; err != nil {
+	if err := db.Omit("InvestmentTypes", "User", "Instrument").Save(&investment).Error; err != nil {
 		return entity.Investments{}, err
 	}
 
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+
+	"server/internal/types/entity"
+	"server/internal/types/view"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type investmentsRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func NewInvestmentsRepositoryMock() *investmentsRepositoryMock {
+	return &investmentsRepositoryMock{Mock: mock.Mock{}}
+}
+
+func (investment_repo *investmentsRepositoryMock) GetAllInvestments(ctx context.Context, tx Transaction) ([]entity.Investments, error) {
+	arguments := investment_repo.Mock.Called(ctx, tx)
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).([]entity.Investments)
+	return result, nil
+}
+
+func (investment_repo *investmentsRepositoryMock) GetInvestmentByID(ctx context.Context, tx Transaction, id string) (entity.Investments, error) {
+	arguments := investment_repo.Mock.Called(ctx, tx, id)
+	if arguments.Get(1) != nil {
+		return entity.Investments{}, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).(entity.Investments)
+	return result, nil
+}
+
+func (investment_repo *investmentsRepositoryMock) GetInvestmentsByUserID(ctx context.Context, tx Transaction, id string) ([]view.ViewUserInvestments, error) {
+	arguments := investment_repo.Mock.Called(ctx, tx, id)
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).([]view.ViewUserInvestments)
+	return result, nil
+}
+
+func (investment_repo *investmentsRepositoryMock) GetUserPositions(ctx context.Context, tx Transaction, userID string) ([]entity.Investments, error) {
+	arguments := investment_repo.Mock.Called(ctx, tx, userID)
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).([]entity.Investments)
+	return result, nil
+}
+
+func (investment_repo *investmentsRepositoryMock) GetHeldInstruments(ctx context.Context, tx Transaction) ([]entity.Instruments, error) {
+	arguments := investment_repo.Mock.Called(ctx, tx)
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).([]entity.Instruments)
+	return result, nil
+}
+
+func (investment_repo *investmentsRepositoryMock) CreateInvestment(ctx context.Context, tx Transaction, investment entity.Investments) (entity.Investments, error) {
+	arguments := investment_repo.Mock.Called(ctx, tx, investment)
+	if arguments.Get(1) != nil {
+		return entity.Investments{}, arguments.Error(1)
+	}
+
+	// * Without a return value the investment is created as given
+	result, ok := arguments.Get(0).(entity.Investments)
+	if !ok {
+		if investment.ID == uuid.Nil {
+			investment.ID = uuid.New()
+		}
+		return investment, nil
+	}
+
+	return result, nil
+}
+
+func (investment_repo *investmentsRepositoryMock) UpdateInvestment(ctx context.Context, tx Transaction, investment entity.Investments) (entity.Investments, error) {
+	arguments := investment_repo.Mock.Called(ctx, tx, investment)
+	if arguments.Get(1) != nil {
+		return entity.Investments{}, arguments.Error(1)
+	}
+
+	// * Without a return value the investment is returned as given
+	result, ok := arguments.Get(0).(entity.Investments)
+	if !ok {
+		return investment, nil
+	}
+
+	return result, nil
+}
+
+func (investment_repo *investmentsRepositoryMock) DeleteInvestment(ctx context.Context, tx Transaction, investment entity.Investments) (entity.Investments, error) {
+	arguments := investment_repo.Mock.Called(ctx, tx, investment)
+	if arguments.Get(1) != nil {
+		return entity.Investments{}, arguments.Error(1)
+	}
+
+	// * Without a return value the investment is returned as given
+	result, ok := arguments.Get(0).(entity.Investments)
+	if !ok {
+		return investment, nil
+	}
+
+	return result, nil
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"server/internal/types/entity"
+
+	"gorm.io/gorm"
+)
+
+type OutboxEventsRepository interface {
+	CreateOutboxEvent(ctx context.Context, tx Transaction, event entity.OutboxEvents) (entity.OutboxEvents, error)
+	ClaimPendingOutboxEvents(ctx context.Context, tx Transaction, limit int, leaseUntil time.Time) ([]entity.OutboxEvents, error)
+	MarkOutboxEventSent(ctx context.Context, tx Transaction, id string, sentAt time.Time) error
+	MarkOutboxEventFailed(ctx context.Context, tx Transaction, id string, lastError string, nextAttemptAt time.Time) error
+	DeleteSentOutboxEvents(ctx context.Context, tx Transaction, sentBefore time.Time) (int64, error)
+}
+
+type outboxEventsRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxEventsRepository(db *gorm.DB) OutboxEventsRepository {
+	return &outboxEventsRepository{db}
+}
+
+// Helper untuk mendapatkan DB instance (transaksi atau biasa)
+func (outbox_repo *outboxEventsRepository) getDB(ctx context.Context, tx Transaction) (*gorm.DB, error) {
+	if tx != nil {
+		gormTx, ok := tx.(*GormTx) // Type assertion ke GORM transaction
+		if !ok {
+			return nil, errors.New("invalid transaction type")
+		}
+		return gormTx.db.WithContext(ctx), nil
+	}
+	return outbox_repo.db.WithContext(ctx), nil
+}
+
+func (outbox_repo *outboxEventsRepository) CreateOutboxEvent(ctx context.Context, tx Transaction, event entity.OutboxEvents) (entity.OutboxEvents, error) {
+	db, err := outbox_repo.getDB(ctx, tx)
+	if err != nil {
+		return entity.OutboxEvents{}, err
+	}
+
+	if err := db.Create(&event).Error; err != nil {
+		return entity.OutboxEvents{}, err
+	}
+
+	return event, nil
+}
+
+// ClaimPendingOutboxEvents returns the oldest unsent events that are due and moves their next attempt to leaseUntil,
+// so no other relay picks them up while they are published. An event whose relay stops before marking it is
+// claimed again once the lease has passed. Events claimed by another relay at the same time are skipped.
+func (outbox_repo *outboxEventsRepository) ClaimPendingOutboxEvents(ctx context.Context, tx Transaction, limit int, leaseUntil time.Time) ([]entity.OutboxEvents, error) {
+	db, err := outbox_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []entity.OutboxEvents
+	if err := db.Raw(`
+		UPDATE outbox_events SET next_attempt_at = ?
+		WHERE id IN (
+			SELECT id FROM outbox_events
+			WHERE sent_at IS NULL AND next_attempt_at <= ? AND deleted_at IS NULL
+			ORDER BY created_at ASC
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, leaseUntil, time.Now(), limit).Scan(&events).Error; err != nil {
+		return nil, err
+	}
+
+	// RETURNING keeps no order
+	slices.SortFunc(events, func(a, b entity.OutboxEvents) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return events, nil
+}
+
+func (outbox_repo *outboxEventsRepository) MarkOutboxEventSent(ctx context.Context, tx Transaction, id string, sentAt time.Time) error {
+	db, err := outbox_repo.getDB(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	return db.Model(&entity.OutboxEvents{}).Where("id = ?", id).Updates(map[string]any{
+		"sent_at":    sentAt,
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": nil,
+	}).Error
+}
+
+func (outbox_repo *outboxEventsRepository) MarkOutboxEventFailed(ctx context.Context, tx Transaction, id string, lastError string, nextAttemptAt time.Time) error {
+	db, err := outbox_repo.getDB(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	return db.Model(&entity.OutboxEvents{}).Where("id = ?", id).Updates(map[string]any{
+		"attempts":        gorm.Expr("attempts + 1"),
+		"last_error":      lastError,
+		"next_attempt_at": nextAttemptAt,
+	}).Error
+}
+
+// DeleteSentOutboxEvents removes the events published before sentBefore, pending events are never removed
+func (outbox_repo *outboxEventsRepository) DeleteSentOutboxEvents(ctx context.Context, tx Transaction, sentBefore time.Time) (int64, error) {
+	db, err := outbox_repo.getDB(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	result := db.Unscoped().Where("sent_at IS NOT NULL AND sent_at < ?", sentBefore).Delete(&entity.OutboxEvents{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
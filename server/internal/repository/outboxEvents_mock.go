@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"server/internal/types/entity"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type outboxEventsRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func NewOutboxEventsRepositoryMock() *outboxEventsRepositoryMock {
+	return &outboxEventsRepositoryMock{Mock: mock.Mock{}}
+}
+
+func (outbox_repo *outboxEventsRepositoryMock) CreateOutboxEvent(ctx context.Context, tx Transaction, event entity.OutboxEvents) (entity.OutboxEvents, error) {
+	arguments := outbox_repo.Mock.Called(ctx, tx, event)
+	if arguments.Get(1) != nil {
+		return entity.OutboxEvents{}, arguments.Error(1)
+	}
+
+	// * Without a return value the event is created as given
+	result, ok := arguments.Get(0).(entity.OutboxEvents)
+	if !ok {
+		if event.ID == uuid.Nil {
+			event.ID = uuid.New()
+		}
+		return event, nil
+	}
+
+	return result, nil
+}
+
+func (outbox_repo *outboxEventsRepositoryMock) ClaimPendingOutboxEvents(ctx context.Context, tx Transaction, limit int, leaseUntil time.Time) ([]entity.OutboxEvents, error) {
+	arguments := outbox_repo.Mock.Called(ctx, tx, limit, leaseUntil)
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).([]entity.OutboxEvents)
+	return result, nil
+}
+
+func (outbox_repo *outboxEventsRepositoryMock) MarkOutboxEventSent(ctx context.Context, tx Transaction, id string, sentAt time.Time) error {
+	arguments := outbox_repo.Mock.Called(ctx, tx, id, sentAt)
+	return arguments.Error(0)
+}
+
+func (outbox_repo *outboxEventsRepositoryMock) MarkOutboxEventFailed(ctx context.Context, tx Transaction, id string, lastError string, nextAttemptAt time.Time) error {
+	arguments := outbox_repo.Mock.Called(ctx, tx, id, lastError, nextAttemptAt)
+	return arguments.Error(0)
+}
+
+func (outbox_repo *outboxEventsRepositoryMock) DeleteSentOutboxEvents(ctx context.Context, tx Transaction, sentBefore time.Time) (int64, error) {
+	arguments := outbox_repo.Mock.Called(ctx, tx, sentBefore)
+	if arguments.Get(1) != nil {
+		return 0, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).(int64)
+	return result, nil
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"server/internal/types/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RecurringTransactionsRepository interface {
+	GetRecurringTransactionsByUserID(ctx context.Context, tx Transaction, userID string) ([]entity.RecurringTransactions, error)
+	GetRecurringTransactionByID(ctx context.Context, tx Transaction, id string) (entity.RecurringTransactions, error)
+	GetDueRecurringTransactionIDs(ctx context.Context, tx Transaction, now time.Time, limit int) ([]string, error)
+	LockDueRecurringTransaction(ctx context.Context, tx Transaction, id string, now time.Time) (*entity.RecurringTransactions, error)
+	CreateRecurringTransaction(ctx context.Context, tx Transaction, recurring entity.RecurringTransactions) (entity.RecurringTransactions, error)
+	UpdateRecurringTransaction(ctx context.Context, tx Transaction, recurring entity.RecurringTransactions) (entity.RecurringTransactions, error)
+	DeleteRecurringTransaction(ctx context.Context, tx Transaction, recurring entity.RecurringTransactions) (entity.RecurringTransactions, error)
+}
+
+type recurringTransactionsRepository struct {
+	db *gorm.DB
+}
+
+func NewRecurringTransactionsRepository(db *gorm.DB) RecurringTransactionsRepository {
+	return &recurringTransactionsRepository{db}
+}
+
+// Helper untuk mendapatkan DB instance (transaksi atau biasa)
+func (recurring_repo *recurringTransactionsRepository) getDB(ctx context.Context, tx Transaction) (*gorm.DB, error) {
+	if tx != nil {
+		gormTx, ok := tx.(*GormTx) // Type assertion ke GORM transaction
+		if !ok {
+			return nil, errors.New("invalid transaction type")
+		}
+		return gormTx.db.WithContext(ctx), nil
+	}
+	return recurring_repo.db.WithContext(ctx), nil
+}
+
+func (recurring_repo *recurringTransactionsRepository) GetRecurringTransactionsByUserID(ctx context.Context, tx Transaction, userID string) ([]entity.RecurringTransactions, error) {
+	db, err := recurring_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var recurring []entity.RecurringTransactions
+	if err := db.Preload("Category").Where("user_id = ?", userID).Order("next_date ASC").Find(&recurring).Error; err != nil {
+		return nil, err
+	}
+
+	return recurring, nil
+}
+
+func (recurring_repo *recurringTransactionsRepository) GetRecurringTransactionByID(ctx context.Context, tx Transaction, id string) (entity.RecurringTransactions, error) {
+	db, err := recurring_repo.getDB(ctx, tx)
+	if err != nil {
+		return entity.RecurringTransactions{}, err
+	}
+
+	var recurring entity.RecurringTransactions
+	if err := db.Where("id = ?", id).First(&recurring).Error; err != nil {
+		return entity.RecurringTransactions{}, err
+	}
+
+	return recurring, nil
+}
+
+// GetDueRecurringTransactionIDs returns the recurring transactions with a booking due at now, oldest first.
+// Those of deleted wallets are left out, they are never booked again.
+func (recurring_repo *recurringTransactionsRepository) GetDueRecurringTransactionIDs(ctx context.Context, tx Transaction, now time.Time, limit int) ([]string, error) {
+	db, err := recurring_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	if err := db.Model(&entity.RecurringTransactions{}).
+		Where("next_date <= ? AND (end_date IS NULL OR next_date <= end_date) AND wallet_id IN (SELECT id FROM wallets WHERE deleted_at IS NULL)", now).
+		Order("next_date ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// LockDueRecurringTransaction locks the recurring transaction until the transaction ends, it returns nil
+// without error when it is no longer due or was deleted in the meantime
+func (recurring_repo *recurringTransactionsRepository) LockDueRecurringTransaction(ctx context.Context, tx Transaction, id string, now time.Time) (*entity.RecurringTransactions, error) {
+	db, err := recurring_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var recurring entity.RecurringTransactions
+	err = db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND next_date <= ? AND (end_date IS NULL OR next_date <= end_date) AND wallet_id IN (SELECT id FROM wallets WHERE deleted_at IS NULL)", id, now).
+		First(&recurring).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &recurring, nil
+}
+
+func (recurring_repo *recurringTransactionsRepository) CreateRecurringTransaction(ctx context.Context, tx Transaction, recurring entity.RecurringTransactions) (entity.RecurringTransactions, error) {
+	db, err := recurring_repo.getDB(ctx, tx)
+	if err != nil {
+		return entity.RecurringTransactions{}, err
+	}
+
+	if err := db.Omit("User", "Wallet", "Category").Create(&recurring).Error; err != nil {
+		return entity.RecurringTransactions{}, err
+	}
+
+	return recurring, nil
+}
+
+func (recurring_repo *recurringTransactionsRepository) UpdateRecurringTransaction(ctx context.Context, tx Transaction, recurring entity.RecurringTransactions) (entity.RecurringTransactions, error) {
+	db, err := recurring_repo.getDB(ctx, tx)
+	if err != nil {
+		return entity.RecurringTransactions{}, err
+	}
+
+	if err := db.Omit("User", "Wallet", "Category").Save(&recurring).Error; err != nil {
+		return entity.RecurringTransactions{}, err
+	}
+
+	return recurring, nil
+}
+
+func (recurring_repo *recurringTransactionsRepository) DeleteRecurringTransaction(ctx context.Context, tx Transaction, recurring entity.RecurringTransactions) (entity.RecurringTransactions, error) {
+	db, err := recurring_repo.getDB(ctx, tx)
+	if err != nil {
+		return entity.RecurringTransactions{}, err
+	}
+
+	if err := db.Delete(&recurring).Error; err != nil {
+		return entity.RecurringTransactions{}, err
+	}
+
+	return recurring, nil
+}
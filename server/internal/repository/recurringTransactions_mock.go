@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"server/internal/types/entity"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type recurringTransactionsRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func NewRecurringTransactionsRepositoryMock() *recurringTransactionsRepositoryMock {
+	return &recurringTransactionsRepositoryMock{Mock: mock.Mock{}}
+}
+
+func (recurring_repo *recurringTransactionsRepositoryMock) GetRecurringTransactionsByUserID(ctx context.Context, tx Transaction, userID string) ([]entity.RecurringTransactions, error) {
+	arguments := recurring_repo.Mock.Called(ctx, tx, userID)
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).([]entity.RecurringTransactions)
+	return result, nil
+}
+
+func (recurring_repo *recurringTransactionsRepositoryMock) GetRecurringTransactionByID(ctx context.Context, tx Transaction, id string) (entity.RecurringTransactions, error) {
+	arguments := recurring_repo.Mock.Called(ctx, tx, id)
+	if arguments.Get(1) != nil {
+		return entity.RecurringTransactions{}, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).(entity.RecurringTransactions)
+	return result, nil
+}
+
+func (recurring_repo *recurringTransactionsRepositoryMock) GetDueRecurringTransactionIDs(ctx context.Context, tx Transaction, now time.Time, limit int) ([]string, error) {
+	arguments := recurring_repo.Mock.Called(ctx, tx, now, limit)
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).([]string)
+	return result, nil
+}
+
+func (recurring_repo *recurringTransactionsRepositoryMock) LockDueRecurringTransaction(ctx context.Context, tx Transaction, id string, now time.Time) (*entity.RecurringTransactions, error) {
+	arguments := recurring_repo.Mock.Called(ctx, tx, id, now)
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).(*entity.RecurringTransactions)
+	return result, nil
+}
+
+func (recurring_repo *recurringTransactionsRepositoryMock) CreateRecurringTransaction(ctx context.Context, tx Transaction, recurring entity.RecurringTransactions) (entity.RecurringTransactions, error) {
+	arguments := recurring_repo.Mock.Called(ctx, tx, recurring)
+	if arguments.Get(1) != nil {
+		return entity.RecurringTransactions{}, arguments.Error(1)
+	}
+
+	// * Without a return value the recurring transaction is created as given
+	result, ok := arguments.Get(0).(entity.RecurringTransactions)
+	if !ok {
+		if recurring.ID == uuid.Nil {
+			recurring.ID = uuid.New()
+		}
+		return recurring, nil
+	}
+
+	return result, nil
+}
+
+func (recurring_repo *recurringTransactionsRepositoryMock) UpdateRecurringTransaction(ctx context.Context, tx Transaction, recurring entity.RecurringTransactions) (entity.RecurringTransactions, error) {
+	arguments := recurring_repo.Mock.Called(ctx, tx, recurring)
+	if arguments.Get(1) != nil {
+		return entity.RecurringTransactions{}, arguments.Error(1)
+	}
+
+	// * Without a return value the recurring transaction is returned as given
+	result, ok := arguments.Get(0).(entity.RecurringTransactions)
+	if !ok {
+		return recurring, nil
+	}
+
+	return result, nil
+}
+
+func (recurring_repo *recurringTransactionsRepositoryMock) DeleteRecurringTransaction(ctx context.Context, tx Transaction, recurring entity.RecurringTransactions) (entity.RecurringTransactions, error) {
+	arguments := recurring_repo.Mock.Called(ctx, tx, recurring)
+	if arguments.Get(1) != nil {
+		return entity.RecurringTransactions{}, arguments.Error(1)
+	}
+
+	// * Without a return value the recurring transaction is returned as given
+	result, ok := arguments.Get(0).(entity.RecurringTransactions)
+	if !ok {
+		return recurring, nil
+	}
+
+	return result, nil
+}
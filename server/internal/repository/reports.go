@@ -15,9 +15,14 @@ type ReportsRepository interface {
 	GetReportByID(ctx context.Context, tx Transaction, id string) (entity.Reports, error)
 	GetReportByUserID(ctx context.Context, tx Transaction, user_id string) ([]entity.Reports, error)
 	GetProcessedReportByUserID(ctx context.Context, tx Transaction, user_id string) ([]entity.Reports, error)
+	GetLatestReportByUserIDAndType(ctx context.Context, tx Transaction, user_id, report_type string) (*entity.Reports, error)
+	LockUserReports(ctx context.Context, tx Transaction, user_id string) error
 	CreateReport(ctx context.Context, tx Transaction, report entity.Reports) (entity.Reports, error)
 	UpdateReport(ctx context.Context, tx Transaction, report entity.Reports) (entity.Reports, error)
+	UpdateProcessingReport(ctx context.Context, tx Transaction, report entity.Reports) (bool, error)
 	DeleteReport(ctx context.Context, tx Transaction, report entity.Reports) (entity.Reports, error)
+	GetAbandonedReportFiles(ctx context.Context, tx Transaction, limit int) ([]entity.Reports, error)
+	ClearReportFile(ctx context.Context, tx Transaction, id string) error
 }
 type reportsRepository struct {
 	db *gorm.DB
@@ -72,7 +77,7 @@ func (reports_repo *reportsRepository) GetReportByUserID(ctx context.Context, tx
 	}
 
 	var reports []entity.Reports
-	if err := db.Where("user_id = ?", user_id).Find(&reports).Error; err != nil {
+	if err := db.Where("user_id = ?", user_id).Order("request_at DESC").Find(&reports).Error; err != nil {
 		return nil, err
 	}
 	return reports, nil
@@ -91,6 +96,29 @@ func (reports_repo *reportsRepository) GetProcessedReportByUserID(ctx context.Co
 	return reports, nil
 }
 
+// GetLatestReportByUserIDAndType returns the last request of the report type that counts toward the cooldown,
+// failed reports are left out so they can be requested again right away. Cancelled reports count, otherwise
+// requesting and cancelling would get around the cooldown. It returns nil when there is none.
+func (reports_repo *reportsRepository) GetLatestReportByUserIDAndType(ctx context.Context, tx Transaction, user_id, report_type string) (*entity.Reports, error) {
+	db, err := reports_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var report entity.Reports
+	err = db.Where("user_id = ? AND type = ? AND status <> ?", user_id, report_type, data.REPORT_STATUS_FAILED).
+		Order("request_at DESC").
+		First(&report).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
+
 func (reports_repo *reportsRepository) CreateReport(ctx context.Context, tx Transaction, report entity.Reports) (entity.Reports, error) {
 	db, err := reports_repo.getDB(ctx, tx)
 	if err != nil {
@@ -115,6 +143,24 @@ func (reports_repo *reportsRepository) UpdateReport(ctx context.Context, tx Tran
 	return report, nil
 }
 
+// UpdateProcessingReport saves the report only while it is still processing in the database,
+// it returns false when the report was cancelled or finished in the meantime
+func (reports_repo *reportsRepository) UpdateProcessingReport(ctx context.Context, tx Transaction, report entity.Reports) (bool, error) {
+	db, err := reports_repo.getDB(ctx, tx)
+	if err != nil {
+		return false, err
+	}
+
+	result := db.Model(&entity.Reports{}).
+		Where("id = ? AND status = ?", report.ID, data.REPORT_STATUS_PROCESSING).
+		Select("status", "file_url", "file_size", "generated_at", "updated_at").
+		Updates(&report)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
 func (reports_repo *reportsRepository) DeleteReport(ctx context.Context, tx Transaction, report entity.Reports) (entity.Reports, error) {
 	db, err := reports_repo.getDB(ctx, tx)
 	if err != nil {
@@ -126,3 +172,48 @@ func (reports_repo *reportsRepository) DeleteReport(ctx context.Context, tx Tran
 	}
 	return report, nil
 }
+
+// GetAbandonedReportFiles returns failed and cancelled reports that still reference a generated file
+func (reports_repo *reportsRepository) GetAbandonedReportFiles(ctx context.Context, tx Transaction, limit int) ([]entity.Reports, error) {
+	db, err := reports_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var reports []entity.Reports
+	if err := db.Where("status IN ? AND file_url IS NOT NULL", []string{data.REPORT_STATUS_FAILED, data.REPORT_STATUS_CANCELLED}).
+		Order("request_at ASC").
+		Limit(limit).
+		Find(&reports).Error; err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// ClearReportFile removes the file reference of a report whose file was deleted
+func (reports_repo *reportsRepository) ClearReportFile(ctx context.Context, tx Transaction, id string) error {
+	db, err := reports_repo.getDB(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	return db.Model(&entity.Reports{}).Where("id = ?", id).Updates(map[string]any{
+		"file_url":  nil,
+		"file_size": nil,
+	}).Error
+}
+
+// LockUserReports serializes the report requests of the user until the transaction ends, so two requests
+// cannot both pass the cooldown check before either report is created
+func (reports_repo *reportsRepository) LockUserReports(ctx context.Context, tx Transaction, user_id string) error {
+	if tx == nil {
+		return errors.New("locking the reports of a user requires a transaction")
+	}
+
+	db, err := reports_repo.getDB(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	return db.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "user_report:"+user_id).Error
+}
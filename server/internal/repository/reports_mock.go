@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+
+	"server/internal/types/entity"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type reportsRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func NewReportsRepositoryMock() *reportsRepositoryMock {
+	return &reportsRepositoryMock{Mock: mock.Mock{}}
+}
+
+func (report_repo *reportsRepositoryMock) GetAllReports(ctx context.Context, tx Transaction) ([]entity.Reports, error) {
+	arguments := report_repo.Mock.Called(ctx, tx)
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).([]entity.Reports)
+	return result, nil
+}
+
+func (report_repo *reportsRepositoryMock) GetReportByID(ctx context.Context, tx Transaction, id string) (entity.Reports, error) {
+	arguments := report_repo.Mock.Called(ctx, tx, id)
+	if arguments.Get(1) != nil {
+		return entity.Reports{}, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).(entity.Reports)
+	return result, nil
+}
+
+func (report_repo *reportsRepositoryMock) GetReportByUserID(ctx context.Context, tx Transaction, user_id string) ([]entity.Reports, error) {
+	arguments := report_repo.Mock.Called(ctx, tx, user_id)
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).([]entity.Reports)
+	return result, nil
+}
+
+func (report_repo *reportsRepositoryMock) GetProcessedReportByUserID(ctx context.Context, tx Transaction, user_id string) ([]entity.Reports, error) {
+	arguments := report_repo.Mock.Called(ctx, tx, user_id)
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).([]entity.Reports)
+	return result, nil
+}
+
+func (report_repo *reportsRepositoryMock) GetLatestReportByUserIDAndType(ctx context.Context, tx Transaction, user_id, report_type string) (*entity.Reports, error) {
+	arguments := report_repo.Mock.Called(ctx, tx, user_id, report_type)
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).(*entity.Reports)
+	return result, nil
+}
+
+func (report_repo *reportsRepositoryMock) LockUserReports(ctx context.Context, tx Transaction, user_id string) error {
+	arguments := report_repo.Mock.Called(ctx, tx, user_id)
+	return arguments.Error(0)
+}
+
+func (report_repo *reportsRepositoryMock) CreateReport(ctx context.Context, tx Transaction, report entity.Reports) (entity.Reports, error) {
+	arguments := report_repo.Mock.Called(ctx, tx, report)
+	if arguments.Get(1) != nil {
+		return entity.Reports{}, arguments.Error(1)
+	}
+
+	// * Without a return value the report is created as given
+	result, ok := arguments.Get(0).(entity.Reports)
+	if !ok {
+		if report.ID == uuid.Nil {
+			report.ID = uuid.New()
+		}
+		return report, nil
+	}
+
+	return result, nil
+}
+
+func (report_repo *reportsRepositoryMock) UpdateReport(ctx context.Context, tx Transaction, report entity.Reports) (entity.Reports, error) {
+	arguments := report_repo.Mock.Called(ctx, tx, report)
+	if arguments.Get(1) != nil {
+		return entity.Reports{}, arguments.Error(1)
+	}
+
+	// * Without a return value the report is returned as given
+	result, ok := arguments.Get(0).(entity.Reports)
+	if !ok {
+		return report, nil
+	}
+
+	return result, nil
+}
+
+func (report_repo *reportsRepositoryMock) UpdateProcessingReport(ctx context.Context, tx Transaction, report entity.Reports) (bool, error) {
+	arguments := report_repo.Mock.Called(ctx, tx, report)
+	if arguments.Get(1) != nil {
+		return false, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).(bool)
+	return result, nil
+}
+
+func (report_repo *reportsRepositoryMock) DeleteReport(ctx context.Context, tx Transaction, report entity.Reports) (entity.Reports, error) {
+	arguments := report_repo.Mock.Called(ctx, tx, report)
+	if arguments.Get(1) != nil {
+		return entity.Reports{}, arguments.Error(1)
+	}
+
+	// * Without a return value the report is returned as given
+	result, ok := arguments.Get(0).(entity.Reports)
+	if !ok {
+		return report, nil
+	}
+
+	return result, nil
+}
+
+func (report_repo *reportsRepositoryMock) GetAbandonedReportFiles(ctx context.Context, tx Transaction, limit int) ([]entity.Reports, error) {
+	arguments := report_repo.Mock.Called(ctx, tx, limit)
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).([]entity.Reports)
+	return result, nil
+}
+
+func (report_repo *reportsRepositoryMock) ClearReportFile(ctx context.Context, tx Transaction, id string) error {
+	arguments := report_repo.Mock.Called(ctx, tx, id)
+	return arguments.Error(0)
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"server/internal/types/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ScheduledJobRunsRepository interface {
+	CreateScheduledJobRun(ctx context.Context, tx Transaction, run entity.ScheduledJobRuns) (entity.ScheduledJobRuns, error)
+	ClaimScheduledJobRun(ctx context.Context, tx Transaction, run entity.ScheduledJobRuns) (entity.ScheduledJobRuns, bool, error)
+	UpdateScheduledJobRun(ctx context.Context, tx Transaction, run entity.ScheduledJobRuns) (entity.ScheduledJobRuns, error)
+	GetScheduledJobRuns(ctx context.Context, tx Transaction, jobName string, limit int) ([]entity.ScheduledJobRuns, error)
+	TryLockScheduledJob(ctx context.Context, tx Transaction, jobName string) (bool, error)
+}
+
+type scheduledJobRunsRepository struct {
+	db *gorm.DB
+}
+
+func NewScheduledJobRunsRepository(db *gorm.DB) ScheduledJobRunsRepository {
+	return &scheduledJobRunsRepository{db}
+}
+
+// Helper untuk mendapatkan DB instance (transaksi atau biasa)
+func (job_repo *scheduledJobRunsRepository) getDB(ctx context.Context, tx Transaction) (*gorm.DB, error) {
+	if tx != nil {
+		gormTx, ok := tx.(*GormTx) // Type assertion ke GORM transaction
+		if !ok {
+			return nil, errors.New("invalid transaction type")
+		}
+		return gormTx.db.WithContext(ctx), nil
+	}
+	return job_repo.db.WithContext(ctx), nil
+}
+
+func (job_repo *scheduledJobRunsRepository) CreateScheduledJobRun(ctx context.Context, tx Transaction, run entity.ScheduledJobRuns) (entity.ScheduledJobRuns, error) {
+	db, err := job_repo.getDB(ctx, tx)
+	if err != nil {
+		return entity.ScheduledJobRuns{}, err
+	}
+
+	if err := db.Create(&run).Error; err != nil {
+		return entity.ScheduledJobRuns{}, err
+	}
+
+	return run, nil
+}
+
+// ClaimScheduledJobRun creates the run of a schedule slot, it returns false when another worker already
+// created the run of the same slot
+func (job_repo *scheduledJobRunsRepository) ClaimScheduledJobRun(ctx context.Context, tx Transaction, run entity.ScheduledJobRuns) (entity.ScheduledJobRuns, bool, error) {
+	db, err := job_repo.getDB(ctx, tx)
+	if err != nil {
+		return entity.ScheduledJobRuns{}, false, err
+	}
+
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&run)
+	if result.Error != nil {
+		return entity.ScheduledJobRuns{}, false, result.Error
+	}
+
+	return run, result.RowsAffected == 1, nil
+}
+
+func (job_repo *scheduledJobRunsRepository) UpdateScheduledJobRun(ctx context.Context, tx Transaction, run entity.ScheduledJobRuns) (entity.ScheduledJobRuns, error) {
+	db, err := job_repo.getDB(ctx, tx)
+	if err != nil {
+		return entity.ScheduledJobRuns{}, err
+	}
+
+	if err := db.Save(&run).Error; err != nil {
+		return entity.ScheduledJobRuns{}, err
+	}
+
+	return run, nil
+}
+
+// GetScheduledJobRuns returns the latest runs first, of all jobs when jobName is empty
+func (job_repo *scheduledJobRunsRepository) GetScheduledJobRuns(ctx context.Context, tx Transaction, jobName string, limit int) ([]entity.ScheduledJobRuns, error) {
+	db, err := job_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if jobName != "" {
+		db = db.Where("job_name = ?", jobName)
+	}
+
+	var runs []entity.ScheduledJobRuns
+	if err := db.Order("created_at DESC").Limit(limit).Find(&runs).Error; err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
+
+// TryLockScheduledJob takes the advisory lock of the job until the transaction ends, it returns false
+// without waiting when another worker holds it
+func (job_repo *scheduledJobRunsRepository) TryLockScheduledJob(ctx context.Context, tx Transaction, jobName string) (bool, error) {
+	if tx == nil {
+		return false, errors.New("locking a scheduled job requires a transaction")
+	}
+
+	db, err := job_repo.getDB(ctx, tx)
+	if err != nil {
+		return false, err
+	}
+
+	var locked bool
+	if err := db.Raw("SELECT pg_try_advisory_xact_lock(hashtext(?))", "scheduled_job:"+jobName).Scan(&locked).Error; err != nil {
+		return false, err
+	}
+
+	return locked, nil
+}
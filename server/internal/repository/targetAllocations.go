@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"server/internal/types/entity"
+	"server/internal/types/view"
+
+	"gorm.io/gorm"
+)
+
+type TargetAllocationsRepository interface {
+	GetTargetAllocationsByUserID(ctx context.Context, tx Transaction, userID string) ([]entity.TargetAllocations, error)
+	ReplaceTargetAllocations(ctx context.Context, tx Transaction, userID string, targets []entity.TargetAllocations) ([]entity.TargetAllocations, error)
+	GetUserAllocations(ctx context.Context, tx Transaction, userID string) ([]view.ViewUserAllocations, error)
+}
+
+type targetAllocationsRepository struct {
+	db *gorm.DB
+}
+
+func NewTargetAllocationsRepository(db *gorm.DB) TargetAllocationsRepository {
+	return &targetAllocationsRepository{db}
+}
+
+// Helper untuk mendapatkan DB instance (transaksi atau biasa)
+func (allocation_repo *targetAllocationsRepository) getDB(ctx context.Context, tx Transaction) (*gorm.DB, error) {
+	if tx != nil {
+		gormTx, ok := tx.(*GormTx) // Type assertion ke GORM transaction
+		if !ok {
+			return nil, errors.New("invalid transaction type")
+		}
+		return gormTx.db.WithContext(ctx), nil
+	}
+	return allocation_repo.db.WithContext(ctx), nil
+}
+
+func (allocation_repo *targetAllocationsRepository) GetTargetAllocationsByUserID(ctx context.Context, tx Transaction, userID string) ([]entity.TargetAllocations, error) {
+	db, err := allocation_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []entity.TargetAllocations
+	if err := db.Preload("InvestmentType").Where("user_id = ?", userID).Order("target_percent DESC").Find(&targets).Error; err != nil {
+		return nil, err
+	}
+
+	return targets, nil
+}
+
+// ReplaceTargetAllocations removes the previous targets of the user for good, a target allocation is always set as a whole
+func (allocation_repo *targetAllocationsRepository) ReplaceTargetAllocations(ctx context.Context, tx Transaction, userID string, targets []entity.TargetAllocations) ([]entity.TargetAllocations, error) {
+	db, err := allocation_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Unscoped().Where("user_id = ?", userID).Delete(&entity.TargetAllocations{}).Error; err != nil {
+		return nil, err
+	}
+
+	if len(targets) == 0 {
+		return targets, nil
+	}
+
+	if err := db.Omit("User", "InvestmentType").Create(&targets).Error; err != nil {
+		return nil, err
+	}
+
+	return targets, nil
+}
+
+func (allocation_repo *targetAllocationsRepository) GetUserAllocations(ctx context.Context, tx Transaction, userID string) ([]view.ViewUserAllocations, error) {
+	db, err := allocation_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var allocations []view.ViewUserAllocations
+	if err := db.Table("view_user_allocations").Where("user_id = ?", userID).Order("target_percent DESC, current_value DESC").Find(&allocations).Error; err != nil {
+		return nil, err
+	}
+
+	return allocations, nil
+}
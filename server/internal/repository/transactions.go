@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"server/internal/types/entity"
 	"server/internal/types/view"
@@ -15,6 +16,7 @@ type TransactionsRepository interface {
 	GetTransactionByID(ctx context.Context, tx Transaction, id string) (entity.Transactions, error)
 	GetTransactionByIDJoin(ctx context.Context, tx Transaction, id string) (view.ViewUserTransactions, error)
 	GetTransactionsByUserID(ctx context.Context, tx Transaction, id string) ([]view.ViewUserTransactions, error)
+	GetTransactionsByUserIDAndPeriod(ctx context.Context, tx Transaction, userID string, from, to time.Time) ([]entity.Transactions, error)
 	CreateTransaction(ctx context.Context, tx Transaction, transaction entity.Transactions) (entity.Transactions, error)
 	UpdateTransaction(ctx context.Context, tx Transaction, transaction entity.Transactions) (entity.Transactions, error)
 	DeleteTransaction(ctx context.Context, tx Transaction, transaction entity.Transactions) (entity.Transactions, error)
@@ -101,6 +103,28 @@ func (transaction_repo *transactionsRepository) GetTransactionsByUserID(ctx cont
 	return userTransactions, nil
 }
 
+// GetTransactionsByUserIDAndPeriod returns the transactions of every wallet of the user in from..to, ordered by date.
+// Wallets and categories are loaded even when they are deleted so old transactions keep their names.
+func (transaction_repo *transactionsRepository) GetTransactionsByUserIDAndPeriod(ctx context.Context, tx Transaction, userID string, from, to time.Time) ([]entity.Transactions, error) {
+	db, err := transaction_repo.getDB(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	unscoped := func(db *gorm.DB) *gorm.DB { return db.Unscoped() }
+
+	var transactions []entity.Transactions
+	err = db.Preload("Wallet", unscoped).Preload("Wallet.WalletType", unscoped).Preload("Category", unscoped).
+		Joins("JOIN wallets ON wallets.id = transactions.wallet_id").
+		Where("wallets.user_id = ? AND transactions.transaction_date BETWEEN ? AND ?", userID, from, to).
+		Order("transactions.transaction_date, transactions.created_at").
+		Find(&transactions).Error
+	if err != nil {
+		return nil, errors.New("user transactions not found")
+	}
+	return transactions, nil
+}
+
 func (transaction_repo *transactionsRepository) CreateTransaction(ctx context.Context, tx Transaction, transaction entity.Transactions) (entity.Transactions, error) {
 	db, err := transaction_repo.getDB(ctx, tx)
 	if err != nil {
@@ -146,9 +170,12 @@ func (transaction_repo *transactionsRepository) GetUserSummary(ctx context.Conte
 	}
 
 	var summaries []view.MVUserSummaries
-	query := db.Table("view_user_summaries")
+	// * Drift depends on live prices and targets, so it is joined instead of materialized
+	query := db.Table("view_user_summaries").
+		Select("view_user_summaries.*, view_user_allocation_drift.allocation_drift, view_user_allocation_drift.rebalance_needed").
+		Joins("LEFT JOIN view_user_allocation_drift ON view_user_allocation_drift.user_id = view_user_summaries.user_id")
 	if userID != nil {
-		query = query.Where("user_id = ?", *userID)
+		query = query.Where("view_user_summaries.user_id = ?", *userID)
 	}
 	err = query.Find(&summaries).Error
 	if err != nil {
@@ -196,21 +223,36 @@ func (transaction_repo *transactionsRepository) GetUserMostExpenses(ctx context.
 	return expenses, nil
 }
 
+// GetUserWalletDailySummary folds the rows of each wallet type into one summary per user and date
 func (transaction_repo *transactionsRepository) GetUserWalletDailySummary(ctx context.Context, tx Transaction, userID *string) ([]view.MVUserWalletDailySummaries, error) {
 	db, err := transaction_repo.getDB(ctx, tx)
 	if err != nil {
 		return nil, err
 	}
 
-	var summaries []view.MVUserWalletDailySummaries
+	var rows []view.ViewUserWalletTypeDailyBalances
 	query := db.Table("view_user_wallet_daily_summaries")
 	if userID != nil {
 		query = query.Where("user_id = ?", *userID)
 	}
-	err = query.Find(&summaries).Error
+	err = query.Order("user_id, date").Find(&rows).Error
 	if err != nil {
 		return nil, errors.New("user wallet daily summaries not found")
 	}
 
+	summaries := []view.MVUserWalletDailySummaries{}
+	for _, row := range rows {
+		last := len(summaries) - 1
+		if last < 0 || summaries[last].UserID != row.UserID || summaries[last].Date != row.Date {
+			summaries = append(summaries, view.MVUserWalletDailySummaries{
+				UserID: row.UserID,
+				Date:   row.Date,
+				Types:  map[string]float64{},
+			})
+			last++
+		}
+		summaries[last].Types[row.WalletType] += row.Total
+	}
+
 	return summaries, nil
 }
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"server/internal/types/entity"
+	"server/internal/types/view"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type transactionsRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func NewTransactionsRepositoryMock() *transactionsRepositoryMock {
+	return &transactionsRepositoryMock{Mock: mock.Mock{}}
+}
+
+func (transaction_repo *transactionsRepositoryMock) GetAllTransactions(ctx context.Context, tx Transaction) ([]view.ViewUserTransactions, error) {
+	arguments := transaction_repo.Mock.Called(ctx, tx)
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).([]view.ViewUserTransactions)
+	return result, nil
+}
+
+func (transaction_repo *transactionsRepositoryMock) GetTransactionByID(ctx context.Context, tx Transaction, id string) (entity.Transactions, error) {
+	arguments := transaction_repo.Mock.Called(ctx, tx, id)
+	if arguments.Get(1) != nil {
+		return entity.Transactions{}, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).(entity.Transactions)
+	return result, nil
+}
+
+func (transaction_repo *transactionsRepositoryMock) GetTransactionByIDJoin(ctx context.Context, tx Transaction, id string) (view.ViewUserTransactions, error) {
+	arguments := transaction_repo.Mock.Called(ctx, tx, id)
+	if arguments.Get(1) != nil {
+		return view.ViewUserTransactions{}, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).(view.ViewUserTransactions)
+	return result, nil
+}
+
+func (transaction_repo *transactionsRepositoryMock) GetTransactionsByUserID(ctx context.Context, tx Transaction, id string) ([]view.ViewUserTransactions, error) {
+	arguments := transaction_repo.Mock.Called(ctx, tx, id)
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).([]view.ViewUserTransactions)
+	return result, nil
+}
+
+func (transaction_repo *transactionsRepositoryMock) GetTransactionsByUserIDAndPeriod(ctx context.Context, tx Transaction, userID string, from, to time.Time) ([]entity.Transactions, error) {
+	arguments := transaction_repo.Mock.Called(ctx, tx, userID, from, to)
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).([]entity.Transactions)
+	return result, nil
+}
+
+func (transaction_repo *transactionsRepositoryMock) CreateTransaction(ctx context.Context, tx Transaction, transaction entity.Transactions) (entity.Transactions, error) {
+	arguments := transaction_repo.Mock.Called(ctx, tx, transaction)
+	if arguments.Get(1) != nil {
+		return entity.Transactions{}, arguments.Error(1)
+	}
+
+	// * Without a return value the transaction is created as given
+	result, ok := arguments.Get(0).(entity.Transactions)
+	if !ok {
+		if transaction.ID == uuid.Nil {
+			transaction.ID = uuid.New()
+		}
+		return transaction, nil
+	}
+
+	return result, nil
+}
+
+func (transaction_repo *transactionsRepositoryMock) UpdateTransaction(ctx context.Context, tx Transaction, transaction entity.Transactions) (entity.Transactions, error) {
+	arguments := transaction_repo.Mock.Called(ctx, tx, transaction)
+	if arguments.Get(1) != nil {
+		return entity.Transactions{}, arguments.Error(1)
+	}
+
+	// * Without a return value the transaction is returned as given
+	result, ok := arguments.Get(0).(entity.Transactions)
+	if !ok {
+		return transaction, nil
+	}
+
+	return result, nil
+}
+
+func (transaction_repo *transactionsRepositoryMock) DeleteTransaction(ctx context.Context, tx Transaction, transaction entity.Transactions) (entity.Transactions, error) {
+	arguments := transaction_repo.Mock.Called(ctx, tx, transaction)
+	if arguments.Get(1) != nil {
+		return entity.Transactions{}, arguments.Error(1)
+	}
+
+	// * Without a return value the transaction is returned as given
+	result, ok := arguments.Get(0).(entity.Transactions)
+	if !ok {
+		return transaction, nil
+	}
+
+	return result, nil
+}
+
+func (transaction_repo *transactionsRepositoryMock) GetUserSummary(ctx context.Context, tx Transaction, userID *string) ([]view.MVUserSummaries, error) {
+	arguments := transaction_repo.Mock.Called(ctx, tx, userID)
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).([]view.MVUserSummaries)
+	return result, nil
+}
+
+func (transaction_repo *transactionsRepositoryMock) GetUserMonthlySummary(ctx context.Context, tx Transaction, userID *string) ([]view.MVUserMonthlySummaries, error) {
+	arguments := transaction_repo.Mock.Called(ctx, tx, userID)
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).([]view.MVUserMonthlySummaries)
+	return result, nil
+}
+
+func (transaction_repo *transactionsRepositoryMock) GetUserMostExpenses(ctx context.Context, tx Transaction, userID *string) ([]view.MVUserMostExpenses, error) {
+	arguments := transaction_repo.Mock.Called(ctx, tx, userID)
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).([]view.MVUserMostExpenses)
+	return result, nil
+}
+
+func (transaction_repo *transactionsRepositoryMock) GetUserWalletDailySummary(ctx context.Context, tx Transaction, userID *string) ([]view.MVUserWalletDailySummaries, error) {
+	arguments := transaction_repo.Mock.Called(ctx, tx, userID)
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).([]view.MVUserWalletDailySummaries)
+	return result, nil
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type txManagerMock struct {
+	Mock mock.Mock
+}
+
+// NewTxManagerMock begins the transaction given to On("Begin"), usually a transactionMock
+func NewTxManagerMock() *txManagerMock {
+	return &txManagerMock{Mock: mock.Mock{}}
+}
+
+func (txm *txManagerMock) Begin(ctx context.Context) (Transaction, error) {
+	arguments := txm.Mock.Called(ctx)
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).(Transaction)
+	return result, nil
+}
+
+type transactionMock struct {
+	Mock mock.Mock
+}
+
+func NewTransactionMock() *transactionMock {
+	return &transactionMock{Mock: mock.Mock{}}
+}
+
+func (txm *transactionMock) Commit() error {
+	arguments := txm.Mock.Called()
+	return arguments.Error(0)
+}
+
+func (txm *transactionMock) Rollback() error {
+	arguments := txm.Mock.Called()
+	return arguments.Error(0)
+}
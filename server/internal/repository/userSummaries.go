@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type UserSummariesRepository interface {
+	RecomputeUserSummaries(ctx context.Context, tx Transaction, userID *string, from, to time.Time) error
+}
+
+type userSummariesRepository struct {
+	db *gorm.DB
+}
+
+func NewUserSummariesRepository(db *gorm.DB) UserSummariesRepository {
+	return &userSummariesRepository{db}
+}
+
+// Helper untuk mendapatkan DB instance (transaksi atau biasa)
+func (summary_repo *userSummariesRepository) getDB(ctx context.Context, tx Transaction) (*gorm.DB, error) {
+	if tx != nil {
+		gormTx, ok := tx.(*GormTx) // Type assertion ke GORM transaction
+		if !ok {
+			return nil, errors.New("invalid transaction type")
+		}
+		return gormTx.db.WithContext(ctx), nil
+	}
+	return summary_repo.db.WithContext(ctx), nil
+}
+
+// RecomputeUserSummaries rebuilds the summary tables from the transactions dated from the start of the month of
+// from until before to, of a single user or of every user when userID is nil. The period is replaced as a whole
+// so a retried or duplicated event gives the same result.
+func (summary_repo *userSummariesRepository) RecomputeUserSummaries(ctx context.Context, tx Transaction, userID *string, from, to time.Time) error {
+	if tx == nil {
+		return errors.New("recomputing user summaries requires a transaction")
+	}
+
+	db, err := summary_repo.getDB(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	// Events of the same user are handled one after another, a recompute reads the transactions of the others
+	if userID != nil {
+		if err := db.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "user_summary:"+*userID).Error; err != nil {
+			return err
+		}
+	}
+
+	fromDate := from.Format("2006-01-02")
+	toDate := to.Format("2006-01-02")
+
+	statements := []struct {
+		query string
+		args  []interface{}
+	}{
+		{
+			query: `DELETE FROM user_monthly_summaries
+			WHERE (?::uuid IS NULL OR user_id = ?::uuid)
+				AND month >= date_trunc('month', ?::date) AND month < ?::date`,
+			args: []interface{}{userID, userID, fromDate, toDate},
+		},
+		{
+			query: `INSERT INTO user_monthly_summaries (user_id, month, total_income, total_expense, updated_at)
+			SELECT
+				w.user_id,
+				date_trunc('month', t.transaction_date)::date,
+				COALESCE(SUM(CASE WHEN c.type = 'income' THEN t.amount END), 0),
+				COALESCE(SUM(CASE WHEN c.type = 'expense' THEN t.amount END), 0),
+				now()
+			FROM transactions t
+			JOIN wallets w ON w.id = t.wallet_id
+			JOIN categories c ON c.id = t.category_id
+			WHERE t.deleted_at IS NULL
+				AND (?::uuid IS NULL OR w.user_id = ?::uuid)
+				AND t.transaction_date >= date_trunc('month', ?::date) AND t.transaction_date < ?::date
+			GROUP BY w.user_id, date_trunc('month', t.transaction_date)
+			ON CONFLICT (user_id, month) DO UPDATE SET
+				total_income = EXCLUDED.total_income,
+				total_expense = EXCLUDED.total_expense,
+				updated_at = EXCLUDED.updated_at`,
+			args: []interface{}{userID, userID, fromDate, toDate},
+		},
+		{
+			query: `DELETE FROM user_category_monthly_expenses
+			WHERE (?::uuid IS NULL OR user_id = ?::uuid)
+				AND month >= date_trunc('month', ?::date) AND month < ?::date`,
+			args: []interface{}{userID, userID, fromDate, toDate},
+		},
+		{
+			query: `INSERT INTO user_category_monthly_expenses (user_id, month, parent_category_name, total, updated_at)
+			SELECT
+				w.user_id,
+				date_trunc('month', t.transaction_date)::date,
+				COALESCE(parent.name, c.name),
+				SUM(t.amount),
+				now()
+			FROM transactions t
+			JOIN wallets w ON w.id = t.wallet_id
+			JOIN categories c ON c.id = t.category_id
+			LEFT JOIN categories parent ON parent.id = c.parent_id
+			WHERE t.deleted_at IS NULL
+				AND c.type = 'expense'
+				AND (?::uuid IS NULL OR w.user_id = ?::uuid)
+				AND t.transaction_date >= date_trunc('month', ?::date) AND t.transaction_date < ?::date
+			GROUP BY w.user_id, date_trunc('month', t.transaction_date), COALESCE(parent.name, c.name)
+			ON CONFLICT (user_id, month, parent_category_name) DO UPDATE SET
+				total = EXCLUDED.total,
+				updated_at = EXCLUDED.updated_at`,
+			args: []interface{}{userID, userID, fromDate, toDate},
+		},
+		{
+			query: `DELETE FROM user_wallet_daily_flows
+			WHERE (?::uuid IS NULL OR user_id = ?::uuid)
+				AND date >= date_trunc('month', ?::date) AND date < ?::date`,
+			args: []interface{}{userID, userID, fromDate, toDate},
+		},
+		{
+			query: `INSERT INTO user_wallet_daily_flows (wallet_id, user_id, date, amount, updated_at)
+			SELECT
+				t.wallet_id,
+				w.user_id,
+				DATE(t.transaction_date),
+				SUM(
+				CASE
+					WHEN c.type = 'expense' OR (c.type = 'fund_transfer' AND c.name = 'Cash Out') THEN -1 * t.amount
+					WHEN c.type = 'income' OR (c.type = 'fund_transfer' AND c.name = 'Cash In') THEN t.amount
+					ELSE 0
+				END
+				),
+				now()
+			FROM transactions t
+			JOIN wallets w ON w.id = t.wallet_id
+			JOIN categories c ON c.id = t.category_id
+			WHERE t.deleted_at IS NULL
+				AND (?::uuid IS NULL OR w.user_id = ?::uuid)
+				AND t.transaction_date >= date_trunc('month', ?::date) AND t.transaction_date < ?::date
+			GROUP BY t.wallet_id, w.user_id, DATE(t.transaction_date)
+			ON CONFLICT (wallet_id, date) DO UPDATE SET
+				amount = EXCLUDED.amount,
+				updated_at = EXCLUDED.updated_at`,
+			args: []interface{}{userID, userID, fromDate, toDate},
+		},
+	}
+
+	for _, statement := range statements {
+		if err := db.Exec(statement.query, statement.args...).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"server/internal/types/entity"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type usersRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func NewUsersRepositoryMock() *usersRepositoryMock {
+	return &usersRepositoryMock{Mock: mock.Mock{}}
+}
+
+func (user_repo *usersRepositoryMock) GetAllUsers() ([]entity.Users, error) {
+	arguments := user_repo.Mock.Called()
+	if arguments.Get(1) != nil {
+		return nil, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).([]entity.Users)
+	return result, nil
+}
+
+func (user_repo *usersRepositoryMock) GetUserByID(id string) (entity.Users, error) {
+	arguments := user_repo.Mock.Called(id)
+	if arguments.Get(1) != nil {
+		return entity.Users{}, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).(entity.Users)
+	return result, nil
+}
+
+func (user_repo *usersRepositoryMock) GetUserByEmail(email string) (entity.Users, error) {
+	arguments := user_repo.Mock.Called(email)
+	if arguments.Get(1) != nil {
+		return entity.Users{}, arguments.Error(1)
+	}
+
+	result, _ := arguments.Get(0).(entity.Users)
+	return result, nil
+}
+
+func (user_repo *usersRepositoryMock) CreateUser(user entity.Users) (entity.Users, error) {
+	arguments := user_repo.Mock.Called(user)
+	if arguments.Get(1) != nil {
+		return entity.Users{}, arguments.Error(1)
+	}
+
+	// * Without a return value the user is created as given
+	result, ok := arguments.Get(0).(entity.Users)
+	if !ok {
+		if user.ID == uuid.Nil {
+			user.ID = uuid.New()
+		}
+		return user, nil
+	}
+
+	return result, nil
+}
+
+func (user_repo *usersRepositoryMock) UpdateUser(user entity.Users) (entity.Users, error) {
+	arguments := user_repo.Mock.Called(user)
+	if arguments.Get(1) != nil {
+		return entity.Users{}, arguments.Error(1)
+	}
+
+	// * Without a return value the user is returned as given
+	result, ok := arguments.Get(0).(entity.Users)
+	if !ok {
+		return user, nil
+	}
+
+	return result, nil
+}
+
+func (user_repo *usersRepositoryMock) DeleteUser(user entity.Users) (entity.Users, error) {
+	arguments := user_repo.Mock.Called(user)
+	if arguments.Get(1) != nil {
+		return entity.Users{}, arguments.Error(1)
+	}
+
+	// * Without a return value the user is returned as given
+	result, ok := arguments.Get(0).(entity.Users)
+	if !ok {
+		return user, nil
+	}
+
+	return result, nil
+}
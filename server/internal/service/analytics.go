@@ -0,0 +1,483 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"server/config/db"
+	"server/internal/repository"
+	"server/internal/types/dto"
+	"server/internal/types/entity"
+	helper "server/internal/utils"
+	"server/internal/utils/analytics"
+	"server/internal/utils/data"
+)
+
+type AnalyticsService interface {
+	GetCashflow(ctx context.Context, token string, query dto.CashflowQuery) (dto.CashflowResponse, error)
+	GetBalances(ctx context.Context, token string, query dto.BalancesQuery) (dto.BalancesResponse, error)
+	GetForecast(ctx context.Context, token string, query dto.ForecastQuery) (dto.ForecastResponse, error)
+}
+
+type analyticsService struct {
+	analyticsRepo repository.AnalyticsRepository
+	recurringRepo repository.RecurringTransactionsRepository
+}
+
+func NewAnalyticsService(analyticsRepo repository.AnalyticsRepository, recurringRepo repository.RecurringTransactionsRepository) AnalyticsService {
+	return &analyticsService{analyticsRepo, recurringRepo}
+}
+
+// GetCashflow returns the income and expense of the range per period, optionally split into groups and compared
+// with an earlier range. Every series has one value per period so it can be charted as is.
+func (analytics_serv *analyticsService) GetCashflow(ctx context.Context, token string, query dto.CashflowQuery) (dto.CashflowResponse, error) {
+	userData, err := helper.VerifyToken(token[7:])
+	if err != nil {
+		return dto.CashflowResponse{}, errors.New("invalid token")
+	}
+
+	to := truncateDate(query.To)
+	if query.To.IsZero() {
+		to = currentDate()
+	}
+	from := truncateDate(query.From)
+	if query.From.IsZero() {
+		from = analytics.PeriodStart(to, analytics.GRANULARITY_MONTH).AddDate(0, -11, 0)
+	}
+
+	granularity := query.Granularity
+	if granularity == "" {
+		granularity = analytics.GRANULARITY_MONTH
+	}
+
+	switch query.GroupBy {
+	case "", data.CASHFLOW_GROUP_BY_CATEGORY, data.CASHFLOW_GROUP_BY_PARENT_CATEGORY, data.CASHFLOW_GROUP_BY_WALLET, data.CASHFLOW_GROUP_BY_WALLET_TYPE:
+	default:
+		return dto.CashflowResponse{}, fmt.Errorf("unknown group_by '%s'", query.GroupBy)
+	}
+
+	periods, series, total, err := analytics_serv.cashflow(ctx, userData.ID, from, to, granularity, query.GroupBy)
+	if err != nil {
+		return dto.CashflowResponse{}, err
+	}
+
+	response := dto.CashflowResponse{
+		From:        from.Format("2006-01-02"),
+		To:          to.Format("2006-01-02"),
+		Granularity: granularity,
+		GroupBy:     query.GroupBy,
+		Periods:     periods,
+		Series:      series,
+		Total:       total,
+	}
+
+	if query.Compare == "" {
+		return response, nil
+	}
+
+	compareFrom, compareTo, err := analytics.PreviousRange(from, to, query.Compare)
+	if err != nil {
+		return dto.CashflowResponse{}, err
+	}
+
+	comparePeriods, compareSeries, compareTotal, err := analytics_serv.cashflow(ctx, userData.ID, compareFrom, compareTo, granularity, query.GroupBy)
+	if err != nil {
+		return dto.CashflowResponse{}, err
+	}
+
+	comparison := &dto.CashflowCompare{
+		Type:    query.Compare,
+		From:    compareFrom.Format("2006-01-02"),
+		To:      compareTo.Format("2006-01-02"),
+		Periods: comparePeriods,
+		Series:  compareSeries,
+		Total:   compareTotal,
+		Change:  cashflowChange(total, compareTotal),
+		Changes: make(map[string]dto.CashflowChange, len(series)),
+	}
+
+	previous := make(map[string]dto.CashflowSeries, len(compareSeries))
+	for _, s := range compareSeries {
+		previous[s.Key] = s
+	}
+	for _, s := range series {
+		comparison.Changes[s.Key] = cashflowChange(s, previous[s.Key])
+	}
+
+	response.Comparison = comparison
+	return response, nil
+}
+
+// cashflow turns the rows of the range into series with a value for every period, periods without
+// transactions are 0. Series are sorted by the money that went through them, largest first.
+func (analytics_serv *analyticsService) cashflow(ctx context.Context, userID string, from, to time.Time, granularity, groupBy string) ([]dto.SeriesPeriod, []dto.CashflowSeries, dto.CashflowSeries, error) {
+	periods, err := analytics.Periods(from, to, granularity)
+	if err != nil {
+		return nil, nil, dto.CashflowSeries{}, err
+	}
+
+	rows, err := analytics_serv.analyticsRepo.GetCashflow(ctx, nil, userID, from, to, granularity, groupBy)
+	if err != nil {
+		return nil, nil, dto.CashflowSeries{}, errors.New("failed to get cash flow")
+	}
+
+	index := make(map[string]int, len(periods))
+	for i, period := range periods {
+		index[period.Label] = i
+	}
+
+	total := newCashflowSeries("total", "Total", len(periods))
+	groups := map[string]*dto.CashflowSeries{}
+	for _, row := range rows {
+		i, ok := index[analytics.Label(row.Period, granularity)]
+		if !ok {
+			continue
+		}
+
+		group, ok := groups[row.GroupKey]
+		if !ok {
+			series := newCashflowSeries(row.GroupKey, row.GroupLabel, len(periods))
+			group = &series
+			groups[row.GroupKey] = group
+		}
+
+		group.Income[i] += row.Income
+		group.Expense[i] += row.Expense
+		total.Income[i] += row.Income
+		total.Expense[i] += row.Expense
+	}
+
+	series := make([]dto.CashflowSeries, 0, len(groups))
+	if groupBy != "" {
+		for _, group := range groups {
+			series = append(series, finishCashflowSeries(*group))
+		}
+	}
+	sort.Slice(series, func(i, j int) bool {
+		vi := series[i].TotalIncome + series[i].TotalExpense
+		vj := series[j].TotalIncome + series[j].TotalExpense
+		if vi != vj {
+			return vi > vj
+		}
+		return series[i].Label < series[j].Label
+	})
+
+	return seriesPeriods(periods), series, finishCashflowSeries(total), nil
+}
+
+// GetBalances returns the balance of every wallet at the end of each period of the range, along with the sum per
+// wallet type and the total. Balances are worked back from the current balances so they match the wallets.
+func (analytics_serv *analyticsService) GetBalances(ctx context.Context, token string, query dto.BalancesQuery) (dto.BalancesResponse, error) {
+	userData, err := helper.VerifyToken(token[7:])
+	if err != nil {
+		return dto.BalancesResponse{}, errors.New("invalid token")
+	}
+
+	to := truncateDate(query.To)
+	if query.To.IsZero() {
+		to = currentDate()
+	}
+	from := truncateDate(query.From)
+	if query.From.IsZero() {
+		from = to.AddDate(0, 0, -89)
+	}
+
+	granularity := query.Granularity
+	if granularity == "" {
+		granularity = analytics.GRANULARITY_DAY
+	}
+
+	periods, err := analytics.Periods(from, to, granularity)
+	if err != nil {
+		return dto.BalancesResponse{}, err
+	}
+
+	wallets, err := analytics_serv.analyticsRepo.GetWalletBalances(ctx, nil, userData.ID)
+	if err != nil {
+		return dto.BalancesResponse{}, errors.New("failed to get wallets")
+	}
+
+	flows, err := analytics_serv.analyticsRepo.GetWalletDailyFlows(ctx, nil, userData.ID, from)
+	if err != nil {
+		return dto.BalancesResponse{}, errors.New("failed to get wallet transactions")
+	}
+
+	walletFlows := map[string][]analytics.Flow{}
+	for _, flow := range flows {
+		walletFlows[flow.WalletID] = append(walletFlows[flow.WalletID], analytics.Flow{Date: flow.Date, Amount: flow.Amount})
+	}
+
+	ends := make([]time.Time, len(periods))
+	for i, period := range periods {
+		ends[i] = period.End
+	}
+
+	response := dto.BalancesResponse{
+		From:        from.Format("2006-01-02"),
+		To:          to.Format("2006-01-02"),
+		Granularity: granularity,
+		Periods:     seriesPeriods(periods),
+		Wallets:     make([]dto.BalanceSeries, 0, len(wallets)),
+		WalletTypes: []dto.BalanceSeries{},
+		Total:       dto.BalanceSeries{Key: "total", Label: "Total", Balances: make([]float64, len(periods))},
+	}
+
+	types := map[string]int{}
+	for _, wallet := range wallets {
+		balances := analytics.BalancesAt(wallet.Balance, walletFlows[wallet.WalletID], ends)
+
+		i, ok := types[wallet.WalletType]
+		if !ok {
+			i = len(response.WalletTypes)
+			types[wallet.WalletType] = i
+			response.WalletTypes = append(response.WalletTypes, dto.BalanceSeries{
+				Key:      wallet.WalletType,
+				Label:    wallet.WalletType,
+				Balances: make([]float64, len(periods)),
+			})
+		}
+
+		for p, balance := range balances {
+			balances[p] = roundCents(balance)
+			response.WalletTypes[i].Balances[p] = roundCents(response.WalletTypes[i].Balances[p] + balance)
+			response.Total.Balances[p] = roundCents(response.Total.Balances[p] + balance)
+		}
+
+		response.Wallets = append(response.Wallets, dto.BalanceSeries{
+			Key:        wallet.WalletID,
+			Label:      wallet.Name,
+			WalletType: wallet.WalletType,
+			Balances:   balances,
+		})
+	}
+
+	sort.Slice(response.WalletTypes, func(i, j int) bool {
+		return response.WalletTypes[i].Key < response.WalletTypes[j].Key
+	})
+
+	return response, nil
+}
+
+// GetForecast projects the balance of every wallet for the days after today. The recurring transactions of the
+// user are expected on their next dates, and items booked monthly without one are detected from history and
+// expected again on their day of the month, on top of the usual spending per category estimated from the last weeks.
+func (analytics_serv *analyticsService) GetForecast(ctx context.Context, token string, query dto.ForecastQuery) (dto.ForecastResponse, error) {
+	userData, err := helper.VerifyToken(token[7:])
+	if err != nil {
+		return dto.ForecastResponse{}, errors.New("invalid token")
+	}
+
+	days := query.Days
+	if days == 0 {
+		days = data.FORECAST_DAYS[0]
+	}
+	if !slices.Contains(data.FORECAST_DAYS, days) {
+		return dto.ForecastResponse{}, fmt.Errorf("days must be one of %v", data.FORECAST_DAYS)
+	}
+
+	today := currentDate()
+	until := today.AddDate(0, 0, days)
+
+	wallets, err := analytics_serv.analyticsRepo.GetWalletBalances(ctx, nil, userData.ID)
+	if err != nil {
+		return dto.ForecastResponse{}, errors.New("failed to get wallets")
+	}
+
+	// Enough history for the complete months of the recurring items and the weeks of the spending estimate
+	historyFrom := analytics.PeriodStart(today, analytics.GRANULARITY_MONTH).AddDate(0, -analytics.RECURRING_MONTHS, 0)
+	if spendingFrom := today.AddDate(0, 0, -7*analytics.SPENDING_WEEKS+1); spendingFrom.Before(historyFrom) {
+		historyFrom = spendingFrom
+	}
+
+	transactions, err := analytics_serv.analyticsRepo.GetWalletTransactions(ctx, nil, userData.ID, historyFrom)
+	if err != nil {
+		return dto.ForecastResponse{}, errors.New("failed to get wallet transactions")
+	}
+
+	history := make([]analytics.HistoryItem, 0, len(transactions))
+	for _, transaction := range transactions {
+		history = append(history, analytics.HistoryItem{
+			WalletID:      transaction.WalletID,
+			CategoryID:    transaction.CategoryID,
+			Category:      transaction.CategoryName,
+			Amount:        transaction.Amount,
+			Date:          transaction.TransactionDate,
+			Discretionary: transaction.CategoryType == string(entity.Expense),
+		})
+	}
+
+	rules, err := analytics_serv.recurringRepo.GetRecurringTransactionsByUserID(ctx, nil, userData.ID)
+	if err != nil {
+		return dto.ForecastResponse{}, errors.New("failed to get recurring transactions")
+	}
+
+	walletIDs := make(map[string]bool, len(wallets))
+	for _, wallet := range wallets {
+		walletIDs[wallet.WalletID] = true
+	}
+	ruleItems, ruled := scheduleRecurringRules(rules, walletIDs, today, until)
+
+	// Items with a recurring transaction are scheduled from it, detecting them as well would count them twice
+	recurring := analytics.ExcludeRecurring(analytics.DetectRecurring(history, today), ruled)
+	scheduled := append(analytics.Schedule(recurring, today, until), ruleItems...)
+	sort.SliceStable(scheduled, func(i, j int) bool { return scheduled[i].Date.Before(scheduled[j].Date) })
+	spending := analytics.EstimateSpending(history, append(recurring, ruled...), today)
+
+	response := dto.ForecastResponse{
+		From:      today.AddDate(0, 0, 1).Format("2006-01-02"),
+		To:        until.Format("2006-01-02"),
+		Days:      days,
+		Dates:     make([]string, days),
+		Wallets:   make([]dto.ForecastSeries, 0, len(wallets)),
+		Total:     dto.ForecastSeries{Key: "total", Label: "Total", Balances: make([]float64, days), NegativeDates: []string{}},
+		Scheduled: make([]dto.ForecastItem, 0, len(scheduled)),
+		Spending:  make([]dto.ForecastSpending, 0, len(spending)),
+	}
+	for i := range response.Dates {
+		response.Dates[i] = today.AddDate(0, 0, i+1).Format("2006-01-02")
+	}
+
+	for _, wallet := range wallets {
+		series := dto.ForecastSeries{
+			Key:           wallet.WalletID,
+			Label:         wallet.Name,
+			WalletType:    wallet.WalletType,
+			Balances:      analytics.Project(wallet.WalletID, wallet.Balance, today, days, scheduled, spending),
+			NegativeDates: []string{},
+		}
+		for i, balance := range series.Balances {
+			series.Balances[i] = roundCents(balance)
+			response.Total.Balances[i] = roundCents(response.Total.Balances[i] + balance)
+			if series.Balances[i] < 0 {
+				series.NegativeDates = append(series.NegativeDates, response.Dates[i])
+			}
+		}
+		response.Wallets = append(response.Wallets, series)
+	}
+	for i, balance := range response.Total.Balances {
+		if balance < 0 {
+			response.Total.NegativeDates = append(response.Total.NegativeDates, response.Dates[i])
+		}
+	}
+
+	for _, item := range scheduled {
+		response.Scheduled = append(response.Scheduled, dto.ForecastItem{
+			WalletID: item.WalletID,
+			Label:    item.Label,
+			Date:     item.Date.Format("2006-01-02"),
+			Amount:   roundCents(item.Amount),
+		})
+	}
+	for _, estimate := range spending {
+		response.Spending = append(response.Spending, dto.ForecastSpending{
+			WalletID:   estimate.WalletID,
+			CategoryID: estimate.CategoryID,
+			Category:   estimate.Category,
+			Daily:      roundCents(estimate.Daily),
+		})
+	}
+
+	return response, nil
+}
+
+func seriesPeriods(periods []analytics.Period) []dto.SeriesPeriod {
+	result := make([]dto.SeriesPeriod, 0, len(periods))
+	for _, period := range periods {
+		result = append(result, dto.SeriesPeriod{
+			Label: period.Label,
+			Start: period.Start.Format("2006-01-02"),
+			End:   period.End.Format("2006-01-02"),
+		})
+	}
+	return result
+}
+
+func newCashflowSeries(key, label string, periods int) dto.CashflowSeries {
+	return dto.CashflowSeries{
+		Key:     key,
+		Label:   label,
+		Income:  make([]float64, periods),
+		Expense: make([]float64, periods),
+		Net:     make([]float64, periods),
+	}
+}
+
+// finishCashflowSeries fills the net values and the totals, rounded to cents
+func finishCashflowSeries(series dto.CashflowSeries) dto.CashflowSeries {
+	for i := range series.Income {
+		series.Income[i] = roundCents(series.Income[i])
+		series.Expense[i] = roundCents(series.Expense[i])
+		series.Net[i] = roundCents(series.Income[i] - series.Expense[i])
+
+		series.TotalIncome += series.Income[i]
+		series.TotalExpense += series.Expense[i]
+	}
+	series.TotalIncome = roundCents(series.TotalIncome)
+	series.TotalExpense = roundCents(series.TotalExpense)
+	series.TotalNet = roundCents(series.TotalIncome - series.TotalExpense)
+	return series
+}
+
+func cashflowChange(current, previous dto.CashflowSeries) dto.CashflowChange {
+	return dto.CashflowChange{
+		IncomePercentage:  analytics.ChangePercentage(current.TotalIncome, previous.TotalIncome),
+		ExpensePercentage: analytics.ChangePercentage(current.TotalExpense, previous.TotalExpense),
+		NetPercentage:     analytics.ChangePercentage(current.TotalNet, previous.TotalNet),
+	}
+}
+
+// scheduleRecurringRules expands the recurring transactions on the wallets into their bookings after today up to
+// until. Each rule is also returned as a recurring item of its wallet and category, to leave those out of the
+// items detected from history and of the spending estimate.
+func scheduleRecurringRules(rules []entity.RecurringTransactions, walletIDs map[string]bool, today, until time.Time) ([]analytics.ScheduledItem, []analytics.Recurring) {
+	var items []analytics.ScheduledItem
+	var ruled []analytics.Recurring
+	for _, rule := range rules {
+		amount := rule.Amount
+		switch rule.Category.Type {
+		case entity.Expense:
+			amount = -amount
+		case entity.Income:
+		default:
+			continue
+		}
+		if !walletIDs[rule.WalletID.String()] {
+			continue
+		}
+
+		ruled = append(ruled, analytics.Recurring{
+			WalletID:   rule.WalletID.String(),
+			CategoryID: rule.CategoryID.String(),
+			Category:   rule.Category.Name,
+			Amount:     amount,
+		})
+
+		// * Dates up to today are booked by the recurring_transactions job and are in the balance by then
+		for n := rule.Occurrences; ; n++ {
+			next := recurringDate(rule.StartDate, rule.Frequency, n)
+			date := truncateDate(next)
+			if date.After(until) || (rule.EndDate != nil && next.After(*rule.EndDate)) {
+				break
+			}
+			if !date.After(today) {
+				continue
+			}
+			items = append(items, analytics.ScheduledItem{WalletID: rule.WalletID.String(), Label: rule.Category.Name, Date: date, Amount: amount})
+		}
+	}
+	return items, ruled
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// currentDate is today in the time zone of the database session, so the dates of transactions and the range agree
+func currentDate() time.Time {
+	return truncateDate(time.Now().In(db.Location))
+}
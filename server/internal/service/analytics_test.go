@@ -0,0 +1,45 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"server/internal/types/entity"
+	"server/internal/utils/analytics"
+	"server/internal/utils/data"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleRecurringRules(t *testing.T) {
+	today := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	until := today.AddDate(0, 0, 30)
+	bank, deleted := uuid.New(), uuid.New()
+	rent := entity.Categories{Base: entity.Base{ID: uuid.New()}, Name: "Sewa", Type: entity.Expense}
+	salary := entity.Categories{Base: entity.Base{ID: uuid.New()}, Name: "Gaji", Type: entity.Income}
+	transfer := entity.Categories{Base: entity.Base{ID: uuid.New()}, Name: "Transfer", Type: entity.FundTransfer}
+	endDate := time.Date(2026, time.November, 10, 0, 0, 0, 0, time.UTC)
+
+	rules := []entity.RecurringTransactions{
+		// * Booked in July, August and September, next on the 31st again
+		{WalletID: bank, CategoryID: rent.ID, Category: rent, Amount: 3000, Frequency: data.RECURRING_FREQUENCY_MONTHLY, StartDate: time.Date(2026, time.July, 31, 9, 0, 0, 0, time.UTC), Occurrences: 3},
+		// * Due today, booked by the job rather than forecast, and ends on November 10
+		{WalletID: bank, CategoryID: salary.ID, Category: salary, Amount: 500, Frequency: data.RECURRING_FREQUENCY_WEEKLY, StartDate: time.Date(2026, time.October, 5, 9, 0, 0, 0, time.UTC), EndDate: &endDate, Occurrences: 2},
+		{WalletID: deleted, CategoryID: rent.ID, Category: rent, Amount: 100, Frequency: data.RECURRING_FREQUENCY_DAILY, StartDate: today},
+		{WalletID: bank, CategoryID: transfer.ID, Category: transfer, Amount: 100, Frequency: data.RECURRING_FREQUENCY_DAILY, StartDate: today},
+	}
+
+	items, ruled := scheduleRecurringRules(rules, map[string]bool{bank.String(): true}, today, until)
+
+	assert.Equal(t, []analytics.ScheduledItem{
+		{WalletID: bank.String(), Label: "Sewa", Date: time.Date(2026, time.October, 31, 0, 0, 0, 0, time.UTC), Amount: -3000},
+		{WalletID: bank.String(), Label: "Gaji", Date: time.Date(2026, time.October, 26, 0, 0, 0, 0, time.UTC), Amount: 500},
+		{WalletID: bank.String(), Label: "Gaji", Date: time.Date(2026, time.November, 2, 0, 0, 0, 0, time.UTC), Amount: 500},
+		{WalletID: bank.String(), Label: "Gaji", Date: time.Date(2026, time.November, 9, 0, 0, 0, 0, time.UTC), Amount: 500},
+	}, items)
+	assert.Equal(t, []analytics.Recurring{
+		{WalletID: bank.String(), CategoryID: rent.ID.String(), Category: "Sewa", Amount: -3000},
+		{WalletID: bank.String(), CategoryID: salary.ID.String(), Category: "Gaji", Amount: 500},
+	}, ruled)
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"server/config/log"
+	"server/config/miniofs"
+	"server/internal/repository"
+	"server/internal/types/entity"
+
+	"github.com/google/uuid"
+)
+
+// Attachment files are stored once per SHA-256 content hash (content addressed) and shared between attachment
+// rows through a reference counted blob. The objects are named after the hash and the blob ID, a blob created again
+// after its objects were purged never shares an object with the purged one. The helpers below are used by every
+// service creating or deleting attachments.
+
+// findOrUploadBlob returns the existing blob for the file content or uploads the file as a new blob, uploaded tells
+// which one. The existing blob stays locked until the transaction ends so it cannot be purged in the meantime.
+// The returned blob is not referenced yet, call AcquireBlob once the attachment row is about to be created.
+func findOrUploadBlob(ctx context.Context, tx repository.Transaction, blobRepo repository.AttachmentBlobsRepository, storage miniofs.Storage, file string) (blob entity.AttachmentBlobs, uploaded bool, err error) {
+	data, _, err := miniofs.DecodeFile(file)
+	if err != nil {
+		return entity.AttachmentBlobs{}, false, fmt.Errorf("decode error: %v", err)
+	}
+	hash := miniofs.ContentHash(data)
+
+	existing, err := blobRepo.LockBlobByHash(ctx, tx, hash)
+	if err != nil {
+		return entity.AttachmentBlobs{}, false, fmt.Errorf("failed to find blob: %v", err)
+	}
+	if existing != nil {
+		return *existing, false, nil
+	}
+
+	id := uuid.New()
+	res, err := storage.Upload(ctx, miniofs.UploadRequest{
+		Prefix:       miniofs.ATTACHMENT_BLOB_PREFIX,
+		Base64Data:   file,
+		BucketName:   miniofs.TRANSACTION_ATTACHMENT_BUCKET,
+		Validation:   miniofs.CreateAttachmentValidationConfig(),
+		ProcessImage: true,
+		ObjectKey:    fmt.Sprintf("%s_%s", hash, id),
+	})
+	if err != nil {
+		return entity.AttachmentBlobs{}, false, err
+	}
+
+	blob = entity.AttachmentBlobs{
+		Hash:       hash,
+		BucketName: res.BucketName,
+		ObjectName: res.ObjectName,
+		URL:        res.URL,
+		Format:     res.Kind,
+		Size:       res.Size,
+
+		ThumbnailSmall:  res.Thumbnails[miniofs.THUMBNAIL_SMALL],
+		ThumbnailMedium: res.Thumbnails[miniofs.THUMBNAIL_MEDIUM],
+	}
+	blob.ID = id
+
+	return blob, true, nil
+}
+
+// releaseAttachmentBlob drops the reference held by a deleted attachment.
+// It returns the blob when this was the last reference, its objects must be purged after the transaction commits.
+func releaseAttachmentBlob(ctx context.Context, tx repository.Transaction, blobRepo repository.AttachmentBlobsRepository, attachment entity.Attachments) (*entity.AttachmentBlobs, error) {
+	// Attachments uploaded before deduplication own their file and have no blob
+	if attachment.BlobID == nil {
+		return nil, nil
+	}
+
+	blob, err := blobRepo.ReleaseBlob(ctx, tx, *attachment.BlobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to release blob: %v", err)
+	}
+	if blob.RefCount > 0 {
+		return nil, nil
+	}
+
+	deleted, err := blobRepo.DeleteUnreferencedBlob(ctx, tx, blob.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete blob: %v", err)
+	}
+	if !deleted {
+		return nil, nil
+	}
+
+	return &blob, nil
+}
+
+// purgeAttachmentBlobs deletes the stored objects of unreferenced blobs, and of uploads whose transaction did not
+// commit, failures are only logged
+func purgeAttachmentBlobs(ctx context.Context, storage miniofs.Storage, blobs []*entity.AttachmentBlobs) {
+	for _, blob := range blobs {
+		if err := storage.Delete(ctx, blob.BucketName, blob.ObjectName); err != nil {
+			log.Error(fmt.Sprintf("failed to delete blob %s: %v", blob.Hash, err))
+		}
+
+		for _, thumbnail := range []string{blob.ThumbnailSmall, blob.ThumbnailMedium} {
+			if thumbnail == "" {
+				continue
+			}
+			bucket, objectName, err := storage.ParseURL(thumbnail)
+			if err != nil {
+				log.Error(fmt.Sprintf("failed to parse thumbnail url of blob %s: %v", blob.Hash, err))
+				continue
+			}
+			if err := storage.Delete(ctx, bucket, objectName); err != nil {
+				log.Error(fmt.Sprintf("failed to delete thumbnail of blob %s: %v", blob.Hash, err))
+			}
+		}
+	}
+}
@@ -2,10 +2,16 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
+	"server/config/log"
+	"server/config/miniofs"
 	"server/internal/repository"
 	"server/internal/types/dto"
 	"server/internal/types/entity"
+	helper "server/internal/utils"
+	"server/internal/utils/data"
 
 	"github.com/google/uuid"
 )
@@ -17,17 +23,30 @@ type AttachmentsService interface {
 	CreateAttachment(ctx context.Context, attachment dto.AttachmentsRequest) (dto.AttachmentsResponse, error)
 	UpdateAttachment(ctx context.Context, id string, attachment dto.AttachmentsRequest) (dto.AttachmentsResponse, error)
 	DeleteAttachment(ctx context.Context, id string) (dto.AttachmentsResponse, error)
+	GetOwnerAttachments(ctx context.Context, token, ownerType, ownerID string) ([]dto.AttachmentsResponse, error)
+	UploadOwnerAttachments(ctx context.Context, token, ownerType, ownerID string, files []string) ([]dto.AttachmentsResponse, error)
+	DeleteOwnerAttachment(ctx context.Context, token, ownerType, ownerID, id string) (dto.AttachmentsResponse, error)
+	AttachFiles(ctx context.Context, tx repository.Transaction, ownerType, ownerID string, files []string) ([]dto.AttachmentsResponse, []*entity.AttachmentBlobs, error)
+	DetachFiles(ctx context.Context, tx repository.Transaction, ownerType, ownerID string, ids []string) ([]*entity.AttachmentBlobs, error)
+	DetachOwner(ctx context.Context, tx repository.Transaction, ownerType, ownerID string) ([]*entity.AttachmentBlobs, error)
+	PurgeBlobs(ctx context.Context, blobs []*entity.AttachmentBlobs)
+	BackfillThumbnails(ctx context.Context, batchSize int) (int, error)
+	RewriteStorageReferences(ctx context.Context, from, to miniofs.Storage) (int, error)
 }
 
 type attachmentsService struct {
 	txManager          repository.TxManager
 	categoryRepository repository.AttachmentsRepository
+	blobRepository     repository.AttachmentBlobsRepository
+	storage            miniofs.Storage
 }
 
-func NewAttachmentsService(txManager repository.TxManager, categoryRepository repository.AttachmentsRepository) AttachmentsService {
+func NewAttachmentsService(txManager repository.TxManager, categoryRepository repository.AttachmentsRepository, blobRepository repository.AttachmentBlobsRepository, storage miniofs.Storage) AttachmentsService {
 	return &attachmentsService{
 		txManager:          txManager,
 		categoryRepository: categoryRepository,
+		blobRepository:     blobRepository,
+		storage:            storage,
 	}
 }
 
@@ -39,13 +58,7 @@ func (attachment_serv *attachmentsService) GetAllAttachments(ctx context.Context
 
 	var attachmentsResponse []dto.AttachmentsResponse
 	for _, attachment := range attachments {
-		attachmentResponse := dto.AttachmentsResponse{
-			ID:            attachment.ID.String(),
-			Image:         attachment.Image,
-			TransactionID: attachment.TransactionID.String(),
-			CreatedAt:     attachment.CreatedAt.String(),
-		}
-		attachmentsResponse = append(attachmentsResponse, attachmentResponse)
+		attachmentsResponse = append(attachmentsResponse, toAttachmentResponse(attachment))
 	}
 
 	return attachmentsResponse, nil
@@ -57,45 +70,38 @@ func (attachment_serv *attachmentsService) GetAttachmentByID(ctx context.Context
 		return dto.AttachmentsResponse{}, err
 	}
 
-	attachmentResponse := dto.AttachmentsResponse{
-		ID:            attachment.ID.String(),
-		Image:         attachment.Image,
-		TransactionID: attachment.TransactionID.String(),
-		CreatedAt:     attachment.CreatedAt.String(),
-	}
-
-	return attachmentResponse, nil
+	return toAttachmentResponse(attachment), nil
 }
 
 func (attachment_serv *attachmentsService) GetAttachmentsByTransactionID(ctx context.Context, transactionID string) ([]dto.AttachmentsResponse, error) {
-	attachments, err := attachment_serv.categoryRepository.GetAttachmentsByTransactionID(ctx, nil, transactionID)
+	attachments, err := attachment_serv.categoryRepository.GetAttachmentsByOwner(ctx, nil, data.ATTACHMENT_OWNER_TRANSACTION, transactionID)
 	if err != nil {
 		return nil, err
 	}
 
 	var attachmentsResponse []dto.AttachmentsResponse
 	for _, attachment := range attachments {
-		attachmentResponse := dto.AttachmentsResponse{
-			ID:            attachment.ID.String(),
-			Image:         attachment.Image,
-			TransactionID: attachment.TransactionID.String(),
-			CreatedAt:     attachment.CreatedAt.String(),
-		}
-		attachmentsResponse = append(attachmentsResponse, attachmentResponse)
+		attachmentsResponse = append(attachmentsResponse, toAttachmentResponse(attachment))
 	}
 
 	return attachmentsResponse, nil
 }
 
 func (attachment_serv *attachmentsService) CreateAttachment(ctx context.Context, attachment dto.AttachmentsRequest) (dto.AttachmentsResponse, error) {
-	TransactionUUID, err := uuid.Parse(attachment.TransactionID)
+	ownerType, ownerID := attachment.OwnerType, attachment.OwnerID
+	if ownerType == "" {
+		ownerType, ownerID = data.ATTACHMENT_OWNER_TRANSACTION, attachment.TransactionID
+	}
+
+	OwnerUUID, err := uuid.Parse(ownerID)
 	if err != nil {
 		return dto.AttachmentsResponse{}, err
 	}
 
 	attachmentEntity := entity.Attachments{
-		Image:         attachment.Image,
-		TransactionID: TransactionUUID,
+		Image:     attachment.Image,
+		OwnerType: ownerType,
+		OwnerID:   OwnerUUID,
 	}
 
 	newAttachment, err := attachment_serv.categoryRepository.CreateAttachment(ctx, nil, attachmentEntity)
@@ -103,14 +109,7 @@ func (attachment_serv *attachmentsService) CreateAttachment(ctx context.Context,
 		return dto.AttachmentsResponse{}, err
 	}
 
-	attachmentResponse := dto.AttachmentsResponse{
-		ID:            newAttachment.ID.String(),
-		Image:         newAttachment.Image,
-		TransactionID: newAttachment.TransactionID.String(),
-		CreatedAt:     newAttachment.CreatedAt.String(),
-	}
-
-	return attachmentResponse, nil
+	return toAttachmentResponse(newAttachment), nil
 }
 
 func (attachment_serv *attachmentsService) UpdateAttachment(ctx context.Context, id string, attachment dto.AttachmentsRequest) (dto.AttachmentsResponse, error) {
@@ -128,33 +127,484 @@ func (attachment_serv *attachmentsService) UpdateAttachment(ctx context.Context,
 		return dto.AttachmentsResponse{}, err
 	}
 
-	attachmentResponse := dto.AttachmentsResponse{
-		ID:            newAttachment.ID.String(),
-		Image:         newAttachment.Image,
-		TransactionID: newAttachment.TransactionID.String(),
-		CreatedAt:     newAttachment.CreatedAt.String(),
+	return toAttachmentResponse(newAttachment), nil
+}
+
+func (attachment_serv *attachmentsService) DeleteAttachment(ctx context.Context, id string) (dto.AttachmentsResponse, error) {
+	tx, err := attachment_serv.txManager.Begin(ctx)
+	if err != nil {
+		return dto.AttachmentsResponse{}, err
+	}
+
+	defer func() {
+		// Rollback otomatis jika transaksi belum di-commit
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	attachmentEntity, err := attachment_serv.categoryRepository.GetAttachmentByID(ctx, tx, id)
+	if err != nil {
+		return dto.AttachmentsResponse{}, err
+	}
+
+	deletedAttachment, err := attachment_serv.categoryRepository.DeleteAttachment(ctx, tx, attachmentEntity)
+	if err != nil {
+		return dto.AttachmentsResponse{}, err
 	}
 
-	return attachmentResponse, nil
+	// The shared file is only deleted with its last reference
+	orphan, err := releaseAttachmentBlob(ctx, tx, attachment_serv.blobRepository, deletedAttachment)
+	if err != nil {
+		return dto.AttachmentsResponse{}, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return dto.AttachmentsResponse{}, err
+	}
+
+	if orphan != nil {
+		purgeAttachmentBlobs(ctx, attachment_serv.storage, []*entity.AttachmentBlobs{orphan})
+	}
+
+	return toAttachmentResponse(deletedAttachment), nil
 }
 
-func (attachment_serv *attachmentsService) DeleteAttachment(ctx context.Context, id string) (dto.AttachmentsResponse, error) {
-	attachmentEntity, err := attachment_serv.categoryRepository.GetAttachmentByID(ctx, nil, id)
+func (attachment_serv *attachmentsService) GetOwnerAttachments(ctx context.Context, token, ownerType, ownerID string) ([]dto.AttachmentsResponse, error) {
+	if err := attachment_serv.verifyOwner(ctx, nil, token, ownerType, ownerID); err != nil {
+		return nil, err
+	}
+
+	attachments, err := attachment_serv.categoryRepository.GetAttachmentsByOwner(ctx, nil, ownerType, ownerID)
 	if err != nil {
+		return nil, errors.New("failed to get attachments")
+	}
+
+	attachmentsResponse := make([]dto.AttachmentsResponse, 0, len(attachments))
+	for _, attachment := range attachments {
+		attachmentsResponse = append(attachmentsResponse, toAttachmentResponse(attachment))
+	}
+
+	return attachmentsResponse, nil
+}
+
+func (attachment_serv *attachmentsService) UploadOwnerAttachments(ctx context.Context, token, ownerType, ownerID string, files []string) ([]dto.AttachmentsResponse, error) {
+	if err := attachment_serv.verifyOwner(ctx, nil, token, ownerType, ownerID); err != nil {
+		return nil, err
+	}
+
+	tx, err := attachment_serv.txManager.Begin(ctx)
+	if err != nil {
+		return nil, errors.New("failed to create transaction")
+	}
+
+	var uploaded []*entity.AttachmentBlobs
+	defer func() {
+		// Rollback otomatis jika transaksi belum di-commit
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+			attachment_serv.PurgeBlobs(ctx, uploaded)
+		}
+	}()
+
+	attachmentsResponse, uploaded, err := attachment_serv.AttachFiles(ctx, tx, ownerType, ownerID, files)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.New("failed to commit transaction")
+	}
+
+	return attachmentsResponse, nil
+}
+
+func (attachment_serv *attachmentsService) DeleteOwnerAttachment(ctx context.Context, token, ownerType, ownerID, id string) (dto.AttachmentsResponse, error) {
+	if err := attachment_serv.verifyOwner(ctx, nil, token, ownerType, ownerID); err != nil {
 		return dto.AttachmentsResponse{}, err
 	}
 
-	deletedAttachment, err := attachment_serv.categoryRepository.DeleteAttachment(ctx, nil, attachmentEntity)
+	tx, err := attachment_serv.txManager.Begin(ctx)
+	if err != nil {
+		return dto.AttachmentsResponse{}, errors.New("failed to create transaction")
+	}
+
+	defer func() {
+		// Rollback otomatis jika transaksi belum di-commit
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	attachment, err := attachment_serv.categoryRepository.GetAttachmentByID(ctx, tx, id)
+	if err != nil {
+		return dto.AttachmentsResponse{}, errors.New("attachment not found")
+	}
+
+	orphanBlobs, err := attachment_serv.DetachFiles(ctx, tx, ownerType, ownerID, []string{id})
 	if err != nil {
 		return dto.AttachmentsResponse{}, err
 	}
 
+	if err = tx.Commit(); err != nil {
+		return dto.AttachmentsResponse{}, errors.New("failed to commit transaction")
+	}
+
+	attachment_serv.PurgeBlobs(ctx, orphanBlobs)
+
+	return toAttachmentResponse(attachment), nil
+}
+
+// AttachFiles uploads base64 files through the attachment pipeline (validation, image processing, deduplication)
+// and attaches them to the resource. The caller owns the transaction and checks that the user may access the resource.
+// The blobs uploaded by the call are returned as well, pass them to PurgeBlobs when the transaction does not commit.
+// On error the uploads are already purged.
+func (attachment_serv *attachmentsService) AttachFiles(ctx context.Context, tx repository.Transaction, ownerType, ownerID string, files []string) (attachmentResponses []dto.AttachmentsResponse, uploaded []*entity.AttachmentBlobs, err error) {
+	defer func() {
+		if err != nil {
+			attachment_serv.PurgeBlobs(ctx, uploaded)
+			uploaded = nil
+		}
+	}()
+
+	if ownerID == "" {
+		log.Error(fmt.Sprintf("%s ID is required", ownerType))
+		return nil, uploaded, fmt.Errorf("%s ID is required", ownerType)
+	}
+	if len(files) == 0 {
+		log.Error("no files to upload")
+		return nil, uploaded, errors.New("no files to upload")
+	}
+
+	OwnerUUID, err := uuid.Parse(ownerID)
+	if err != nil {
+		log.Error(fmt.Sprintf("invalid %s ID %s: %v", ownerType, ownerID, err))
+		return nil, uploaded, fmt.Errorf("invalid %s id", ownerType)
+	}
+
+	// Duplicates are looked up across every resource of the user owning this one
+	userID, err := attachment_serv.categoryRepository.GetAttachmentOwnerUserID(ctx, tx, ownerType, ownerID)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to get owner of %s %s: %v", ownerType, ownerID, err))
+		return nil, uploaded, fmt.Errorf("%s not found", ownerType)
+	}
+
+	for idx, file := range files {
+		if file == "" {
+			log.Error("file is empty")
+			return nil, uploaded, errors.New("file is empty")
+		}
+
+		blob, isNew, err := findOrUploadBlob(ctx, tx, attachment_serv.blobRepository, attachment_serv.storage, file)
+		if err != nil {
+			log.Error(fmt.Sprintf("failed to upload file %d: %v", idx+1, err))
+			return nil, uploaded, errors.New("failed to upload file")
+		}
+
+		// ? Tell the client where the same file is already attached
+		var alreadyAttachedTo []dto.AttachmentOwner
+		var existing *entity.Attachments
+		if !isNew {
+			duplicates, err := attachment_serv.categoryRepository.GetUserAttachmentsByBlobID(ctx, tx, blob.ID, userID)
+			if err != nil {
+				log.Error(fmt.Sprintf("failed to get duplicates of blob %s: %v", blob.Hash, err))
+				return nil, uploaded, errors.New("failed to create attachment")
+			}
+			for _, duplicate := range duplicates {
+				if duplicate.OwnerType == ownerType && duplicate.OwnerID == OwnerUUID {
+					existing = &duplicate
+					continue
+				}
+				alreadyAttachedTo = append(alreadyAttachedTo, dto.AttachmentOwner{
+					OwnerType: duplicate.OwnerType,
+					OwnerID:   duplicate.OwnerID.String(),
+				})
+			}
+		}
+
+		// * Same file uploaded twice to the same resource (e.g. a retry), keep the existing attachment
+		attachment := entity.Attachments{}
+		if existing != nil {
+			attachment = *existing
+		} else {
+			acquired, err := attachment_serv.blobRepository.AcquireBlob(ctx, tx, blob)
+			if err != nil {
+				log.Error(fmt.Sprintf("failed to acquire blob %s: %v", blob.Hash, err))
+				if isNew {
+					uploaded = append(uploaded, &blob)
+				}
+				return nil, uploaded, errors.New("failed to create attachment")
+			}
+
+			if isNew {
+				if acquired.ID == blob.ID {
+					uploaded = append(uploaded, &acquired)
+				} else {
+					// * A concurrent upload of the same file stored its blob first, nothing references this upload
+					attachment_serv.PurgeBlobs(ctx, []*entity.AttachmentBlobs{&blob})
+				}
+			}
+			blob = acquired
+
+			// Save attachment to database
+			attachment, err = attachment_serv.categoryRepository.CreateAttachment(ctx, tx, entity.Attachments{
+				Image:     blob.URL,
+				OwnerType: ownerType,
+				OwnerID:   OwnerUUID,
+				BlobID:    &blob.ID,
+				Size:      blob.Size,
+				Format:    blob.Format,
+
+				ThumbnailSmall:  blob.ThumbnailSmall,
+				ThumbnailMedium: blob.ThumbnailMedium,
+			})
+			if err != nil {
+				log.Error(fmt.Sprintf("failed to create attachment for %s %s: %v", ownerType, ownerID, err))
+				return nil, uploaded, errors.New("failed to create attachment")
+			}
+		}
+
+		attachmentResponse := toAttachmentResponse(attachment)
+		attachmentResponse.Hash = blob.Hash
+		attachmentResponse.AlreadyAttachedTo = alreadyAttachedTo
+
+		attachmentResponses = append(attachmentResponses, attachmentResponse)
+	}
+
+	return attachmentResponses, uploaded, nil
+}
+
+// DetachFiles deletes attachments of the resource by ID and releases their files.
+// Blobs that lost their last reference are returned, pass them to PurgeBlobs after the transaction commits.
+func (attachment_serv *attachmentsService) DetachFiles(ctx context.Context, tx repository.Transaction, ownerType, ownerID string, ids []string) ([]*entity.AttachmentBlobs, error) {
+	var orphanBlobs []*entity.AttachmentBlobs
+	for _, ID := range ids {
+		// * Get attachment by ID
+		attachment, err := attachment_serv.categoryRepository.GetAttachmentByID(ctx, tx, ID)
+		if err != nil {
+			return nil, fmt.Errorf("attachment with file %s not found: %w", ID, err)
+		}
+
+		// * Check if attachment belongs to the resource
+		if attachment.OwnerType != ownerType || attachment.OwnerID.String() != ownerID {
+			return nil, fmt.Errorf("attachment with file %s does not belong to %s %s", ID, ownerType, ownerID)
+		}
+
+		// * Delete file from database
+		if _, err := attachment_serv.categoryRepository.DeleteAttachment(ctx, tx, attachment); err != nil {
+			return nil, fmt.Errorf("attachment with file %s not found: %w", ID, err)
+		}
+
+		// * Release the shared file, it is only deleted with its last reference
+		orphan, err := releaseAttachmentBlob(ctx, tx, attachment_serv.blobRepository, attachment)
+		if err != nil {
+			return nil, fmt.Errorf("failed to release attachment file: %w", err)
+		}
+		if orphan != nil {
+			orphanBlobs = append(orphanBlobs, orphan)
+		}
+	}
+
+	return orphanBlobs, nil
+}
+
+// DetachOwner deletes every attachment of a resource that is being deleted, see DetachFiles
+func (attachment_serv *attachmentsService) DetachOwner(ctx context.Context, tx repository.Transaction, ownerType, ownerID string) ([]*entity.AttachmentBlobs, error) {
+	attachments, err := attachment_serv.categoryRepository.GetAttachmentsByOwner(ctx, tx, ownerType, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachments of %s %s: %w", ownerType, ownerID, err)
+	}
+	if len(attachments) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(attachments))
+	for _, attachment := range attachments {
+		ids = append(ids, attachment.ID.String())
+	}
+
+	return attachment_serv.DetachFiles(ctx, tx, ownerType, ownerID, ids)
+}
+
+// PurgeBlobs deletes the stored objects of blobs returned by DetachFiles, or by AttachFiles when the transaction failed
+func (attachment_serv *attachmentsService) PurgeBlobs(ctx context.Context, blobs []*entity.AttachmentBlobs) {
+	purgeAttachmentBlobs(ctx, attachment_serv.storage, blobs)
+}
+
+// verifyOwner checks that the resource exists and belongs to the user of the token
+func (attachment_serv *attachmentsService) verifyOwner(ctx context.Context, tx repository.Transaction, token, ownerType, ownerID string) error {
+	userData, err := helper.VerifyToken(token[7:])
+	if err != nil {
+		return errors.New("invalid token")
+	}
+
+	userID, err := attachment_serv.categoryRepository.GetAttachmentOwnerUserID(ctx, tx, ownerType, ownerID)
+	if err != nil || userID != userData.ID {
+		return fmt.Errorf("%s not found", ownerType)
+	}
+
+	return nil
+}
+
+// BackfillThumbnails strips metadata and generates thumbnails for attachments uploaded before image processing existed.
+// Attachments that fail are logged and skipped, the number of processed attachments is returned.
+func (attachment_serv *attachmentsService) BackfillThumbnails(ctx context.Context, batchSize int) (int, error) {
+	processed := 0
+	failed := make(map[string]bool)
+
+	for {
+		attachments, err := attachment_serv.categoryRepository.GetAttachmentsWithoutThumbnails(ctx, nil, batchSize+len(failed))
+		if err != nil {
+			return processed, fmt.Errorf("failed to get attachments: %w", err)
+		}
+
+		pending := 0
+		for _, attachment := range attachments {
+			if failed[attachment.ID.String()] {
+				continue
+			}
+			pending++
+
+			if err := attachment_serv.reprocessAttachment(ctx, attachment); err != nil {
+				log.Warn(fmt.Sprintf("failed to backfill thumbnails for attachment %s: %v", attachment.ID, err))
+				failed[attachment.ID.String()] = true
+				continue
+			}
+			processed++
+		}
+
+		if pending == 0 {
+			break
+		}
+	}
+
+	return processed, nil
+}
+
+func (attachment_serv *attachmentsService) reprocessAttachment(ctx context.Context, attachment entity.Attachments) error {
+	bucket, objectName, err := attachment_serv.storage.ParseURL(attachment.Image)
+	if err != nil {
+		return err
+	}
+
+	res, err := miniofs.ReprocessImage(ctx, attachment_serv.storage, bucket, objectName)
+	if err != nil {
+		return err
+	}
+
+	original := attachment.Image
+	attachment.Image = res.URL
+	attachment.Size = res.Size
+	attachment.Format = res.Kind
+	attachment.ThumbnailSmall = res.Thumbnails[miniofs.THUMBNAIL_SMALL]
+	attachment.ThumbnailMedium = res.Thumbnails[miniofs.THUMBNAIL_MEDIUM]
+
+	if _, err = attachment_serv.categoryRepository.UpdateAttachment(ctx, nil, attachment); err != nil {
+		return err
+	}
+
+	// The image was written under a new name (webp -> jpg), attachments without a blob own their file
+	if res.URL != original && attachment.BlobID == nil {
+		if err := attachment_serv.storage.Delete(ctx, bucket, objectName); err != nil {
+			log.Warn(fmt.Sprintf("failed to delete original image %s of attachment %s: %v", objectName, attachment.ID, err))
+		}
+	}
+
+	return nil
+}
+
+// RewriteStorageReferences points attachment (and blob) URLs stored for one backend to the same objects on another backend.
+// The objects themselves must already be copied, see miniofs.CopyObjects.
+func (attachment_serv *attachmentsService) RewriteStorageReferences(ctx context.Context, from, to miniofs.Storage) (int, error) {
+	tx, err := attachment_serv.txManager.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		// Rollback otomatis jika transaksi belum di-commit
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	blobs, err := attachment_serv.blobRepository.GetAllBlobs(ctx, tx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get blobs: %w", err)
+	}
+
+	for _, blob := range blobs {
+		if !rewriteStorageURLs(from, to, &blob.URL, &blob.ThumbnailSmall, &blob.ThumbnailMedium) {
+			continue
+		}
+
+		if _, err = attachment_serv.blobRepository.UpdateBlob(ctx, tx, blob); err != nil {
+			return 0, fmt.Errorf("failed to update blob %s: %w", blob.Hash, err)
+		}
+	}
+
+	attachments, err := attachment_serv.categoryRepository.GetAllAttachments(ctx, tx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get attachments: %w", err)
+	}
+
+	rewritten := 0
+	for _, attachment := range attachments {
+		if !rewriteStorageURLs(from, to, &attachment.Image, &attachment.ThumbnailSmall, &attachment.ThumbnailMedium) {
+			continue
+		}
+
+		if _, err = attachment_serv.categoryRepository.UpdateAttachment(ctx, tx, attachment); err != nil {
+			return 0, fmt.Errorf("failed to update attachment %s: %w", attachment.ID, err)
+		}
+		rewritten++
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return rewritten, nil
+}
+
+// rewriteStorageURLs rewrites the given references in place and reports whether any of them changed
+func rewriteStorageURLs(from, to miniofs.Storage, refs ...*string) bool {
+	changed := false
+	for _, ref := range refs {
+		if *ref == "" {
+			continue
+		}
+
+		bucket, objectName, err := from.ParseURL(*ref)
+		if err != nil {
+			log.Warn(fmt.Sprintf("skipping storage reference %s: %v", *ref, err))
+			continue
+		}
+
+		if url := to.ObjectURL(bucket, objectName); url != *ref {
+			*ref = url
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+func toAttachmentResponse(attachment entity.Attachments) dto.AttachmentsResponse {
 	attachmentResponse := dto.AttachmentsResponse{
-		ID:            deletedAttachment.ID.String(),
-		Image:         deletedAttachment.Image,
-		TransactionID: deletedAttachment.TransactionID.String(),
-		CreatedAt:     deletedAttachment.CreatedAt.String(),
+		ID:        attachment.ID.String(),
+		OwnerType: attachment.OwnerType,
+		OwnerID:   attachment.OwnerID.String(),
+		Image:     attachment.Image,
+		Format:    attachment.Format,
+		CreatedAt: attachment.CreatedAt.String(),
+
+		ThumbnailSmall:  attachment.ThumbnailSmall,
+		ThumbnailMedium: attachment.ThumbnailMedium,
+	}
+	if attachment.OwnerType == data.ATTACHMENT_OWNER_TRANSACTION {
+		attachmentResponse.TransactionID = attachment.OwnerID.String()
 	}
 
-	return attachmentResponse, nil
+	return attachmentResponse
 }
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"server/config/log"
+	"server/config/miniofs"
+	"server/internal/repository"
+	"server/internal/types/dto"
+	"server/internal/types/entity"
+	"server/internal/utils/data"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func testAttachmentFile(t *testing.T, shade uint8) string {
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	for x := 0; x < 8; x++ {
+		for y := 0; y < 8; y++ {
+			img.Set(x, y, color.RGBA{R: shade, G: 100, B: 50, A: 255})
+		}
+	}
+
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, img); err != nil {
+		t.Fatal(err)
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buffer.Bytes())
+}
+
+func testAttachmentHash(t *testing.T, file string) string {
+	decoded, _, err := miniofs.DecodeFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return miniofs.ContentHash(decoded)
+}
+
+func newTestLocalStorage(t *testing.T) *miniofs.LocalStorage {
+	log.SetupLogger()
+
+	storage, err := miniofs.NewLocalStorage(t.TempDir(), "http://localhost:8080", "signing-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return storage
+}
+
+func storedObjects(t *testing.T, storage miniofs.Storage) []string {
+	objects, err := storage.List(context.Background(), miniofs.TRANSACTION_ATTACHMENT_BUCKET, "")
+	assert.Nil(t, err)
+
+	names := make([]string, 0, len(objects))
+	for _, object := range objects {
+		names = append(names, object.Name)
+	}
+	return names
+}
+
+func TestAttachFiles(t *testing.T) {
+	ctx := context.Background()
+	ownerID := uuid.New()
+	userID := uuid.NewString()
+	file := testAttachmentFile(t, 200)
+	hash := testAttachmentHash(t, file)
+
+	setup := func(t *testing.T) (AttachmentsService, *miniofs.LocalStorage, *mock.Mock, *mock.Mock) {
+		storage := newTestLocalStorage(t)
+		attachment_repo_mock := repository.NewAttachmentsRepositoryMock()
+		blob_repo_mock := repository.NewAttachmentBlobsRepositoryMock()
+		attachment_repo_mock.Mock.On("GetAttachmentOwnerUserID", ctx, nil, data.ATTACHMENT_OWNER_TRANSACTION, ownerID.String()).Return(userID, nil)
+
+		attachment_serv_test := NewAttachmentsService(nil, attachment_repo_mock, blob_repo_mock, storage)
+		return attachment_serv_test, storage, &attachment_repo_mock.Mock, &blob_repo_mock.Mock
+	}
+
+	t.Run("New File Is Uploaded Once", func(t *testing.T) {
+		attachment_serv_test, storage, attachment_mock, blob_mock := setup(t)
+		defer attachment_mock.AssertExpectations(t)
+		defer blob_mock.AssertExpectations(t)
+
+		blob_mock.On("LockBlobByHash", ctx, nil, hash).Return(nil, nil).Once()
+		blob_mock.On("AcquireBlob", ctx, nil, mock.MatchedBy(func(blob entity.AttachmentBlobs) bool {
+			return blob.Hash == hash && blob.ID != uuid.Nil
+		})).Return(nil, nil).Once()
+		attachment_mock.On("CreateAttachment", ctx, nil, mock.MatchedBy(func(attachment entity.Attachments) bool {
+			return attachment.OwnerType == data.ATTACHMENT_OWNER_TRANSACTION && attachment.OwnerID == ownerID && attachment.BlobID != nil
+		})).Return(nil, nil).Once()
+
+		responses, uploaded, err := attachment_serv_test.AttachFiles(ctx, nil, data.ATTACHMENT_OWNER_TRANSACTION, ownerID.String(), []string{file})
+		assert.Nil(t, err)
+		assert.Len(t, responses, 1)
+		assert.Equal(t, hash, responses[0].Hash)
+		assert.Empty(t, responses[0].AlreadyAttachedTo)
+		assert.Equal(t, miniofs.DOCUMENT_KIND_IMAGE, responses[0].Format)
+		assert.Len(t, uploaded, 1)
+
+		// * The object is named after the hash and the blob ID, next to its thumbnails
+		objects := storedObjects(t, storage)
+		assert.Len(t, objects, 3)
+		assert.Contains(t, objects, uploaded[0].ObjectName)
+		assert.Contains(t, uploaded[0].ObjectName, hash+"_"+uploaded[0].ID.String())
+
+		attachment_serv_test.PurgeBlobs(ctx, uploaded)
+		assert.Empty(t, storedObjects(t, storage))
+	})
+
+	t.Run("Duplicate Of Another Resource Is Shared", func(t *testing.T) {
+		attachment_serv_test, storage, attachment_mock, blob_mock := setup(t)
+		defer attachment_mock.AssertExpectations(t)
+		defer blob_mock.AssertExpectations(t)
+
+		existing := entity.AttachmentBlobs{Base: entity.Base{ID: uuid.New()}, Hash: hash, URL: "http://localhost:8080/v1/storage/attachments/blob.jpg", RefCount: 1}
+		otherOwner := uuid.New()
+		blob_mock.On("LockBlobByHash", ctx, nil, hash).Return(&existing, nil).Once()
+		attachment_mock.On("GetUserAttachmentsByBlobID", ctx, nil, existing.ID, userID).Return([]entity.Attachments{
+			{OwnerType: data.ATTACHMENT_OWNER_WALLET, OwnerID: otherOwner, BlobID: &existing.ID},
+		}, nil).Once()
+		blob_mock.On("AcquireBlob", ctx, nil, existing).Return(entity.AttachmentBlobs{Base: existing.Base, Hash: hash, URL: existing.URL, RefCount: 2}, nil).Once()
+		attachment_mock.On("CreateAttachment", ctx, nil, mock.MatchedBy(func(attachment entity.Attachments) bool {
+			return *attachment.BlobID == existing.ID && attachment.Image == existing.URL
+		})).Return(nil, nil).Once()
+
+		responses, uploaded, err := attachment_serv_test.AttachFiles(ctx, nil, data.ATTACHMENT_OWNER_TRANSACTION, ownerID.String(), []string{file})
+		assert.Nil(t, err)
+		assert.Empty(t, uploaded)
+		assert.Equal(t, []dto.AttachmentOwner{{OwnerType: data.ATTACHMENT_OWNER_WALLET, OwnerID: otherOwner.String()}}, responses[0].AlreadyAttachedTo)
+		assert.Empty(t, storedObjects(t, storage))
+	})
+
+	t.Run("Same File Twice On The Same Resource", func(t *testing.T) {
+		attachment_serv_test, _, attachment_mock, blob_mock := setup(t)
+		defer attachment_mock.AssertExpectations(t)
+		defer blob_mock.AssertExpectations(t)
+
+		existing := entity.AttachmentBlobs{Base: entity.Base{ID: uuid.New()}, Hash: hash, RefCount: 1}
+		attachment := entity.Attachments{Base: entity.Base{ID: uuid.New()}, OwnerType: data.ATTACHMENT_OWNER_TRANSACTION, OwnerID: ownerID, BlobID: &existing.ID}
+		blob_mock.On("LockBlobByHash", ctx, nil, hash).Return(&existing, nil).Once()
+		attachment_mock.On("GetUserAttachmentsByBlobID", ctx, nil, existing.ID, userID).Return([]entity.Attachments{attachment}, nil).Once()
+
+		responses, uploaded, err := attachment_serv_test.AttachFiles(ctx, nil, data.ATTACHMENT_OWNER_TRANSACTION, ownerID.String(), []string{file})
+		assert.Nil(t, err)
+		assert.Empty(t, uploaded)
+		assert.Equal(t, attachment.ID.String(), responses[0].ID)
+		blob_mock.AssertNotCalled(t, "AcquireBlob", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Concurrent Upload Of The Same File", func(t *testing.T) {
+		attachment_serv_test, storage, attachment_mock, blob_mock := setup(t)
+		defer attachment_mock.AssertExpectations(t)
+		defer blob_mock.AssertExpectations(t)
+
+		winner := entity.AttachmentBlobs{Base: entity.Base{ID: uuid.New()}, Hash: hash, BucketName: miniofs.TRANSACTION_ATTACHMENT_BUCKET, ObjectName: "winner.jpg", RefCount: 2}
+		blob_mock.On("LockBlobByHash", ctx, nil, hash).Return(nil, nil).Once()
+		blob_mock.On("AcquireBlob", ctx, nil, mock.Anything).Return(winner, nil).Once()
+		attachment_mock.On("CreateAttachment", ctx, nil, mock.MatchedBy(func(attachment entity.Attachments) bool {
+			return *attachment.BlobID == winner.ID
+		})).Return(nil, nil).Once()
+
+		_, uploaded, err := attachment_serv_test.AttachFiles(ctx, nil, data.ATTACHMENT_OWNER_TRANSACTION, ownerID.String(), []string{file})
+		assert.Nil(t, err)
+		// * The losing upload is purged right away, the winner's object is left alone
+		assert.Empty(t, uploaded)
+		assert.Empty(t, storedObjects(t, storage))
+	})
+
+	t.Run("Failed Attachment Purges The Upload", func(t *testing.T) {
+		attachment_serv_test, storage, attachment_mock, blob_mock := setup(t)
+		defer attachment_mock.AssertExpectations(t)
+		defer blob_mock.AssertExpectations(t)
+
+		blob_mock.On("LockBlobByHash", ctx, nil, hash).Return(nil, nil).Once()
+		blob_mock.On("AcquireBlob", ctx, nil, mock.Anything).Return(nil, nil).Once()
+		attachment_mock.On("CreateAttachment", ctx, nil, mock.Anything).Return(nil, errors.New("insert failed")).Once()
+
+		_, uploaded, err := attachment_serv_test.AttachFiles(ctx, nil, data.ATTACHMENT_OWNER_TRANSACTION, ownerID.String(), []string{file})
+		assert.NotNil(t, err)
+		assert.Nil(t, uploaded)
+		assert.Empty(t, storedObjects(t, storage))
+	})
+
+	t.Run("Failed Second File Purges The First", func(t *testing.T) {
+		attachment_serv_test, storage, attachment_mock, blob_mock := setup(t)
+		defer attachment_mock.AssertExpectations(t)
+		defer blob_mock.AssertExpectations(t)
+
+		blob_mock.On("LockBlobByHash", ctx, nil, mock.Anything).Return(nil, nil).Twice()
+		blob_mock.On("AcquireBlob", ctx, nil, mock.Anything).Return(nil, nil).Once()
+		attachment_mock.On("CreateAttachment", ctx, nil, mock.Anything).Return(nil, nil).Once()
+
+		_, uploaded, err := attachment_serv_test.AttachFiles(ctx, nil, data.ATTACHMENT_OWNER_TRANSACTION, ownerID.String(), []string{file, "data:image/png;base64,bm90IGFuIGltYWdl"})
+		assert.NotNil(t, err)
+		assert.Nil(t, uploaded)
+		assert.Empty(t, storedObjects(t, storage))
+	})
+}
+
+func TestDetachFiles(t *testing.T) {
+	ctx := context.Background()
+	walletID := uuid.New()
+	blobID := uuid.New()
+
+	attachment := entity.Attachments{Base: entity.Base{ID: uuid.New()}, OwnerType: data.ATTACHMENT_OWNER_WALLET, OwnerID: walletID, BlobID: &blobID}
+	// Attachments uploaded before deduplication own their file and have no blob
+	legacy := entity.Attachments{Base: entity.Base{ID: uuid.New()}, OwnerType: data.ATTACHMENT_OWNER_WALLET, OwnerID: walletID}
+
+	tests := []struct {
+		name       string
+		attachment entity.Attachments
+		released   *entity.AttachmentBlobs // blob left after releasing the reference, nil when not released
+		deleted    bool
+		orphans    int
+	}{
+		{"Last Reference", attachment, &entity.AttachmentBlobs{Base: entity.Base{ID: blobID}, RefCount: 0}, true, 1},
+		{"Shared File", attachment, &entity.AttachmentBlobs{Base: entity.Base{ID: blobID}, RefCount: 1}, false, 0},
+		{"Referenced Again In The Meantime", attachment, &entity.AttachmentBlobs{Base: entity.Base{ID: blobID}, RefCount: 0}, false, 0},
+		{"Without Blob", legacy, nil, false, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			attachment_repo_mock := repository.NewAttachmentsRepositoryMock()
+			blob_repo_mock := repository.NewAttachmentBlobsRepositoryMock()
+			attachment_serv_test := NewAttachmentsService(nil, attachment_repo_mock, blob_repo_mock, newTestLocalStorage(t))
+			defer attachment_repo_mock.Mock.AssertExpectations(t)
+			defer blob_repo_mock.Mock.AssertExpectations(t)
+
+			attachment_repo_mock.Mock.On("GetAttachmentsByOwner", ctx, nil, data.ATTACHMENT_OWNER_WALLET, walletID.String()).Return([]entity.Attachments{test.attachment}, nil).Once()
+			attachment_repo_mock.Mock.On("GetAttachmentByID", ctx, nil, test.attachment.ID.String()).Return(test.attachment, nil).Once()
+			attachment_repo_mock.Mock.On("DeleteAttachment", ctx, nil, test.attachment).Return(test.attachment, nil).Once()
+			if test.released != nil {
+				blob_repo_mock.Mock.On("ReleaseBlob", ctx, nil, blobID).Return(*test.released, nil).Once()
+				if test.released.RefCount == 0 {
+					blob_repo_mock.Mock.On("DeleteUnreferencedBlob", ctx, nil, blobID).Return(test.deleted, nil).Once()
+				}
+			}
+
+			orphans, err := attachment_serv_test.DetachOwner(ctx, nil, data.ATTACHMENT_OWNER_WALLET, walletID.String())
+			assert.Nil(t, err)
+			assert.Len(t, orphans, test.orphans)
+		})
+	}
+
+	t.Run("Attachment Of Another Resource", func(t *testing.T) {
+		attachment_repo_mock := repository.NewAttachmentsRepositoryMock()
+		blob_repo_mock := repository.NewAttachmentBlobsRepositoryMock()
+		attachment_serv_test := NewAttachmentsService(nil, attachment_repo_mock, blob_repo_mock, newTestLocalStorage(t))
+		defer attachment_repo_mock.Mock.AssertExpectations(t)
+
+		attachment_repo_mock.Mock.On("GetAttachmentByID", ctx, nil, attachment.ID.String()).Return(attachment, nil).Once()
+
+		// * Same owner ID under another owner type is another resource
+		_, err := attachment_serv_test.DetachFiles(ctx, nil, data.ATTACHMENT_OWNER_INVESTMENT, walletID.String(), []string{attachment.ID.String()})
+		assert.NotNil(t, err)
+		attachment_repo_mock.Mock.AssertNotCalled(t, "DeleteAttachment", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Resource Without Attachments", func(t *testing.T) {
+		attachment_repo_mock := repository.NewAttachmentsRepositoryMock()
+		attachment_serv_test := NewAttachmentsService(nil, attachment_repo_mock, repository.NewAttachmentBlobsRepositoryMock(), newTestLocalStorage(t))
+		defer attachment_repo_mock.Mock.AssertExpectations(t)
+
+		attachment_repo_mock.Mock.On("GetAttachmentsByOwner", ctx, nil, data.ATTACHMENT_OWNER_INVESTMENT, walletID.String()).Return(nil, nil).Once()
+
+		orphans, err := attachment_serv_test.DetachOwner(ctx, nil, data.ATTACHMENT_OWNER_INVESTMENT, walletID.String())
+		assert.Nil(t, err)
+		assert.Empty(t, orphans)
+	})
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"server/config/log"
+	"server/config/miniofs"
+	"server/internal/repository"
+)
+
+const (
+	// OUTBOX_RETENTION is how long published outbox events are kept, e.g. to look into a consumer issue
+	OUTBOX_RETENTION = 7 * 24 * time.Hour
+	// CLEANUP_BATCH_SIZE is how many report files one cleanup run deletes at most, the next run continues
+	CLEANUP_BATCH_SIZE = 500
+)
+
+// CleanupService removes data nothing reads anymore, it runs as the cleanup scheduled job
+type CleanupService interface {
+	Cleanup(ctx context.Context) error
+}
+
+type cleanupService struct {
+	outboxRepo  repository.OutboxEventsRepository
+	reportsRepo repository.ReportsRepository
+	storage     miniofs.Storage
+}
+
+func NewCleanupService(outboxRepo repository.OutboxEventsRepository, reportsRepo repository.ReportsRepository, storage miniofs.Storage) CleanupService {
+	return &cleanupService{
+		outboxRepo:  outboxRepo,
+		reportsRepo: reportsRepo,
+		storage:     storage,
+	}
+}
+
+// Cleanup deletes the published outbox events past OUTBOX_RETENTION and the files of failed and cancelled
+// reports, each part runs even when the other one failed
+func (cleanup_serv *cleanupService) Cleanup(ctx context.Context) error {
+	return errors.Join(cleanup_serv.deleteSentOutboxEvents(ctx), cleanup_serv.deleteAbandonedReportFiles(ctx))
+}
+
+func (cleanup_serv *cleanupService) deleteSentOutboxEvents(ctx context.Context) error {
+	deleted, err := cleanup_serv.outboxRepo.DeleteSentOutboxEvents(ctx, nil, time.Now().Add(-OUTBOX_RETENTION))
+	if err != nil {
+		return fmt.Errorf("failed to delete sent outbox events: %w", err)
+	}
+
+	log.Info(fmt.Sprintf("deleted %d sent outbox events", deleted))
+	return nil
+}
+
+// deleteAbandonedReportFiles deletes the files generated for reports that failed or were cancelled afterwards,
+// they are never downloaded. The reference is only cleared once the file is gone, a failed delete is tried again.
+func (cleanup_serv *cleanupService) deleteAbandonedReportFiles(ctx context.Context) error {
+	reports, err := cleanup_serv.reportsRepo.GetAbandonedReportFiles(ctx, nil, CLEANUP_BATCH_SIZE)
+	if err != nil {
+		return fmt.Errorf("failed to get abandoned report files: %w", err)
+	}
+
+	var errs []error
+	deleted := 0
+	for _, report := range reports {
+		bucketName, objectName, err := cleanup_serv.storage.ParseURL(*report.FileURL)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to parse file url of report %s: %w", report.ID, err))
+			continue
+		}
+		if err := cleanup_serv.storage.Delete(ctx, bucketName, objectName); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete file of report %s: %w", report.ID, err))
+			continue
+		}
+		if err := cleanup_serv.reportsRepo.ClearReportFile(ctx, nil, report.ID.String()); err != nil {
+			errs = append(errs, fmt.Errorf("failed to clear file of report %s: %w", report.ID, err))
+			continue
+		}
+		deleted++
+	}
+
+	log.Info(fmt.Sprintf("deleted %d files of failed and cancelled reports", deleted))
+	return errors.Join(errs...)
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"server/config/env"
+	"server/config/queue"
+	"server/internal/repository"
+	"server/internal/types/dto"
+	helper "server/internal/utils"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// EmailsService queues emails in the transaction of the change they report and sends them from the worker,
+// so a slow or failing SMTP server never fails or repeats the change itself
+type EmailsService interface {
+	QueueEmail(ctx context.Context, tx repository.Transaction, to, subject, htmlFile string, data any) error
+	ProcessEmail(ctx context.Context, delivery amqp091.Delivery) error
+}
+
+type emailsService struct {
+	outboxServ OutboxService
+	smtpClient helper.SMTPClientInterface
+}
+
+func NewEmailsService(outboxServ OutboxService, smtpClient helper.SMTPClientInterface) EmailsService {
+	return &emailsService{
+		outboxServ: outboxServ,
+		smtpClient: smtpClient,
+	}
+}
+
+// NewZohoEmailsService sends with the Zoho account of the config, the one reports have always been sent with
+func NewZohoEmailsService(outboxServ OutboxService) EmailsService {
+	return NewEmailsService(outboxServ, helper.NewSMTPClient(helper.NewZohoSMTP(env.Cfg.ZSMTP)))
+}
+
+// QueueEmail renders the template now, the data may have changed by the time the email is sent
+func (email_serv *emailsService) QueueEmail(ctx context.Context, tx repository.Transaction, to, subject, htmlFile string, data any) error {
+	html, err := helper.RenderEmail(htmlFile, data)
+	if err != nil {
+		return fmt.Errorf("failed to render email %s: %w", htmlFile, err)
+	}
+
+	return email_serv.outboxServ.Enqueue(ctx, tx, queue.EMAIL_EXCHANGE, queue.EMAIL_ROUTING_KEY, dto.EmailMessage{
+		To:      to,
+		Subject: subject,
+		HTML:    html,
+	})
+}
+
+// ProcessEmail sends one queued email, failures are retried with the backoff of the email queue
+func (email_serv *emailsService) ProcessEmail(ctx context.Context, delivery amqp091.Delivery) error {
+	var message dto.EmailMessage
+	if err := json.Unmarshal(delivery.Body, &message); err != nil {
+		return queue.Permanent(fmt.Errorf("failed to unmarshal email: %w", err))
+	}
+	if message.To == "" {
+		return queue.Permanent(fmt.Errorf("email has no recipient"))
+	}
+
+	if err := email_serv.smtpClient.SendHTMLEmail(message.To, message.Subject, message.HTML); err != nil {
+		return fmt.Errorf("failed to send email to %s: %w", message.To, err)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"server/config/queue"
+	"server/internal/repository"
+	"server/internal/types/dto"
+	"server/internal/types/entity"
+
+	"github.com/google/uuid"
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// EventHandler handles one domain event, a returned error retries it like any other message
+type EventHandler func(ctx context.Context, event dto.Event) error
+
+// NewEventHandler decodes the domain events of a queue subscribed with queue.EventQueue before handing them
+// to the handler. Messages that are not a valid event go to the dead-letter queue without being retried.
+func NewEventHandler(handler EventHandler) queue.Handler {
+	return func(ctx context.Context, delivery amqp091.Delivery) error {
+		var event dto.Event
+		if err := json.Unmarshal(delivery.Body, &event); err != nil {
+			return queue.Permanent(fmt.Errorf("failed to unmarshal event: %w", err))
+		}
+		if event.Type == "" {
+			return queue.Permanent(fmt.Errorf("event type is empty"))
+		}
+
+		return handler(ctx, event)
+	}
+}
+
+// enqueueEvent stores the event in the outbox within the transaction of the change, before or after is nil
+// when the entity was created or deleted
+func enqueueEvent(ctx context.Context, tx repository.Transaction, outboxServ OutboxService, eventType, userID, entityID string, before, after any) error {
+	event := dto.Event{
+		ID:         uuid.NewString(),
+		Type:       eventType,
+		Version:    eventVersion(eventType),
+		OccurredAt: time.Now(),
+		UserID:     userID,
+		EntityID:   entityID,
+	}
+
+	var err error
+	if event.Before, err = json.Marshal(before); err != nil {
+		return fmt.Errorf("failed to marshal %s event: %w", eventType, err)
+	}
+	if event.After, err = json.Marshal(after); err != nil {
+		return fmt.Errorf("failed to marshal %s event: %w", eventType, err)
+	}
+
+	return outboxServ.Enqueue(ctx, tx, queue.EVENTS_EXCHANGE, eventType, event)
+}
+
+// eventVersion reads the version from the suffix of the event type, e.g. 1 for transaction.created.v1
+func eventVersion(eventType string) int {
+	index := strings.LastIndex(eventType, ".v")
+	if index < 0 {
+		return 0
+	}
+
+	version, err := strconv.Atoi(eventType[index+2:])
+	if err != nil {
+		return 0
+	}
+	return version
+}
+
+func toTransactionEventPayload(transaction entity.Transactions) *dto.TransactionEventPayload {
+	return &dto.TransactionEventPayload{
+		ID:              transaction.ID.String(),
+		WalletID:        transaction.WalletID.String(),
+		CategoryID:      transaction.CategoryID.String(),
+		Amount:          transaction.Amount,
+		TransactionDate: transaction.TransactionDate,
+		Description:     transaction.Description,
+	}
+}
+
+func toWalletEventPayload(wallet entity.Wallets) *dto.WalletEventPayload {
+	return &dto.WalletEventPayload{
+		ID:           wallet.ID.String(),
+		UserID:       wallet.UserID.String(),
+		WalletTypeID: wallet.WalletTypeID.String(),
+		Name:         wallet.Name,
+		Number:       wallet.Number,
+		Balance:      wallet.Balance,
+	}
+}
+
+func toInvestmentEventPayload(investment entity.Investments) *dto.InvestmentEventPayload {
+	return &dto.InvestmentEventPayload{
+		ID:               investment.ID.String(),
+		UserID:           investment.UserID.String(),
+		InvestmentTypeID: investment.InvestmentTypeID.String(),
+		InstrumentID:     investment.InstrumentID.String(),
+		Amount:           investment.Amount,
+		Quantity:         investment.Quantity,
+		CostMethod:       investment.CostMethod,
+		InvestmentDate:   investment.InvestmentDate,
+		Description:      investment.Description,
+	}
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"server/internal/repository"
+	"server/internal/types/dto"
+	"server/internal/types/entity"
+	helper "server/internal/utils"
+	"server/internal/utils/portfolio"
+)
+
+const DEFAULT_INSTRUMENT_CURRENCY = "IDR"
+
+var instrumentKinds = map[entity.InstrumentKind]bool{
+	entity.InstrumentStock:      true,
+	entity.InstrumentMutualFund: true,
+	entity.InstrumentBond:       true,
+	entity.InstrumentGold:       true,
+	entity.InstrumentCrypto:     true,
+	entity.InstrumentDeposit:    true,
+	entity.InstrumentOther:      true,
+}
+
+type InstrumentsService interface {
+	GetInstruments(ctx context.Context, query dto.InstrumentsQuery) ([]dto.InstrumentsResponse, error)
+	GetInstrumentByID(ctx context.Context, id string) (dto.InstrumentsResponse, error)
+	CreateInstrument(ctx context.Context, instrument dto.InstrumentsRequest) (dto.InstrumentsResponse, error)
+	UpdateInstrument(ctx context.Context, id string, instrument dto.InstrumentsRequest) (dto.InstrumentsResponse, error)
+	DeleteInstrument(ctx context.Context, id string) (dto.InstrumentsResponse, error)
+}
+
+type instrumentsService struct {
+	txManager                 repository.TxManager
+	instrumentsRepository     repository.InstrumentsRepository
+	investmentTypesRepository repository.InvestmentTypesRepository
+}
+
+func NewInstrumentsService(txManager repository.TxManager, instrumentsRepository repository.InstrumentsRepository, investmentTypesRepository repository.InvestmentTypesRepository) InstrumentsService {
+	return &instrumentsService{
+		txManager:                 txManager,
+		instrumentsRepository:     instrumentsRepository,
+		investmentTypesRepository: investmentTypesRepository,
+	}
+}
+
+func (instrument_serv *instrumentsService) GetInstruments(ctx context.Context, query dto.InstrumentsQuery) ([]dto.InstrumentsResponse, error) {
+	instruments, err := instrument_serv.instrumentsRepository.GetInstruments(ctx, nil, query.Search, query.Kind, query.InvestmentTypeID)
+	if err != nil {
+		return nil, errors.New("failed to get instruments")
+	}
+
+	instrumentsResponse := make([]dto.InstrumentsResponse, 0, len(instruments))
+	for _, instrument := range instruments {
+		instrumentsResponse = append(instrumentsResponse, helper.ConvertToResponseType(instrument).(dto.InstrumentsResponse))
+	}
+
+	return instrumentsResponse, nil
+}
+
+func (instrument_serv *instrumentsService) GetInstrumentByID(ctx context.Context, id string) (dto.InstrumentsResponse, error) {
+	instrument, err := instrument_serv.instrumentsRepository.GetInstrumentByID(ctx, nil, id)
+	if err != nil {
+		return dto.InstrumentsResponse{}, errors.New("instrument not found")
+	}
+
+	return helper.ConvertToResponseType(instrument).(dto.InstrumentsResponse), nil
+}
+
+func (instrument_serv *instrumentsService) CreateInstrument(ctx context.Context, instrument dto.InstrumentsRequest) (dto.InstrumentsResponse, error) {
+	newInstrument := entity.Instruments{
+		Code:      portfolio.InstrumentKey(instrument.Code),
+		Name:      strings.TrimSpace(instrument.Name),
+		Issuer:    instrument.Issuer,
+		Currency:  strings.ToUpper(strings.TrimSpace(instrument.Currency)),
+		Unit:      instrument.Unit,
+		RiskLevel: instrument.RiskLevel,
+		Kind:      entity.InstrumentKind(instrument.Kind),
+	}
+	if newInstrument.Code == "" {
+		return dto.InstrumentsResponse{}, errors.New("code is required")
+	}
+	if newInstrument.Name == "" {
+		newInstrument.Name = newInstrument.Code
+	}
+	if newInstrument.Currency == "" {
+		newInstrument.Currency = DEFAULT_INSTRUMENT_CURRENCY
+	}
+	if newInstrument.Kind == "" {
+		newInstrument.Kind = entity.InstrumentOther
+	}
+
+	if err := instrument_serv.applyInvestmentType(ctx, &newInstrument, instrument.InvestmentTypeID); err != nil {
+		return dto.InstrumentsResponse{}, err
+	}
+	if err := validateInstrument(newInstrument); err != nil {
+		return dto.InstrumentsResponse{}, err
+	}
+
+	if _, err := instrument_serv.instrumentsRepository.GetInstrumentByCode(ctx, nil, newInstrument.Code); err == nil {
+		return dto.InstrumentsResponse{}, fmt.Errorf("instrument %s already exists", newInstrument.Code)
+	}
+
+	createdInstrument, err := instrument_serv.instrumentsRepository.CreateInstrument(ctx, nil, newInstrument)
+	if err != nil {
+		return dto.InstrumentsResponse{}, errors.New("failed to create instrument")
+	}
+
+	return helper.ConvertToResponseType(createdInstrument).(dto.InstrumentsResponse), nil
+}
+
+func (instrument_serv *instrumentsService) UpdateInstrument(ctx context.Context, id string, instrument dto.InstrumentsRequest) (dto.InstrumentsResponse, error) {
+	existingInstrument, err := instrument_serv.instrumentsRepository.GetInstrumentByID(ctx, nil, id)
+	if err != nil {
+		return dto.InstrumentsResponse{}, errors.New("instrument not found")
+	}
+
+	if code := portfolio.InstrumentKey(instrument.Code); code != "" && code != existingInstrument.Code {
+		if _, err := instrument_serv.instrumentsRepository.GetInstrumentByCode(ctx, nil, code); err == nil {
+			return dto.InstrumentsResponse{}, fmt.Errorf("instrument %s already exists", code)
+		}
+		existingInstrument.Code = code
+	}
+	if name := strings.TrimSpace(instrument.Name); name != "" {
+		existingInstrument.Name = name
+	}
+	if instrument.Issuer != "" {
+		existingInstrument.Issuer = instrument.Issuer
+	}
+	if currency := strings.TrimSpace(instrument.Currency); currency != "" {
+		existingInstrument.Currency = strings.ToUpper(currency)
+	}
+	if instrument.Unit != "" {
+		existingInstrument.Unit = instrument.Unit
+	}
+	if instrument.RiskLevel != nil {
+		existingInstrument.RiskLevel = instrument.RiskLevel
+	}
+	if instrument.Kind != "" {
+		existingInstrument.Kind = entity.InstrumentKind(instrument.Kind)
+	}
+
+	if err := instrument_serv.applyInvestmentType(ctx, &existingInstrument, instrument.InvestmentTypeID); err != nil {
+		return dto.InstrumentsResponse{}, err
+	}
+	if err := validateInstrument(existingInstrument); err != nil {
+		return dto.InstrumentsResponse{}, err
+	}
+
+	updatedInstrument, err := instrument_serv.instrumentsRepository.UpdateInstrument(ctx, nil, existingInstrument)
+	if err != nil {
+		return dto.InstrumentsResponse{}, errors.New("failed to update instrument")
+	}
+
+	return helper.ConvertToResponseType(updatedInstrument).(dto.InstrumentsResponse), nil
+}
+
+func (instrument_serv *instrumentsService) DeleteInstrument(ctx context.Context, id string) (dto.InstrumentsResponse, error) {
+	instrument, err := instrument_serv.instrumentsRepository.GetInstrumentByID(ctx, nil, id)
+	if err != nil {
+		return dto.InstrumentsResponse{}, errors.New("instrument not found")
+	}
+
+	count, err := instrument_serv.instrumentsRepository.CountInvestmentsByInstrument(ctx, nil, id)
+	if err != nil {
+		return dto.InstrumentsResponse{}, errors.New("failed to check investments of the instrument")
+	}
+	if count > 0 {
+		return dto.InstrumentsResponse{}, errors.New("instrument is still held by investments")
+	}
+
+	deletedInstrument, err := instrument_serv.instrumentsRepository.DeleteInstrument(ctx, nil, instrument)
+	if err != nil {
+		return dto.InstrumentsResponse{}, errors.New("failed to delete instrument")
+	}
+
+	return helper.ConvertToResponseType(deletedInstrument).(dto.InstrumentsResponse), nil
+}
+
+// applyInvestmentType links the instrument to an existing investment type, an empty id keeps the current one
+func (instrument_serv *instrumentsService) applyInvestmentType(ctx context.Context, instrument *entity.Instruments, investmentTypeID string) error {
+	if investmentTypeID == "" {
+		return nil
+	}
+
+	investmentType, err := instrument_serv.investmentTypesRepository.GetInvestmentTypeByID(ctx, nil, investmentTypeID)
+	if err != nil {
+		return errors.New("investment type not found")
+	}
+
+	instrument.InvestmentTypeID = &investmentType.ID
+	instrument.InvestmentTypes = &investmentType
+	if instrument.Unit == "" {
+		instrument.Unit = investmentType.Unit
+	}
+	return nil
+}
+
+func validateInstrument(instrument entity.Instruments) error {
+	if !instrumentKinds[instrument.Kind] {
+		return fmt.Errorf("invalid instrument kind '%s'", instrument.Kind)
+	}
+	if len(instrument.Currency) != 3 {
+		return errors.New("currency must be a 3-letter code")
+	}
+	if instrument.RiskLevel != nil && (*instrument.RiskLevel < 1 || *instrument.RiskLevel > 5) {
+		return errors.New("risk level must be between 1 and 5")
+	}
+	if len(instrument.Code) > 50 || len(instrument.Name) > 50 {
+		return errors.New("code and name must be at most 50 characters")
+	}
+	return nil
+}
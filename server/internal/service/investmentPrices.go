@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"server/config/log"
+	"server/config/pricing"
+	"server/internal/repository"
+	"server/internal/types/dto"
+	"server/internal/types/entity"
+	"server/internal/utils/portfolio"
+)
+
+const (
+	PRICE_SOURCE_MANUAL = "manual"
+	PRICE_SOURCE_CSV    = "csv"
+	PRICE_SOURCE_TRADE  = "trade" // not stored, reported when a position is valued at its last trade price
+)
+
+// Accepted date formats of imported prices
+var priceDateLayouts = []string{"2006-01-02", "02/01/2006"}
+
+type InvestmentPricesService interface {
+	GetPrices(ctx context.Context, instrument string, from, to time.Time) ([]dto.InvestmentPricesResponse, error)
+	CreatePrices(ctx context.Context, prices []dto.InvestmentPricesRequest) ([]dto.InvestmentPricesResponse, error)
+	ImportPricesCSV(ctx context.Context, reader io.Reader) (int, error)
+	SyncPrices(ctx context.Context, date time.Time) (int, error)
+	DeletePrice(ctx context.Context, id string) (dto.InvestmentPricesResponse, error)
+}
+
+type investmentPricesService struct {
+	txManager             repository.TxManager
+	pricesRepository      repository.InvestmentPricesRepository
+	investmentsRepository repository.InvestmentsRepository
+	instrumentsRepository repository.InstrumentsRepository
+	provider              pricing.Provider
+}
+
+func NewInvestmentPricesService(txManager repository.TxManager, pricesRepository repository.InvestmentPricesRepository, investmentsRepository repository.InvestmentsRepository, instrumentsRepository repository.InstrumentsRepository, provider pricing.Provider) InvestmentPricesService {
+	return &investmentPricesService{
+		txManager:             txManager,
+		pricesRepository:      pricesRepository,
+		investmentsRepository: investmentsRepository,
+		instrumentsRepository: instrumentsRepository,
+		provider:              provider,
+	}
+}
+
+func (price_serv *investmentPricesService) GetPrices(ctx context.Context, instrument string, from, to time.Time) ([]dto.InvestmentPricesResponse, error) {
+	if instrument == "" {
+		return nil, errors.New("instrument is required")
+	}
+	if to.IsZero() {
+		to = time.Now()
+	}
+
+	instrumentEntity, err := price_serv.instrumentsRepository.GetInstrumentByCode(ctx, nil, portfolio.InstrumentKey(instrument))
+	if err != nil {
+		return nil, errors.New("instrument not found")
+	}
+
+	prices, err := price_serv.pricesRepository.GetPrices(ctx, nil, instrumentEntity.ID.String(), from, to)
+	if err != nil {
+		return nil, errors.New("failed to get prices")
+	}
+
+	pricesResponse := make([]dto.InvestmentPricesResponse, 0, len(prices))
+	for _, price := range prices {
+		pricesResponse = append(pricesResponse, toPriceResponse(price))
+	}
+
+	return pricesResponse, nil
+}
+
+func (price_serv *investmentPricesService) CreatePrices(ctx context.Context, prices []dto.InvestmentPricesRequest) ([]dto.InvestmentPricesResponse, error) {
+	if len(prices) == 0 {
+		return nil, errors.New("no prices to save")
+	}
+
+	codes := make([]string, 0, len(prices))
+	for _, price := range prices {
+		codes = append(codes, portfolio.InstrumentKey(price.Instrument))
+	}
+	instruments, err := price_serv.instrumentsByCode(ctx, codes)
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make([]entity.InvestmentPrices, 0, len(prices))
+	for idx, price := range prices {
+		if price.Instrument == "" || price.PriceDate.IsZero() {
+			return nil, fmt.Errorf("price %d: instrument and price date are required", idx+1)
+		}
+		if price.Price < 0 {
+			return nil, fmt.Errorf("price %d: price must not be negative", idx+1)
+		}
+		instrument, ok := instruments[portfolio.InstrumentKey(price.Instrument)]
+		if !ok {
+			return nil, fmt.Errorf("price %d: unknown instrument '%s'", idx+1, price.Instrument)
+		}
+
+		entities = append(entities, entity.InvestmentPrices{
+			InstrumentID: instrument.ID,
+			PriceDate:    truncateDate(price.PriceDate),
+			Price:        price.Price,
+			Source:       PRICE_SOURCE_MANUAL,
+			Instrument:   instrument,
+		})
+	}
+
+	if _, err := price_serv.pricesRepository.UpsertPrices(ctx, nil, entities); err != nil {
+		return nil, errors.New("failed to save prices")
+	}
+
+	pricesResponse := make([]dto.InvestmentPricesResponse, 0, len(entities))
+	for _, price := range entities {
+		pricesResponse = append(pricesResponse, toPriceResponse(price))
+	}
+
+	return pricesResponse, nil
+}
+
+// ImportPricesCSV imports "instrument,date,price" rows, an optional header row is skipped.
+// Nothing is imported when any row is invalid or names an unknown instrument code.
+func (price_serv *investmentPricesService) ImportPricesCSV(ctx context.Context, reader io.Reader) (int, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = 3
+	csvReader.TrimLeadingSpace = true
+
+	var prices []entity.InvestmentPrices
+	var codes []string
+	var lines []int
+	for line := 1; ; line++ {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("invalid csv: %v", err)
+		}
+
+		if line == 1 && strings.EqualFold(strings.TrimSpace(record[0]), "instrument") {
+			continue
+		}
+
+		code, price, err := parsePriceRecord(record)
+		if err != nil {
+			return 0, fmt.Errorf("line %d: %v", line, err)
+		}
+		prices = append(prices, price)
+		codes = append(codes, code)
+		lines = append(lines, line)
+	}
+
+	if len(prices) == 0 {
+		return 0, errors.New("no prices to import")
+	}
+
+	instruments, err := price_serv.instrumentsByCode(ctx, codes)
+	if err != nil {
+		return 0, err
+	}
+	for idx, code := range codes {
+		instrument, ok := instruments[code]
+		if !ok {
+			return 0, fmt.Errorf("line %d: unknown instrument '%s'", lines[idx], code)
+		}
+		prices[idx].InstrumentID = instrument.ID
+	}
+
+	if _, err := price_serv.pricesRepository.UpsertPrices(ctx, nil, prices); err != nil {
+		return 0, errors.New("failed to import prices")
+	}
+
+	return len(prices), nil
+}
+
+// SyncPrices fetches the prices of every held instrument from the configured provider
+func (price_serv *investmentPricesService) SyncPrices(ctx context.Context, date time.Time) (int, error) {
+	if price_serv.provider == nil {
+		return 0, errors.New("no price provider configured")
+	}
+
+	instruments, err := price_serv.investmentsRepository.GetHeldInstruments(ctx, nil)
+	if err != nil {
+		return 0, errors.New("failed to get held instruments")
+	}
+	if len(instruments) == 0 {
+		return 0, nil
+	}
+
+	codes := make([]string, 0, len(instruments))
+	instrumentsByCode := make(map[string]entity.Instruments, len(instruments))
+	for _, instrument := range instruments {
+		codes = append(codes, instrument.Code)
+		instrumentsByCode[instrument.Code] = instrument
+	}
+
+	quotes, err := price_serv.provider.FetchQuotes(ctx, codes, truncateDate(date))
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to fetch quotes from %s: %v", price_serv.provider.Name(), err))
+		return 0, errors.New("failed to fetch prices")
+	}
+
+	prices := make([]entity.InvestmentPrices, 0, len(quotes))
+	for _, quote := range quotes {
+		if quote.Price < 0 {
+			log.Warn(fmt.Sprintf("skipping negative price of %s from %s", quote.Instrument, price_serv.provider.Name()))
+			continue
+		}
+		instrument, ok := instrumentsByCode[portfolio.InstrumentKey(quote.Instrument)]
+		if !ok {
+			log.Warn(fmt.Sprintf("skipping price of unknown instrument %s from %s", quote.Instrument, price_serv.provider.Name()))
+			continue
+		}
+		prices = append(prices, entity.InvestmentPrices{
+			InstrumentID: instrument.ID,
+			PriceDate:    truncateDate(quote.Date),
+			Price:        quote.Price,
+			Source:       price_serv.provider.Name(),
+		})
+	}
+
+	if _, err := price_serv.pricesRepository.UpsertPrices(ctx, nil, prices); err != nil {
+		return 0, errors.New("failed to save prices")
+	}
+
+	return len(prices), nil
+}
+
+func (price_serv *investmentPricesService) DeletePrice(ctx context.Context, id string) (dto.InvestmentPricesResponse, error) {
+	price, err := price_serv.pricesRepository.GetPriceByID(ctx, nil, id)
+	if err != nil {
+		return dto.InvestmentPricesResponse{}, errors.New("price not found")
+	}
+
+	deletedPrice, err := price_serv.pricesRepository.DeletePrice(ctx, nil, price)
+	if err != nil {
+		return dto.InvestmentPricesResponse{}, errors.New("failed to delete price")
+	}
+
+	return toPriceResponse(deletedPrice), nil
+}
+
+// instrumentsByCode looks up the instruments of normalized codes, unknown codes are missing from the map
+func (price_serv *investmentPricesService) instrumentsByCode(ctx context.Context, codes []string) (map[string]entity.Instruments, error) {
+	instruments, err := price_serv.instrumentsRepository.GetInstrumentsByCodes(ctx, nil, codes)
+	if err != nil {
+		return nil, errors.New("failed to get instruments")
+	}
+
+	instrumentsByCode := make(map[string]entity.Instruments, len(instruments))
+	for _, instrument := range instruments {
+		instrumentsByCode[instrument.Code] = instrument
+	}
+	return instrumentsByCode, nil
+}
+
+// parsePriceRecord returns the normalized instrument code and the price of a csv row
+func parsePriceRecord(record []string) (string, entity.InvestmentPrices, error) {
+	instrument := portfolio.InstrumentKey(record[0])
+	if instrument == "" {
+		return "", entity.InvestmentPrices{}, errors.New("instrument is required")
+	}
+
+	var date time.Time
+	var err error
+	for _, layout := range priceDateLayouts {
+		if date, err = time.Parse(layout, strings.TrimSpace(record[1])); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return "", entity.InvestmentPrices{}, fmt.Errorf("invalid date '%s'", record[1])
+	}
+
+	price, err := strconv.ParseFloat(strings.TrimSpace(record[2]), 64)
+	if err != nil || price < 0 {
+		return "", entity.InvestmentPrices{}, fmt.Errorf("invalid price '%s'", record[2])
+	}
+
+	return instrument, entity.InvestmentPrices{
+		PriceDate: date,
+		Price:     price,
+		Source:    PRICE_SOURCE_CSV,
+	}, nil
+}
+
+func toPriceResponse(price entity.InvestmentPrices) dto.InvestmentPricesResponse {
+	return dto.InvestmentPricesResponse{
+		ID:           price.ID.String(),
+		InstrumentID: price.InstrumentID.String(),
+		Instrument:   price.Instrument.Code,
+		PriceDate:    price.PriceDate,
+		Price:        price.Price,
+		Source:       price.Source,
+	}
+}
+
+// truncateDate drops the time of day, prices are stored per calendar date
+func truncateDate(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"server/internal/repository"
+	"server/internal/types/dto"
+	"server/internal/types/entity"
+	helper "server/internal/utils"
+)
+
+type InvestmentTypesService interface {
+	GetAllInvestmentTypes(ctx context.Context) ([]dto.InvestmentTypesResponse, error)
+	GetInvestmentTypeByID(ctx context.Context, id string) (dto.InvestmentTypesResponse, error)
+	CreateInvestmentType(ctx context.Context, investmentType dto.InvestmentTypesRequest) (dto.InvestmentTypesResponse, error)
+	UpdateInvestmentType(ctx context.Context, id string, investmentType dto.InvestmentTypesRequest) (dto.InvestmentTypesResponse, error)
+	DeleteInvestmentType(ctx context.Context, id string) (dto.InvestmentTypesResponse, error)
+}
+
+type investmentTypesService struct {
+	txManager                 repository.TxManager
+	investmentTypesRepository repository.InvestmentTypesRepository
+}
+
+func NewInvestmentTypesService(txManager repository.TxManager, investmentTypesRepository repository.InvestmentTypesRepository) InvestmentTypesService {
+	return &investmentTypesService{
+		txManager:                 txManager,
+		investmentTypesRepository: investmentTypesRepository,
+	}
+}
+
+func (investment_type_serv *investmentTypesService) GetAllInvestmentTypes(ctx context.Context) ([]dto.InvestmentTypesResponse, error) {
+	investmentTypes, err := investment_type_serv.investmentTypesRepository.GetAllInvestmentTypes(ctx, nil)
+	if err != nil {
+		return nil, errors.New("failed to get investment types")
+	}
+
+	investmentTypesResponse := make([]dto.InvestmentTypesResponse, 0, len(investmentTypes))
+	for _, investmentType := range investmentTypes {
+		investmentTypesResponse = append(investmentTypesResponse, helper.ConvertToResponseType(investmentType).(dto.InvestmentTypesResponse))
+	}
+
+	return investmentTypesResponse, nil
+}
+
+func (investment_type_serv *investmentTypesService) GetInvestmentTypeByID(ctx context.Context, id string) (dto.InvestmentTypesResponse, error) {
+	investmentType, err := investment_type_serv.investmentTypesRepository.GetInvestmentTypeByID(ctx, nil, id)
+	if err != nil {
+		return dto.InvestmentTypesResponse{}, errors.New("investment type not found")
+	}
+
+	return helper.ConvertToResponseType(investmentType).(dto.InvestmentTypesResponse), nil
+}
+
+func (investment_type_serv *investmentTypesService) CreateInvestmentType(ctx context.Context, investmentType dto.InvestmentTypesRequest) (dto.InvestmentTypesResponse, error) {
+	name := strings.TrimSpace(investmentType.Name)
+	if name == "" {
+		return dto.InvestmentTypesResponse{}, errors.New("name is required")
+	}
+
+	newInvestmentType, err := investment_type_serv.investmentTypesRepository.CreateInvestmentType(ctx, nil, entity.InvestmentTypes{
+		Name: name,
+		Unit: investmentType.Unit,
+	})
+	if err != nil {
+		return dto.InvestmentTypesResponse{}, errors.New("failed to create investment type")
+	}
+
+	return helper.ConvertToResponseType(newInvestmentType).(dto.InvestmentTypesResponse), nil
+}
+
+func (investment_type_serv *investmentTypesService) UpdateInvestmentType(ctx context.Context, id string, investmentType dto.InvestmentTypesRequest) (dto.InvestmentTypesResponse, error) {
+	existingInvestmentType, err := investment_type_serv.investmentTypesRepository.GetInvestmentTypeByID(ctx, nil, id)
+	if err != nil {
+		return dto.InvestmentTypesResponse{}, errors.New("investment type not found")
+	}
+
+	if name := strings.TrimSpace(investmentType.Name); name != "" {
+		existingInvestmentType.Name = name
+	}
+	if investmentType.Unit != "" {
+		existingInvestmentType.Unit = investmentType.Unit
+	}
+
+	updatedInvestmentType, err := investment_type_serv.investmentTypesRepository.UpdateInvestmentType(ctx, nil, existingInvestmentType)
+	if err != nil {
+		return dto.InvestmentTypesResponse{}, errors.New("failed to update investment type")
+	}
+
+	return helper.ConvertToResponseType(updatedInvestmentType).(dto.InvestmentTypesResponse), nil
+}
+
+func (investment_type_serv *investmentTypesService) DeleteInvestmentType(ctx context.Context, id string) (dto.InvestmentTypesResponse, error) {
+	investmentType, err := investment_type_serv.investmentTypesRepository.GetInvestmentTypeByID(ctx, nil, id)
+	if err != nil {
+		return dto.InvestmentTypesResponse{}, errors.New("investment type not found")
+	}
+
+	count, err := investment_type_serv.investmentTypesRepository.CountInvestmentsByType(ctx, nil, id)
+	if err != nil {
+		return dto.InvestmentTypesResponse{}, errors.New("failed to check investments of the type")
+	}
+	if count > 0 {
+		return dto.InvestmentTypesResponse{}, errors.New("investment type is still used by investments")
+	}
+
+	deletedInvestmentType, err := investment_type_serv.investmentTypesRepository.DeleteInvestmentType(ctx, nil, investmentType)
+	if err != nil {
+		return dto.InvestmentTypesResponse{}, errors.New("failed to delete investment type")
+	}
+
+	return helper.ConvertToResponseType(deletedInvestmentType).(dto.InvestmentTypesResponse), nil
+}
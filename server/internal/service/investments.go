@@ -3,12 +3,19 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
+	"time"
 
 	"server/internal/repository"
 	"server/internal/types/dto"
 	"server/internal/types/entity"
 	"server/internal/types/view"
 	helper "server/internal/utils"
+	"server/internal/utils/data"
+	"server/internal/utils/portfolio"
+
+	"github.com/google/uuid"
 )
 
 type InvestmentsService interface {
@@ -18,17 +25,48 @@ type InvestmentsService interface {
 	CreateInvestment(ctx context.Context, investment dto.InvestmentsRequest) (dto.InvestmentsResponse, error)
 	UpdateInvestment(ctx context.Context, id string, investment dto.InvestmentsRequest) (dto.InvestmentsResponse, error)
 	DeleteInvestment(ctx context.Context, id string) (dto.InvestmentsResponse, error)
+	GetInvestmentTrades(ctx context.Context, token, id string) ([]dto.InvestmentTradesResponse, error)
+	CreateInvestmentTrade(ctx context.Context, token, id string, trade dto.InvestmentTradesRequest) (dto.InvestmentTradesResponse, error)
+	DeleteInvestmentTrade(ctx context.Context, token, id, tradeID string) (dto.InvestmentTradesResponse, error)
+	GetInvestmentPosition(ctx context.Context, token, id, method string) (dto.InvestmentPositionResponse, error)
+	GetPortfolioValuation(ctx context.Context, token string) (dto.PortfolioValuationResponse, error)
+	GetPortfolioHistory(ctx context.Context, token string, from, to time.Time, interval string) ([]dto.PortfolioValuePoint, error)
+	GetInvestmentIncomes(ctx context.Context, token, id string) ([]dto.InvestmentIncomesResponse, error)
+	CreateInvestmentIncome(ctx context.Context, token, id string, income dto.InvestmentIncomesRequest) (dto.InvestmentIncomesResponse, error)
+	DeleteInvestmentIncome(ctx context.Context, token, id, incomeID string) (dto.InvestmentIncomesResponse, error)
+	GetInvestmentYield(ctx context.Context, token, id string) (dto.InvestmentYieldResponse, error)
+	GetCouponSchedule(ctx context.Context, token, id string) ([]dto.CouponPaymentResponse, error)
+	GetInvestmentPerformance(ctx context.Context, token, id, period string) (dto.InvestmentPerformanceResponse, error)
+	GetPortfolioPerformance(ctx context.Context, token, period string) (dto.PortfolioPerformanceResponse, error)
 }
 
 type investmentsService struct {
 	txManager             repository.TxManager
 	investmentsRepository repository.InvestmentsRepository
+	tradesRepository      repository.InvestmentTradesRepository
+	incomesRepository     repository.InvestmentIncomesRepository
+	pricesRepository      repository.InvestmentPricesRepository
+	instrumentsRepository repository.InstrumentsRepository
+	walletRepository      repository.WalletsRepository
+	transactionRepository repository.TransactionsRepository
+	categoryRepository    repository.CategoriesRepository
+	attachmentServ        AttachmentsService
+	outboxServ            OutboxService
 }
 
-func NewInvestmentService(txManager repository.TxManager, investmentsRepository repository.InvestmentsRepository) InvestmentsService {
+func NewInvestmentService(txManager repository.TxManager, investmentsRepository repository.InvestmentsRepository, tradesRepository repository.InvestmentTradesRepository, incomesRepository repository.InvestmentIncomesRepository, pricesRepository repository.InvestmentPricesRepository, instrumentsRepository repository.InstrumentsRepository, walletRepository repository.WalletsRepository, transactionRepository repository.TransactionsRepository, categoryRepository repository.CategoriesRepository, attachmentServ AttachmentsService, outboxServ OutboxService) InvestmentsService {
 	return &investmentsService{
 		txManager:             txManager,
 		investmentsRepository: investmentsRepository,
+		tradesRepository:      tradesRepository,
+		incomesRepository:     incomesRepository,
+		pricesRepository:      pricesRepository,
+		instrumentsRepository: instrumentsRepository,
+		walletRepository:      walletRepository,
+		transactionRepository: transactionRepository,
+		categoryRepository:    categoryRepository,
+		attachmentServ:        attachmentServ,
+		outboxServ:            outboxServ,
 	}
 }
 
@@ -78,70 +116,1355 @@ func (investment_serv *investmentsService) CreateInvestment(ctx context.Context,
 		return dto.InvestmentsResponse{}, errors.New("invalid user id")
 	}
 
-	investmentTypeID, err := helper.ParseUUID(investment.InvestmentTypeID)
+	instrument, err := investment_serv.instrumentsRepository.GetInstrumentByID(ctx, nil, investment.InstrumentID)
 	if err != nil {
+		return dto.InvestmentsResponse{}, errors.New("instrument not found")
+	}
+
+	// ? The investment type defaults to the type of the instrument
+	var investmentTypeID uuid.UUID
+	switch {
+	case investment.InvestmentTypeID != "":
+		if investmentTypeID, err = helper.ParseUUID(investment.InvestmentTypeID); err != nil {
+			return dto.InvestmentsResponse{}, err
+		}
+	case instrument.InvestmentTypeID != nil:
+		investmentTypeID = *instrument.InvestmentTypeID
+	default:
+		return dto.InvestmentsResponse{}, errors.New("investment type is required")
+	}
+
+	if investment.CostMethod == "" {
+		investment.CostMethod = portfolio.COST_METHOD_FIFO
+	}
+	if !portfolio.ValidCostMethod(investment.CostMethod) {
+		return dto.InvestmentsResponse{}, errors.New("invalid cost method")
+	}
+	if investment.Amount < 0 || investment.Quantity < 0 {
+		return dto.InvestmentsResponse{}, errors.New("amount and quantity must not be negative")
+	}
+	if err := validateCouponTerms(investment); err != nil {
 		return dto.InvestmentsResponse{}, err
 	}
 
-	newInvestment, err := investment_serv.investmentsRepository.CreateInvestment(ctx, nil, entity.Investments{
+	tx, err := investment_serv.txManager.Begin(ctx)
+	if err != nil {
+		return dto.InvestmentsResponse{}, errors.New("failed to create transaction")
+	}
+
+	defer func() {
+		// Rollback otomatis jika transaksi belum di-commit
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	newInvestment, err := investment_serv.investmentsRepository.CreateInvestment(ctx, tx, entity.Investments{
 		UserID:           userID,
 		InvestmentTypeID: investmentTypeID,
-		Name:             investment.Name,
+		InstrumentID:     instrument.ID,
 		Amount:           investment.Amount,
 		Quantity:         investment.Quantity,
+		CostMethod:       investment.CostMethod,
 		InvestmentDate:   investment.InvestmentDate,
 		Description:      investment.Description,
+		CouponRate:       investment.CouponRate,
+		CouponFrequency:  investment.CouponFrequency,
+		MaturityDate:     investment.MaturityDate,
 	})
 	if err != nil {
 		return dto.InvestmentsResponse{}, err
 	}
 
+	// ? The initial amount and quantity are the first buy of the position
+	if investment.Quantity > 0 {
+		initialTrade := entity.InvestmentTrades{
+			InvestmentID: newInvestment.ID,
+			Type:         portfolio.TRADE_BUY,
+			TradeDate:    investment.InvestmentDate,
+			Quantity:     investment.Quantity,
+			Price:        investment.Amount / investment.Quantity,
+			Description:  investment.Description,
+		}
+
+		if investment.WalletID != "" {
+			// * Reload to get the investment type, it picks the category of the wallet transaction
+			var fundedInvestment entity.Investments
+			fundedInvestment, err = investment_serv.investmentsRepository.GetInvestmentByID(ctx, tx, newInvestment.ID.String())
+			if err != nil {
+				return dto.InvestmentsResponse{}, errors.New("investment not found")
+			}
+			if err = investment_serv.fundTrade(ctx, tx, fundedInvestment, investment.WalletID, &initialTrade); err != nil {
+				return dto.InvestmentsResponse{}, err
+			}
+		}
+
+		if _, err = investment_serv.tradesRepository.CreateTrade(ctx, tx, initialTrade); err != nil {
+			return dto.InvestmentsResponse{}, errors.New("failed to create investment trade")
+		}
+	}
+
+	after, err := investment_serv.investmentEventPayload(ctx, tx, newInvestment)
+	if err != nil {
+		return dto.InvestmentsResponse{}, errors.New("failed to store investment event")
+	}
+
+	if err = enqueueEvent(ctx, tx, investment_serv.outboxServ, data.EVENT_INVESTMENT_CREATED, newInvestment.UserID.String(), newInvestment.ID.String(), nil, after); err != nil {
+		return dto.InvestmentsResponse{}, errors.New("failed to store investment event")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return dto.InvestmentsResponse{}, errors.New("failed to commit transaction")
+	}
+
+	newInvestment.Instrument = instrument
 	investmentResponse := helper.ConvertToResponseType(newInvestment).(dto.InvestmentsResponse)
 
 	return investmentResponse, nil
 }
 
 func (investment_serv *investmentsService) UpdateInvestment(ctx context.Context, id string, investment dto.InvestmentsRequest) (dto.InvestmentsResponse, error) {
-	existingInvestment, err := investment_serv.investmentsRepository.GetInvestmentByID(ctx, nil, id)
+	if investment.Amount != 0 || investment.Quantity != 0 {
+		return dto.InvestmentsResponse{}, errors.New("amount and quantity are derived from trades, record a trade instead")
+	}
+	if err := validateCouponTerms(investment); err != nil {
+		return dto.InvestmentsResponse{}, err
+	}
+
+	tx, err := investment_serv.txManager.Begin(ctx)
 	if err != nil {
-		return dto.InvestmentsResponse{}, errors.New("investment not found")
+		return dto.InvestmentsResponse{}, errors.New("failed to create transaction")
 	}
 
-	if investment.Name != "" {
-		existingInvestment.Name = investment.Name
+	defer func() {
+		// Rollback otomatis jika transaksi belum di-commit
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	existingInvestment, err := investment_serv.investmentsRepository.GetInvestmentByID(ctx, tx, id)
+	if err != nil {
+		return dto.InvestmentsResponse{}, errors.New("investment not found")
 	}
-	if investment.Amount != 0 {
-		existingInvestment.Amount = investment.Amount
+	before, err := investment_serv.investmentEventPayload(ctx, tx, existingInvestment)
+	if err != nil {
+		return dto.InvestmentsResponse{}, errors.New("failed to store investment event")
 	}
-	if investment.Quantity != 0 {
-		existingInvestment.Quantity = investment.Quantity
+
+	if investment.InstrumentID != "" && investment.InstrumentID != existingInvestment.InstrumentID.String() {
+		var instrument entity.Instruments
+		instrument, err = investment_serv.instrumentsRepository.GetInstrumentByID(ctx, tx, investment.InstrumentID)
+		if err != nil {
+			err = errors.New("instrument not found")
+			return dto.InvestmentsResponse{}, err
+		}
+		existingInvestment.InstrumentID = instrument.ID
+		existingInvestment.Instrument = instrument
 	}
 	if !investment.InvestmentDate.IsZero() {
 		existingInvestment.Description = investment.Description
 	}
+	if investment.CostMethod != "" {
+		if !portfolio.ValidCostMethod(investment.CostMethod) {
+			err = errors.New("invalid cost method")
+			return dto.InvestmentsResponse{}, err
+		}
+		existingInvestment.CostMethod = investment.CostMethod
+	}
+	if investment.CouponRate != nil {
+		existingInvestment.CouponRate = investment.CouponRate
+	}
+	if investment.CouponFrequency != nil {
+		existingInvestment.CouponFrequency = investment.CouponFrequency
+	}
+	if investment.MaturityDate != nil {
+		existingInvestment.MaturityDate = investment.MaturityDate
+	}
 
-	investmentUpdated, err := investment_serv.investmentsRepository.UpdateInvestment(ctx, nil, existingInvestment)
+	// * Cost basis depends on the cost method
+	if _, err = investment_serv.applyTrades(ctx, tx, &existingInvestment); err != nil {
+		return dto.InvestmentsResponse{}, err
+	}
+
+	investmentUpdated, err := investment_serv.investmentsRepository.UpdateInvestment(ctx, tx, existingInvestment)
 	if err != nil {
 		return dto.InvestmentsResponse{}, errors.New("failed to update investment")
 	}
 
+	after, err := investment_serv.investmentEventPayload(ctx, tx, investmentUpdated)
+	if err != nil {
+		return dto.InvestmentsResponse{}, errors.New("failed to store investment event")
+	}
+
+	if err = enqueueEvent(ctx, tx, investment_serv.outboxServ, data.EVENT_INVESTMENT_UPDATED, investmentUpdated.UserID.String(), investmentUpdated.ID.String(), before, after); err != nil {
+		return dto.InvestmentsResponse{}, errors.New("failed to store investment event")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return dto.InvestmentsResponse{}, errors.New("failed to commit transaction")
+	}
+
 	investmentResponse := helper.ConvertToResponseType(investmentUpdated).(dto.InvestmentsResponse)
 
 	return investmentResponse, nil
 }
 
 func (investment_serv *investmentsService) DeleteInvestment(ctx context.Context, id string) (dto.InvestmentsResponse, error) {
-	existingInvestment, err := investment_serv.investmentsRepository.GetInvestmentByID(ctx, nil, id)
+	tx, err := investment_serv.txManager.Begin(ctx)
+	if err != nil {
+		return dto.InvestmentsResponse{}, errors.New("failed to create transaction")
+	}
+
+	defer func() {
+		// Rollback otomatis jika transaksi belum di-commit
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	existingInvestment, err := investment_serv.investmentsRepository.GetInvestmentByID(ctx, tx, id)
 	if err != nil {
 		return dto.InvestmentsResponse{}, errors.New("investment not found")
 	}
+	before, err := investment_serv.investmentEventPayload(ctx, tx, existingInvestment)
+	if err != nil {
+		return dto.InvestmentsResponse{}, errors.New("failed to store investment event")
+	}
+
+	// * Money that moved between wallets and the investment goes back with it
+	trades, err := investment_serv.tradesRepository.GetTradesByInvestmentID(ctx, tx, id)
+	if err != nil {
+		return dto.InvestmentsResponse{}, errors.New("failed to get investment trades")
+	}
+	for _, trade := range trades {
+		if err = investment_serv.revertWalletTransaction(ctx, tx, trade.TransactionID); err != nil {
+			return dto.InvestmentsResponse{}, err
+		}
+	}
+
+	incomes, err := investment_serv.incomesRepository.GetIncomesByInvestmentID(ctx, tx, id)
+	if err != nil {
+		return dto.InvestmentsResponse{}, errors.New("failed to get investment incomes")
+	}
+	for _, income := range incomes {
+		if err = investment_serv.revertWalletTransaction(ctx, tx, income.TransactionID); err != nil {
+			return dto.InvestmentsResponse{}, err
+		}
+	}
 
-	investmentDeleted, err := investment_serv.investmentsRepository.DeleteInvestment(ctx, nil, existingInvestment)
+	investmentDeleted, err := investment_serv.investmentsRepository.DeleteInvestment(ctx, tx, existingInvestment)
 	if err != nil {
 		return dto.InvestmentsResponse{}, errors.New("failed to delete investment")
 	}
 
+	// * Release the files of the investment, they are only deleted with their last reference
+	orphanBlobs, err := investment_serv.attachmentServ.DetachOwner(ctx, tx, data.ATTACHMENT_OWNER_INVESTMENT, existingInvestment.ID.String())
+	if err != nil {
+		return dto.InvestmentsResponse{}, errors.New("failed to delete investment attachments")
+	}
+
+	if err = enqueueEvent(ctx, tx, investment_serv.outboxServ, data.EVENT_INVESTMENT_DELETED, existingInvestment.UserID.String(), existingInvestment.ID.String(), before, nil); err != nil {
+		return dto.InvestmentsResponse{}, errors.New("failed to store investment event")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return dto.InvestmentsResponse{}, errors.New("failed to commit transaction")
+	}
+
+	investment_serv.attachmentServ.PurgeBlobs(ctx, orphanBlobs)
+
 	investmentResponse := helper.ConvertToResponseType(investmentDeleted).(dto.InvestmentsResponse)
 
 	return investmentResponse, nil
 }
+
+func (investment_serv *investmentsService) GetInvestmentTrades(ctx context.Context, token, id string) ([]dto.InvestmentTradesResponse, error) {
+	if _, err := investment_serv.getUserInvestment(ctx, nil, token, id); err != nil {
+		return nil, err
+	}
+
+	trades, err := investment_serv.tradesRepository.GetTradesByInvestmentID(ctx, nil, id)
+	if err != nil {
+		return nil, errors.New("failed to get investment trades")
+	}
+
+	tradesResponse := make([]dto.InvestmentTradesResponse, 0, len(trades))
+	for _, trade := range trades {
+		tradesResponse = append(tradesResponse, helper.ConvertToResponseType(trade).(dto.InvestmentTradesResponse))
+	}
+
+	return tradesResponse, nil
+}
+
+func (investment_serv *investmentsService) CreateInvestmentTrade(ctx context.Context, token, id string, trade dto.InvestmentTradesRequest) (dto.InvestmentTradesResponse, error) {
+	switch trade.Type {
+	case portfolio.TRADE_BUY, portfolio.TRADE_SELL:
+		if trade.Quantity <= 0 || trade.Price <= 0 {
+			return dto.InvestmentTradesResponse{}, errors.New("quantity and price must be greater than zero")
+		}
+	case portfolio.TRADE_FEE:
+		if trade.Fee <= 0 {
+			return dto.InvestmentTradesResponse{}, errors.New("fee must be greater than zero")
+		}
+		trade.Quantity, trade.Price = 0, 0
+	default:
+		return dto.InvestmentTradesResponse{}, errors.New("invalid trade type")
+	}
+	if trade.Fee < 0 {
+		return dto.InvestmentTradesResponse{}, errors.New("fee must not be negative")
+	}
+	if trade.TradeDate.IsZero() {
+		return dto.InvestmentTradesResponse{}, errors.New("trade date is required")
+	}
+
+	tx, err := investment_serv.txManager.Begin(ctx)
+	if err != nil {
+		return dto.InvestmentTradesResponse{}, errors.New("failed to create transaction")
+	}
+
+	defer func() {
+		// Rollback otomatis jika transaksi belum di-commit
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	investment, err := investment_serv.getUserInvestment(ctx, tx, token, id)
+	if err != nil {
+		return dto.InvestmentTradesResponse{}, err
+	}
+	before, err := investment_serv.investmentEventPayload(ctx, tx, investment)
+	if err != nil {
+		return dto.InvestmentTradesResponse{}, errors.New("failed to store investment event")
+	}
+
+	tradeEntity := entity.InvestmentTrades{
+		InvestmentID: investment.ID,
+		Type:         trade.Type,
+		TradeDate:    trade.TradeDate,
+		Quantity:     trade.Quantity,
+		Price:        trade.Price,
+		Fee:          trade.Fee,
+		Description:  trade.Description,
+	}
+
+	if trade.WalletID != "" {
+		if err = investment_serv.fundTrade(ctx, tx, investment, trade.WalletID, &tradeEntity); err != nil {
+			return dto.InvestmentTradesResponse{}, err
+		}
+	}
+
+	newTrade, err := investment_serv.tradesRepository.CreateTrade(ctx, tx, tradeEntity)
+	if err != nil {
+		return dto.InvestmentTradesResponse{}, errors.New("failed to create investment trade")
+	}
+
+	// * Replaying all trades rejects sells of more than was held at that date
+	if _, err = investment_serv.applyTrades(ctx, tx, &investment); err != nil {
+		return dto.InvestmentTradesResponse{}, err
+	}
+
+	if _, err = investment_serv.investmentsRepository.UpdateInvestment(ctx, tx, investment); err != nil {
+		return dto.InvestmentTradesResponse{}, errors.New("failed to update investment")
+	}
+
+	after, err := investment_serv.investmentEventPayload(ctx, tx, investment)
+	if err != nil {
+		return dto.InvestmentTradesResponse{}, errors.New("failed to store investment event")
+	}
+
+	if err = enqueueEvent(ctx, tx, investment_serv.outboxServ, data.EVENT_INVESTMENT_UPDATED, investment.UserID.String(), investment.ID.String(), before, after); err != nil {
+		return dto.InvestmentTradesResponse{}, errors.New("failed to store investment event")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return dto.InvestmentTradesResponse{}, errors.New("failed to commit transaction")
+	}
+
+	tradeResponse := helper.ConvertToResponseType(newTrade).(dto.InvestmentTradesResponse)
+
+	return tradeResponse, nil
+}
+
+func (investment_serv *investmentsService) DeleteInvestmentTrade(ctx context.Context, token, id, tradeID string) (dto.InvestmentTradesResponse, error) {
+	tx, err := investment_serv.txManager.Begin(ctx)
+	if err != nil {
+		return dto.InvestmentTradesResponse{}, errors.New("failed to create transaction")
+	}
+
+	defer func() {
+		// Rollback otomatis jika transaksi belum di-commit
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	investment, err := investment_serv.getUserInvestment(ctx, tx, token, id)
+	if err != nil {
+		return dto.InvestmentTradesResponse{}, err
+	}
+	before, err := investment_serv.investmentEventPayload(ctx, tx, investment)
+	if err != nil {
+		return dto.InvestmentTradesResponse{}, errors.New("failed to store investment event")
+	}
+
+	trade, err := investment_serv.tradesRepository.GetTradeByID(ctx, tx, tradeID)
+	if err != nil || trade.InvestmentID != investment.ID {
+		err = errors.New("investment trade not found")
+		return dto.InvestmentTradesResponse{}, err
+	}
+
+	deletedTrade, err := investment_serv.tradesRepository.DeleteTrade(ctx, tx, trade)
+	if err != nil {
+		return dto.InvestmentTradesResponse{}, errors.New("failed to delete investment trade")
+	}
+
+	if err = investment_serv.revertWalletTransaction(ctx, tx, trade.TransactionID); err != nil {
+		return dto.InvestmentTradesResponse{}, err
+	}
+
+	// * Deleting a buy may leave later sells uncovered
+	if _, err = investment_serv.applyTrades(ctx, tx, &investment); err != nil {
+		return dto.InvestmentTradesResponse{}, err
+	}
+
+	if _, err = investment_serv.investmentsRepository.UpdateInvestment(ctx, tx, investment); err != nil {
+		return dto.InvestmentTradesResponse{}, errors.New("failed to update investment")
+	}
+
+	after, err := investment_serv.investmentEventPayload(ctx, tx, investment)
+	if err != nil {
+		return dto.InvestmentTradesResponse{}, errors.New("failed to store investment event")
+	}
+
+	if err = enqueueEvent(ctx, tx, investment_serv.outboxServ, data.EVENT_INVESTMENT_UPDATED, investment.UserID.String(), investment.ID.String(), before, after); err != nil {
+		return dto.InvestmentTradesResponse{}, errors.New("failed to store investment event")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return dto.InvestmentTradesResponse{}, errors.New("failed to commit transaction")
+	}
+
+	tradeResponse := helper.ConvertToResponseType(deletedTrade).(dto.InvestmentTradesResponse)
+
+	return tradeResponse, nil
+}
+
+// GetInvestmentPosition computes cost basis and P&L of the position, method overrides the cost method of the investment
+func (investment_serv *investmentsService) GetInvestmentPosition(ctx context.Context, token, id, method string) (dto.InvestmentPositionResponse, error) {
+	investment, err := investment_serv.getUserInvestment(ctx, nil, token, id)
+	if err != nil {
+		return dto.InvestmentPositionResponse{}, err
+	}
+
+	if method == "" {
+		method = investment.CostMethod
+	}
+	if !portfolio.ValidCostMethod(method) {
+		return dto.InvestmentPositionResponse{}, errors.New("invalid cost method")
+	}
+
+	trades, err := investment_serv.tradesRepository.GetTradesByInvestmentID(ctx, nil, id)
+	if err != nil {
+		return dto.InvestmentPositionResponse{}, errors.New("failed to get investment trades")
+	}
+
+	position, err := portfolio.ComputePosition(toPortfolioTrades(trades), method)
+	if err != nil {
+		return dto.InvestmentPositionResponse{}, err
+	}
+
+	price, priceDate := latestTradePrice(trades)
+	marketPrice, err := investment_serv.pricesRepository.GetLatestPrice(ctx, nil, investment.InstrumentID.String(), time.Now())
+	if err != nil {
+		return dto.InvestmentPositionResponse{}, errors.New("failed to get latest price")
+	}
+	if marketPrice != nil {
+		price, priceDate = marketPrice.Price, &marketPrice.PriceDate
+	}
+
+	positionResponse := dto.InvestmentPositionResponse{
+		InvestmentID:  investment.ID.String(),
+		CostMethod:    position.Method,
+		Quantity:      position.Quantity,
+		CostBasis:     position.CostBasis,
+		AverageCost:   position.AverageCost,
+		TotalFees:     position.TotalFees,
+		RealizedPnL:   position.RealizedPnL,
+		LatestPrice:   price,
+		PriceDate:     priceDate,
+		MarketValue:   position.MarketValue(price),
+		UnrealizedPnL: position.UnrealizedPnL(price),
+		Sales:         make([]dto.InvestmentSaleResponse, 0, len(position.Sales)),
+	}
+	for _, sale := range position.Sales {
+		positionResponse.Sales = append(positionResponse.Sales, dto.InvestmentSaleResponse{
+			TradeID:     sale.TradeID,
+			TradeDate:   sale.Date,
+			Quantity:    sale.Quantity,
+			Proceeds:    sale.Proceeds,
+			CostBasis:   sale.CostBasis,
+			RealizedPnL: sale.RealizedPnL,
+		})
+	}
+
+	return positionResponse, nil
+}
+
+// getUserInvestment returns the investment when it belongs to the user of the token
+func (investment_serv *investmentsService) getUserInvestment(ctx context.Context, tx repository.Transaction, token, id string) (entity.Investments, error) {
+	userData, err := helper.VerifyToken(token[7:])
+	if err != nil {
+		return entity.Investments{}, errors.New("invalid token")
+	}
+
+	investment, err := investment_serv.investmentsRepository.GetInvestmentByID(ctx, tx, id)
+	if err != nil || investment.UserID.String() != userData.ID {
+		return entity.Investments{}, errors.New("investment not found")
+	}
+
+	return investment, nil
+}
+
+// applyTrades replays the trades of the investment and stores the held quantity and cost basis on it
+func (investment_serv *investmentsService) applyTrades(ctx context.Context, tx repository.Transaction, investment *entity.Investments) (portfolio.Position, error) {
+	trades, err := investment_serv.tradesRepository.GetTradesByInvestmentID(ctx, tx, investment.ID.String())
+	if err != nil {
+		return portfolio.Position{}, errors.New("failed to get investment trades")
+	}
+
+	position, err := portfolio.ComputePosition(toPortfolioTrades(trades), investment.CostMethod)
+	if err != nil {
+		return portfolio.Position{}, err
+	}
+
+	investment.Quantity = position.Quantity
+	investment.Amount = position.CostBasis
+
+	return position, nil
+}
+
+func (investment_serv *investmentsService) GetInvestmentIncomes(ctx context.Context, token, id string) ([]dto.InvestmentIncomesResponse, error) {
+	if _, err := investment_serv.getUserInvestment(ctx, nil, token, id); err != nil {
+		return nil, err
+	}
+
+	incomes, err := investment_serv.incomesRepository.GetIncomesByInvestmentID(ctx, nil, id)
+	if err != nil {
+		return nil, errors.New("failed to get investment incomes")
+	}
+
+	incomesResponse := make([]dto.InvestmentIncomesResponse, 0, len(incomes))
+	for _, income := range incomes {
+		incomesResponse = append(incomesResponse, helper.ConvertToResponseType(income).(dto.InvestmentIncomesResponse))
+	}
+
+	return incomesResponse, nil
+}
+
+// CreateInvestmentIncome records a payout of the holding and books the net amount as income in the wallet
+func (investment_serv *investmentsService) CreateInvestmentIncome(ctx context.Context, token, id string, income dto.InvestmentIncomesRequest) (dto.InvestmentIncomesResponse, error) {
+	if !portfolio.ValidIncomeType(income.Type) {
+		return dto.InvestmentIncomesResponse{}, errors.New("invalid income type")
+	}
+	if income.GrossAmount <= 0 {
+		return dto.InvestmentIncomesResponse{}, errors.New("gross amount must be greater than zero")
+	}
+	if income.TaxAmount == 0 && income.TaxRate > 0 {
+		income.TaxAmount = portfolio.WithholdingTax(income.GrossAmount, income.TaxRate)
+	}
+	if income.TaxAmount < 0 || income.TaxAmount >= income.GrossAmount {
+		return dto.InvestmentIncomesResponse{}, errors.New("tax amount must be between zero and the gross amount")
+	}
+	if income.PaymentDate.IsZero() {
+		return dto.InvestmentIncomesResponse{}, errors.New("payment date is required")
+	}
+
+	walletID, err := helper.ParseUUID(income.WalletID)
+	if err != nil {
+		return dto.InvestmentIncomesResponse{}, errors.New("invalid wallet id")
+	}
+
+	tx, err := investment_serv.txManager.Begin(ctx)
+	if err != nil {
+		return dto.InvestmentIncomesResponse{}, errors.New("failed to create transaction")
+	}
+
+	defer func() {
+		// Rollback otomatis jika transaksi belum di-commit
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	investment, err := investment_serv.getUserInvestment(ctx, tx, token, id)
+	if err != nil {
+		return dto.InvestmentIncomesResponse{}, err
+	}
+	before, err := investment_serv.investmentEventPayload(ctx, tx, investment)
+	if err != nil {
+		return dto.InvestmentIncomesResponse{}, errors.New("failed to store investment event")
+	}
+
+	description := income.Description
+	if description == "" {
+		description = fmt.Sprintf("Investment %s %s", income.Type, investment.Instrument.Name)
+	}
+
+	transactionID, err := investment_serv.createWalletTransaction(ctx, tx, investment, income.WalletID, entity.Income, data.INVESTMENT_INCOME_CATEGORIES[income.Type], income.GrossAmount-income.TaxAmount, income.PaymentDate, description)
+	if err != nil {
+		return dto.InvestmentIncomesResponse{}, err
+	}
+
+	newIncome, err := investment_serv.incomesRepository.CreateIncome(ctx, tx, entity.InvestmentIncomes{
+		InvestmentID:  investment.ID,
+		Type:          income.Type,
+		PaymentDate:   income.PaymentDate,
+		GrossAmount:   income.GrossAmount,
+		TaxAmount:     income.TaxAmount,
+		WalletID:      walletID,
+		TransactionID: &transactionID,
+		Description:   income.Description,
+	})
+	if err != nil {
+		return dto.InvestmentIncomesResponse{}, errors.New("failed to create investment income")
+	}
+
+	after, err := investment_serv.investmentEventPayload(ctx, tx, investment)
+	if err != nil {
+		return dto.InvestmentIncomesResponse{}, errors.New("failed to store investment event")
+	}
+
+	if err = enqueueEvent(ctx, tx, investment_serv.outboxServ, data.EVENT_INVESTMENT_UPDATED, investment.UserID.String(), investment.ID.String(), before, after); err != nil {
+		return dto.InvestmentIncomesResponse{}, errors.New("failed to store investment event")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return dto.InvestmentIncomesResponse{}, errors.New("failed to commit transaction")
+	}
+
+	incomeResponse := helper.ConvertToResponseType(newIncome).(dto.InvestmentIncomesResponse)
+
+	return incomeResponse, nil
+}
+
+func (investment_serv *investmentsService) DeleteInvestmentIncome(ctx context.Context, token, id, incomeID string) (dto.InvestmentIncomesResponse, error) {
+	tx, err := investment_serv.txManager.Begin(ctx)
+	if err != nil {
+		return dto.InvestmentIncomesResponse{}, errors.New("failed to create transaction")
+	}
+
+	defer func() {
+		// Rollback otomatis jika transaksi belum di-commit
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	investment, err := investment_serv.getUserInvestment(ctx, tx, token, id)
+	if err != nil {
+		return dto.InvestmentIncomesResponse{}, err
+	}
+	before, err := investment_serv.investmentEventPayload(ctx, tx, investment)
+	if err != nil {
+		return dto.InvestmentIncomesResponse{}, errors.New("failed to store investment event")
+	}
+
+	income, err := investment_serv.incomesRepository.GetIncomeByID(ctx, tx, incomeID)
+	if err != nil || income.InvestmentID != investment.ID {
+		err = errors.New("investment income not found")
+		return dto.InvestmentIncomesResponse{}, err
+	}
+
+	deletedIncome, err := investment_serv.incomesRepository.DeleteIncome(ctx, tx, income)
+	if err != nil {
+		return dto.InvestmentIncomesResponse{}, errors.New("failed to delete investment income")
+	}
+
+	if err = investment_serv.revertWalletTransaction(ctx, tx, income.TransactionID); err != nil {
+		return dto.InvestmentIncomesResponse{}, err
+	}
+
+	after, err := investment_serv.investmentEventPayload(ctx, tx, investment)
+	if err != nil {
+		return dto.InvestmentIncomesResponse{}, errors.New("failed to store investment event")
+	}
+
+	if err = enqueueEvent(ctx, tx, investment_serv.outboxServ, data.EVENT_INVESTMENT_UPDATED, investment.UserID.String(), investment.ID.String(), before, after); err != nil {
+		return dto.InvestmentIncomesResponse{}, errors.New("failed to store investment event")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return dto.InvestmentIncomesResponse{}, errors.New("failed to commit transaction")
+	}
+
+	incomeResponse := helper.ConvertToResponseType(deletedIncome).(dto.InvestmentIncomesResponse)
+
+	return incomeResponse, nil
+}
+
+// GetInvestmentYield reports the income of the last 12 months against the cost basis and market value of the holding
+func (investment_serv *investmentsService) GetInvestmentYield(ctx context.Context, token, id string) (dto.InvestmentYieldResponse, error) {
+	position, err := investment_serv.GetInvestmentPosition(ctx, token, id, "")
+	if err != nil {
+		return dto.InvestmentYieldResponse{}, err
+	}
+
+	incomes, err := investment_serv.incomesRepository.GetIncomesByInvestmentID(ctx, nil, id)
+	if err != nil {
+		return dto.InvestmentYieldResponse{}, errors.New("failed to get investment incomes")
+	}
+
+	payouts := make([]portfolio.Income, 0, len(incomes))
+	for _, income := range incomes {
+		payouts = append(payouts, portfolio.Income{Date: income.PaymentDate, Gross: income.GrossAmount, Tax: income.TaxAmount})
+	}
+
+	trailing := portfolio.TrailingIncome(payouts, time.Now(), portfolio.TRAILING_MONTHS)
+
+	return dto.InvestmentYieldResponse{
+		InvestmentID: id,
+		From:         trailing.From,
+		To:           trailing.To,
+		GrossIncome:  trailing.Gross,
+		TaxWithheld:  trailing.Tax,
+		NetIncome:    trailing.Net,
+		PaymentCount: trailing.Count,
+		CostBasis:    position.CostBasis,
+		YieldOnCost:  portfolio.YieldPercent(trailing.Net, position.CostBasis),
+		MarketValue:  position.MarketValue,
+		CurrentYield: portfolio.YieldPercent(trailing.Net, position.MarketValue),
+	}, nil
+}
+
+// GetCouponSchedule lists the upcoming coupons of a fixed-income holding, the held quantity is its face value
+func (investment_serv *investmentsService) GetCouponSchedule(ctx context.Context, token, id string) ([]dto.CouponPaymentResponse, error) {
+	investment, err := investment_serv.getUserInvestment(ctx, nil, token, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if investment.CouponRate == nil || investment.CouponFrequency == nil || investment.MaturityDate == nil {
+		return nil, errors.New("investment has no coupon terms")
+	}
+
+	payments, err := portfolio.CouponSchedule(portfolio.CouponTerms{
+		Rate:      *investment.CouponRate,
+		Frequency: *investment.CouponFrequency,
+		Maturity:  *investment.MaturityDate,
+	}, investment.Quantity, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := make([]dto.CouponPaymentResponse, 0, len(payments))
+	for _, payment := range payments {
+		schedule = append(schedule, dto.CouponPaymentResponse{PaymentDate: payment.Date, GrossAmount: payment.Gross})
+	}
+
+	return schedule, nil
+}
+
+// validateCouponTerms checks the fixed-income terms that are set, unset terms are left as they are
+func validateCouponTerms(investment dto.InvestmentsRequest) error {
+	if investment.CouponRate != nil && *investment.CouponRate <= 0 {
+		return errors.New("coupon rate must be greater than zero")
+	}
+	if investment.CouponFrequency != nil && (*investment.CouponFrequency <= 0 || 12%*investment.CouponFrequency != 0) {
+		return errors.New("coupon frequency must be 1, 2, 3, 4, 6 or 12 payments per year")
+	}
+	return nil
+}
+
+// fundTrade moves the money of the trade in or out of the wallet and links the created wallet transaction to the trade
+func (investment_serv *investmentsService) fundTrade(ctx context.Context, tx repository.Transaction, investment entity.Investments, walletID string, trade *entity.InvestmentTrades) error {
+	var amount float64
+	var categoryType entity.CategoryType
+	switch trade.Type {
+	case portfolio.TRADE_BUY:
+		amount, categoryType = trade.Quantity*trade.Price+trade.Fee, entity.Expense
+	case portfolio.TRADE_SELL:
+		amount, categoryType = trade.Quantity*trade.Price-trade.Fee, entity.Income
+		if amount <= 0 {
+			return errors.New("sell proceeds must be greater than the fee")
+		}
+	case portfolio.TRADE_FEE:
+		amount, categoryType = trade.Fee, entity.Expense
+	default:
+		return errors.New("invalid trade type")
+	}
+
+	category := data.CategoryPath{Parent: data.INVESTMENT_CATEGORY, Name: data.INVESTMENT_TYPE_CATEGORIES[investment.InvestmentTypes.Name]}
+	if categoryType == entity.Income {
+		category.Name = data.INVESTMENT_SALE_CATEGORY
+	}
+
+	description := trade.Description
+	if description == "" {
+		description = fmt.Sprintf("Investment %s %s", trade.Type, investment.Instrument.Name)
+	}
+
+	transactionID, err := investment_serv.createWalletTransaction(ctx, tx, investment, walletID, categoryType, category, amount, trade.TradeDate, description)
+	if err != nil {
+		return err
+	}
+
+	trade.TransactionID = &transactionID
+	return nil
+}
+
+// createWalletTransaction books the amount on a wallet of the investment owner under the category
+func (investment_serv *investmentsService) createWalletTransaction(ctx context.Context, tx repository.Transaction, investment entity.Investments, walletID string, categoryType entity.CategoryType, categoryPath data.CategoryPath, amount float64, date time.Time, description string) (uuid.UUID, error) {
+	wallet, err := investment_serv.walletRepository.GetWalletByID(ctx, tx, walletID)
+	if err != nil || wallet.UserID != investment.UserID {
+		return uuid.Nil, errors.New("wallet not found")
+	}
+
+	category, err := investment_serv.investmentCategory(ctx, tx, categoryType, categoryPath)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if categoryType == entity.Income {
+		wallet.Balance += amount
+	} else {
+		wallet.Balance -= amount
+	}
+
+	if _, err := investment_serv.walletRepository.UpdateWallet(ctx, tx, wallet); err != nil {
+		return uuid.Nil, errors.New("failed to update wallet")
+	}
+
+	transaction, err := investment_serv.transactionRepository.CreateTransaction(ctx, tx, entity.Transactions{
+		WalletID:        wallet.ID,
+		CategoryID:      category.ID,
+		Amount:          amount,
+		TransactionDate: date,
+		Description:     description,
+	})
+	if err != nil {
+		return uuid.Nil, errors.New("failed to create wallet transaction")
+	}
+
+	if err := enqueueEvent(ctx, tx, investment_serv.outboxServ, data.EVENT_TRANSACTION_CREATED, wallet.UserID.String(), transaction.ID.String(), nil, toTransactionEventPayload(transaction)); err != nil {
+		return uuid.Nil, errors.New("failed to store transaction event")
+	}
+
+	return transaction.ID, nil
+}
+
+// revertWalletTransaction deletes a wallet transaction created by createWalletTransaction and restores the wallet balance
+func (investment_serv *investmentsService) revertWalletTransaction(ctx context.Context, tx repository.Transaction, transactionID *uuid.UUID) error {
+	if transactionID == nil {
+		return nil
+	}
+
+	transaction, err := investment_serv.transactionRepository.GetTransactionByID(ctx, tx, transactionID.String())
+	if err != nil {
+		// ? Already deleted from the transaction list, its balance change was reverted there
+		return nil
+	}
+
+	switch transaction.Category.Type {
+	case entity.Expense:
+		transaction.Wallet.Balance += transaction.Amount
+	case entity.Income:
+		transaction.Wallet.Balance -= transaction.Amount
+	default:
+		return errors.New("invalid transaction type")
+	}
+
+	if _, err := investment_serv.walletRepository.UpdateWallet(ctx, tx, transaction.Wallet); err != nil {
+		return errors.New("failed to update wallet")
+	}
+
+	if _, err := investment_serv.transactionRepository.DeleteTransaction(ctx, tx, transaction); err != nil {
+		return errors.New("failed to delete wallet transaction")
+	}
+
+	if err := enqueueEvent(ctx, tx, investment_serv.outboxServ, data.EVENT_TRANSACTION_DELETED, transaction.Wallet.UserID.String(), transaction.ID.String(), toTransactionEventPayload(transaction), nil); err != nil {
+		return errors.New("failed to store transaction event")
+	}
+
+	return nil
+}
+
+// investmentCategory returns the named category under its root category, or the root itself when there is none
+func (investment_serv *investmentsService) investmentCategory(ctx context.Context, tx repository.Transaction, categoryType entity.CategoryType, path data.CategoryPath) (entity.Categories, error) {
+	root, err := investment_serv.categoryRepository.GetCategoryByName(ctx, tx, path.Parent, string(categoryType), nil)
+	if err != nil {
+		return entity.Categories{}, errors.New("investment category not found")
+	}
+
+	if path.Name == "" {
+		return root, nil
+	}
+
+	category, err := investment_serv.categoryRepository.GetCategoryByName(ctx, tx, path.Name, string(categoryType), &root.ID)
+	if err != nil {
+		return root, nil
+	}
+
+	return category, nil
+}
+
+// investmentEventPayload adds the realized P&L of the trades and the income received to the event payload
+func (investment_serv *investmentsService) investmentEventPayload(ctx context.Context, tx repository.Transaction, investment entity.Investments) (*dto.InvestmentEventPayload, error) {
+	payload := toInvestmentEventPayload(investment)
+
+	trades, err := investment_serv.tradesRepository.GetTradesByInvestmentID(ctx, tx, investment.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	position, err := portfolio.ComputePosition(toPortfolioTrades(trades), investment.CostMethod)
+	if err != nil {
+		return nil, err
+	}
+	payload.RealizedPnL = position.RealizedPnL
+
+	incomes, err := investment_serv.incomesRepository.GetIncomesByInvestmentID(ctx, tx, investment.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	for _, income := range incomes {
+		payload.Income += income.GrossAmount - income.TaxAmount
+	}
+
+	return payload, nil
+}
+
+func toPortfolioTrades(trades []entity.InvestmentTrades) []portfolio.Trade {
+	result := make([]portfolio.Trade, 0, len(trades))
+	for _, trade := range trades {
+		result = append(result, portfolio.Trade{
+			ID:       trade.ID.String(),
+			Type:     trade.Type,
+			Date:     trade.TradeDate,
+			Quantity: trade.Quantity,
+			Price:    trade.Price,
+			Fee:      trade.Fee,
+		})
+	}
+	return result
+}
+
+// GetPortfolioValuation values every open position of the user at its latest price
+func (investment_serv *investmentsService) GetPortfolioValuation(ctx context.Context, token string) (dto.PortfolioValuationResponse, error) {
+	userData, err := helper.VerifyToken(token[7:])
+	if err != nil {
+		return dto.PortfolioValuationResponse{}, errors.New("invalid token")
+	}
+
+	now := time.Now()
+	holdings, err := investment_serv.loadHoldings(ctx, userData.ID, now)
+	if err != nil {
+		return dto.PortfolioValuationResponse{}, err
+	}
+
+	valuation := dto.PortfolioValuationResponse{
+		Date:      now,
+		Positions: make([]dto.PositionValuationResponse, 0, len(holdings)),
+	}
+	for _, holding := range holdings {
+		position, err := portfolio.ComputePosition(holding.trades, holding.investment.CostMethod)
+		if err != nil {
+			return dto.PortfolioValuationResponse{}, err
+		}
+		if position.Quantity == 0 {
+			continue
+		}
+
+		positionValuation := dto.PositionValuationResponse{
+			InvestmentID:   holding.investment.ID.String(),
+			InvestmentName: holding.investment.Instrument.Name,
+			Instrument:     holding.investment.Instrument.Code,
+			Quantity:       position.Quantity,
+			CostBasis:      position.CostBasis,
+		}
+		if price, source, ok := holding.priceAt(now); ok {
+			positionValuation.Price = price.Price
+			positionValuation.PriceDate = &price.Date
+			positionValuation.PriceSource = source
+		}
+		positionValuation.MarketValue = position.MarketValue(positionValuation.Price)
+		positionValuation.UnrealizedPnL = position.UnrealizedPnL(positionValuation.Price)
+
+		valuation.CostBasis += positionValuation.CostBasis
+		valuation.MarketValue += positionValuation.MarketValue
+		valuation.UnrealizedPnL += positionValuation.UnrealizedPnL
+		valuation.Positions = append(valuation.Positions, positionValuation)
+	}
+
+	return valuation, nil
+}
+
+// GetPortfolioHistory values the portfolio at the end of every date of the series, using the prices known at that date
+func (investment_serv *investmentsService) GetPortfolioHistory(ctx context.Context, token string, from, to time.Time, interval string) ([]dto.PortfolioValuePoint, error) {
+	userData, err := helper.VerifyToken(token[7:])
+	if err != nil {
+		return nil, errors.New("invalid token")
+	}
+
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.IsZero() {
+		from = to.AddDate(-1, 0, 0)
+	}
+
+	dates, err := portfolio.DateSeries(truncateDate(from), truncateDate(to), interval)
+	if err != nil {
+		return nil, err
+	}
+
+	holdings, err := investment_serv.loadHoldings(ctx, userData.ID, endOfDay(to))
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]dto.PortfolioValuePoint, 0, len(dates))
+	for _, date := range dates {
+		point := dto.PortfolioValuePoint{Date: date}
+		at := endOfDay(date)
+
+		for _, holding := range holdings {
+			position, err := portfolio.ComputePosition(portfolio.TradesUntil(holding.trades, at), holding.investment.CostMethod)
+			if err != nil {
+				return nil, err
+			}
+			if position.Quantity == 0 {
+				continue
+			}
+
+			point.CostBasis += position.CostBasis
+			if price, _, ok := holding.priceAt(at); ok {
+				point.MarketValue += position.MarketValue(price.Price)
+			}
+		}
+
+		points = append(points, point)
+	}
+
+	return points, nil
+}
+
+// GetInvestmentPerformance measures XIRR and TWR of a single holding over the period
+func (investment_serv *investmentsService) GetInvestmentPerformance(ctx context.Context, token, id, period string) (dto.InvestmentPerformanceResponse, error) {
+	investment, err := investment_serv.getUserInvestment(ctx, nil, token, id)
+	if err != nil {
+		return dto.InvestmentPerformanceResponse{}, err
+	}
+
+	now := time.Now()
+	holdings, err := investment_serv.loadHoldings(ctx, investment.UserID.String(), now)
+	if err != nil {
+		return dto.InvestmentPerformanceResponse{}, err
+	}
+
+	var selected []holding
+	for _, holding := range holdings {
+		if holding.investment.ID == investment.ID {
+			selected = append(selected, holding)
+		}
+	}
+
+	performance, err := measurePerformance(selected, period, now)
+	if err != nil {
+		return dto.InvestmentPerformanceResponse{}, err
+	}
+
+	return dto.InvestmentPerformanceResponse{
+		InvestmentID:        investment.ID.String(),
+		InvestmentName:      investment.Instrument.Name,
+		PerformanceResponse: performance,
+	}, nil
+}
+
+// GetPortfolioPerformance measures XIRR and TWR of the whole portfolio, every investment type and every holding over the period
+func (investment_serv *investmentsService) GetPortfolioPerformance(ctx context.Context, token, period string) (dto.PortfolioPerformanceResponse, error) {
+	userData, err := helper.VerifyToken(token[7:])
+	if err != nil {
+		return dto.PortfolioPerformanceResponse{}, errors.New("invalid token")
+	}
+
+	if period == "" {
+		period = portfolio.PERIOD_INCEPTION
+	}
+
+	now := time.Now()
+	holdings, err := investment_serv.loadHoldings(ctx, userData.ID, now)
+	if err != nil {
+		return dto.PortfolioPerformanceResponse{}, err
+	}
+
+	response := dto.PortfolioPerformanceResponse{
+		Period:      period,
+		Types:       make([]dto.InvestmentTypePerformanceResponse, 0),
+		Investments: make([]dto.InvestmentPerformanceResponse, 0, len(holdings)),
+	}
+
+	if response.Portfolio, err = measurePerformance(holdings, period, now); err != nil {
+		return dto.PortfolioPerformanceResponse{}, err
+	}
+
+	var typeIDs []uuid.UUID
+	holdingsByType := make(map[uuid.UUID][]holding)
+	for i, holding := range holdings {
+		typeID := holding.investment.InvestmentTypeID
+		if _, ok := holdingsByType[typeID]; !ok {
+			typeIDs = append(typeIDs, typeID)
+		}
+		holdingsByType[typeID] = append(holdingsByType[typeID], holding)
+
+		if len(holding.trades) == 0 {
+			continue
+		}
+		performance, err := measurePerformance(holdings[i:i+1], period, now)
+		if err != nil {
+			return dto.PortfolioPerformanceResponse{}, err
+		}
+		response.Investments = append(response.Investments, dto.InvestmentPerformanceResponse{
+			InvestmentID:        holding.investment.ID.String(),
+			InvestmentName:      holding.investment.Instrument.Name,
+			PerformanceResponse: performance,
+		})
+	}
+
+	for _, typeID := range typeIDs {
+		typeHoldings := holdingsByType[typeID]
+		performance, err := measurePerformance(typeHoldings, period, now)
+		if err != nil {
+			return dto.PortfolioPerformanceResponse{}, err
+		}
+		response.Types = append(response.Types, dto.InvestmentTypePerformanceResponse{
+			InvestmentTypeID:    typeID.String(),
+			InvestmentType:      typeHoldings[0].investment.InvestmentTypes.Name,
+			PerformanceResponse: performance,
+		})
+	}
+
+	return response, nil
+}
+
+// measurePerformance treats buys, sells, fees and payouts as external flows, so only price moves count as return
+func measurePerformance(holdings []holding, period string, end time.Time) (dto.PerformanceResponse, error) {
+	var inception time.Time
+	for _, holding := range holdings {
+		if len(holding.trades) > 0 && (inception.IsZero() || holding.trades[0].Date.Before(inception)) {
+			inception = holding.trades[0].Date
+		}
+	}
+	if inception.IsZero() {
+		return dto.PerformanceResponse{From: end, To: end}, nil
+	}
+
+	start, err := portfolio.PeriodStart(period, end, truncateDate(inception))
+	if err != nil {
+		return dto.PerformanceResponse{}, err
+	}
+	start = truncateDate(start)
+	beforeStart := start.Add(-time.Nanosecond)
+
+	performance := dto.PerformanceResponse{From: start, To: end}
+	if performance.StartValue, err = holdingsValue(holdings, beforeStart); err != nil {
+		return dto.PerformanceResponse{}, err
+	}
+	if performance.EndValue, err = holdingsValue(holdings, end); err != nil {
+		return dto.PerformanceResponse{}, err
+	}
+
+	var flows []portfolio.CashFlow
+	if performance.StartValue > 0 {
+		flows = append(flows, portfolio.CashFlow{Date: start, Amount: -performance.StartValue})
+	}
+
+	contributions := make(map[time.Time]float64)
+	inPeriod := func(date time.Time) bool {
+		return !date.Before(start) && !date.After(end)
+	}
+	for _, holding := range holdings {
+		for _, trade := range holding.trades {
+			if !inPeriod(trade.Date) {
+				continue
+			}
+			contribution := trade.Fee
+			switch trade.Type {
+			case portfolio.TRADE_BUY:
+				contribution += trade.Quantity * trade.Price
+			case portfolio.TRADE_SELL:
+				contribution -= trade.Quantity * trade.Price
+			}
+			contributions[truncateDate(trade.Date)] += contribution
+			performance.NetContributions += contribution
+			flows = append(flows, portfolio.CashFlow{Date: trade.Date, Amount: -contribution})
+		}
+		for _, income := range holding.incomes {
+			if !inPeriod(income.Date) {
+				continue
+			}
+			contributions[truncateDate(income.Date)] -= income.Net()
+			performance.Income += income.Net()
+			flows = append(flows, portfolio.CashFlow{Date: income.Date, Amount: income.Net()})
+		}
+	}
+	if performance.EndValue > 0 {
+		flows = append(flows, portfolio.CashFlow{Date: end, Amount: performance.EndValue})
+	}
+
+	if rate, err := portfolio.XIRR(flows); err == nil {
+		xirr := rate * 100
+		performance.XIRR = &xirr
+	}
+
+	// * The period is split at every day with an external flow, valued at the end of that day
+	points := []portfolio.ValuePoint{{Date: beforeStart, Value: performance.StartValue}}
+	days := make([]time.Time, 0, len(contributions))
+	for day := range contributions {
+		days = append(days, day)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	for _, day := range days {
+		at := endOfDay(day)
+		if at.After(end) {
+			at = end
+		}
+		value, err := holdingsValue(holdings, at)
+		if err != nil {
+			return dto.PerformanceResponse{}, err
+		}
+		points = append(points, portfolio.ValuePoint{Date: at, Value: value, Contribution: contributions[day]})
+	}
+	points = append(points, portfolio.ValuePoint{Date: end, Value: performance.EndValue})
+
+	twr := portfolio.TWR(points)
+	performance.TWR = twr * 100
+	if annualized, ok := portfolio.Annualize(twr, start, end); ok {
+		annualizedTWR := annualized * 100
+		performance.AnnualizedTWR = &annualizedTWR
+	}
+
+	return performance, nil
+}
+
+// holdingsValue is the market value of the holdings at the date, at the latest known prices
+func holdingsValue(holdings []holding, date time.Time) (float64, error) {
+	var value float64
+	for _, holding := range holdings {
+		position, err := portfolio.ComputePosition(portfolio.TradesUntil(holding.trades, date), holding.investment.CostMethod)
+		if err != nil {
+			return 0, err
+		}
+		if position.Quantity == 0 {
+			continue
+		}
+		if price, _, ok := holding.priceAt(date); ok {
+			value += position.MarketValue(price.Price)
+		}
+	}
+	return value, nil
+}
+
+// holding is an investment with everything needed to value it at any date
+type holding struct {
+	investment  entity.Investments
+	trades      []portfolio.Trade
+	incomes     []portfolio.Income
+	prices      portfolio.PriceSeries
+	sources     map[time.Time]string // price source by price date
+	tradePrices portfolio.PriceSeries
+}
+
+// priceAt prefers market prices and falls back to the last trade price when the instrument has no price yet
+func (h holding) priceAt(date time.Time) (portfolio.PricePoint, string, bool) {
+	if price, ok := h.prices.At(date); ok {
+		return price, h.sources[price.Date], true
+	}
+	if price, ok := h.tradePrices.At(date); ok {
+		return price, PRICE_SOURCE_TRADE, true
+	}
+	return portfolio.PricePoint{}, "", false
+}
+
+// loadHoldings loads the investments of the user with their trades and prices up to the given date
+func (investment_serv *investmentsService) loadHoldings(ctx context.Context, userID string, to time.Time) ([]holding, error) {
+	investments, err := investment_serv.investmentsRepository.GetUserPositions(ctx, nil, userID)
+	if err != nil {
+		return nil, errors.New("failed to get investments")
+	}
+
+	ids := make([]string, 0, len(investments))
+	instruments := make([]string, 0, len(investments))
+	for _, investment := range investments {
+		ids = append(ids, investment.ID.String())
+		instruments = append(instruments, investment.InstrumentID.String())
+	}
+
+	trades, err := investment_serv.tradesRepository.GetTradesByInvestmentIDs(ctx, nil, ids)
+	if err != nil {
+		return nil, errors.New("failed to get investment trades")
+	}
+	tradesByInvestment := make(map[uuid.UUID][]entity.InvestmentTrades)
+	for _, trade := range trades {
+		tradesByInvestment[trade.InvestmentID] = append(tradesByInvestment[trade.InvestmentID], trade)
+	}
+
+	incomes, err := investment_serv.incomesRepository.GetIncomesByInvestmentIDs(ctx, nil, ids)
+	if err != nil {
+		return nil, errors.New("failed to get investment incomes")
+	}
+	incomesByInvestment := make(map[uuid.UUID][]portfolio.Income)
+	for _, income := range incomes {
+		incomesByInvestment[income.InvestmentID] = append(incomesByInvestment[income.InvestmentID], portfolio.Income{
+			Date:  income.PaymentDate,
+			Gross: income.GrossAmount,
+			Tax:   income.TaxAmount,
+		})
+	}
+
+	prices, err := investment_serv.pricesRepository.GetPricesUntil(ctx, nil, instruments, to)
+	if err != nil {
+		return nil, errors.New("failed to get prices")
+	}
+	pricesByInstrument := make(map[uuid.UUID][]portfolio.PricePoint)
+	sourcesByInstrument := make(map[uuid.UUID]map[time.Time]string)
+	for _, price := range prices {
+		date := truncateDate(price.PriceDate)
+		pricesByInstrument[price.InstrumentID] = append(pricesByInstrument[price.InstrumentID], portfolio.PricePoint{Date: date, Price: price.Price})
+		if sourcesByInstrument[price.InstrumentID] == nil {
+			sourcesByInstrument[price.InstrumentID] = make(map[time.Time]string)
+		}
+		sourcesByInstrument[price.InstrumentID][date] = price.Source
+	}
+
+	holdings := make([]holding, 0, len(investments))
+	for _, investment := range investments {
+		instrument := investment.InstrumentID
+		positionTrades := toPortfolioTrades(tradesByInvestment[investment.ID])
+		holdings = append(holdings, holding{
+			investment:  investment,
+			trades:      positionTrades,
+			incomes:     incomesByInvestment[investment.ID],
+			prices:      portfolio.NewPriceSeries(pricesByInstrument[instrument]),
+			sources:     sourcesByInstrument[instrument],
+			tradePrices: portfolio.TradePrices(positionTrades),
+		})
+	}
+
+	return holdings, nil
+}
+
+// endOfDay is the last instant of the date, trades made during the day are included when valuing the date
+func endOfDay(date time.Time) time.Time {
+	return truncateDate(date).AddDate(0, 0, 1).Add(-time.Nanosecond)
+}
+
+// latestTradePrice returns the price of the most recent buy or sell, trades must be sorted by date
+func latestTradePrice(trades []entity.InvestmentTrades) (float64, *time.Time) {
+	for i := len(trades) - 1; i >= 0; i-- {
+		if trades[i].Type != portfolio.TRADE_FEE {
+			return trades[i].Price, &trades[i].TradeDate
+		}
+	}
+	return 0, nil
+}
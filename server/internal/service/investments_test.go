@@ -0,0 +1,455 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"server/config/env"
+	"server/internal/repository"
+	"server/internal/types/dto"
+	"server/internal/types/entity"
+	helper "server/internal/utils"
+	"server/internal/utils/data"
+	"server/internal/utils/portfolio"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func testToken(t *testing.T, userID uuid.UUID, role entity.Role) string {
+	env.Cfg.Server.JWTSecretKey = "test-secret"
+
+	token, err := helper.GenerateToken(userID.String(), "user", "user@mail.com", string(role))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "Bearer " + token
+}
+
+type investmentMocks struct {
+	tx           *mock.Mock
+	txManager    *mock.Mock
+	investments  *mock.Mock
+	trades       *mock.Mock
+	incomes      *mock.Mock
+	prices       *mock.Mock
+	wallets      *mock.Mock
+	transactions *mock.Mock
+	categories   *mock.Mock
+	attachments  *mock.Mock
+	outbox       *mock.Mock
+}
+
+func (mocks investmentMocks) assertExpectations(t *testing.T) {
+	for _, m := range []*mock.Mock{mocks.tx, mocks.txManager, mocks.investments, mocks.trades, mocks.incomes, mocks.prices, mocks.wallets, mocks.transactions, mocks.categories, mocks.attachments, mocks.outbox} {
+		m.AssertExpectations(t)
+	}
+}
+
+// newTestInvestmentService wires the service to repository mocks, every transaction it begins is mocks.tx
+func newTestInvestmentService() (InvestmentsService, investmentMocks) {
+	tx := repository.NewTransactionMock()
+	tx_manager_mock := repository.NewTxManagerMock()
+	investment_repo_mock := repository.NewInvestmentsRepositoryMock()
+	trade_repo_mock := repository.NewInvestmentTradesRepositoryMock()
+	income_repo_mock := repository.NewInvestmentIncomesRepositoryMock()
+	price_repo_mock := repository.NewInvestmentPricesRepositoryMock()
+	wallet_repo_mock := repository.NewWalletsRepositoryMock()
+	transaction_repo_mock := repository.NewTransactionsRepositoryMock()
+	category_repo_mock := repository.NewCategoriesRepositoryMock()
+	attachment_repo_mock := repository.NewAttachmentsRepositoryMock()
+	outbox_repo_mock := repository.NewOutboxEventsRepositoryMock()
+
+	tx_manager_mock.Mock.On("Begin", mock.Anything).Return(tx, nil).Maybe()
+	tx.Mock.On("Rollback").Return(nil).Maybe()
+	outbox_repo_mock.Mock.On("CreateOutboxEvent", mock.Anything, tx, mock.Anything).Return(nil, nil).Maybe()
+
+	investment_serv_test := NewInvestmentService(
+		tx_manager_mock,
+		investment_repo_mock,
+		trade_repo_mock,
+		income_repo_mock,
+		price_repo_mock,
+		nil,
+		wallet_repo_mock,
+		transaction_repo_mock,
+		category_repo_mock,
+		NewAttachmentsService(nil, attachment_repo_mock, nil, nil),
+		NewOutboxService(nil, outbox_repo_mock),
+	)
+
+	return investment_serv_test, investmentMocks{
+		tx:           &tx.Mock,
+		txManager:    &tx_manager_mock.Mock,
+		investments:  &investment_repo_mock.Mock,
+		trades:       &trade_repo_mock.Mock,
+		incomes:      &income_repo_mock.Mock,
+		prices:       &price_repo_mock.Mock,
+		wallets:      &wallet_repo_mock.Mock,
+		transactions: &transaction_repo_mock.Mock,
+		categories:   &category_repo_mock.Mock,
+		attachments:  &attachment_repo_mock.Mock,
+		outbox:       &outbox_repo_mock.Mock,
+	}
+}
+
+func walletWithBalance(walletID uuid.UUID, balance float64) interface{} {
+	return mock.MatchedBy(func(wallet entity.Wallets) bool {
+		return wallet.ID == walletID && wallet.Balance == balance
+	})
+}
+
+func TestInvestmentTradeFunding(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	token := testToken(t, userID, entity.User)
+	tradeDate := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+	investment := entity.Investments{
+		Base:            entity.Base{ID: uuid.New()},
+		UserID:          userID,
+		CostMethod:      portfolio.COST_METHOD_FIFO,
+		InvestmentTypes: entity.InvestmentTypes{Name: "Stocks"},
+		Instrument:      entity.Instruments{Name: "BBCA"},
+	}
+	wallet := entity.Wallets{Base: entity.Base{ID: uuid.New()}, UserID: userID, Balance: 1000}
+	expenseRoot := entity.Categories{Base: entity.Base{ID: uuid.New()}, Name: data.INVESTMENT_CATEGORY, Type: entity.Expense}
+	stocks := entity.Categories{Base: entity.Base{ID: uuid.New()}, Name: "Saham", Type: entity.Expense, ParentID: &expenseRoot.ID}
+	incomeRoot := entity.Categories{Base: entity.Base{ID: uuid.New()}, Name: data.INVESTMENT_CATEGORY, Type: entity.Income}
+	sale := entity.Categories{Base: entity.Base{ID: uuid.New()}, Name: data.INVESTMENT_SALE_CATEGORY, Type: entity.Income, ParentID: &incomeRoot.ID}
+	held := entity.InvestmentTrades{Base: entity.Base{ID: uuid.New()}, InvestmentID: investment.ID, Type: portfolio.TRADE_BUY, TradeDate: tradeDate.AddDate(0, -1, 0), Quantity: 10, Price: 100}
+
+	// expectTrade sets up the reads of a trade on the investment and the updates of a successful one
+	expectTrade := func(mocks investmentMocks, trades []entity.InvestmentTrades) {
+		mocks.investments.On("GetInvestmentByID", ctx, mock.Anything, investment.ID.String()).Return(investment, nil)
+		mocks.trades.On("GetTradesByInvestmentID", ctx, mock.Anything, investment.ID.String()).Return(trades, nil)
+		mocks.incomes.On("GetIncomesByInvestmentID", ctx, mock.Anything, investment.ID.String()).Return(nil, nil)
+		mocks.wallets.On("GetWalletByID", ctx, mock.Anything, wallet.ID.String()).Return(wallet, nil)
+	}
+
+	t.Run("Buy Debits Wallet", func(t *testing.T) {
+		investment_serv_test, mocks := newTestInvestmentService()
+		defer mocks.assertExpectations(t)
+
+		expectTrade(mocks, nil)
+		mocks.categories.On("GetCategoryByName", ctx, mock.Anything, data.INVESTMENT_CATEGORY, string(entity.Expense), (*uuid.UUID)(nil)).Return(expenseRoot, nil)
+		mocks.categories.On("GetCategoryByName", ctx, mock.Anything, "Saham", string(entity.Expense), &expenseRoot.ID).Return(stocks, nil)
+		mocks.wallets.On("UpdateWallet", ctx, mock.Anything, walletWithBalance(wallet.ID, -5)).Return(entity.Wallets{}, nil).Once()
+		mocks.transactions.On("CreateTransaction", ctx, mock.Anything, mock.MatchedBy(func(transaction entity.Transactions) bool {
+			return transaction.WalletID == wallet.ID && transaction.CategoryID == stocks.ID && transaction.Amount == 1005
+		})).Return(nil, nil).Once()
+		mocks.trades.On("CreateTrade", ctx, mock.Anything, mock.MatchedBy(func(trade entity.InvestmentTrades) bool {
+			return trade.TransactionID != nil
+		})).Return(nil, nil).Once()
+		mocks.investments.On("UpdateInvestment", ctx, mock.Anything, mock.Anything).Return(nil, nil)
+		mocks.tx.On("Commit").Return(nil).Once()
+
+		_, err := investment_serv_test.CreateInvestmentTrade(ctx, token, investment.ID.String(), dto.InvestmentTradesRequest{
+			Type:      portfolio.TRADE_BUY,
+			TradeDate: tradeDate,
+			Quantity:  10,
+			Price:     100,
+			Fee:       5,
+			WalletID:  wallet.ID.String(),
+		})
+		assert.Nil(t, err)
+	})
+
+	t.Run("Sell Credits Wallet", func(t *testing.T) {
+		investment_serv_test, mocks := newTestInvestmentService()
+		defer mocks.assertExpectations(t)
+
+		expectTrade(mocks, []entity.InvestmentTrades{held})
+		mocks.categories.On("GetCategoryByName", ctx, mock.Anything, data.INVESTMENT_CATEGORY, string(entity.Income), (*uuid.UUID)(nil)).Return(incomeRoot, nil)
+		mocks.categories.On("GetCategoryByName", ctx, mock.Anything, data.INVESTMENT_SALE_CATEGORY, string(entity.Income), &incomeRoot.ID).Return(sale, nil)
+		mocks.wallets.On("UpdateWallet", ctx, mock.Anything, walletWithBalance(wallet.ID, 1590)).Return(entity.Wallets{}, nil).Once()
+		mocks.transactions.On("CreateTransaction", ctx, mock.Anything, mock.MatchedBy(func(transaction entity.Transactions) bool {
+			return transaction.CategoryID == sale.ID && transaction.Amount == 590
+		})).Return(nil, nil).Once()
+		mocks.trades.On("CreateTrade", ctx, mock.Anything, mock.Anything).Return(nil, nil).Once()
+		mocks.investments.On("UpdateInvestment", ctx, mock.Anything, mock.Anything).Return(nil, nil)
+		mocks.tx.On("Commit").Return(nil).Once()
+
+		_, err := investment_serv_test.CreateInvestmentTrade(ctx, token, investment.ID.String(), dto.InvestmentTradesRequest{
+			Type:      portfolio.TRADE_SELL,
+			TradeDate: tradeDate,
+			Quantity:  4,
+			Price:     150,
+			Fee:       10,
+			WalletID:  wallet.ID.String(),
+		})
+		assert.Nil(t, err)
+	})
+
+	t.Run("Rejects Wallet Of Another User", func(t *testing.T) {
+		investment_serv_test, mocks := newTestInvestmentService()
+		defer mocks.assertExpectations(t)
+
+		other := entity.Wallets{Base: entity.Base{ID: uuid.New()}, UserID: uuid.New(), Balance: 1000}
+		mocks.investments.On("GetInvestmentByID", ctx, mock.Anything, investment.ID.String()).Return(investment, nil)
+		mocks.trades.On("GetTradesByInvestmentID", ctx, mock.Anything, investment.ID.String()).Return(nil, nil)
+		mocks.incomes.On("GetIncomesByInvestmentID", ctx, mock.Anything, investment.ID.String()).Return(nil, nil)
+		mocks.wallets.On("GetWalletByID", ctx, mock.Anything, other.ID.String()).Return(other, nil)
+
+		_, err := investment_serv_test.CreateInvestmentTrade(ctx, token, investment.ID.String(), dto.InvestmentTradesRequest{
+			Type:      portfolio.TRADE_BUY,
+			TradeDate: tradeDate,
+			Quantity:  1,
+			Price:     100,
+			WalletID:  other.ID.String(),
+		})
+		assert.EqualError(t, err, "wallet not found")
+		mocks.wallets.AssertNotCalled(t, "UpdateWallet", mock.Anything, mock.Anything, mock.Anything)
+		mocks.trades.AssertNotCalled(t, "CreateTrade", mock.Anything, mock.Anything, mock.Anything)
+		mocks.tx.AssertNotCalled(t, "Commit")
+		mocks.tx.AssertCalled(t, "Rollback")
+	})
+
+	t.Run("Delete Trade Reverts Wallet", func(t *testing.T) {
+		investment_serv_test, mocks := newTestInvestmentService()
+		defer mocks.assertExpectations(t)
+
+		transactionID := uuid.New()
+		trade := held
+		trade.TransactionID = &transactionID
+		mocks.investments.On("GetInvestmentByID", ctx, mock.Anything, investment.ID.String()).Return(investment, nil)
+		mocks.trades.On("GetTradesByInvestmentID", ctx, mock.Anything, investment.ID.String()).Return(nil, nil)
+		mocks.incomes.On("GetIncomesByInvestmentID", ctx, mock.Anything, investment.ID.String()).Return(nil, nil)
+		mocks.trades.On("GetTradeByID", ctx, mock.Anything, trade.ID.String()).Return(trade, nil)
+		mocks.trades.On("DeleteTrade", ctx, mock.Anything, trade).Return(nil, nil).Once()
+		mocks.transactions.On("GetTransactionByID", ctx, mock.Anything, transactionID.String()).Return(entity.Transactions{
+			Base:     entity.Base{ID: transactionID},
+			WalletID: wallet.ID,
+			Amount:   1000,
+			Category: stocks,
+			Wallet:   entity.Wallets{Base: entity.Base{ID: wallet.ID}, UserID: userID, Balance: 0},
+		}, nil)
+		mocks.wallets.On("UpdateWallet", ctx, mock.Anything, walletWithBalance(wallet.ID, 1000)).Return(entity.Wallets{}, nil).Once()
+		mocks.transactions.On("DeleteTransaction", ctx, mock.Anything, mock.Anything).Return(nil, nil).Once()
+		mocks.investments.On("UpdateInvestment", ctx, mock.Anything, mock.Anything).Return(nil, nil)
+		mocks.tx.On("Commit").Return(nil).Once()
+
+		_, err := investment_serv_test.DeleteInvestmentTrade(ctx, token, investment.ID.String(), trade.ID.String())
+		assert.Nil(t, err)
+	})
+}
+
+func TestDeleteInvestmentRevertsWallets(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	investment := entity.Investments{Base: entity.Base{ID: uuid.New()}, UserID: userID, CostMethod: portfolio.COST_METHOD_FIFO}
+	buyWallet := entity.Wallets{Base: entity.Base{ID: uuid.New()}, UserID: userID, Balance: 0}
+	incomeWallet := entity.Wallets{Base: entity.Base{ID: uuid.New()}, UserID: userID, Balance: 590}
+
+	buyTransactionID, incomeTransactionID, removedTransactionID := uuid.New(), uuid.New(), uuid.New()
+	trades := []entity.InvestmentTrades{
+		{Base: entity.Base{ID: uuid.New()}, Type: portfolio.TRADE_BUY, Quantity: 10, Price: 100, TransactionID: &buyTransactionID},
+		// * Without a wallet nothing is reverted
+		{Base: entity.Base{ID: uuid.New()}, Type: portfolio.TRADE_FEE, Fee: 5},
+		// * Deleted from the transaction list, which already reverted its balance
+		{Base: entity.Base{ID: uuid.New()}, Type: portfolio.TRADE_FEE, Fee: 5, TransactionID: &removedTransactionID},
+	}
+	incomes := []entity.InvestmentIncomes{
+		{Base: entity.Base{ID: uuid.New()}, GrossAmount: 100, TaxAmount: 10, TransactionID: &incomeTransactionID},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		investment_serv_test, mocks := newTestInvestmentService()
+		defer mocks.assertExpectations(t)
+
+		mocks.investments.On("GetInvestmentByID", ctx, mock.Anything, investment.ID.String()).Return(investment, nil)
+		mocks.trades.On("GetTradesByInvestmentID", ctx, mock.Anything, investment.ID.String()).Return(trades, nil)
+		mocks.incomes.On("GetIncomesByInvestmentID", ctx, mock.Anything, investment.ID.String()).Return(incomes, nil)
+		mocks.transactions.On("GetTransactionByID", ctx, mock.Anything, buyTransactionID.String()).Return(entity.Transactions{
+			Base:     entity.Base{ID: buyTransactionID},
+			Amount:   1000,
+			Category: entity.Categories{Type: entity.Expense},
+			Wallet:   buyWallet,
+		}, nil)
+		mocks.transactions.On("GetTransactionByID", ctx, mock.Anything, incomeTransactionID.String()).Return(entity.Transactions{
+			Base:     entity.Base{ID: incomeTransactionID},
+			Amount:   90,
+			Category: entity.Categories{Type: entity.Income},
+			Wallet:   incomeWallet,
+		}, nil)
+		mocks.transactions.On("GetTransactionByID", ctx, mock.Anything, removedTransactionID.String()).Return(nil, errors.New("record not found"))
+		mocks.wallets.On("UpdateWallet", ctx, mock.Anything, walletWithBalance(buyWallet.ID, 1000)).Return(entity.Wallets{}, nil).Once()
+		mocks.wallets.On("UpdateWallet", ctx, mock.Anything, walletWithBalance(incomeWallet.ID, 500)).Return(entity.Wallets{}, nil).Once()
+		mocks.transactions.On("DeleteTransaction", ctx, mock.Anything, mock.Anything).Return(nil, nil).Twice()
+		mocks.investments.On("DeleteInvestment", ctx, mock.Anything, investment).Return(nil, nil).Once()
+		mocks.attachments.On("GetAttachmentsByOwner", ctx, mock.Anything, data.ATTACHMENT_OWNER_INVESTMENT, investment.ID.String()).Return(nil, nil)
+		mocks.tx.On("Commit").Return(nil).Once()
+
+		_, err := investment_serv_test.DeleteInvestment(ctx, investment.ID.String())
+		assert.Nil(t, err)
+	})
+
+	t.Run("Failed Wallet Update", func(t *testing.T) {
+		investment_serv_test, mocks := newTestInvestmentService()
+
+		mocks.investments.On("GetInvestmentByID", ctx, mock.Anything, investment.ID.String()).Return(investment, nil)
+		mocks.trades.On("GetTradesByInvestmentID", ctx, mock.Anything, investment.ID.String()).Return(trades, nil)
+		mocks.incomes.On("GetIncomesByInvestmentID", ctx, mock.Anything, investment.ID.String()).Return(incomes, nil)
+		mocks.transactions.On("GetTransactionByID", ctx, mock.Anything, buyTransactionID.String()).Return(entity.Transactions{
+			Base:     entity.Base{ID: buyTransactionID},
+			Amount:   1000,
+			Category: entity.Categories{Type: entity.Expense},
+			Wallet:   buyWallet,
+		}, nil)
+		mocks.wallets.On("UpdateWallet", ctx, mock.Anything, mock.Anything).Return(nil, nil)
+
+		_, err := investment_serv_test.DeleteInvestment(ctx, investment.ID.String())
+		assert.EqualError(t, err, "failed to update wallet")
+		mocks.investments.AssertNotCalled(t, "DeleteInvestment", mock.Anything, mock.Anything, mock.Anything)
+		mocks.tx.AssertNotCalled(t, "Commit")
+		mocks.tx.AssertCalled(t, "Rollback")
+	})
+}
+
+func TestCreateInvestmentIncomeTax(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	token := testToken(t, userID, entity.User)
+	paymentDate := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+	investment := entity.Investments{Base: entity.Base{ID: uuid.New()}, UserID: userID, CostMethod: portfolio.COST_METHOD_FIFO}
+	wallet := entity.Wallets{Base: entity.Base{ID: uuid.New()}, UserID: userID, Balance: 0}
+	path := data.INVESTMENT_INCOME_CATEGORIES[portfolio.INCOME_DIVIDEND]
+	root := entity.Categories{Base: entity.Base{ID: uuid.New()}, Name: path.Parent, Type: entity.Income}
+	dividend := entity.Categories{Base: entity.Base{ID: uuid.New()}, Name: path.Name, Type: entity.Income, ParentID: &root.ID}
+
+	tests := []struct {
+		name      string
+		gross     float64
+		taxAmount float64
+		taxRate   float64
+		tax       float64
+		err       string
+	}{
+		{name: "Tax From Rate", gross: 1_000_000, taxRate: 10, tax: 100_000},
+		{name: "Rate Rounded To A Whole Unit", gross: 1234.52, taxRate: 15, tax: 185.18},
+		{name: "Tax Amount Over Rate", gross: 1_000_000, taxAmount: 50_000, taxRate: 10, tax: 50_000},
+		{name: "No Tax", gross: 1_000_000, tax: 0},
+		{name: "Tax Of The Whole Gross", gross: 1_000_000, taxRate: 100, err: "tax amount must be between zero and the gross amount"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			investment_serv_test, mocks := newTestInvestmentService()
+			defer mocks.assertExpectations(t)
+
+			if test.err == "" {
+				net := test.gross - test.tax
+				mocks.investments.On("GetInvestmentByID", ctx, mock.Anything, investment.ID.String()).Return(investment, nil)
+				mocks.trades.On("GetTradesByInvestmentID", ctx, mock.Anything, investment.ID.String()).Return(nil, nil)
+				mocks.incomes.On("GetIncomesByInvestmentID", ctx, mock.Anything, investment.ID.String()).Return(nil, nil)
+				mocks.wallets.On("GetWalletByID", ctx, mock.Anything, wallet.ID.String()).Return(wallet, nil)
+				mocks.categories.On("GetCategoryByName", ctx, mock.Anything, path.Parent, string(entity.Income), (*uuid.UUID)(nil)).Return(root, nil)
+				mocks.categories.On("GetCategoryByName", ctx, mock.Anything, path.Name, string(entity.Income), &root.ID).Return(dividend, nil)
+				mocks.wallets.On("UpdateWallet", ctx, mock.Anything, walletWithBalance(wallet.ID, net)).Return(entity.Wallets{}, nil).Once()
+				mocks.transactions.On("CreateTransaction", ctx, mock.Anything, mock.MatchedBy(func(transaction entity.Transactions) bool {
+					return transaction.CategoryID == dividend.ID && transaction.Amount == net
+				})).Return(nil, nil).Once()
+				mocks.incomes.On("CreateIncome", ctx, mock.Anything, mock.MatchedBy(func(income entity.InvestmentIncomes) bool {
+					return income.GrossAmount == test.gross && income.TaxAmount == test.tax
+				})).Return(nil, nil).Once()
+				mocks.tx.On("Commit").Return(nil).Once()
+			}
+
+			_, err := investment_serv_test.CreateInvestmentIncome(ctx, token, investment.ID.String(), dto.InvestmentIncomesRequest{
+				Type:        portfolio.INCOME_DIVIDEND,
+				PaymentDate: paymentDate,
+				GrossAmount: test.gross,
+				TaxAmount:   test.taxAmount,
+				TaxRate:     test.taxRate,
+				WalletID:    wallet.ID.String(),
+			})
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				mocks.txManager.AssertNotCalled(t, "Begin", mock.Anything)
+				return
+			}
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestGetInvestmentYield(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	token := testToken(t, userID, entity.User)
+	now := time.Now()
+
+	investment := entity.Investments{Base: entity.Base{ID: uuid.New()}, UserID: userID, InstrumentID: uuid.New(), CostMethod: portfolio.COST_METHOD_FIFO}
+	trades := []entity.InvestmentTrades{
+		{Base: entity.Base{ID: uuid.New()}, Type: portfolio.TRADE_BUY, TradeDate: now.AddDate(-2, 0, 0), Quantity: 10, Price: 100},
+		{Base: entity.Base{ID: uuid.New()}, Type: portfolio.TRADE_BUY, TradeDate: now.AddDate(-1, 0, 0), Quantity: 10, Price: 200},
+		// * FIFO sells the first lot, the cost basis left is the 2000 of the second one
+		{Base: entity.Base{ID: uuid.New()}, Type: portfolio.TRADE_SELL, TradeDate: now.AddDate(0, -6, 0), Quantity: 10, Price: 220},
+	}
+
+	tests := []struct {
+		name         string
+		incomes      []entity.InvestmentIncomes
+		price        *entity.InvestmentPrices
+		netIncome    float64
+		count        int
+		yieldOnCost  float64
+		currentYield float64
+	}{
+		{
+			name: "Trailing Twelve Months",
+			incomes: []entity.InvestmentIncomes{
+				{PaymentDate: now.AddDate(0, -13, 0), GrossAmount: 1000, TaxAmount: 100},
+				{PaymentDate: now.AddDate(0, -9, 0), GrossAmount: 100, TaxAmount: 10},
+				{PaymentDate: now.AddDate(0, -3, 0), GrossAmount: 125, TaxAmount: 15},
+			},
+			price:        &entity.InvestmentPrices{Price: 250, PriceDate: now},
+			netIncome:    200,
+			count:        2,
+			yieldOnCost:  10,
+			currentYield: 8,
+		},
+		{
+			name:         "No Income",
+			price:        &entity.InvestmentPrices{Price: 250, PriceDate: now},
+			netIncome:    0,
+			count:        0,
+			yieldOnCost:  0,
+			currentYield: 0,
+		},
+		{
+			name: "Falls Back To The Last Trade Price",
+			incomes: []entity.InvestmentIncomes{
+				{PaymentDate: now.AddDate(0, -1, 0), GrossAmount: 220, TaxAmount: 0},
+			},
+			netIncome:    220,
+			count:        1,
+			yieldOnCost:  11,
+			currentYield: 10,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			investment_serv_test, mocks := newTestInvestmentService()
+			defer mocks.assertExpectations(t)
+
+			mocks.investments.On("GetInvestmentByID", ctx, nil, investment.ID.String()).Return(investment, nil)
+			mocks.trades.On("GetTradesByInvestmentID", ctx, nil, investment.ID.String()).Return(trades, nil)
+			mocks.prices.On("GetLatestPrice", ctx, nil, investment.InstrumentID.String(), mock.Anything).Return(test.price, nil)
+			mocks.incomes.On("GetIncomesByInvestmentID", ctx, nil, investment.ID.String()).Return(test.incomes, nil)
+
+			yield, err := investment_serv_test.GetInvestmentYield(ctx, token, investment.ID.String())
+			assert.Nil(t, err)
+			assert.Equal(t, 2000.0, yield.CostBasis)
+			assert.Equal(t, test.count, yield.PaymentCount)
+			assert.InDelta(t, test.netIncome, yield.NetIncome, 1e-9)
+			assert.InDelta(t, test.yieldOnCost, yield.YieldOnCost, 1e-9)
+			assert.InDelta(t, test.currentYield, yield.CurrentYield, 1e-9)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"server/config/log"
+	"server/config/queue"
+	"server/internal/repository"
+	"server/internal/types/entity"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	OUTBOX_RELAY_INTERVAL   = 2 * time.Second
+	OUTBOX_RELAY_BATCH_SIZE = 100
+	// OUTBOX_MAX_BACKOFF caps the wait before an event that failed to publish is tried again
+	OUTBOX_MAX_BACKOFF = 10 * time.Minute
+	// OUTBOX_CLAIM_LEASE is how long a claimed batch is kept from other relays, it has to cover publishing a whole batch
+	OUTBOX_CLAIM_LEASE = time.Minute
+)
+
+// OutboxService stores events in the transaction of the change they describe and relays them to RabbitMQ,
+// every stored event is published at least once
+type OutboxService interface {
+	Enqueue(ctx context.Context, tx repository.Transaction, exchange, routingKey string, payload any) error
+	RelayPending(ctx context.Context, batchSize int) (int, error)
+	RunRelay(ctx context.Context, interval time.Duration)
+}
+
+type outboxService struct {
+	txManager  repository.TxManager
+	outboxRepo repository.OutboxEventsRepository
+}
+
+func NewOutboxService(txManager repository.TxManager, outboxRepo repository.OutboxEventsRepository) OutboxService {
+	return &outboxService{
+		txManager:  txManager,
+		outboxRepo: outboxRepo,
+	}
+}
+
+// Enqueue stores the payload as JSON, it is only published once the transaction commits
+func (outbox_serv *outboxService) Enqueue(ctx context.Context, tx repository.Transaction, exchange, routingKey string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox event: %w", err)
+	}
+
+	_, err = outbox_serv.outboxRepo.CreateOutboxEvent(ctx, tx, entity.OutboxEvents{
+		Exchange:   exchange,
+		RoutingKey: routingKey,
+		Payload:    body,
+	})
+	return err
+}
+
+// RelayPending publishes one batch of due events and returns how many were sent. The batch is claimed in its
+// own statement and published without holding locks, events that fail are tried again later with a backoff,
+// the rest of the batch is still published.
+func (outbox_serv *outboxService) RelayPending(ctx context.Context, batchSize int) (int, error) {
+	events, err := outbox_serv.outboxRepo.ClaimPendingOutboxEvents(ctx, nil, batchSize, time.Now().Add(OUTBOX_CLAIM_LEASE))
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, event := range events {
+		publishErr := queue.Publish(ctx, event.Exchange, event.RoutingKey, amqp091.Publishing{
+			ContentType: "application/json",
+			MessageId:   event.ID.String(),
+			Timestamp:   event.CreatedAt,
+			Body:        event.Payload,
+		})
+		if publishErr != nil {
+			log.Error(fmt.Sprintf("failed to publish outbox event %s: %v", event.ID, publishErr))
+			if err := outbox_serv.outboxRepo.MarkOutboxEventFailed(ctx, nil, event.ID.String(), publishErr.Error(), time.Now().Add(outboxBackoff(event.Attempts+1))); err != nil {
+				return sent, err
+			}
+			continue
+		}
+
+		// A crash before the event is marked publishes it again after the lease, consumers have to tolerate duplicates
+		if err := outbox_serv.outboxRepo.MarkOutboxEventSent(ctx, nil, event.ID.String(), time.Now()); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+// RunRelay relays pending events until the context is cancelled, full batches are followed by the next one right away
+func (outbox_serv *outboxService) RunRelay(ctx context.Context, interval time.Duration) {
+	for {
+		sent, err := outbox_serv.RelayPending(ctx, OUTBOX_RELAY_BATCH_SIZE)
+		if err != nil && ctx.Err() == nil {
+			log.Error("failed to relay outbox events: " + err.Error())
+		}
+
+		if sent == OUTBOX_RELAY_BATCH_SIZE {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// outboxBackoff doubles the wait with every failed attempt, starting at one second
+func outboxBackoff(attempts int) time.Duration {
+	if attempts > 10 {
+		return OUTBOX_MAX_BACKOFF
+	}
+	return min(time.Second<<(attempts-1), OUTBOX_MAX_BACKOFF)
+}
@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"server/config/log"
+	"server/internal/repository"
+	"server/internal/types/dto"
+	"server/internal/types/entity"
+	helper "server/internal/utils"
+	"server/internal/utils/data"
+)
+
+const (
+	// RECURRING_BATCH_SIZE is how many recurring transactions one run books at most, the next run continues
+	RECURRING_BATCH_SIZE = 500
+	// RECURRING_MAX_BOOKINGS caps the bookings of one recurring transaction per run, e.g. a daily one started long ago
+	RECURRING_MAX_BOOKINGS = 100
+)
+
+type RecurringTransactionsService interface {
+	GetRecurringTransactions(ctx context.Context, token string) ([]dto.RecurringTransactionsResponse, error)
+	CreateRecurringTransaction(ctx context.Context, token string, request dto.RecurringTransactionsRequest) (dto.RecurringTransactionsResponse, error)
+	DeleteRecurringTransaction(ctx context.Context, token string, id string) (dto.RecurringTransactionsResponse, error)
+	BookDueTransactions(ctx context.Context) error
+}
+
+type recurringTransactionsService struct {
+	txManager       repository.TxManager
+	recurringRepo   repository.RecurringTransactionsRepository
+	transactionRepo repository.TransactionsRepository
+	walletRepo      repository.WalletsRepository
+	categoryRepo    repository.CategoriesRepository
+	outboxServ      OutboxService
+}
+
+func NewRecurringTransactionsService(txManager repository.TxManager, recurringRepo repository.RecurringTransactionsRepository, transactionRepo repository.TransactionsRepository, walletRepo repository.WalletsRepository, categoryRepo repository.CategoriesRepository, outboxServ OutboxService) RecurringTransactionsService {
+	return &recurringTransactionsService{
+		txManager:       txManager,
+		recurringRepo:   recurringRepo,
+		transactionRepo: transactionRepo,
+		walletRepo:      walletRepo,
+		categoryRepo:    categoryRepo,
+		outboxServ:      outboxServ,
+	}
+}
+
+func (recurring_serv *recurringTransactionsService) GetRecurringTransactions(ctx context.Context, token string) ([]dto.RecurringTransactionsResponse, error) {
+	userData, err := helper.VerifyToken(token[7:])
+	if err != nil {
+		return nil, errors.New("invalid token")
+	}
+
+	recurring, err := recurring_serv.recurringRepo.GetRecurringTransactionsByUserID(ctx, nil, userData.ID)
+	if err != nil {
+		return nil, errors.New("failed to get recurring transactions")
+	}
+
+	recurringResponse := make([]dto.RecurringTransactionsResponse, 0, len(recurring))
+	for _, item := range recurring {
+		recurringResponse = append(recurringResponse, toRecurringTransactionResponse(item))
+	}
+
+	return recurringResponse, nil
+}
+
+// CreateRecurringTransaction books the first transaction on the start date, a start date in the past is booked
+// by the next run of the recurring_transactions job
+func (recurring_serv *recurringTransactionsService) CreateRecurringTransaction(ctx context.Context, token string, request dto.RecurringTransactionsRequest) (dto.RecurringTransactionsResponse, error) {
+	userData, err := helper.VerifyToken(token[7:])
+	if err != nil {
+		return dto.RecurringTransactionsResponse{}, errors.New("invalid token")
+	}
+
+	if request.Amount <= 0 {
+		return dto.RecurringTransactionsResponse{}, errors.New("amount must be greater than 0")
+	}
+	if !slices.Contains(data.RECURRING_FREQUENCIES, request.Frequency) {
+		return dto.RecurringTransactionsResponse{}, fmt.Errorf("invalid frequency %s", request.Frequency)
+	}
+	if request.StartDate.IsZero() {
+		return dto.RecurringTransactionsResponse{}, errors.New("start date is required")
+	}
+	if request.EndDate != nil && request.EndDate.Before(request.StartDate) {
+		return dto.RecurringTransactionsResponse{}, errors.New("end date must not be before the start date")
+	}
+
+	wallet, err := recurring_serv.walletRepo.GetWalletByID(ctx, nil, request.WalletID)
+	if err != nil || wallet.UserID.String() != userData.ID {
+		return dto.RecurringTransactionsResponse{}, errors.New("wallet not found")
+	}
+
+	category, err := recurring_serv.categoryRepo.GetCategoryByID(ctx, nil, request.CategoryID)
+	if err != nil {
+		return dto.RecurringTransactionsResponse{}, errors.New("category not found")
+	}
+	if category.Type != "expense" && category.Type != "income" {
+		return dto.RecurringTransactionsResponse{}, errors.New("invalid transaction type")
+	}
+
+	recurring, err := recurring_serv.recurringRepo.CreateRecurringTransaction(ctx, nil, entity.RecurringTransactions{
+		UserID:      wallet.UserID,
+		WalletID:    wallet.ID,
+		CategoryID:  category.ID,
+		Amount:      request.Amount,
+		Description: request.Description,
+		Frequency:   request.Frequency,
+		StartDate:   request.StartDate,
+		EndDate:     request.EndDate,
+		NextDate:    request.StartDate,
+	})
+	if err != nil {
+		return dto.RecurringTransactionsResponse{}, errors.New("failed to create recurring transaction")
+	}
+
+	return toRecurringTransactionResponse(recurring), nil
+}
+
+// DeleteRecurringTransaction stops the bookings, the transactions booked so far are kept
+func (recurring_serv *recurringTransactionsService) DeleteRecurringTransaction(ctx context.Context, token string, id string) (dto.RecurringTransactionsResponse, error) {
+	userData, err := helper.VerifyToken(token[7:])
+	if err != nil {
+		return dto.RecurringTransactionsResponse{}, errors.New("invalid token")
+	}
+
+	recurring, err := recurring_serv.recurringRepo.GetRecurringTransactionByID(ctx, nil, id)
+	if err != nil || recurring.UserID.String() != userData.ID {
+		return dto.RecurringTransactionsResponse{}, errors.New("recurring transaction not found")
+	}
+
+	if _, err := recurring_serv.recurringRepo.DeleteRecurringTransaction(ctx, nil, recurring); err != nil {
+		return dto.RecurringTransactionsResponse{}, errors.New("failed to delete recurring transaction")
+	}
+
+	return toRecurringTransactionResponse(recurring), nil
+}
+
+// BookDueTransactions books every due date of the recurring transactions as a transaction, it runs as the
+// recurring_transactions scheduled job. Each recurring transaction is booked in its own transaction, so one
+// that fails, e.g. because its wallet was deleted, does not hold back the others.
+func (recurring_serv *recurringTransactionsService) BookDueTransactions(ctx context.Context) error {
+	now := time.Now()
+	ids, err := recurring_serv.recurringRepo.GetDueRecurringTransactionIDs(ctx, nil, now, RECURRING_BATCH_SIZE)
+	if err != nil {
+		return fmt.Errorf("failed to get due recurring transactions: %w", err)
+	}
+
+	var errs []error
+	booked := 0
+	for _, id := range ids {
+		count, err := recurring_serv.bookRecurringTransaction(ctx, id, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to book recurring transaction %s: %w", id, err))
+			continue
+		}
+		booked += count
+	}
+
+	log.Info(fmt.Sprintf("booked %d transactions of %d recurring transactions", booked, len(ids)))
+	return errors.Join(errs...)
+}
+
+// bookRecurringTransaction books the due dates of one recurring transaction up to now and moves its next date
+// in the same transaction, so a date is never booked twice
+func (recurring_serv *recurringTransactionsService) bookRecurringTransaction(ctx context.Context, id string, now time.Time) (booked int, err error) {
+	tx, err := recurring_serv.txManager.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		// Rollback otomatis jika transaksi belum di-commit
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	recurring, err := recurring_serv.recurringRepo.LockDueRecurringTransaction(ctx, tx, id, now)
+	if err != nil {
+		return 0, err
+	}
+	if recurring == nil {
+		// Booked by another run or deleted since it was listed
+		err = tx.Commit()
+		return 0, err
+	}
+
+	wallet, err := recurring_serv.walletRepo.GetWalletByID(ctx, tx, recurring.WalletID.String())
+	if err != nil {
+		return 0, fmt.Errorf("wallet not found: %w", err)
+	}
+
+	category, err := recurring_serv.categoryRepo.GetCategoryByID(ctx, tx, recurring.CategoryID.String())
+	if err != nil {
+		return 0, fmt.Errorf("category not found: %w", err)
+	}
+
+	for booked < RECURRING_MAX_BOOKINGS && !recurring.NextDate.After(now) &&
+		(recurring.EndDate == nil || !recurring.NextDate.After(*recurring.EndDate)) {
+		switch category.Type {
+		case "expense":
+			wallet.Balance -= recurring.Amount
+		case "income":
+			wallet.Balance += recurring.Amount
+		default:
+			err = errors.New("invalid transaction type")
+			return 0, err
+		}
+
+		var transaction entity.Transactions
+		transaction, err = recurring_serv.transactionRepo.CreateTransaction(ctx, tx, entity.Transactions{
+			WalletID:        recurring.WalletID,
+			CategoryID:      recurring.CategoryID,
+			Amount:          recurring.Amount,
+			TransactionDate: recurring.NextDate,
+			Description:     recurring.Description,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to create transaction: %w", err)
+		}
+
+		if err = enqueueEvent(ctx, tx, recurring_serv.outboxServ, data.EVENT_TRANSACTION_CREATED, recurring.UserID.String(), transaction.ID.String(), nil, toTransactionEventPayload(transaction)); err != nil {
+			return 0, fmt.Errorf("failed to store transaction event: %w", err)
+		}
+
+		recurring.Occurrences++
+		recurring.NextDate = recurringDate(recurring.StartDate, recurring.Frequency, recurring.Occurrences)
+		booked++
+	}
+
+	if _, err = recurring_serv.walletRepo.UpdateWallet(ctx, tx, wallet); err != nil {
+		return 0, fmt.Errorf("failed to update wallet: %w", err)
+	}
+
+	if _, err = recurring_serv.recurringRepo.UpdateRecurringTransaction(ctx, tx, *recurring); err != nil {
+		return 0, fmt.Errorf("failed to update recurring transaction: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return booked, nil
+}
+
+// recurringDate is the date of the nth booking after the start date. Months are counted from the start date
+// rather than from the previous booking, so a recurring transaction on the 31st falls on the last day of
+// shorter months and returns to the 31st afterwards.
+func recurringDate(start time.Time, frequency string, n int) time.Time {
+	switch frequency {
+	case data.RECURRING_FREQUENCY_DAILY:
+		return start.AddDate(0, 0, n)
+	case data.RECURRING_FREQUENCY_WEEKLY:
+		return start.AddDate(0, 0, 7*n)
+	case data.RECURRING_FREQUENCY_YEARLY:
+		return addMonthsClamped(start, 12*n)
+	default:
+		return addMonthsClamped(start, n)
+	}
+}
+
+// addMonthsClamped adds months to date, keeping the day within the resulting month
+func addMonthsClamped(date time.Time, months int) time.Time {
+	first := time.Date(date.Year(), date.Month()+time.Month(months), 1, date.Hour(), date.Minute(), date.Second(), date.Nanosecond(), date.Location())
+	lastDay := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(date.Day(), lastDay)-1)
+}
+
+func toRecurringTransactionResponse(recurring entity.RecurringTransactions) dto.RecurringTransactionsResponse {
+	return dto.RecurringTransactionsResponse{
+		ID:          recurring.ID.String(),
+		WalletID:    recurring.WalletID.String(),
+		CategoryID:  recurring.CategoryID.String(),
+		Amount:      recurring.Amount,
+		Description: recurring.Description,
+		Frequency:   recurring.Frequency,
+		StartDate:   recurring.StartDate,
+		EndDate:     recurring.EndDate,
+		Occurrences: recurring.Occurrences,
+		NextDate:    recurring.NextDate,
+	}
+}
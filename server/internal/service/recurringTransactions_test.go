@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"server/config/log"
+	"server/internal/repository"
+	"server/internal/types/entity"
+	"server/internal/utils/data"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRecurringDate(t *testing.T) {
+	start := time.Date(2026, time.January, 31, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		frequency string
+		n         int
+		expected  time.Time
+	}{
+		{"Daily", data.RECURRING_FREQUENCY_DAILY, 1, time.Date(2026, time.February, 1, 9, 0, 0, 0, time.UTC)},
+		{"Weekly", data.RECURRING_FREQUENCY_WEEKLY, 2, time.Date(2026, time.February, 14, 9, 0, 0, 0, time.UTC)},
+		{"Monthly Clamped To Last Day", data.RECURRING_FREQUENCY_MONTHLY, 1, time.Date(2026, time.February, 28, 9, 0, 0, 0, time.UTC)},
+		{"Monthly Back To The 31st", data.RECURRING_FREQUENCY_MONTHLY, 2, time.Date(2026, time.March, 31, 9, 0, 0, 0, time.UTC)},
+		{"Monthly Across Years", data.RECURRING_FREQUENCY_MONTHLY, 13, time.Date(2027, time.February, 28, 9, 0, 0, 0, time.UTC)},
+		{"Yearly", data.RECURRING_FREQUENCY_YEARLY, 1, time.Date(2027, time.January, 31, 9, 0, 0, 0, time.UTC)},
+		{"Start Date", data.RECURRING_FREQUENCY_MONTHLY, 0, start},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, recurringDate(start, test.frequency, test.n))
+		})
+	}
+
+	t.Run("Leap Day Yearly", func(t *testing.T) {
+		leapDay := time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)
+		assert.Equal(t, time.Date(2029, time.February, 28, 0, 0, 0, 0, time.UTC), recurringDate(leapDay, data.RECURRING_FREQUENCY_YEARLY, 1))
+		assert.Equal(t, time.Date(2032, time.February, 29, 0, 0, 0, 0, time.UTC), recurringDate(leapDay, data.RECURRING_FREQUENCY_YEARLY, 4))
+	})
+}
+
+type recurringMocks struct {
+	tx           *mock.Mock
+	txManager    *mock.Mock
+	recurring    *mock.Mock
+	transactions *mock.Mock
+	wallets      *mock.Mock
+	categories   *mock.Mock
+	outbox       *mock.Mock
+}
+
+func (mocks recurringMocks) assertExpectations(t *testing.T) {
+	for _, m := range []*mock.Mock{mocks.tx, mocks.txManager, mocks.recurring, mocks.transactions, mocks.wallets, mocks.categories, mocks.outbox} {
+		m.AssertExpectations(t)
+	}
+}
+
+// newTestRecurringService wires the service to repository mocks, every transaction it begins is mocks.tx
+func newTestRecurringService() (RecurringTransactionsService, recurringMocks) {
+	tx := repository.NewTransactionMock()
+	tx_manager_mock := repository.NewTxManagerMock()
+	recurring_repo_mock := repository.NewRecurringTransactionsRepositoryMock()
+	transaction_repo_mock := repository.NewTransactionsRepositoryMock()
+	wallet_repo_mock := repository.NewWalletsRepositoryMock()
+	category_repo_mock := repository.NewCategoriesRepositoryMock()
+	outbox_repo_mock := repository.NewOutboxEventsRepositoryMock()
+
+	tx_manager_mock.Mock.On("Begin", mock.Anything).Return(tx, nil).Maybe()
+	tx.Mock.On("Rollback").Return(nil).Maybe()
+	outbox_repo_mock.Mock.On("CreateOutboxEvent", mock.Anything, tx, mock.Anything).Return(nil, nil).Maybe()
+
+	recurring_serv_test := NewRecurringTransactionsService(tx_manager_mock, recurring_repo_mock, transaction_repo_mock, wallet_repo_mock, category_repo_mock, NewOutboxService(nil, outbox_repo_mock))
+
+	return recurring_serv_test, recurringMocks{
+		tx:           &tx.Mock,
+		txManager:    &tx_manager_mock.Mock,
+		recurring:    &recurring_repo_mock.Mock,
+		transactions: &transaction_repo_mock.Mock,
+		wallets:      &wallet_repo_mock.Mock,
+		categories:   &category_repo_mock.Mock,
+		outbox:       &outbox_repo_mock.Mock,
+	}
+}
+
+func TestBookDueTransactions(t *testing.T) {
+	log.SetupLogger()
+	ctx := context.Background()
+	now := time.Now()
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	// * An hour after the time of day of now, so the booking of today is not due yet
+	daysAgo := func(days int) time.Time {
+		return now.AddDate(0, 0, -days).Add(time.Hour)
+	}
+
+	tests := []struct {
+		name         string
+		categoryType entity.CategoryType
+		frequency    string
+		start        time.Time
+		end          *time.Time
+		balance      float64
+		bookings     int
+		finalBalance float64
+	}{
+		{
+			name:         "Expense Debits Every Due Date",
+			categoryType: entity.Expense,
+			frequency:    data.RECURRING_FREQUENCY_MONTHLY,
+			start:        thisMonth.AddDate(0, -2, 0),
+			balance:      1000,
+			bookings:     3,
+			finalBalance: 700,
+		},
+		{
+			name:         "Income Credits Every Due Date",
+			categoryType: entity.Income,
+			frequency:    data.RECURRING_FREQUENCY_WEEKLY,
+			start:        daysAgo(15),
+			balance:      1000,
+			bookings:     3,
+			finalBalance: 1300,
+		},
+		{
+			name:         "Stops At End Date",
+			categoryType: entity.Income,
+			frequency:    data.RECURRING_FREQUENCY_DAILY,
+			start:        daysAgo(10),
+			end:          func() *time.Time { end := daysAgo(7); return &end }(),
+			balance:      0,
+			bookings:     4,
+			finalBalance: 400,
+		},
+		{
+			name:         "Catches Up At Most RECURRING_MAX_BOOKINGS",
+			categoryType: entity.Expense,
+			frequency:    data.RECURRING_FREQUENCY_DAILY,
+			start:        daysAgo(150),
+			balance:      RECURRING_MAX_BOOKINGS * 100,
+			bookings:     RECURRING_MAX_BOOKINGS,
+			finalBalance: 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recurring_serv_test, mocks := newTestRecurringService()
+			defer mocks.assertExpectations(t)
+
+			wallet := entity.Wallets{Base: entity.Base{ID: uuid.New()}, Balance: test.balance}
+			category := entity.Categories{Base: entity.Base{ID: uuid.New()}, Type: test.categoryType}
+			recurring := entity.RecurringTransactions{
+				Base:       entity.Base{ID: uuid.New()},
+				UserID:     uuid.New(),
+				WalletID:   wallet.ID,
+				CategoryID: category.ID,
+				Amount:     100,
+				Frequency:  test.frequency,
+				StartDate:  test.start,
+				EndDate:    test.end,
+				NextDate:   test.start,
+			}
+
+			var bookedDates []time.Time
+			mocks.recurring.On("GetDueRecurringTransactionIDs", ctx, nil, mock.Anything, RECURRING_BATCH_SIZE).Return([]string{recurring.ID.String()}, nil)
+			mocks.recurring.On("LockDueRecurringTransaction", ctx, mock.Anything, recurring.ID.String(), mock.Anything).Return(&recurring, nil)
+			mocks.wallets.On("GetWalletByID", ctx, mock.Anything, wallet.ID.String()).Return(wallet, nil)
+			mocks.categories.On("GetCategoryByID", ctx, mock.Anything, category.ID.String()).Return(category, nil)
+			mocks.transactions.On("CreateTransaction", ctx, mock.Anything, mock.MatchedBy(func(transaction entity.Transactions) bool {
+				return transaction.WalletID == wallet.ID && transaction.CategoryID == category.ID && transaction.Amount == 100
+			})).Run(func(args mock.Arguments) {
+				bookedDates = append(bookedDates, args.Get(2).(entity.Transactions).TransactionDate)
+			}).Return(nil, nil).Times(test.bookings)
+			mocks.wallets.On("UpdateWallet", ctx, mock.Anything, walletWithBalance(wallet.ID, test.finalBalance)).Return(entity.Wallets{}, nil).Once()
+			mocks.recurring.On("UpdateRecurringTransaction", ctx, mock.Anything, mock.MatchedBy(func(updated entity.RecurringTransactions) bool {
+				return updated.Occurrences == test.bookings && updated.NextDate.Equal(recurringDate(test.start, test.frequency, test.bookings))
+			})).Return(nil, nil).Once()
+			mocks.tx.On("Commit").Return(nil).Once()
+
+			err := recurring_serv_test.BookDueTransactions(ctx)
+			assert.Nil(t, err)
+
+			expectedDates := make([]time.Time, 0, test.bookings)
+			for n := range test.bookings {
+				expectedDates = append(expectedDates, recurringDate(test.start, test.frequency, n))
+			}
+			assert.Equal(t, expectedDates, bookedDates)
+		})
+	}
+
+	t.Run("No Longer Due", func(t *testing.T) {
+		recurring_serv_test, mocks := newTestRecurringService()
+		defer mocks.assertExpectations(t)
+
+		id := uuid.New().String()
+		mocks.recurring.On("GetDueRecurringTransactionIDs", ctx, nil, mock.Anything, RECURRING_BATCH_SIZE).Return([]string{id}, nil)
+		mocks.recurring.On("LockDueRecurringTransaction", ctx, mock.Anything, id, mock.Anything).Return(nil, nil)
+		mocks.tx.On("Commit").Return(nil).Once()
+
+		err := recurring_serv_test.BookDueTransactions(ctx)
+		assert.Nil(t, err)
+		mocks.transactions.AssertNotCalled(t, "CreateTransaction", mock.Anything, mock.Anything, mock.Anything)
+		mocks.wallets.AssertNotCalled(t, "UpdateWallet", mock.Anything, mock.Anything, mock.Anything)
+		mocks.recurring.AssertNotCalled(t, "UpdateRecurringTransaction", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failed Booking Does Not Hold Back Others", func(t *testing.T) {
+		recurring_serv_test, mocks := newTestRecurringService()
+		defer mocks.assertExpectations(t)
+
+		wallet := entity.Wallets{Base: entity.Base{ID: uuid.New()}, Balance: 1000}
+		category := entity.Categories{Base: entity.Base{ID: uuid.New()}, Type: entity.Expense}
+		failing := entity.RecurringTransactions{Base: entity.Base{ID: uuid.New()}, WalletID: uuid.New(), CategoryID: category.ID, Amount: 100, Frequency: data.RECURRING_FREQUENCY_DAILY, StartDate: daysAgo(1), NextDate: daysAgo(1)}
+		booked := entity.RecurringTransactions{Base: entity.Base{ID: uuid.New()}, WalletID: wallet.ID, CategoryID: category.ID, Amount: 100, Frequency: data.RECURRING_FREQUENCY_DAILY, StartDate: daysAgo(1), NextDate: daysAgo(1)}
+
+		mocks.recurring.On("GetDueRecurringTransactionIDs", ctx, nil, mock.Anything, RECURRING_BATCH_SIZE).Return([]string{failing.ID.String(), booked.ID.String()}, nil)
+		mocks.recurring.On("LockDueRecurringTransaction", ctx, mock.Anything, failing.ID.String(), mock.Anything).Return(&failing, nil)
+		mocks.recurring.On("LockDueRecurringTransaction", ctx, mock.Anything, booked.ID.String(), mock.Anything).Return(&booked, nil)
+		mocks.wallets.On("GetWalletByID", ctx, mock.Anything, failing.WalletID.String()).Return(nil, errors.New("record not found"))
+		mocks.wallets.On("GetWalletByID", ctx, mock.Anything, wallet.ID.String()).Return(wallet, nil)
+		mocks.categories.On("GetCategoryByID", ctx, mock.Anything, category.ID.String()).Return(category, nil)
+		mocks.transactions.On("CreateTransaction", ctx, mock.Anything, mock.Anything).Return(nil, nil).Once()
+		mocks.wallets.On("UpdateWallet", ctx, mock.Anything, walletWithBalance(wallet.ID, 900)).Return(entity.Wallets{}, nil).Once()
+		mocks.recurring.On("UpdateRecurringTransaction", ctx, mock.Anything, mock.Anything).Return(nil, nil).Once()
+		mocks.tx.On("Commit").Return(nil).Once()
+
+		err := recurring_serv_test.BookDueTransactions(ctx)
+		assert.ErrorContains(t, err, failing.ID.String())
+		assert.NotContains(t, err.Error(), booked.ID.String())
+		mocks.tx.AssertCalled(t, "Rollback")
+	})
+}
@@ -3,55 +3,153 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"server/config/env"
+	"server/config/log"
+	"server/config/miniofs"
 	"server/config/queue"
 	"server/internal/repository"
 	"server/internal/types/dto"
 	"server/internal/types/entity"
 	helper "server/internal/utils"
 	"server/internal/utils/data"
+	"server/internal/utils/report"
 
 	"github.com/rabbitmq/amqp091-go"
+	"gorm.io/gorm"
 )
 
+const (
+	// REPORT_URL_EXPIRY is how long the download link in the report email stays valid, the maximum MinIO allows
+	REPORT_URL_EXPIRY = 7 * 24 * time.Hour
+	// REPORT_DOWNLOAD_EXPIRY is how long a link requested from the download endpoint stays valid
+	REPORT_DOWNLOAD_EXPIRY = 15 * time.Minute
+	// REPORT_DEFAULT_COOLDOWN applies to report types without a cooldown in the config
+	REPORT_DEFAULT_COOLDOWN = 24 * time.Hour
+)
+
+// ReportCooldownError is returned when a report is requested before the cooldown of the previous one ended
+type ReportCooldownError struct {
+	ReportType    string
+	NextRequestAt time.Time
+}
+
+func (e *ReportCooldownError) Error() string {
+	return fmt.Sprintf("%s report can be requested again at %s", e.ReportType, e.NextRequestAt.Format(time.RFC3339))
+}
+
 type ReportsService interface {
-	RequestReport(ctx context.Context, userID, fromDate, toDate string) error
-	UpdateUserReport(ctx context.Context) error
+	GetReports(ctx context.Context, token string) ([]dto.ReportsResponse, error)
+	GetReportByID(ctx context.Context, token, id string) (dto.ReportsResponse, error)
+	GetReportDownload(ctx context.Context, token, id string) (dto.ReportDownloadResponse, error)
+	RequestReport(ctx context.Context, token, reportType, fromDate, toDate string) error
+	CancelReport(ctx context.Context, token, id string) (dto.ReportsResponse, error)
+	ProcessReport(ctx context.Context, delivery amqp091.Delivery) error
+	FailReport(ctx context.Context, delivery amqp091.Delivery, err error)
 }
 
 type reportsService struct {
-	reportsRepo repository.ReportsRepository
-	usersRepo   repository.UsersRepository
+	txManager        repository.TxManager
+	reportsRepo      repository.ReportsRepository
+	usersRepo        repository.UsersRepository
+	transactionsRepo repository.TransactionsRepository
+	walletsRepo      repository.WalletsRepository
+	outboxServ       OutboxService
+	emailServ        EmailsService
+	storage          miniofs.Storage
 }
 
-func NewReportsService(reportsRepo repository.ReportsRepository, usersRepo repository.UsersRepository) ReportsService {
+func NewReportsService(txManager repository.TxManager, reportsRepo repository.ReportsRepository, usersRepo repository.UsersRepository, transactionsRepo repository.TransactionsRepository, walletsRepo repository.WalletsRepository, outboxServ OutboxService, emailServ EmailsService, storage miniofs.Storage) ReportsService {
 	return &reportsService{
-		reportsRepo: reportsRepo,
-		usersRepo:   usersRepo,
+		txManager:        txManager,
+		reportsRepo:      reportsRepo,
+		usersRepo:        usersRepo,
+		transactionsRepo: transactionsRepo,
+		walletsRepo:      walletsRepo,
+		outboxServ:       outboxServ,
+		emailServ:        emailServ,
+		storage:          storage,
+	}
+}
+
+func (s *reportsService) GetReports(ctx context.Context, token string) ([]dto.ReportsResponse, error) {
+	userData, err := helper.VerifyToken(token[7:])
+	if err != nil {
+		return nil, errors.New("invalid token")
 	}
+
+	reports, err := s.reportsRepo.GetReportByUserID(ctx, nil, userData.ID)
+	if err != nil {
+		return nil, errors.New("failed to get reports")
+	}
+
+	reportsResponse := make([]dto.ReportsResponse, 0, len(reports))
+	for _, report := range reports {
+		reportsResponse = append(reportsResponse, toReportResponse(report))
+	}
+
+	return reportsResponse, nil
 }
 
-func (s *reportsService) RequestReport(ctx context.Context, userID, fromDate, toDate string) error {
+func (s *reportsService) GetReportByID(ctx context.Context, token, id string) (dto.ReportsResponse, error) {
+	report, err := s.getUserReport(ctx, token, id)
+	if err != nil {
+		return dto.ReportsResponse{}, err
+	}
+
+	return toReportResponse(report), nil
+}
+
+func (s *reportsService) GetReportDownload(ctx context.Context, token, id string) (dto.ReportDownloadResponse, error) {
+	report, err := s.getUserReport(ctx, token, id)
+	if err != nil {
+		return dto.ReportDownloadResponse{}, err
+	}
+
+	if report.Status != data.REPORT_STATUS_COMPLETED || report.FileURL == nil {
+		return dto.ReportDownloadResponse{}, fmt.Errorf("report is not ready for download, current status: %s", report.Status)
+	}
+
+	bucketName, objectName, err := s.storage.ParseURL(*report.FileURL)
+	if err != nil {
+		return dto.ReportDownloadResponse{}, errors.New("invalid report file")
+	}
+
+	url, err := s.storage.PresignedURL(ctx, bucketName, objectName, REPORT_DOWNLOAD_EXPIRY)
+	if err != nil {
+		return dto.ReportDownloadResponse{}, errors.New("failed to create download link")
+	}
+
+	return dto.ReportDownloadResponse{
+		ID:        report.ID.String(),
+		URL:       url,
+		ExpiresAt: time.Now().Add(REPORT_DOWNLOAD_EXPIRY),
+	}, nil
+}
+
+func (s *reportsService) RequestReport(ctx context.Context, token, reportType, fromDate, toDate string) error {
+	userData, err := helper.VerifyToken(token[7:])
+	if err != nil {
+		return errors.New("invalid token")
+	}
+	userID := userData.ID
+
 	// Check if user exists
 	if _, err := s.usersRepo.GetUserByID(userID); err != nil {
 		return err
 	}
 
-	// Check if there are any processed reports for the user
-	userReports, err := s.reportsRepo.GetProcessedReportByUserID(ctx, nil, userID)
-	if err != nil {
-		return err
+	if reportType == "" {
+		reportType = data.REPORT_TYPE_FINANCIAL
 	}
-	if len(userReports) > 0 {
-		return fmt.Errorf("there are %d processed reports for user %s", len(userReports), userID)
+	if !slices.Contains(data.REPORT_TYPES, reportType) {
+		return fmt.Errorf("invalid report type %s", reportType)
 	}
 
-	// Create a new report
-	report := entity.Reports{}
-	report.UserID = userID
 	fromDateTime, err := time.Parse(time.RFC3339, fromDate)
 	if err != nil {
 		return err
@@ -60,104 +158,308 @@ func (s *reportsService) RequestReport(ctx context.Context, userID, fromDate, to
 	if err != nil {
 		return err
 	}
+
+	// The checks, the report and its message are one transaction, the outbox relay publishes the message to the worker
+	tx, err := s.txManager.Begin(ctx)
+	if err != nil {
+		return errors.New("failed to create transaction")
+	}
+
+	defer func() {
+		// Rollback otomatis jika transaksi belum di-commit
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Requests of the same user wait for each other, so concurrent requests see each other's report
+	if err = s.reportsRepo.LockUserReports(ctx, tx, userID); err != nil {
+		return err
+	}
+
+	// Check if there are any processed reports for the user
+	userReports, err := s.reportsRepo.GetProcessedReportByUserID(ctx, tx, userID)
+	if err != nil {
+		return err
+	}
+	if len(userReports) > 0 {
+		err = fmt.Errorf("there are %d processed reports for user %s", len(userReports), userID)
+		return err
+	}
+
+	// Admins are not limited by the cooldown
+	now := time.Now()
+	if userData.Role != string(entity.Admin) {
+		var latestReport *entity.Reports
+		if latestReport, err = s.reportsRepo.GetLatestReportByUserIDAndType(ctx, tx, userID, reportType); err != nil {
+			return err
+		}
+		if latestReport != nil && now.Before(latestReport.NextRequestAt) {
+			err = &ReportCooldownError{ReportType: reportType, NextRequestAt: latestReport.NextRequestAt}
+			return err
+		}
+	}
+
+	// Create a new report
+	report := entity.Reports{}
+	report.UserID = userID
+	report.Type = reportType
 	report.FromDate = fromDateTime
 	report.ToDate = toDateTime
-	report.RequestAt = time.Now()
-	report.NextRequestAt = time.Now().Add(24 * time.Hour)
+	report.RequestAt = now
+	report.NextRequestAt = now.Add(reportCooldown(reportType))
 	report.Status = data.REPORT_STATUS_PROCESSING
-	if report, err = s.reportsRepo.CreateReport(ctx, nil, report); err != nil {
+
+	if report, err = s.reportsRepo.CreateReport(ctx, tx, report); err != nil {
+		return err
+	}
+
+	if err = s.outboxServ.Enqueue(ctx, tx, queue.USER_REPORT_EXCHANGE, queue.USER_REPORT_ROUTING_KEY, reportMessage{ID: report.ID.String(), UserID: userID}); err != nil {
 		return err
 	}
 
-	// Publish a message to the RabbitMQ queue to process the report
-	message := amqp091.Publishing{
-		ContentType: "application/json",
-		Body:        []byte(`{"id":"` + report.ID.String() + `", "user_id":"` + userID + `"}`),
+	if err = tx.Commit(); err != nil {
+		return errors.New("failed to commit transaction")
+	}
+
+	return nil
+}
+
+// CancelReport stops a report that is still processing, the worker skips it once it picks the message up
+func (s *reportsService) CancelReport(ctx context.Context, token, id string) (dto.ReportsResponse, error) {
+	report, err := s.getUserReport(ctx, token, id)
+	if err != nil {
+		return dto.ReportsResponse{}, err
+	}
+
+	if report.Status != data.REPORT_STATUS_PROCESSING {
+		return dto.ReportsResponse{}, fmt.Errorf("only processing reports can be cancelled, current status: %s", report.Status)
+	}
+
+	report.Status = data.REPORT_STATUS_CANCELLED
+	cancelled, err := s.reportsRepo.UpdateProcessingReport(ctx, nil, report)
+	if err != nil {
+		return dto.ReportsResponse{}, errors.New("failed to cancel report")
+	}
+	if !cancelled {
+		return dto.ReportsResponse{}, errors.New("report has already been processed")
+	}
+
+	return toReportResponse(report), nil
+}
+
+type reportMessage struct {
+	ID     string `json:"id"`
+	UserID string `json:"user_id"`
+}
+
+// ProcessReport generates and emails the report of one request message, failed reports are retried and
+// only marked failed by FailReport once they reach the dead-letter queue
+func (s *reportsService) ProcessReport(ctx context.Context, delivery amqp091.Delivery) error {
+	// Unmarshal the message body to get the report ID and user ID
+	var report reportMessage
+	if err := json.Unmarshal(delivery.Body, &report); err != nil {
+		return queue.Permanent(fmt.Errorf("failed to unmarshal user report: %w", err))
+	}
+	if report.ID == "" {
+		return queue.Permanent(fmt.Errorf("report ID is empty, skipping report processing"))
+	}
+
+	// Get the user by ID
+	user, err := s.usersRepo.GetUserByID(report.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get user by ID %s: %w", report.UserID, err)
+	}
+
+	// Check if the report exists and is in processing status
+	existingReport, err := s.reportsRepo.GetReportByID(ctx, nil, report.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return queue.Permanent(fmt.Errorf("report %s not found", report.ID))
 	}
-	prodRequestReports, err := queue.GetChannel()
+	if err != nil {
+		return fmt.Errorf("failed to get report by ID %s: %w", report.ID, err)
+	}
+	if existingReport.UserID != user.ID.String() {
+		return queue.Permanent(fmt.Errorf("report user ID %s does not match user ID %s", existingReport.UserID, user.ID.String()))
+	}
+	if existingReport.Status != data.REPORT_STATUS_PROCESSING {
+		// Cancelled, or already handled by an earlier delivery of the same message
+		log.Info(fmt.Sprintf("report %s is %s, skipping report processing", existingReport.ID, existingReport.Status))
+		return nil
+	}
+
+	// Generate the PDF and store it before the email links to it
+	var userReport dto.ReportResponse
+	userReport.UserID = user.ID.String()
+	userReport.UserName = user.Name
+	userReport.FromDate = existingReport.FromDate
+	userReport.ToDate = existingReport.ToDate
+	userReport.GeneratedAt = time.Now()
+
+	uploaded, err := s.generateReport(ctx, user, existingReport, userReport.GeneratedAt)
+	if err != nil {
+		return fmt.Errorf("failed to generate report %s: %w", existingReport.ID, err)
+	}
+
+	// The stored reference is kept on the report, the email gets a temporary download link
+	existingReport.FileURL = &uploaded.URL
+	existingReport.FileSize = &uploaded.Size
+	existingReport.GeneratedAt = &userReport.GeneratedAt
+	saved, err := s.reportsRepo.UpdateProcessingReport(ctx, nil, existingReport)
 	if err != nil {
 		return err
 	}
-	err = prodRequestReports.PublishWithContext(ctx, "user_report", "user_report", false, false, message)
+	if !saved {
+		// Cancelled while generating, the file is dropped and no email is sent
+		if err := s.storage.Delete(ctx, uploaded.BucketName, uploaded.ObjectName); err != nil {
+			log.Error(fmt.Sprintf("failed to delete file of cancelled report %s: %v", existingReport.ID, err))
+		}
+		return nil
+	}
+
+	userReport.FileSize = uploaded.Size
+	if userReport.FileURL, err = s.storage.PresignedURL(ctx, uploaded.BucketName, uploaded.ObjectName, REPORT_URL_EXPIRY); err != nil {
+		return fmt.Errorf("failed to create download link for report %s: %w", existingReport.ID, err)
+	}
+
+	// The report is completed and its email queued together, a retry of the message never sends it twice
+	tx, err := s.txManager.Begin(ctx)
 	if err != nil {
+		return fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	existingReport.Status = data.REPORT_STATUS_COMPLETED
+	if saved, err = s.reportsRepo.UpdateProcessingReport(ctx, tx, existingReport); err != nil {
 		return err
 	}
+	if !saved {
+		log.Info(fmt.Sprintf("report %s was cancelled while generating, no email is sent", existingReport.ID))
+		return nil
+	}
 
-	return nil
+	if err := s.emailServ.QueueEmail(ctx, tx, user.Email, "Report Generated", "financial-report-template.html", userReport); err != nil {
+		return fmt.Errorf("failed to queue email of report %s: %w", existingReport.ID, err)
+	}
+
+	return tx.Commit()
 }
 
-func (s *reportsService) UpdateUserReport(ctx context.Context) error {
-	// Consume messages from the RabbitMQ queue
-	consRequestReports, err := queue.GetChannel()
+// FailReport marks the report of a dead-lettered message as failed so the user can request it again
+func (s *reportsService) FailReport(ctx context.Context, delivery amqp091.Delivery, _ error) {
+	var report reportMessage
+	if err := json.Unmarshal(delivery.Body, &report); err != nil || report.ID == "" {
+		return
+	}
+
+	existingReport, err := s.reportsRepo.GetReportByID(ctx, nil, report.ID)
 	if err != nil {
-		return err
+		log.Error(fmt.Sprintf("failed to get dead-lettered report %s: %v", report.ID, err))
+		return
+	}
+
+	existingReport.Status = data.REPORT_STATUS_FAILED
+	if _, err := s.reportsRepo.UpdateProcessingReport(ctx, nil, existingReport); err != nil {
+		log.Error(fmt.Sprintf("failed to update report %s status to FAILED: %v", report.ID, err))
 	}
-	userReports, err := consRequestReports.ConsumeWithContext(ctx, "user_report", "consumer_user_report", true, false, false, false, nil)
+}
+
+// reportCooldown returns the configured cooldown of the report type
+func reportCooldown(reportType string) time.Duration {
+	if cooldown, ok := env.Cfg.Report.Cooldowns[reportType]; ok {
+		return cooldown
+	}
+	return REPORT_DEFAULT_COOLDOWN
+}
+
+// getUserReport returns the report only when it belongs to the user of the token
+func (s *reportsService) getUserReport(ctx context.Context, token, id string) (entity.Reports, error) {
+	userData, err := helper.VerifyToken(token[7:])
 	if err != nil {
-		return err
+		return entity.Reports{}, errors.New("invalid token")
 	}
 
-	// Process each user report
-	for userReport := range userReports {
-		// Unmarshal the message body to get the report ID and user ID
-		var report struct {
-			ID     string `json:"id"`
-			UserID string `json:"user_id"`
-		}
-		if err := json.Unmarshal(userReport.Body, &report); err != nil {
-			return fmt.Errorf("failed to unmarshal user report: %w", err)
-		}
-		if report.ID == "" {
-			return fmt.Errorf("report ID is empty, skipping report processing")
-		}
+	report, err := s.reportsRepo.GetReportByID(ctx, nil, id)
+	if err != nil || report.UserID != userData.ID {
+		return entity.Reports{}, errors.New("report not found")
+	}
 
-		// Get the user by ID
-		user, err := s.usersRepo.GetUserByID(report.UserID)
-		if err != nil {
-			return fmt.Errorf("failed to get user by ID %s: %w", report.UserID, err)
-		}
+	return report, nil
+}
 
-		// Check if the report exists and is in processing status
-		existingReport, err := s.reportsRepo.GetReportByID(ctx, nil, report.ID)
-		if err != nil {
-			return fmt.Errorf("failed to get report by ID %s: %w", report.ID, err)
-		}
-		if existingReport.UserID != user.ID.String() {
-			return fmt.Errorf("report user ID %s does not match user ID %s", existingReport.UserID, user.ID.String())
+// generateReport renders the PDF report of the period and uploads it to the reports bucket
+func (s *reportsService) generateReport(ctx context.Context, user entity.Users, userReport entity.Reports, generatedAt time.Time) (*miniofs.UploadResponse, error) {
+	// Balances at the end of the period are derived from the current balances and everything recorded since
+	transactions, err := s.transactionsRepo.GetTransactionsByUserIDAndPeriod(ctx, nil, user.ID.String(), userReport.FromDate, generatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	wallets, err := s.walletsRepo.GetWalletsByUserID(ctx, nil, user.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	reportData := report.Data{
+		UserName:    user.Name,
+		Email:       user.Email,
+		From:        userReport.FromDate,
+		To:          userReport.ToDate,
+		GeneratedAt: generatedAt,
+	}
+
+	var allTransactions []report.Transaction
+	for _, transaction := range transactions {
+		reportTransaction := report.Transaction{
+			Date:        transaction.TransactionDate,
+			WalletID:    transaction.WalletID.String(),
+			Wallet:      transaction.Wallet.Name,
+			Category:    transaction.Category.Name,
+			Type:        string(transaction.Category.Type),
+			Amount:      transaction.Amount,
+			Description: transaction.Description,
 		}
-		if existingReport.Status != data.REPORT_STATUS_PROCESSING {
-			return fmt.Errorf("report status is not processing, current status: %s", existingReport.Status)
+		allTransactions = append(allTransactions, reportTransaction)
+		if !transaction.TransactionDate.After(userReport.ToDate) {
+			reportData.Transactions = append(reportData.Transactions, reportTransaction)
 		}
+	}
 
-		// Simulate report generation and sending email
-		var userReport dto.ReportResponse
-		userReport.UserID = user.ID.String()
-		userReport.UserName = user.Name
-		userReport.FromDate = existingReport.FromDate
-		userReport.ToDate = existingReport.ToDate
-		userReport.GeneratedAt = time.Now()
-		userReport.FileURL = fmt.Sprintf("https://example.com/reports/%s_report.pdf", user.ID.String())
-		userReport.FileSize = 250000
-
-		SMTPProvider := helper.NewZohoSMTP(env.Cfg.ZSMTP)
-		if err := helper.NewSMTPClient(SMTPProvider).SendSingleEmail(user.Email, "Report Generated", "financial-report-template.html", userReport); err != nil {
-			// Handle error
-			existingReport.Status = data.REPORT_STATUS_FAILED
-			if _, updateErr := s.reportsRepo.UpdateReport(ctx, nil, existingReport); updateErr != nil {
-				return fmt.Errorf("failed to update report status to FAILED after email error: %w", updateErr)
-			}
-			return fmt.Errorf("failed to send email: %w", err)
-		}
+	currentBalances := make([]report.Wallet, 0, len(wallets))
+	for _, wallet := range wallets {
+		currentBalances = append(currentBalances, report.Wallet{
+			ID:      wallet.ID,
+			Name:    wallet.WalletName,
+			Type:    wallet.WalletTypeName,
+			Closing: wallet.WalletBalance,
+		})
+	}
+	reportData.Wallets = report.WalletBalances(currentBalances, allTransactions, userReport.FromDate, userReport.ToDate)
 
-		// Update the report status and details
-		existingReport.FileURL = &userReport.FileURL
-		existingReport.FileSize = &userReport.FileSize
-		existingReport.GeneratedAt = &userReport.GeneratedAt
-		existingReport.Status = data.REPORT_STATUS_COMPLETED
-		if _, err := s.reportsRepo.UpdateReport(ctx, nil, existingReport); err != nil {
-			return err
-		}
+	pdf, err := report.Render(reportData)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	if err := s.storage.EnsureBucket(ctx, miniofs.REPORT_BUCKET); err != nil {
+		return nil, err
+	}
+
+	objectName := fmt.Sprintf("%s/%s.pdf", user.ID.String(), userReport.ID.String())
+	return s.storage.Put(ctx, miniofs.REPORT_BUCKET, objectName, pdf, "application/pdf")
+}
+
+func toReportResponse(report entity.Reports) dto.ReportsResponse {
+	return dto.ReportsResponse{
+		ID:            report.ID.String(),
+		UserID:        report.UserID,
+		FromDate:      report.FromDate,
+		ToDate:        report.ToDate,
+		RequestAt:     report.RequestAt,
+		NextRequestAt: report.NextRequestAt,
+		Type:          report.Type,
+		Status:        report.Status,
+		FileSize:      report.FileSize,
+		GeneratedAt:   report.GeneratedAt,
+	}
 }
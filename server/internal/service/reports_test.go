@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"server/config/env"
+	"server/internal/repository"
+	"server/internal/types/entity"
+	"server/internal/utils/data"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type reportMocks struct {
+	tx        *mock.Mock
+	txManager *mock.Mock
+	reports   *mock.Mock
+	users     *mock.Mock
+	outbox    *mock.Mock
+}
+
+func (mocks reportMocks) assertExpectations(t *testing.T) {
+	for _, m := range []*mock.Mock{mocks.tx, mocks.txManager, mocks.reports, mocks.users, mocks.outbox} {
+		m.AssertExpectations(t)
+	}
+}
+
+// newTestReportService wires the service to repository mocks, every transaction it begins is mocks.tx
+func newTestReportService() (ReportsService, reportMocks) {
+	tx := repository.NewTransactionMock()
+	tx_manager_mock := repository.NewTxManagerMock()
+	report_repo_mock := repository.NewReportsRepositoryMock()
+	user_repo_mock := repository.NewUsersRepositoryMock()
+	outbox_repo_mock := repository.NewOutboxEventsRepositoryMock()
+
+	tx_manager_mock.Mock.On("Begin", mock.Anything).Return(tx, nil).Maybe()
+	tx.Mock.On("Rollback").Return(nil).Maybe()
+
+	report_serv_test := NewReportsService(tx_manager_mock, report_repo_mock, user_repo_mock, nil, nil, NewOutboxService(nil, outbox_repo_mock), nil, nil)
+
+	return report_serv_test, reportMocks{
+		tx:        &tx.Mock,
+		txManager: &tx_manager_mock.Mock,
+		reports:   &report_repo_mock.Mock,
+		users:     &user_repo_mock.Mock,
+		outbox:    &outbox_repo_mock.Mock,
+	}
+}
+
+func TestRequestReport(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	from, to := "2026-09-01T00:00:00Z", "2026-09-30T23:59:59Z"
+
+	env.Cfg.Report.Cooldowns = map[string]time.Duration{data.REPORT_TYPE_FINANCIAL: 2 * time.Hour}
+	defer func() { env.Cfg.Report.Cooldowns = nil }()
+
+	// expectRequest sets up the checks every request of the user goes through
+	expectRequest := func(mocks reportMocks) {
+		mocks.users.On("GetUserByID", userID.String()).Return(entity.Users{Base: entity.Base{ID: userID}}, nil)
+		mocks.reports.On("LockUserReports", ctx, mock.Anything, userID.String()).Return(nil).Once()
+		mocks.reports.On("GetProcessedReportByUserID", ctx, mock.Anything, userID.String()).Return(nil, nil)
+	}
+
+	// expectCreated sets up a report created with the configured cooldown and its message in the outbox
+	expectCreated := func(mocks reportMocks, requestedAt time.Time) {
+		mocks.reports.On("CreateReport", ctx, mock.Anything, mock.MatchedBy(func(report entity.Reports) bool {
+			return report.UserID == userID.String() &&
+				report.Type == data.REPORT_TYPE_FINANCIAL &&
+				report.Status == data.REPORT_STATUS_PROCESSING &&
+				report.NextRequestAt.Sub(report.RequestAt) == 2*time.Hour &&
+				!report.RequestAt.Before(requestedAt)
+		})).Return(nil, nil).Once()
+		mocks.outbox.On("CreateOutboxEvent", ctx, mock.Anything, mock.Anything).Return(nil, nil).Once()
+		mocks.tx.On("Commit").Return(nil).Once()
+	}
+
+	t.Run("Inside Cooldown", func(t *testing.T) {
+		report_serv_test, mocks := newTestReportService()
+		defer mocks.assertExpectations(t)
+
+		nextRequestAt := time.Now().Add(time.Hour).Truncate(time.Second)
+		expectRequest(mocks)
+		mocks.reports.On("GetLatestReportByUserIDAndType", ctx, mock.Anything, userID.String(), data.REPORT_TYPE_FINANCIAL).Return(&entity.Reports{NextRequestAt: nextRequestAt}, nil)
+
+		err := report_serv_test.RequestReport(ctx, testToken(t, userID, entity.User), "", from, to)
+
+		var cooldownErr *ReportCooldownError
+		assert.True(t, errors.As(err, &cooldownErr))
+		assert.Equal(t, data.REPORT_TYPE_FINANCIAL, cooldownErr.ReportType)
+		assert.Equal(t, nextRequestAt, cooldownErr.NextRequestAt)
+		mocks.reports.AssertNotCalled(t, "CreateReport", mock.Anything, mock.Anything, mock.Anything)
+		mocks.tx.AssertNotCalled(t, "Commit")
+		mocks.tx.AssertCalled(t, "Rollback")
+	})
+
+	t.Run("After Cooldown", func(t *testing.T) {
+		report_serv_test, mocks := newTestReportService()
+		defer mocks.assertExpectations(t)
+
+		requestedAt := time.Now()
+		expectRequest(mocks)
+		mocks.reports.On("GetLatestReportByUserIDAndType", ctx, mock.Anything, userID.String(), data.REPORT_TYPE_FINANCIAL).Return(&entity.Reports{NextRequestAt: requestedAt.Add(-time.Minute)}, nil)
+		expectCreated(mocks, requestedAt)
+
+		err := report_serv_test.RequestReport(ctx, testToken(t, userID, entity.User), data.REPORT_TYPE_FINANCIAL, from, to)
+		assert.Nil(t, err)
+	})
+
+	t.Run("Admin Bypasses Cooldown", func(t *testing.T) {
+		report_serv_test, mocks := newTestReportService()
+		defer mocks.assertExpectations(t)
+
+		requestedAt := time.Now()
+		expectRequest(mocks)
+		expectCreated(mocks, requestedAt)
+
+		err := report_serv_test.RequestReport(ctx, testToken(t, userID, entity.Admin), data.REPORT_TYPE_FINANCIAL, from, to)
+		assert.Nil(t, err)
+		mocks.reports.AssertNotCalled(t, "GetLatestReportByUserIDAndType", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unknown Type", func(t *testing.T) {
+		report_serv_test, mocks := newTestReportService()
+		defer mocks.assertExpectations(t)
+
+		mocks.users.On("GetUserByID", userID.String()).Return(entity.Users{Base: entity.Base{ID: userID}}, nil)
+
+		err := report_serv_test.RequestReport(ctx, testToken(t, userID, entity.User), "tax", from, to)
+		assert.EqualError(t, err, "invalid report type tax")
+		mocks.txManager.AssertNotCalled(t, "Begin", mock.Anything)
+	})
+}
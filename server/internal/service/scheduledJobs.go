@@ -0,0 +1,321 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"server/config/env"
+	"server/config/log"
+	"server/config/queue"
+	"server/internal/repository"
+	"server/internal/types/dto"
+	"server/internal/types/entity"
+	helper "server/internal/utils"
+	"server/internal/utils/data"
+
+	"github.com/google/uuid"
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// SCHEDULED_JOB_RUNS_LIMIT is how many of the latest runs the history endpoint returns
+const SCHEDULED_JOB_RUNS_LIMIT = 50
+
+// SUMMARY_REBUILD_MONTHS is how many months up to the current one the scheduled refresh rebuilds, the
+// monthly summaries of the dashboard show 12
+const SUMMARY_REBUILD_MONTHS = 13
+
+type ScheduledJobsService interface {
+	GetJobs(ctx context.Context) ([]dto.ScheduledJobsResponse, error)
+	GetJobRuns(ctx context.Context, jobName string) ([]dto.ScheduledJobRunsResponse, error)
+	TriggerJob(ctx context.Context, token, jobName string) error
+	RunScheduledJob(ctx context.Context, jobName string, scheduledAt time.Time) error
+	ProcessJobTrigger(ctx context.Context, delivery amqp091.Delivery) error
+}
+
+type scheduledJobsService struct {
+	txManager     repository.TxManager
+	jobRunsRepo   repository.ScheduledJobRunsRepository
+	outboxServ    OutboxService
+	summaryServ   UserSummariesService
+	cleanupServ   CleanupService
+	recurringServ RecurringTransactionsService
+	tasks         map[string]func(ctx context.Context) error
+}
+
+func NewScheduledJobsService(txManager repository.TxManager, jobRunsRepo repository.ScheduledJobRunsRepository, outboxServ OutboxService, summaryServ UserSummariesService, cleanupServ CleanupService, recurringServ RecurringTransactionsService) ScheduledJobsService {
+	s := &scheduledJobsService{
+		txManager:     txManager,
+		jobRunsRepo:   jobRunsRepo,
+		outboxServ:    outboxServ,
+		summaryServ:   summaryServ,
+		cleanupServ:   cleanupServ,
+		recurringServ: recurringServ,
+	}
+	s.tasks = map[string]func(ctx context.Context) error{
+		data.SCHEDULED_JOB_REFRESH_SUMMARIES: s.refreshSummaries,
+		data.SCHEDULED_JOB_CLEANUP:           s.cleanupServ.Cleanup,
+		data.SCHEDULED_JOB_RECURRING:         s.recurringServ.BookDueTransactions,
+	}
+	return s
+}
+
+// JobSchedule returns the configured cron schedule of the job, or data.SCHEDULE_OFF when it only runs by hand
+func JobSchedule(jobName string) string {
+	if schedule, ok := env.Cfg.Worker.Schedules[jobName]; ok {
+		return schedule
+	}
+	if schedule, ok := data.SCHEDULED_JOB_SCHEDULES[jobName]; ok {
+		return schedule
+	}
+	return data.SCHEDULE_OFF
+}
+
+func (job_serv *scheduledJobsService) GetJobs(ctx context.Context) ([]dto.ScheduledJobsResponse, error) {
+	names := make([]string, 0, len(job_serv.tasks))
+	for name := range job_serv.tasks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	jobs := make([]dto.ScheduledJobsResponse, 0, len(names))
+	for _, name := range names {
+		runs, err := job_serv.jobRunsRepo.GetScheduledJobRuns(ctx, nil, name, 1)
+		if err != nil {
+			return nil, errors.New("failed to get scheduled job runs")
+		}
+
+		job := dto.ScheduledJobsResponse{
+			Name:     name,
+			Schedule: JobSchedule(name),
+		}
+		if len(runs) > 0 {
+			lastRun := toScheduledJobRunResponse(runs[0])
+			job.LastRun = &lastRun
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+func (job_serv *scheduledJobsService) GetJobRuns(ctx context.Context, jobName string) ([]dto.ScheduledJobRunsResponse, error) {
+	if _, ok := job_serv.tasks[jobName]; !ok {
+		return nil, errors.New("scheduled job not found")
+	}
+
+	runs, err := job_serv.jobRunsRepo.GetScheduledJobRuns(ctx, nil, jobName, SCHEDULED_JOB_RUNS_LIMIT)
+	if err != nil {
+		return nil, errors.New("failed to get scheduled job runs")
+	}
+
+	responses := make([]dto.ScheduledJobRunsResponse, 0, len(runs))
+	for _, run := range runs {
+		responses = append(responses, toScheduledJobRunResponse(run))
+	}
+
+	return responses, nil
+}
+
+type jobTriggerMessage struct {
+	JobName     string `json:"job_name"`
+	TriggeredBy string `json:"triggered_by"`
+}
+
+// TriggerJob hands the job to the worker, its run shows up in the history once a worker picked it up
+func (job_serv *scheduledJobsService) TriggerJob(ctx context.Context, token, jobName string) error {
+	userData, err := helper.VerifyToken(token[7:])
+	if err != nil {
+		return errors.New("invalid token")
+	}
+
+	if _, ok := job_serv.tasks[jobName]; !ok {
+		return errors.New("scheduled job not found")
+	}
+
+	tx, err := job_serv.txManager.Begin(ctx)
+	if err != nil {
+		return errors.New("failed to create transaction")
+	}
+
+	defer func() {
+		// Rollback otomatis jika transaksi belum di-commit
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err = job_serv.outboxServ.Enqueue(ctx, tx, queue.SCHEDULED_JOB_EXCHANGE, queue.SCHEDULED_JOB_ROUTING_KEY, jobTriggerMessage{JobName: jobName, TriggeredBy: userData.ID}); err != nil {
+		return errors.New("failed to trigger scheduled job")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.New("failed to commit transaction")
+	}
+
+	return nil
+}
+
+// RunScheduledJob runs the job for a slot of its schedule, every worker fires the schedule but only the
+// first one to claim the slot runs it
+func (job_serv *scheduledJobsService) RunScheduledJob(ctx context.Context, jobName string, scheduledAt time.Time) error {
+	return job_serv.runJob(ctx, entity.ScheduledJobRuns{
+		JobName:     jobName,
+		Trigger:     data.SCHEDULED_JOB_TRIGGER_SCHEDULE,
+		ScheduledAt: &scheduledAt,
+	})
+}
+
+// ProcessJobTrigger runs a job triggered by an admin, a failed run is recorded and not retried
+func (job_serv *scheduledJobsService) ProcessJobTrigger(ctx context.Context, delivery amqp091.Delivery) error {
+	var message jobTriggerMessage
+	if err := json.Unmarshal(delivery.Body, &message); err != nil {
+		return queue.Permanent(fmt.Errorf("failed to unmarshal job trigger: %w", err))
+	}
+	if _, ok := job_serv.tasks[message.JobName]; !ok {
+		return queue.Permanent(fmt.Errorf("scheduled job %s does not exist", message.JobName))
+	}
+
+	run := entity.ScheduledJobRuns{
+		JobName: message.JobName,
+		Trigger: data.SCHEDULED_JOB_TRIGGER_MANUAL,
+	}
+	if triggeredBy, err := uuid.Parse(message.TriggeredBy); err == nil {
+		run.TriggeredBy = &triggeredBy
+	}
+
+	var taskErr *scheduledJobError
+	if err := job_serv.runJob(ctx, run); err != nil {
+		if errors.As(err, &taskErr) {
+			return queue.Permanent(err)
+		}
+		return err
+	}
+	return nil
+}
+
+// scheduledJobError is the failure of the job itself, as opposed to failing to lock or record the run
+type scheduledJobError struct {
+	err error
+}
+
+func (e *scheduledJobError) Error() string { return e.err.Error() }
+func (e *scheduledJobError) Unwrap() error { return e.err }
+
+// runJob runs the job while holding its advisory lock so a job never runs twice at the same time
+func (job_serv *scheduledJobsService) runJob(ctx context.Context, run entity.ScheduledJobRuns) error {
+	task, ok := job_serv.tasks[run.JobName]
+	if !ok {
+		return fmt.Errorf("scheduled job %s does not exist", run.JobName)
+	}
+
+	// The transaction only holds the lock, ending it releases the lock
+	tx, err := job_serv.txManager.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	locked, err := job_serv.jobRunsRepo.TryLockScheduledJob(ctx, tx, run.JobName)
+	if err != nil {
+		return fmt.Errorf("failed to lock scheduled job %s: %w", run.JobName, err)
+	}
+
+	now := time.Now()
+	run.StartedAt = &now
+	run.Status = data.SCHEDULED_JOB_STATUS_RUNNING
+
+	if !locked {
+		if run.Trigger == data.SCHEDULED_JOB_TRIGGER_SCHEDULE {
+			log.Info(fmt.Sprintf("scheduled job %s is running on another worker, skipping", run.JobName))
+			return nil
+		}
+
+		// Runs triggered by hand are recorded so the admin sees why nothing happened
+		reason := "job is already running"
+		run.Status = data.SCHEDULED_JOB_STATUS_SKIPPED
+		run.FinishedAt = &now
+		run.Error = &reason
+		if _, err := job_serv.jobRunsRepo.CreateScheduledJobRun(ctx, nil, run); err != nil {
+			return fmt.Errorf("failed to record run of scheduled job %s: %w", run.JobName, err)
+		}
+		return nil
+	}
+
+	// Runs are stored outside the lock transaction so they are visible while the job runs
+	var created entity.ScheduledJobRuns
+	if run.Trigger == data.SCHEDULED_JOB_TRIGGER_SCHEDULE {
+		var claimed bool
+		if created, claimed, err = job_serv.jobRunsRepo.ClaimScheduledJobRun(ctx, nil, run); err != nil {
+			return fmt.Errorf("failed to record run of scheduled job %s: %w", run.JobName, err)
+		}
+		if !claimed {
+			log.Info(fmt.Sprintf("scheduled job %s already ran for %s, skipping", run.JobName, run.ScheduledAt.Format(time.RFC3339)))
+			return nil
+		}
+	} else if created, err = job_serv.jobRunsRepo.CreateScheduledJobRun(ctx, nil, run); err != nil {
+		return fmt.Errorf("failed to record run of scheduled job %s: %w", run.JobName, err)
+	}
+	run = created
+
+	taskErr := runTask(ctx, task)
+
+	// The result is recorded even when the worker is shutting down
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.Status = data.SCHEDULED_JOB_STATUS_SUCCEEDED
+	if taskErr != nil {
+		message := taskErr.Error()
+		run.Status = data.SCHEDULED_JOB_STATUS_FAILED
+		run.Error = &message
+	}
+	if _, err := job_serv.jobRunsRepo.UpdateScheduledJobRun(context.WithoutCancel(ctx), nil, run); err != nil {
+		log.Error(fmt.Sprintf("failed to record result of scheduled job %s: %v", run.JobName, err))
+	}
+
+	if taskErr != nil {
+		return &scheduledJobError{fmt.Errorf("scheduled job %s failed: %w", run.JobName, taskErr)}
+	}
+	return nil
+}
+
+// runTask turns a panic of the task into a failed run
+func runTask(ctx context.Context, task func(ctx context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+
+	return task(ctx)
+}
+
+// refreshSummaries rebuilds the summaries of the months the dashboard shows, repairing totals of events that
+// were lost or of changes made to the database by hand
+func (job_serv *scheduledJobsService) refreshSummaries(ctx context.Context) error {
+	now := time.Now()
+	from := startOfMonth(now).AddDate(0, -SUMMARY_REBUILD_MONTHS+1, 0)
+	return job_serv.summaryServ.RebuildSummaries(ctx, from, startOfMonth(now).AddDate(0, 1, 0))
+}
+
+func toScheduledJobRunResponse(run entity.ScheduledJobRuns) dto.ScheduledJobRunsResponse {
+	response := dto.ScheduledJobRunsResponse{
+		ID:          run.ID.String(),
+		JobName:     run.JobName,
+		Trigger:     run.Trigger,
+		Status:      run.Status,
+		ScheduledAt: run.ScheduledAt,
+		StartedAt:   run.StartedAt,
+		FinishedAt:  run.FinishedAt,
+		Error:       run.Error,
+		CreatedAt:   run.CreatedAt,
+	}
+	if run.TriggeredBy != nil {
+		triggeredBy := run.TriggeredBy.String()
+		response.TriggeredBy = &triggeredBy
+	}
+	return response
+}
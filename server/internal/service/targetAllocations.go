@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"server/internal/repository"
+	"server/internal/types/dto"
+	"server/internal/types/entity"
+	helper "server/internal/utils"
+	"server/internal/utils/portfolio"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DEFAULT_TOLERANCE_PERCENT = 5.0
+	CASH_ALLOCATION_CLASS     = "Cash"
+)
+
+type TargetAllocationsService interface {
+	GetTargetAllocations(ctx context.Context, token string) ([]dto.TargetAllocationsResponse, error)
+	SetTargetAllocations(ctx context.Context, token string, targets []dto.TargetAllocationsRequest) ([]dto.TargetAllocationsResponse, error)
+	GetRebalance(ctx context.Context, token string) (dto.RebalanceResponse, error)
+}
+
+type targetAllocationsService struct {
+	txManager                   repository.TxManager
+	targetAllocationsRepository repository.TargetAllocationsRepository
+	investmentTypesRepository   repository.InvestmentTypesRepository
+}
+
+func NewTargetAllocationsService(txManager repository.TxManager, targetAllocationsRepository repository.TargetAllocationsRepository, investmentTypesRepository repository.InvestmentTypesRepository) TargetAllocationsService {
+	return &targetAllocationsService{
+		txManager:                   txManager,
+		targetAllocationsRepository: targetAllocationsRepository,
+		investmentTypesRepository:   investmentTypesRepository,
+	}
+}
+
+func (allocation_serv *targetAllocationsService) GetTargetAllocations(ctx context.Context, token string) ([]dto.TargetAllocationsResponse, error) {
+	userData, err := helper.VerifyToken(token[7:])
+	if err != nil {
+		return nil, errors.New("invalid token")
+	}
+
+	targets, err := allocation_serv.targetAllocationsRepository.GetTargetAllocationsByUserID(ctx, nil, userData.ID)
+	if err != nil {
+		return nil, errors.New("failed to get target allocations")
+	}
+
+	targetsResponse := make([]dto.TargetAllocationsResponse, 0, len(targets))
+	for _, target := range targets {
+		targetsResponse = append(targetsResponse, toTargetAllocationResponse(target))
+	}
+
+	return targetsResponse, nil
+}
+
+// SetTargetAllocations replaces the target allocation of the user, the targets must add up to 100%
+func (allocation_serv *targetAllocationsService) SetTargetAllocations(ctx context.Context, token string, targets []dto.TargetAllocationsRequest) ([]dto.TargetAllocationsResponse, error) {
+	userData, err := helper.VerifyToken(token[7:])
+	if err != nil {
+		return nil, errors.New("invalid token")
+	}
+
+	userID, err := helper.ParseUUID(userData.ID)
+	if err != nil {
+		return nil, errors.New("invalid user id")
+	}
+
+	tx, err := allocation_serv.txManager.Begin(ctx)
+	if err != nil {
+		return nil, errors.New("failed to create transaction")
+	}
+
+	defer func() {
+		// Rollback otomatis jika transaksi belum di-commit
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var total float64
+	seen := make(map[string]bool)
+	newTargets := make([]entity.TargetAllocations, 0, len(targets))
+	for _, target := range targets {
+		if target.TargetPercent < 0 || target.TargetPercent > 100 {
+			err = errors.New("target percent must be between 0 and 100")
+			return nil, err
+		}
+
+		tolerance := DEFAULT_TOLERANCE_PERCENT
+		if target.TolerancePercent != nil {
+			tolerance = *target.TolerancePercent
+		}
+		if tolerance < 0 || tolerance > 100 {
+			err = errors.New("tolerance percent must be between 0 and 100")
+			return nil, err
+		}
+
+		newTarget := entity.TargetAllocations{
+			UserID:           userID,
+			TargetPercent:    target.TargetPercent,
+			TolerancePercent: tolerance,
+		}
+
+		key := CASH_ALLOCATION_CLASS
+		if target.InvestmentTypeID != nil && *target.InvestmentTypeID != "" {
+			if _, err = allocation_serv.investmentTypesRepository.GetInvestmentTypeByID(ctx, tx, *target.InvestmentTypeID); err != nil {
+				err = errors.New("investment type not found")
+				return nil, err
+			}
+			var investmentTypeID uuid.UUID
+			if investmentTypeID, err = helper.ParseUUID(*target.InvestmentTypeID); err != nil {
+				err = errors.New("invalid investment type id")
+				return nil, err
+			}
+			newTarget.InvestmentTypeID = &investmentTypeID
+			key = investmentTypeID.String()
+		}
+
+		if seen[key] {
+			err = errors.New("every asset class can only have one target")
+			return nil, err
+		}
+		seen[key] = true
+
+		total += target.TargetPercent
+		newTargets = append(newTargets, newTarget)
+	}
+
+	// * An empty list clears the target allocation
+	if len(newTargets) > 0 && math.Abs(total-100) > 0.01 {
+		err = errors.New("target percentages must add up to 100")
+		return nil, err
+	}
+
+	if _, err = allocation_serv.targetAllocationsRepository.ReplaceTargetAllocations(ctx, tx, userData.ID, newTargets); err != nil {
+		return nil, errors.New("failed to save target allocations")
+	}
+
+	savedTargets, err := allocation_serv.targetAllocationsRepository.GetTargetAllocationsByUserID(ctx, tx, userData.ID)
+	if err != nil {
+		return nil, errors.New("failed to get target allocations")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.New("failed to commit transaction")
+	}
+
+	targetsResponse := make([]dto.TargetAllocationsResponse, 0, len(savedTargets))
+	for _, target := range savedTargets {
+		targetsResponse = append(targetsResponse, toTargetAllocationResponse(target))
+	}
+
+	return targetsResponse, nil
+}
+
+// GetRebalance compares the current holdings and wallet balances with the target allocation of the user
+func (allocation_serv *targetAllocationsService) GetRebalance(ctx context.Context, token string) (dto.RebalanceResponse, error) {
+	userData, err := helper.VerifyToken(token[7:])
+	if err != nil {
+		return dto.RebalanceResponse{}, errors.New("invalid token")
+	}
+
+	allocations, err := allocation_serv.targetAllocationsRepository.GetUserAllocations(ctx, nil, userData.ID)
+	if err != nil {
+		return dto.RebalanceResponse{}, errors.New("failed to get allocations")
+	}
+	if len(allocations) == 0 {
+		return dto.RebalanceResponse{}, errors.New("target allocation is not set")
+	}
+
+	classes := make([]portfolio.AllocationClass, 0, len(allocations))
+	for _, allocation := range allocations {
+		classes = append(classes, portfolio.AllocationClass{
+			Key:              allocation.InvestmentType,
+			Value:            allocation.CurrentValue,
+			TargetPercent:    allocation.TargetPercent,
+			TolerancePercent: allocation.TolerancePercent,
+		})
+	}
+
+	results, needed := portfolio.Rebalance(classes)
+
+	response := dto.RebalanceResponse{
+		RebalanceNeeded: needed,
+		Classes:         make([]dto.AllocationClassResponse, 0, len(results)),
+	}
+	for i, result := range results {
+		response.TotalValue += result.Value
+		response.Classes = append(response.Classes, dto.AllocationClassResponse{
+			InvestmentTypeID: allocations[i].InvestmentTypeID,
+			InvestmentType:   allocations[i].InvestmentType,
+			CurrentValue:     result.Value,
+			CurrentPercent:   result.CurrentPercent,
+			TargetPercent:    result.TargetPercent,
+			TolerancePercent: result.TolerancePercent,
+			DriftPercent:     result.DriftPercent,
+			WithinBand:       result.WithinBand,
+			SuggestedAmount:  result.Suggested,
+		})
+	}
+
+	return response, nil
+}
+
+func toTargetAllocationResponse(target entity.TargetAllocations) dto.TargetAllocationsResponse {
+	response := dto.TargetAllocationsResponse{
+		ID:               target.ID.String(),
+		InvestmentType:   CASH_ALLOCATION_CLASS,
+		TargetPercent:    target.TargetPercent,
+		TolerancePercent: target.TolerancePercent,
+	}
+	if target.InvestmentTypeID != nil {
+		investmentTypeID := target.InvestmentTypeID.String()
+		response.InvestmentTypeID = &investmentTypeID
+	}
+	if target.InvestmentType != nil {
+		response.InvestmentType = target.InvestmentType.Name
+	}
+	return response
+}
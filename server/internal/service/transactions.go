@@ -5,15 +5,12 @@ import (
 	"errors"
 	"fmt"
 
-	"server/config/log"
-	"server/config/miniofs"
 	"server/internal/repository"
 	"server/internal/types/dto"
 	"server/internal/types/entity"
 	"server/internal/types/view"
 	helper "server/internal/utils"
-
-	"github.com/google/uuid"
+	"server/internal/utils/data"
 )
 
 type TransactionsService interface {
@@ -37,17 +34,19 @@ type transactionsService struct {
 	walletRepo      repository.WalletsRepository
 	categoryRepo    repository.CategoriesRepository
 	attachmentRepo  repository.AttachmentsRepository
-	minio           *miniofs.MinIOManager
+	attachmentServ  AttachmentsService
+	outboxServ      OutboxService
 }
 
-func NewTransactionService(txManager repository.TxManager, transactionRepo repository.TransactionsRepository, walletRepo repository.WalletsRepository, categoryRepo repository.CategoriesRepository, attachmentRepo repository.AttachmentsRepository, minio *miniofs.MinIOManager) TransactionsService {
+func NewTransactionService(txManager repository.TxManager, transactionRepo repository.TransactionsRepository, walletRepo repository.WalletsRepository, categoryRepo repository.CategoriesRepository, attachmentRepo repository.AttachmentsRepository, attachmentServ AttachmentsService, outboxServ OutboxService) TransactionsService {
 	return &transactionsService{
 		txManager:       txManager,
 		transactionRepo: transactionRepo,
 		walletRepo:      walletRepo,
 		categoryRepo:    categoryRepo,
 		attachmentRepo:  attachmentRepo,
-		minio:           minio,
+		attachmentServ:  attachmentServ,
+		outboxServ:      outboxServ,
 	}
 }
 
@@ -66,7 +65,7 @@ func (transaction_serv *transactionsService) GetTransactionByID(ctx context.Cont
 		return view.ViewUserTransactions{}, errors.New("transaction not found")
 	}
 
-	attachments, err := transaction_serv.attachmentRepo.GetAttachmentsByTransactionID(ctx, nil, transaction.ID)
+	attachments, err := transaction_serv.attachmentRepo.GetAttachmentsByOwner(ctx, nil, data.ATTACHMENT_OWNER_TRANSACTION, transaction.ID)
 	if err != nil {
 		return view.ViewUserTransactions{}, errors.New("failed to get attachments")
 	}
@@ -76,10 +75,13 @@ func (transaction_serv *transactionsService) GetTransactionByID(ctx context.Cont
 			if attachment.Image != "" {
 				result := view.Attachment{
 					ID:            attachment.ID.String(),
-					TransactionID: attachment.TransactionID.String(),
+					TransactionID: attachment.OwnerID.String(),
 					Image:         attachment.Image,
 					Format:        attachment.Format,
 					Size:          attachment.Size,
+
+					ThumbnailSmall:  attachment.ThumbnailSmall,
+					ThumbnailMedium: attachment.ThumbnailMedium,
 				}
 				transaction.Attachments = append(transaction.Attachments, result)
 			}
@@ -111,10 +113,12 @@ func (transaction_serv *transactionsService) CreateTransaction(ctx context.Conte
 		return dto.TransactionsResponse{}, errors.New("failed to create transaction")
 	}
 
+	var uploaded []*entity.AttachmentBlobs
 	defer func() {
 		// Rollback otomatis jika transaksi belum di-commit
 		if r := recover(); r != nil || err != nil {
 			tx.Rollback()
+			transaction_serv.attachmentServ.PurgeBlobs(ctx, uploaded)
 		}
 	}()
 
@@ -173,17 +177,25 @@ func (transaction_serv *transactionsService) CreateTransaction(ctx context.Conte
 		for _, attachment := range transaction.Attachments {
 			// * Create new attachment
 			if len(attachment.Files) == 0 {
-				return dto.TransactionsResponse{}, errors.New("no files to upload")
+				err = errors.New("no files to upload")
+				return dto.TransactionsResponse{}, err
 			}
 
-			if _, err := transaction_serv.UploadAttachment(ctx, transactionNew.ID.String(), attachment.Files); err != nil {
+			var attached []*entity.AttachmentBlobs
+			_, attached, err = transaction_serv.attachmentServ.AttachFiles(ctx, tx, data.ATTACHMENT_OWNER_TRANSACTION, transactionNew.ID.String(), attachment.Files)
+			uploaded = append(uploaded, attached...)
+			if err != nil {
 				return dto.TransactionsResponse{}, fmt.Errorf("failed to upload attachment: %w", err)
 			}
 		}
 	}
 
+	if err = enqueueEvent(ctx, tx, transaction_serv.outboxServ, data.EVENT_TRANSACTION_CREATED, wallet.UserID.String(), transactionNew.ID.String(), nil, toTransactionEventPayload(transactionNew)); err != nil {
+		return dto.TransactionsResponse{}, errors.New("failed to store transaction event")
+	}
+
 	// Commit transaksi jika semua sukses
-	if err := tx.Commit(); err != nil {
+	if err = tx.Commit(); err != nil {
 		return dto.TransactionsResponse{}, errors.New("failed to commit transaction")
 	}
 
@@ -275,6 +287,25 @@ func (transaction_serv *transactionsService) FundTransfer(ctx context.Context, t
 		return dto.FundTransferResponse{}, errors.New("failed to create to transaction")
 	}
 
+	// Both legs are regular transactions, the transfer event ties them together
+	userID := fromWallet.UserID.String()
+	for _, leg := range []entity.Transactions{transactionNewFrom, transactionNewTo} {
+		if err = enqueueEvent(ctx, tx, transaction_serv.outboxServ, data.EVENT_TRANSACTION_CREATED, userID, leg.ID.String(), nil, toTransactionEventPayload(leg)); err != nil {
+			return dto.FundTransferResponse{}, errors.New("failed to store transaction event")
+		}
+	}
+	if err = enqueueEvent(ctx, tx, transaction_serv.outboxServ, data.EVENT_TRANSFER_CREATED, userID, transactionNewFrom.ID.String(), nil, &dto.TransferEventPayload{
+		CashOutTransactionID: transactionNewFrom.ID.String(),
+		CashInTransactionID:  transactionNewTo.ID.String(),
+		FromWalletID:         transaction.FromWalletID,
+		ToWalletID:           transaction.ToWalletID,
+		Amount:               transaction.Amount,
+		AdminFee:             transaction.AdminFee,
+		Date:                 transaction.Date,
+	}); err != nil {
+		return dto.FundTransferResponse{}, errors.New("failed to store transfer event")
+	}
+
 	if err := tx.Commit(); err != nil {
 		return dto.FundTransferResponse{}, errors.New("failed to commit transaction")
 	}
@@ -293,65 +324,30 @@ func (transaction_serv *transactionsService) FundTransfer(ctx context.Context, t
 }
 
 func (transaction_serv *transactionsService) UploadAttachment(ctx context.Context, transactionID string, files []string) ([]dto.AttachmentsResponse, error) {
-	var attachmentResponses []dto.AttachmentsResponse
-
-	if transactionID == "" {
-		log.Error("transaction ID is required")
-		return nil, errors.New("transaction ID is required")
-	}
-	if len(files) == 0 {
-		log.Error("no files to upload")
-		return nil, errors.New("no files to upload")
+	tx, err := transaction_serv.txManager.Begin(ctx)
+	if err != nil {
+		return nil, errors.New("failed to create transaction")
 	}
 
-	for idx, file := range files {
-		if file == "" {
-			log.Error("file is empty")
-			return nil, errors.New("file is empty")
-		}
-
-		ctx := context.Background()
-		fileReq := miniofs.UploadRequest{
-			Prefix:     fmt.Sprintf("%s_%s", miniofs.TRANSACTION_ATTACHMENT_PREFIX, transactionID),
-			Base64Data: file,
-			BucketName: miniofs.TRANSACTION_ATTACHMENT_BUCKET,
-			Validation: miniofs.CreateImageValidationConfig(),
-		}
-		res, err := transaction_serv.minio.UploadFile(ctx, fileReq)
-		if err != nil {
-			log.Error(fmt.Sprintf("failed to upload file %d: %v", idx+1, err))
-			return nil, errors.New("failed to upload file")
-		}
-
-		// Save attachment to database
-		TransactionUUID, err := uuid.Parse(transactionID)
-		if err != nil {
-			log.Error(fmt.Sprintf("invalid transaction ID %s: %v", transactionID, err))
-			return nil, errors.New("invalid transaction id")
-		}
-
-		attachment, err := transaction_serv.attachmentRepo.CreateAttachment(ctx, nil, entity.Attachments{
-			Image:         res.URL,
-			TransactionID: TransactionUUID,
-			Size:          res.Size,
-			Format:        res.Ext,
-		})
-		if err != nil {
-			log.Error(fmt.Sprintf("failed to create attachment for transaction %s: %v", transactionID, err))
-			return nil, errors.New("failed to create attachment")
+	var uploaded []*entity.AttachmentBlobs
+	defer func() {
+		// Rollback otomatis jika transaksi belum di-commit
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+			transaction_serv.attachmentServ.PurgeBlobs(ctx, uploaded)
 		}
+	}()
 
-		attachmentResponse := dto.AttachmentsResponse{
-			ID:            attachment.ID.String(),
-			Image:         attachment.Image,
-			TransactionID: attachment.TransactionID.String(),
-			CreatedAt:     attachment.CreatedAt.String(),
-		}
+	attachmentsResponse, uploaded, err := transaction_serv.attachmentServ.AttachFiles(ctx, tx, data.ATTACHMENT_OWNER_TRANSACTION, transactionID, files)
+	if err != nil {
+		return nil, err
+	}
 
-		attachmentResponses = append(attachmentResponses, attachmentResponse)
+	if err = tx.Commit(); err != nil {
+		return nil, errors.New("failed to commit transaction")
 	}
 
-	return attachmentResponses, nil
+	return attachmentsResponse, nil
 }
 
 func (transaction_serv *transactionsService) UpdateTransaction(ctx context.Context, id string, transaction dto.TransactionsRequest) (dto.TransactionsResponse, error) {
@@ -361,11 +357,13 @@ func (transaction_serv *transactionsService) UpdateTransaction(ctx context.Conte
 		return dto.TransactionsResponse{}, errors.New("failed to create transaction")
 	}
 
-	// ! Defer rollback if there is an error
+	// ! Defer rollback if there is an error, files uploaded in the transaction are deleted with it
+	var uploaded []*entity.AttachmentBlobs
 	defer func() {
 		// Rollback otomatis jika transaksi belum di-commit
 		if r := recover(); r != nil || err != nil {
 			tx.Rollback()
+			transaction_serv.attachmentServ.PurgeBlobs(ctx, uploaded)
 		}
 	}()
 
@@ -374,6 +372,7 @@ func (transaction_serv *transactionsService) UpdateTransaction(ctx context.Conte
 	if err != nil {
 		return dto.TransactionsResponse{}, errors.New("transaction not found")
 	}
+	before := toTransactionEventPayload(transactionExist)
 
 	// ? If category ID is different, update category
 	if transaction.CategoryID != transactionExist.CategoryID.String() {
@@ -486,54 +485,58 @@ func (transaction_serv *transactionsService) UpdateTransaction(ctx context.Conte
 	}
 
 	// ? If attachments exist, update attachments
+	var orphanBlobs []*entity.AttachmentBlobs
 	if len(transaction.Attachments) > 0 {
 		for _, attachment := range transaction.Attachments {
 			switch attachment.Status {
 			case "create":
 				// * Create new attachment
 				if len(attachment.Files) == 0 {
-					return dto.TransactionsResponse{}, errors.New("no files to upload")
+					err = errors.New("no files to upload")
+					return dto.TransactionsResponse{}, err
 				}
 
-				if _, err := transaction_serv.UploadAttachment(ctx, transactionUpdated.ID.String(), attachment.Files); err != nil {
+				var attached []*entity.AttachmentBlobs
+				_, attached, err = transaction_serv.attachmentServ.AttachFiles(ctx, tx, data.ATTACHMENT_OWNER_TRANSACTION, transactionUpdated.ID.String(), attachment.Files)
+				uploaded = append(uploaded, attached...)
+				if err != nil {
 					return dto.TransactionsResponse{}, fmt.Errorf("failed to upload attachment: %w", err)
 				}
 
 			case "delete":
 				// * Delete attachment
 				if len(attachment.Files) == 0 {
-					return dto.TransactionsResponse{}, errors.New("no files to delete")
+					err = errors.New("no files to delete")
+					return dto.TransactionsResponse{}, err
 				}
 
-				for _, ID := range attachment.Files {
-					// * Get attachment by ID
-					attachmentToDelete, err := transaction_serv.attachmentRepo.GetAttachmentByID(ctx, tx, ID)
-					if err != nil {
-						return dto.TransactionsResponse{}, fmt.Errorf("attachment with file %s not found: %w", ID, err)
-					}
-
-					// * Check if attachment belongs to transaction
-					if attachmentToDelete.TransactionID != transactionUpdated.ID {
-						return dto.TransactionsResponse{}, fmt.Errorf("attachment with file %s does not belong to transaction %s", ID, transactionUpdated.ID)
-					}
-
-					// * Delete file from database
-					if _, err := transaction_serv.attachmentRepo.DeleteAttachment(ctx, tx, attachmentToDelete); err != nil {
-						return dto.TransactionsResponse{}, fmt.Errorf("attachment with file %v not found: %w", attachmentToDelete, err)
-					}
+				// * Release the shared files, they are only deleted with their last reference
+				var orphans []*entity.AttachmentBlobs
+				orphans, err = transaction_serv.attachmentServ.DetachFiles(ctx, tx, data.ATTACHMENT_OWNER_TRANSACTION, transactionUpdated.ID.String(), attachment.Files)
+				if err != nil {
+					return dto.TransactionsResponse{}, err
 				}
+				orphanBlobs = append(orphanBlobs, orphans...)
 
 			default:
-				return dto.TransactionsResponse{}, errors.New("invalid attachment status")
+				err = errors.New("invalid attachment status")
+				return dto.TransactionsResponse{}, err
 			}
 		}
 	}
 
+	// ? Store the event with the change
+	if err = enqueueEvent(ctx, tx, transaction_serv.outboxServ, data.EVENT_TRANSACTION_UPDATED, transactionExist.Wallet.UserID.String(), transactionUpdated.ID.String(), before, toTransactionEventPayload(transactionUpdated)); err != nil {
+		return dto.TransactionsResponse{}, errors.New("failed to store transaction event")
+	}
+
 	// ! Commit transaction if all operations are successful
 	if err = tx.Commit(); err != nil {
 		return dto.TransactionsResponse{}, errors.New("failed to commit transaction")
 	}
 
+	transaction_serv.attachmentServ.PurgeBlobs(ctx, orphanBlobs)
+
 	transactionResponse := helper.ConvertToResponseType(transactionUpdated).(dto.TransactionsResponse)
 
 	return transactionResponse, nil
@@ -584,11 +587,23 @@ func (transaction_serv *transactionsService) DeleteTransaction(ctx context.Conte
 		return dto.TransactionsResponse{}, errors.New("failed to delete transaction")
 	}
 
+	// * Release the files of the transaction, they are only deleted with their last reference
+	orphanBlobs, err := transaction_serv.attachmentServ.DetachOwner(ctx, tx, data.ATTACHMENT_OWNER_TRANSACTION, transactionExist.ID.String())
+	if err != nil {
+		return dto.TransactionsResponse{}, errors.New("failed to delete transaction attachments")
+	}
+
+	if err = enqueueEvent(ctx, tx, transaction_serv.outboxServ, data.EVENT_TRANSACTION_DELETED, transactionExist.Wallet.UserID.String(), transactionExist.ID.String(), toTransactionEventPayload(transactionExist), nil); err != nil {
+		return dto.TransactionsResponse{}, errors.New("failed to store transaction event")
+	}
+
 	// Commit transaksi jika semua sukses
-	if err := tx.Commit(); err != nil {
+	if err = tx.Commit(); err != nil {
 		return dto.TransactionsResponse{}, errors.New("failed to commit transaction")
 	}
 
+	transaction_serv.attachmentServ.PurgeBlobs(ctx, orphanBlobs)
+
 	transactionResponse := helper.ConvertToResponseType(transactionDeleted).(dto.TransactionsResponse)
 
 	return transactionResponse, nil
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"server/config/queue"
+	"server/internal/repository"
+	"server/internal/types/dto"
+
+	"github.com/google/uuid"
+)
+
+// UserSummariesService keeps the tables behind the dashboard views up to date, transaction events recompute
+// the months they touch and the scheduled rebuild repairs whatever events missed
+type UserSummariesService interface {
+	ApplyTransactionEvent(ctx context.Context, event dto.Event) error
+	RebuildSummaries(ctx context.Context, from, to time.Time) error
+}
+
+type userSummariesService struct {
+	txManager   repository.TxManager
+	summaryRepo repository.UserSummariesRepository
+}
+
+func NewUserSummariesService(txManager repository.TxManager, summaryRepo repository.UserSummariesRepository) UserSummariesService {
+	return &userSummariesService{
+		txManager:   txManager,
+		summaryRepo: summaryRepo,
+	}
+}
+
+// ApplyTransactionEvent recomputes the months of the transaction before and after the change. The months are
+// recomputed from the transactions instead of adding the amounts of the event, so events that arrive twice or
+// out of order still give the right totals.
+func (summary_serv *userSummariesService) ApplyTransactionEvent(ctx context.Context, event dto.Event) error {
+	if _, err := uuid.Parse(event.UserID); err != nil {
+		return queue.Permanent(fmt.Errorf("invalid user id %q of event %s", event.UserID, event.ID))
+	}
+
+	months := map[time.Time]struct{}{}
+	for _, raw := range []json.RawMessage{event.Before, event.After} {
+		if len(raw) == 0 || string(raw) == "null" {
+			continue
+		}
+
+		var payload dto.TransactionEventPayload
+		if err := json.Unmarshal(raw, &payload); err != nil {
+			return queue.Permanent(fmt.Errorf("failed to unmarshal transaction of event %s: %w", event.ID, err))
+		}
+		months[startOfMonth(payload.TransactionDate)] = struct{}{}
+	}
+
+	sorted := make([]time.Time, 0, len(months))
+	for month := range months {
+		sorted = append(sorted, month)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+
+	for _, month := range sorted {
+		if err := summary_serv.recompute(ctx, &event.UserID, month, month.AddDate(0, 1, 0)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RebuildSummaries recomputes the months from the month of from until before to for every user
+func (summary_serv *userSummariesService) RebuildSummaries(ctx context.Context, from, to time.Time) error {
+	return summary_serv.recompute(ctx, nil, from, to)
+}
+
+func (summary_serv *userSummariesService) recompute(ctx context.Context, userID *string, from, to time.Time) (err error) {
+	tx, err := summary_serv.txManager.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create transaction: %w", err)
+	}
+
+	defer func() {
+		// Rollback otomatis jika transaksi belum di-commit
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err = summary_serv.summaryRepo.RecomputeUserSummaries(ctx, tx, userID, from, to); err != nil {
+		return fmt.Errorf("failed to recompute user summaries from %s: %w", from.Format("2006-01"), err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// startOfMonth keeps the location of the time, transaction dates are stored without a time zone
+func startOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
@@ -98,7 +98,7 @@ func (user_serv *usersService) Login(user dto.UsersRequest) (*string, error) {
 		return nil, errors.New("password is incorrect")
 	}
 
-	token, err := helper.GenerateToken(userExist.ID.String(), userExist.Name, userExist.Email)
+	token, err := helper.GenerateToken(userExist.ID.String(), userExist.Name, userExist.Email, userExist.Role)
 	if err != nil {
 		return nil, err
 	}
@@ -107,7 +107,7 @@ func (user_serv *usersService) Login(user dto.UsersRequest) (*string, error) {
 }
 
 func (user_serv *usersService) OAuthLogin(name string, email string) (*string, error) {
-	token, err := helper.GenerateToken("99", name, email)
+	token, err := helper.GenerateToken("99", name, email, string(entity.User))
 	if err != nil {
 		return nil, err
 	}
@@ -9,6 +9,7 @@ import (
 	"server/internal/types/dto"
 	"server/internal/types/entity"
 	"server/internal/types/view"
+	"server/internal/utils/data"
 )
 
 type WalletsService interface {
@@ -24,12 +25,16 @@ type WalletsService interface {
 type walletsService struct {
 	txManager         repository.TxManager
 	walletsRepository repository.WalletsRepository
+	attachmentServ    AttachmentsService
+	outboxServ        OutboxService
 }
 
-func NewWalletService(txManager repository.TxManager, walletsRepository repository.WalletsRepository) WalletsService {
+func NewWalletService(txManager repository.TxManager, walletsRepository repository.WalletsRepository, attachmentServ AttachmentsService, outboxServ OutboxService) WalletsService {
 	return &walletsService{
 		txManager:         txManager,
 		walletsRepository: walletsRepository,
+		attachmentServ:    attachmentServ,
+		outboxServ:        outboxServ,
 	}
 }
 
@@ -103,7 +108,19 @@ func (wallet_serv *walletsService) CreateWallet(ctx context.Context, token strin
 		return dto.WalletsResponse{}, errors.New("invalid wallet type id")
 	}
 
-	newWallet, err := wallet_serv.walletsRepository.CreateWallet(ctx, nil, entity.Wallets{
+	tx, err := wallet_serv.txManager.Begin(ctx)
+	if err != nil {
+		return dto.WalletsResponse{}, errors.New("failed to create transaction")
+	}
+
+	defer func() {
+		// Rollback otomatis jika transaksi belum di-commit
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	newWallet, err := wallet_serv.walletsRepository.CreateWallet(ctx, tx, entity.Wallets{
 		UserID:       UserID,
 		WalletTypeID: WalletTypeID,
 		Name:         wallet.Name,
@@ -114,42 +131,99 @@ func (wallet_serv *walletsService) CreateWallet(ctx context.Context, token strin
 		return dto.WalletsResponse{}, err
 	}
 
+	if err = enqueueEvent(ctx, tx, wallet_serv.outboxServ, data.EVENT_WALLET_CREATED, newWallet.UserID.String(), newWallet.ID.String(), nil, toWalletEventPayload(newWallet)); err != nil {
+		return dto.WalletsResponse{}, errors.New("failed to store wallet event")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return dto.WalletsResponse{}, errors.New("failed to commit transaction")
+	}
+
 	walletResponse := utils.ConvertToResponseType(newWallet).(dto.WalletsResponse)
 
 	return walletResponse, nil
 }
 
 func (wallet_serv *walletsService) UpdateWallet(ctx context.Context, id string, wallet dto.WalletsRequest) (dto.WalletsResponse, error) {
-	existingWallet, err := wallet_serv.walletsRepository.GetWalletByID(ctx, nil, id)
+	tx, err := wallet_serv.txManager.Begin(ctx)
+	if err != nil {
+		return dto.WalletsResponse{}, errors.New("failed to create transaction")
+	}
+
+	defer func() {
+		// Rollback otomatis jika transaksi belum di-commit
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	existingWallet, err := wallet_serv.walletsRepository.GetWalletByID(ctx, tx, id)
 	if err != nil {
 		return dto.WalletsResponse{}, errors.New("wallet not found")
 	}
+	before := toWalletEventPayload(existingWallet)
 
 	existingWallet.Name = wallet.Name
 	existingWallet.Number = wallet.Number
 	existingWallet.Balance = wallet.Balance
 
-	walletUpdated, err := wallet_serv.walletsRepository.UpdateWallet(ctx, nil, existingWallet)
+	walletUpdated, err := wallet_serv.walletsRepository.UpdateWallet(ctx, tx, existingWallet)
 	if err != nil {
 		return dto.WalletsResponse{}, err
 	}
 
+	if err = enqueueEvent(ctx, tx, wallet_serv.outboxServ, data.EVENT_WALLET_UPDATED, walletUpdated.UserID.String(), walletUpdated.ID.String(), before, toWalletEventPayload(walletUpdated)); err != nil {
+		return dto.WalletsResponse{}, errors.New("failed to store wallet event")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return dto.WalletsResponse{}, errors.New("failed to commit transaction")
+	}
+
 	walletResponse := utils.ConvertToResponseType(walletUpdated).(dto.WalletsResponse)
 
 	return walletResponse, nil
 }
 
 func (wallet_serv *walletsService) DeleteWallet(ctx context.Context, id string) (dto.WalletsResponse, error) {
-	existingWallet, err := wallet_serv.walletsRepository.GetWalletByID(ctx, nil, id)
+	tx, err := wallet_serv.txManager.Begin(ctx)
+	if err != nil {
+		return dto.WalletsResponse{}, errors.New("failed to create transaction")
+	}
+
+	defer func() {
+		// Rollback otomatis jika transaksi belum di-commit
+		if r := recover(); r != nil || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	existingWallet, err := wallet_serv.walletsRepository.GetWalletByID(ctx, tx, id)
 	if err != nil {
 		return dto.WalletsResponse{}, errors.New("wallet not found")
 	}
 
-	deletedWallet, err := wallet_serv.walletsRepository.DeleteWallet(ctx, nil, existingWallet)
+	deletedWallet, err := wallet_serv.walletsRepository.DeleteWallet(ctx, tx, existingWallet)
 	if err != nil {
 		return dto.WalletsResponse{}, err
 	}
 
+	// * Release the files of the wallet, they are only deleted with their last reference
+	orphanBlobs, err := wallet_serv.attachmentServ.DetachOwner(ctx, tx, data.ATTACHMENT_OWNER_WALLET, existingWallet.ID.String())
+	if err != nil {
+		return dto.WalletsResponse{}, errors.New("failed to delete wallet attachments")
+	}
+
+	if err = enqueueEvent(ctx, tx, wallet_serv.outboxServ, data.EVENT_WALLET_DELETED, existingWallet.UserID.String(), existingWallet.ID.String(), toWalletEventPayload(existingWallet), nil); err != nil {
+		return dto.WalletsResponse{}, errors.New("failed to store wallet event")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return dto.WalletsResponse{}, errors.New("failed to commit transaction")
+	}
+
+	wallet_serv.attachmentServ.PurgeBlobs(ctx, orphanBlobs)
+
 	walletResponse := utils.ConvertToResponseType(deletedWallet).(dto.WalletsResponse)
 
 	return walletResponse, nil
@@ -16,7 +16,7 @@ import (
 
 func TestGetAllWallets(t *testing.T) {
 	wallet_repo_mock := repository.NewWalletsRepositoryMock()
-	wallet_serv_test := NewWalletService(nil, wallet_repo_mock)
+	wallet_serv_test := NewWalletService(nil, wallet_repo_mock, nil, nil)
 
 	defer wallet_repo_mock.Mock.AssertExpectations(t)
 
@@ -74,7 +74,7 @@ func TestGetAllWallets(t *testing.T) {
 
 func TestGetWalletByID(t *testing.T) {
 	wallet_repo_mock := repository.NewWalletsRepositoryMock()
-	wallet_serv_test := NewWalletService(nil, wallet_repo_mock)
+	wallet_serv_test := NewWalletService(nil, wallet_repo_mock, nil, nil)
 
 	defer wallet_repo_mock.Mock.AssertExpectations(t)
 
@@ -0,0 +1,123 @@
+package dto
+
+import "time"
+
+type CashflowQuery struct {
+	From        time.Time `form:"from" time_format:"2006-01-02"` // defaults to the start of the month 11 months before to
+	To          time.Time `form:"to" time_format:"2006-01-02"`   // defaults to today
+	Granularity string    `form:"granularity"`                   // day, week, month or year, defaults to month
+	GroupBy     string    `form:"group_by"`                      // category, parent_category, wallet or wallet_type, empty for totals only
+	Compare     string    `form:"compare"`                       // previous_period or previous_year, empty for no comparison
+}
+
+// SeriesPeriod is a point of the series, Start and End are clipped to the requested range
+type SeriesPeriod struct {
+	Label string `json:"label"`
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// CashflowSeries holds one value per period of the response, in the order of its periods
+type CashflowSeries struct {
+	Key          string    `json:"key"`
+	Label        string    `json:"label"`
+	Income       []float64 `json:"income"`
+	Expense      []float64 `json:"expense"`
+	Net          []float64 `json:"net"`
+	TotalIncome  float64   `json:"total_income"`
+	TotalExpense float64   `json:"total_expense"`
+	TotalNet     float64   `json:"total_net"`
+}
+
+type CashflowChange struct {
+	IncomePercentage  *float64 `json:"income_percentage"` // nil when the previous value is 0
+	ExpensePercentage *float64 `json:"expense_percentage"`
+	NetPercentage     *float64 `json:"net_percentage"`
+}
+
+type CashflowResponse struct {
+	From        string           `json:"from"`
+	To          string           `json:"to"`
+	Granularity string           `json:"granularity"`
+	GroupBy     string           `json:"group_by"`
+	Periods     []SeriesPeriod   `json:"periods"`
+	Series      []CashflowSeries `json:"series"`
+	Total       CashflowSeries   `json:"total"`
+	Comparison  *CashflowCompare `json:"comparison,omitempty"`
+}
+
+// CashflowCompare is the cash flow of the range compared with, its periods line up with the periods of the
+// response by index, ranges of whole months have as many periods as the response. Changes compare the totals
+// of the range with the totals of the compared range.
+type CashflowCompare struct {
+	Type    string                    `json:"type"`
+	From    string                    `json:"from"`
+	To      string                    `json:"to"`
+	Periods []SeriesPeriod            `json:"periods"`
+	Series  []CashflowSeries          `json:"series"`
+	Total   CashflowSeries            `json:"total"`
+	Change  CashflowChange            `json:"change"`
+	Changes map[string]CashflowChange `json:"changes"` // by series key
+}
+
+type BalancesQuery struct {
+	From        time.Time `form:"from" time_format:"2006-01-02"` // defaults to 89 days before to
+	To          time.Time `form:"to" time_format:"2006-01-02"`   // defaults to today
+	Granularity string    `form:"granularity"`                   // day, week, month or year, defaults to day
+}
+
+// BalanceSeries holds the balance at the end of every period of the response, in the order of its periods
+type BalanceSeries struct {
+	Key        string    `json:"key"`
+	Label      string    `json:"label"`
+	WalletType string    `json:"wallet_type,omitempty"` // set on wallet series
+	Balances   []float64 `json:"balances"`
+}
+
+type BalancesResponse struct {
+	From        string          `json:"from"`
+	To          string          `json:"to"`
+	Granularity string          `json:"granularity"`
+	Periods     []SeriesPeriod  `json:"periods"`
+	Wallets     []BalanceSeries `json:"wallets"`
+	WalletTypes []BalanceSeries `json:"wallet_types"`
+	Total       BalanceSeries   `json:"total"`
+}
+
+type ForecastQuery struct {
+	Days int `form:"days"` // 30, 90 or 180, defaults to 30
+}
+
+// ForecastSeries holds the projected balance at the end of every date of the response, in the order of its dates
+type ForecastSeries struct {
+	Key           string    `json:"key"`
+	Label         string    `json:"label"`
+	WalletType    string    `json:"wallet_type,omitempty"` // set on wallet series
+	Balances      []float64 `json:"balances"`
+	NegativeDates []string  `json:"negative_dates"` // dates the balance is projected below 0
+}
+
+type ForecastItem struct {
+	WalletID string  `json:"wallet_id"`
+	Label    string  `json:"label"`
+	Date     string  `json:"date"`
+	Amount   float64 `json:"amount"`
+}
+
+type ForecastSpending struct {
+	WalletID   string  `json:"wallet_id"`
+	CategoryID string  `json:"category_id"`
+	Category   string  `json:"category"`
+	Daily      float64 `json:"daily"`
+}
+
+type ForecastResponse struct {
+	From      string             `json:"from"`
+	To        string             `json:"to"`
+	Days      int                `json:"days"`
+	Dates     []string           `json:"dates"`
+	Wallets   []ForecastSeries   `json:"wallets"`
+	Total     ForecastSeries     `json:"total"`
+	Scheduled []ForecastItem     `json:"scheduled"` // recurring items expected within the forecast
+	Spending  []ForecastSpending `json:"spending"`  // estimated discretionary spending per day
+}
@@ -2,13 +2,29 @@ package dto
 
 type AttachmentsResponse struct {
 	ID            string `json:"id"`
-	TransactionID string `json:"transaction_id"`
+	OwnerType     string `json:"owner_type"`
+	OwnerID       string `json:"owner_id"`
+	TransactionID string `json:"transaction_id,omitempty"` // kept for transaction attachments, use owner_id instead
 	Image         string `json:"image"`
+	Format        string `json:"format"`
 	CreatedAt     string `json:"created_at"`
+
+	ThumbnailSmall  string `json:"thumbnail_small"`
+	ThumbnailMedium string `json:"thumbnail_medium"`
+
+	Hash              string            `json:"hash"`
+	AlreadyAttachedTo []AttachmentOwner `json:"already_attached_to,omitempty"` // other resources of the user holding the same file
+}
+
+type AttachmentOwner struct {
+	OwnerType string `json:"owner_type"`
+	OwnerID   string `json:"owner_id"`
 }
 
 type AttachmentsRequest struct {
-	TransactionID string `json:"transaction_id"`
+	OwnerType     string `json:"owner_type"`
+	OwnerID       string `json:"owner_id"`
+	TransactionID string `json:"transaction_id"` // used when owner_type is empty
 	Image         string `json:"image"`
 }
 
@@ -0,0 +1,8 @@
+package dto
+
+// EmailMessage is an email queued for the email job, the body is rendered before it is queued
+type EmailMessage struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	HTML    string `json:"html"`
+}
@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Event is the envelope of every domain event, the schema is documented in docs/events.md.
+// Before is null for created events and After is null for deleted events.
+type Event struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	Version    int             `json:"version"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	UserID     string          `json:"user_id"`
+	EntityID   string          `json:"entity_id"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+}
+
+type TransactionEventPayload struct {
+	ID              string    `json:"id"`
+	WalletID        string    `json:"wallet_id"`
+	CategoryID      string    `json:"category_id"`
+	Amount          float64   `json:"amount"`
+	TransactionDate time.Time `json:"transaction_date"`
+	Description     string    `json:"description"`
+}
+
+type TransferEventPayload struct {
+	CashOutTransactionID string    `json:"cash_out_transaction_id"`
+	CashInTransactionID  string    `json:"cash_in_transaction_id"`
+	FromWalletID         string    `json:"from_wallet_id"`
+	ToWalletID           string    `json:"to_wallet_id"`
+	Amount               float64   `json:"amount"`
+	AdminFee             float64   `json:"admin_fee"`
+	Date                 time.Time `json:"date"`
+}
+
+type WalletEventPayload struct {
+	ID           string  `json:"id"`
+	UserID       string  `json:"user_id"`
+	WalletTypeID string  `json:"wallet_type_id"`
+	Name         string  `json:"name"`
+	Number       string  `json:"number"`
+	Balance      float64 `json:"balance"`
+}
+
+type InvestmentEventPayload struct {
+	ID               string    `json:"id"`
+	UserID           string    `json:"user_id"`
+	InvestmentTypeID string    `json:"investment_type_id"`
+	InstrumentID     string    `json:"instrument_id"`
+	Amount           float64   `json:"amount"`
+	Quantity         float64   `json:"quantity"`
+	CostMethod       string    `json:"cost_method"`
+	InvestmentDate   time.Time `json:"investment_date"`
+	Description      string    `json:"description"`
+	RealizedPnL      float64   `json:"realized_pnl"` // sales and standalone fees of the trades
+	Income           float64   `json:"income"`       // incomes received, net of tax
+}
@@ -0,0 +1,31 @@
+package dto
+
+type InstrumentsResponse struct {
+	ID               string `json:"id"`
+	InvestmentTypeID string `json:"investment_type_id,omitempty"`
+	InvestmentType   string `json:"investment_type,omitempty"`
+	Code             string `json:"code"`
+	Name             string `json:"name"`
+	Issuer           string `json:"issuer"`
+	Currency         string `json:"currency"`
+	Unit             string `json:"unit"`
+	RiskLevel        *int   `json:"risk_level"`
+	Kind             string `json:"kind"`
+}
+
+type InstrumentsRequest struct {
+	InvestmentTypeID string `json:"investment_type_id"`
+	Code             string `json:"code"`
+	Name             string `json:"name"`
+	Issuer           string `json:"issuer"`
+	Currency         string `json:"currency"` // ISO 4217, defaults to IDR
+	Unit             string `json:"unit"`
+	RiskLevel        *int   `json:"risk_level"` // 1 (lowest) to 5 (highest)
+	Kind             string `json:"kind"`
+}
+
+type InstrumentsQuery struct {
+	Search           string `form:"search"` // matches the code or the name
+	Kind             string `form:"kind"`
+	InvestmentTypeID string `form:"investment_type_id"`
+}
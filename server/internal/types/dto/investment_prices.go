@@ -0,0 +1,92 @@
+package dto
+
+import "time"
+
+type InvestmentPricesResponse struct {
+	ID           string    `json:"id"`
+	InstrumentID string    `json:"instrument_id"`
+	Instrument   string    `json:"instrument"` // instrument code
+	PriceDate    time.Time `json:"price_date"`
+	Price        float64   `json:"price"`
+	Source       string    `json:"source"`
+}
+
+type InvestmentPricesRequest struct {
+	Instrument string    `json:"instrument"` // instrument code
+	PriceDate  time.Time `json:"price_date"`
+	Price      float64   `json:"price"`
+}
+
+type InvestmentPricesImportResponse struct {
+	Imported int `json:"imported"`
+}
+
+type PositionValuationResponse struct {
+	InvestmentID   string     `json:"investment_id"`
+	InvestmentName string     `json:"investment_name"`
+	Instrument     string     `json:"instrument"`
+	Quantity       float64    `json:"quantity"`
+	CostBasis      float64    `json:"cost_basis"`
+	Price          float64    `json:"price"`
+	PriceDate      *time.Time `json:"price_date"`
+	PriceSource    string     `json:"price_source"` // price source, or "trade" when valued at the last trade price
+	MarketValue    float64    `json:"market_value"`
+	UnrealizedPnL  float64    `json:"unrealized_pnl"`
+}
+
+type PortfolioValuationResponse struct {
+	Date          time.Time                   `json:"date"`
+	CostBasis     float64                     `json:"cost_basis"`
+	MarketValue   float64                     `json:"market_value"`
+	UnrealizedPnL float64                     `json:"unrealized_pnl"`
+	Positions     []PositionValuationResponse `json:"positions"`
+}
+
+type PortfolioValuePoint struct {
+	Date        time.Time `json:"date"`
+	CostBasis   float64   `json:"cost_basis"`
+	MarketValue float64   `json:"market_value"`
+}
+
+type InvestmentPricesQuery struct {
+	Instrument string    `form:"instrument"`
+	From       time.Time `form:"from" time_format:"2006-01-02"`
+	To         time.Time `form:"to" time_format:"2006-01-02"`
+}
+
+type PortfolioHistoryQuery struct {
+	From     time.Time `form:"from" time_format:"2006-01-02"`
+	To       time.Time `form:"to" time_format:"2006-01-02"`
+	Interval string    `form:"interval"` // day, week or month
+}
+
+type PerformanceResponse struct {
+	From             time.Time `json:"from"`
+	To               time.Time `json:"to"`
+	StartValue       float64   `json:"start_value"`
+	EndValue         float64   `json:"end_value"`
+	NetContributions float64   `json:"net_contributions"` // buys and fees minus sell proceeds
+	Income           float64   `json:"income"`            // net dividends, coupons and interest
+	XIRR             *float64  `json:"xirr"`              // annualized money-weighted return in percent, nil when undefined
+	TWR              float64   `json:"twr"`               // cumulative time-weighted return in percent
+	AnnualizedTWR    *float64  `json:"annualized_twr"`    // nil for periods shorter than a year
+}
+
+type InvestmentPerformanceResponse struct {
+	InvestmentID   string `json:"investment_id"`
+	InvestmentName string `json:"investment_name"`
+	PerformanceResponse
+}
+
+type InvestmentTypePerformanceResponse struct {
+	InvestmentTypeID string `json:"investment_type_id"`
+	InvestmentType   string `json:"investment_type"`
+	PerformanceResponse
+}
+
+type PortfolioPerformanceResponse struct {
+	Period      string                              `json:"period"`
+	Portfolio   PerformanceResponse                 `json:"portfolio"`
+	Types       []InvestmentTypePerformanceResponse `json:"types"`
+	Investments []InvestmentPerformanceResponse     `json:"investments"`
+}
@@ -3,22 +3,121 @@ package dto
 import "time"
 
 type InvestmentsResponse struct {
-	ID               string    `json:"id"`
-	InvestmentTypeID string    `json:"investment_type_id"`
-	UserID           string    `json:"user_id"`
-	Name             string    `json:"name"`
-	Amount           float64   `json:"amount"`
-	Quantity         float64   `json:"quantity"`
-	InvestmentDate   time.Time `json:"investment_date"`
-	Description      string    `json:"description"`
+	ID               string     `json:"id"`
+	InvestmentTypeID string     `json:"investment_type_id"`
+	UserID           string     `json:"user_id"`
+	InstrumentID     string     `json:"instrument_id"`
+	Instrument       string     `json:"instrument"` // instrument code
+	Name             string     `json:"name"`       // instrument name
+	Amount           float64    `json:"amount"`
+	Quantity         float64    `json:"quantity"`
+	CostMethod       string     `json:"cost_method"`
+	InvestmentDate   time.Time  `json:"investment_date"`
+	Description      string     `json:"description"`
+	CouponRate       *float64   `json:"coupon_rate"`
+	CouponFrequency  *int       `json:"coupon_frequency"`
+	MaturityDate     *time.Time `json:"maturity_date"`
 }
 
 type InvestmentsRequest struct {
-	InvestmentTypeID string    `json:"investment_type_id"`
-	UserID           string    `json:"user_id"`
-	Name             string    `json:"name"`
-	Amount           float64   `json:"amount"`
-	Quantity         float64   `json:"quantity"`
-	InvestmentDate   time.Time `json:"investment_date"`
-	Description      string    `json:"description"`
+	InvestmentTypeID string     `json:"investment_type_id"` // optional, defaults to the type of the instrument
+	UserID           string     `json:"user_id"`
+	InstrumentID     string     `json:"instrument_id"`
+	Amount           float64    `json:"amount"`   // initial purchase, recorded as the first buy trade
+	Quantity         float64    `json:"quantity"` // initial purchase, recorded as the first buy trade
+	CostMethod       string     `json:"cost_method"`
+	InvestmentDate   time.Time  `json:"investment_date"`
+	Description      string     `json:"description"`
+	WalletID         string     `json:"wallet_id"`        // optional, wallet that pays for the initial purchase
+	CouponRate       *float64   `json:"coupon_rate"`      // annual rate in percent
+	CouponFrequency  *int       `json:"coupon_frequency"` // coupons per year
+	MaturityDate     *time.Time `json:"maturity_date"`
+}
+
+type InvestmentTradesResponse struct {
+	ID            string    `json:"id"`
+	InvestmentID  string    `json:"investment_id"`
+	Type          string    `json:"type"`
+	TradeDate     time.Time `json:"trade_date"`
+	Quantity      float64   `json:"quantity"`
+	Price         float64   `json:"price"`
+	Fee           float64   `json:"fee"`
+	Description   string    `json:"description"`
+	TransactionID string    `json:"transaction_id,omitempty"`
+}
+
+type InvestmentTradesRequest struct {
+	Type        string    `json:"type"`
+	TradeDate   time.Time `json:"trade_date"`
+	Quantity    float64   `json:"quantity"`
+	Price       float64   `json:"price"`
+	Fee         float64   `json:"fee"`
+	Description string    `json:"description"`
+	WalletID    string    `json:"wallet_id"` // optional, wallet that pays for a buy or fee or receives the proceeds of a sell
+}
+
+type InvestmentSaleResponse struct {
+	TradeID     string    `json:"trade_id"`
+	TradeDate   time.Time `json:"trade_date"`
+	Quantity    float64   `json:"quantity"`
+	Proceeds    float64   `json:"proceeds"`
+	CostBasis   float64   `json:"cost_basis"`
+	RealizedPnL float64   `json:"realized_pnl"`
+}
+
+type InvestmentPositionResponse struct {
+	InvestmentID  string                   `json:"investment_id"`
+	CostMethod    string                   `json:"cost_method"`
+	Quantity      float64                  `json:"quantity"`
+	CostBasis     float64                  `json:"cost_basis"`
+	AverageCost   float64                  `json:"average_cost"`
+	TotalFees     float64                  `json:"total_fees"`
+	RealizedPnL   float64                  `json:"realized_pnl"`
+	LatestPrice   float64                  `json:"latest_price"`
+	PriceDate     *time.Time               `json:"price_date"`
+	MarketValue   float64                  `json:"market_value"`
+	UnrealizedPnL float64                  `json:"unrealized_pnl"`
+	Sales         []InvestmentSaleResponse `json:"sales"`
+}
+
+type InvestmentIncomesResponse struct {
+	ID            string    `json:"id"`
+	InvestmentID  string    `json:"investment_id"`
+	Type          string    `json:"type"`
+	PaymentDate   time.Time `json:"payment_date"`
+	GrossAmount   float64   `json:"gross_amount"`
+	TaxAmount     float64   `json:"tax_amount"`
+	NetAmount     float64   `json:"net_amount"`
+	WalletID      string    `json:"wallet_id"`
+	TransactionID string    `json:"transaction_id,omitempty"`
+	Description   string    `json:"description"`
+}
+
+type InvestmentIncomesRequest struct {
+	Type        string    `json:"type"`
+	PaymentDate time.Time `json:"payment_date"`
+	GrossAmount float64   `json:"gross_amount"`
+	TaxAmount   float64   `json:"tax_amount"`
+	TaxRate     float64   `json:"tax_rate"` // percent of the gross amount, used when tax_amount is empty
+	WalletID    string    `json:"wallet_id"`
+	Description string    `json:"description"`
+}
+
+type InvestmentYieldResponse struct {
+	InvestmentID string    `json:"investment_id"`
+	From         time.Time `json:"from"`
+	To           time.Time `json:"to"`
+	GrossIncome  float64   `json:"gross_income"`
+	TaxWithheld  float64   `json:"tax_withheld"`
+	NetIncome    float64   `json:"net_income"`
+	PaymentCount int       `json:"payment_count"`
+	CostBasis    float64   `json:"cost_basis"`
+	YieldOnCost  float64   `json:"yield_on_cost"` // net trailing income / cost basis, in percent
+	MarketValue  float64   `json:"market_value"`
+	CurrentYield float64   `json:"current_yield"` // net trailing income / market value, in percent
+}
+
+type CouponPaymentResponse struct {
+	PaymentDate time.Time `json:"payment_date"`
+	GrossAmount float64   `json:"gross_amount"`
 }
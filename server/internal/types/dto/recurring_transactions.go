@@ -0,0 +1,26 @@
+package dto
+
+import "time"
+
+type RecurringTransactionsResponse struct {
+	ID          string     `json:"id"`
+	WalletID    string     `json:"wallet_id"`
+	CategoryID  string     `json:"category_id"`
+	Amount      float64    `json:"amount"`
+	Description string     `json:"description"`
+	Frequency   string     `json:"frequency"`
+	StartDate   time.Time  `json:"start_date"`
+	EndDate     *time.Time `json:"end_date"`
+	Occurrences int        `json:"occurrences"`
+	NextDate    time.Time  `json:"next_date"`
+}
+
+type RecurringTransactionsRequest struct {
+	WalletID    string     `json:"wallet_id"`
+	CategoryID  string     `json:"category_id"`
+	Amount      float64    `json:"amount"`
+	Description string     `json:"description"`
+	Frequency   string     `json:"frequency"` // daily, weekly, monthly or yearly
+	StartDate   time.Time  `json:"start_date"`
+	EndDate     *time.Time `json:"end_date"` // null repeats until deleted
+}
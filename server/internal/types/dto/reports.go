@@ -3,7 +3,7 @@ package dto
 import "time"
 
 type ReportRequest struct {
-	UserID   string `json:"user_id"`
+	Type     string `json:"type"`
 	FromDate string `json:"from_date"`
 	ToDate   string `json:"to_date"`
 }
@@ -17,3 +17,22 @@ type ReportResponse struct {
 	FileSize    int64     `json:"file_size"`
 	GeneratedAt time.Time `json:"generated_at"`
 }
+
+type ReportsResponse struct {
+	ID            string     `json:"id"`
+	UserID        string     `json:"user_id"`
+	FromDate      time.Time  `json:"from_date"`
+	ToDate        time.Time  `json:"to_date"`
+	RequestAt     time.Time  `json:"request_at"`
+	NextRequestAt time.Time  `json:"next_request_at"`
+	Type          string     `json:"type"`
+	Status        string     `json:"status"`
+	FileSize      *int64     `json:"file_size"`
+	GeneratedAt   *time.Time `json:"generated_at"`
+}
+
+type ReportDownloadResponse struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
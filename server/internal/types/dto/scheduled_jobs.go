@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+type ScheduledJobsResponse struct {
+	Name     string                    `json:"name"`
+	Schedule string                    `json:"schedule"`
+	LastRun  *ScheduledJobRunsResponse `json:"last_run"`
+}
+
+type ScheduledJobRunsResponse struct {
+	ID          string     `json:"id"`
+	JobName     string     `json:"job_name"`
+	Trigger     string     `json:"trigger"`
+	Status      string     `json:"status"`
+	ScheduledAt *time.Time `json:"scheduled_at"`
+	TriggeredBy *string    `json:"triggered_by"`
+	StartedAt   *time.Time `json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at"`
+	Error       *string    `json:"error"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
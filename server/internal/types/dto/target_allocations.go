@@ -0,0 +1,33 @@
+package dto
+
+type TargetAllocationsResponse struct {
+	ID               string  `json:"id"`
+	InvestmentTypeID *string `json:"investment_type_id"` // nil is cash
+	InvestmentType   string  `json:"investment_type"`
+	TargetPercent    float64 `json:"target_percent"`
+	TolerancePercent float64 `json:"tolerance_percent"`
+}
+
+type TargetAllocationsRequest struct {
+	InvestmentTypeID *string  `json:"investment_type_id"` // null or empty for cash
+	TargetPercent    float64  `json:"target_percent"`
+	TolerancePercent *float64 `json:"tolerance_percent"` // defaults to 5
+}
+
+type AllocationClassResponse struct {
+	InvestmentTypeID *string `json:"investment_type_id"`
+	InvestmentType   string  `json:"investment_type"`
+	CurrentValue     float64 `json:"current_value"`
+	CurrentPercent   float64 `json:"current_percent"`
+	TargetPercent    float64 `json:"target_percent"`
+	TolerancePercent float64 `json:"tolerance_percent"`
+	DriftPercent     float64 `json:"drift_percent"`
+	WithinBand       bool    `json:"within_band"`
+	SuggestedAmount  float64 `json:"suggested_amount"` // positive to buy, negative to sell
+}
+
+type RebalanceResponse struct {
+	TotalValue      float64                   `json:"total_value"`
+	RebalanceNeeded bool                      `json:"rebalance_needed"`
+	Classes         []AllocationClassResponse `json:"classes"`
+}
@@ -0,0 +1,16 @@
+package entity
+
+// AttachmentBlobs is a content-addressed file shared by every attachment with the same SHA-256 hash
+type AttachmentBlobs struct {
+	Base
+	Hash       string `gorm:"type:char(64);uniqueIndex;not null"`
+	BucketName string `gorm:"type:varchar(63);not null"`
+	ObjectName string `gorm:"type:text;not null"`
+	URL        string `gorm:"type:text;not null"`
+	Format     string `gorm:"type:text"`
+	Size       int64  `gorm:"type:bigint"`
+	RefCount   int    `gorm:"type:int;not null;default:0"`
+
+	ThumbnailSmall  string `gorm:"type:text"`
+	ThumbnailMedium string `gorm:"type:text"`
+}
@@ -2,12 +2,16 @@ package entity
 
 import "github.com/google/uuid"
 
+// Attachments belong to any resource through (OwnerType, OwnerID), see data.ATTACHMENT_OWNER_*
 type Attachments struct {
 	Base
-	TransactionID uuid.UUID `gorm:"type:uuid;not null"`
-	Image         string    `gorm:"type:text"`
-	Format        string    `gorm:"type:text"`
-	Size          int64     `gorm:"type:bigint"`
+	OwnerType string     `gorm:"type:varchar(20);not null;index:idx_attachments_owner"`
+	OwnerID   uuid.UUID  `gorm:"type:uuid;not null;index:idx_attachments_owner"`
+	BlobID    *uuid.UUID `gorm:"type:uuid;index"`
+	Image     string     `gorm:"type:text"`
+	Format    string     `gorm:"type:text"`
+	Size      int64      `gorm:"type:bigint"`
 
-	Transaction Transactions `gorm:"foreignKey:TransactionID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	ThumbnailSmall  string `gorm:"type:text"`
+	ThumbnailMedium string `gorm:"type:text"`
 }
@@ -0,0 +1,30 @@
+package entity
+
+import "github.com/google/uuid"
+
+type InstrumentKind string
+
+const (
+	InstrumentStock      InstrumentKind = "stock"
+	InstrumentMutualFund InstrumentKind = "mutual_fund"
+	InstrumentBond       InstrumentKind = "bond"
+	InstrumentGold       InstrumentKind = "gold"
+	InstrumentCrypto     InstrumentKind = "crypto"
+	InstrumentDeposit    InstrumentKind = "deposit"
+	InstrumentOther      InstrumentKind = "other"
+)
+
+// Instruments is the catalog of tradable instruments, holdings and prices of the same instrument share one row
+type Instruments struct {
+	Base
+	InvestmentTypeID *uuid.UUID     `gorm:"type:uuid"`
+	Code             string         `gorm:"type:varchar(50);not null"` // ticker or fund code, see portfolio.InstrumentKey
+	Name             string         `gorm:"type:varchar(50);not null"`
+	Issuer           string         `gorm:"type:varchar(100)"`
+	Currency         string         `gorm:"type:varchar(3);not null;default:'IDR'"`
+	Unit             string         `gorm:"type:text"`
+	RiskLevel        *int           `gorm:"type:smallint"` // 1 (lowest) to 5 (highest)
+	Kind             InstrumentKind `gorm:"type:varchar(20);not null"`
+
+	InvestmentTypes *InvestmentTypes `gorm:"foreignKey:InvestmentTypeID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+}
@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InvestmentIncomes are dividends, coupons and interest paid by a holding, see portfolio.INCOME_*
+type InvestmentIncomes struct {
+	Base
+	InvestmentID  uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Type          string     `gorm:"type:varchar(10);not null"`
+	PaymentDate   time.Time  `gorm:"type:timestamp;not null"`
+	GrossAmount   float64    `gorm:"type:decimal(18,2);not null"`
+	TaxAmount     float64    `gorm:"type:decimal(18,2);not null;default:0"` // withholding tax
+	WalletID      uuid.UUID  `gorm:"type:uuid;not null"`
+	TransactionID *uuid.UUID `gorm:"type:uuid;index"` // income transaction of the net amount in the wallet
+	Description   string     `gorm:"type:text"`
+
+	Investment Investments `gorm:"foreignKey:InvestmentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Wallet     Wallets     `gorm:"foreignKey:WalletID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+}
@@ -0,0 +1,18 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InvestmentPrices is the closing price of an instrument on a date, shared by every holding of the instrument
+type InvestmentPrices struct {
+	Base
+	InstrumentID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_investment_prices_instrument_date"`
+	PriceDate    time.Time `gorm:"type:date;not null;uniqueIndex:idx_investment_prices_instrument_date"`
+	Price        float64   `gorm:"type:decimal(18,6);not null"`
+	Source       string    `gorm:"type:varchar(30);not null"` // manual, csv or the provider name
+
+	Instrument Instruments `gorm:"foreignKey:InstrumentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InvestmentTrades are the buys, sells and fees of a position, see portfolio.TRADE_*
+type InvestmentTrades struct {
+	Base
+	InvestmentID uuid.UUID `gorm:"type:uuid;not null;index"`
+	Type         string    `gorm:"type:varchar(10);not null"`
+	TradeDate    time.Time `gorm:"type:timestamp;not null"`
+	Quantity     float64   `gorm:"type:decimal(18,6);not null"`
+	Price        float64   `gorm:"type:decimal(18,6);not null"`
+	Fee          float64   `gorm:"type:decimal(18,2);not null;default:0"`
+	Description  string    `gorm:"type:text"`
+
+	// Wallet transaction that funded a buy or received the proceeds of a sell, nil when no wallet was used
+	TransactionID *uuid.UUID `gorm:"type:uuid;index"`
+
+	Investment Investments `gorm:"foreignKey:InvestmentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
@@ -10,12 +10,19 @@ type Investments struct {
 	Base
 	InvestmentTypeID uuid.UUID `gorm:"type:uuid;not null"`
 	UserID           uuid.UUID `gorm:"type:uuid;not null"`
-	Name             string    `gorm:"type:varchar(50);not null"`
-	Amount           float64   `gorm:"type:decimal(18,2);not null"`
-	Quantity         float64   `gorm:"type:decimal(18,2);not null"`
+	InstrumentID     uuid.UUID `gorm:"type:uuid;not null"`
+	Amount           float64   `gorm:"type:decimal(18,2);not null"` // cost basis of the held quantity, derived from trades
+	Quantity         float64   `gorm:"type:decimal(18,6);not null"` // held quantity, derived from trades
+	CostMethod       string    `gorm:"type:varchar(10);not null;default:'fifo'"`
 	InvestmentDate   time.Time `gorm:"type:timestamp;not null"`
 	Description      string    `gorm:"type:text"`
 
+	// Fixed-income terms, only set for bonds and deposits
+	CouponRate      *float64   `gorm:"type:decimal(7,4)"` // annual rate in percent
+	CouponFrequency *int       // coupons per year
+	MaturityDate    *time.Time `gorm:"type:date"`
+
 	InvestmentTypes InvestmentTypes `gorm:"foreignKey:InvestmentTypeID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
 	User            Users           `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
-}
\ No newline at end of file
+	Instrument      Instruments     `gorm:"foreignKey:InstrumentID;constraint:OnUpdate:CASCADE;"`
+}
@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// OutboxEvents are messages waiting to be published, a nil SentAt means the relay has not published it yet
+type OutboxEvents struct {
+	Base
+	Exchange      string     `gorm:"type:varchar(255);not null"`
+	RoutingKey    string     `gorm:"type:varchar(255);not null"`
+	Payload       []byte     `gorm:"type:jsonb;not null"`
+	Attempts      int        `gorm:"type:integer;not null;default:0"`
+	LastError     *string    `gorm:"type:text"`
+	NextAttemptAt time.Time  `gorm:"type:timestamptz;not null;default:now()"`
+	SentAt        *time.Time `gorm:"type:timestamptz"`
+}
@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RecurringTransactions are booked as a transaction on every NextDate, Occurrences counts the bookings so far
+type RecurringTransactions struct {
+	Base
+	UserID      uuid.UUID  `gorm:"type:uuid;not null"`
+	WalletID    uuid.UUID  `gorm:"type:uuid;not null"`
+	CategoryID  uuid.UUID  `gorm:"type:uuid;not null"`
+	Amount      float64    `gorm:"type:decimal(18,2);not null"`
+	Description string     `gorm:"type:text"`
+	Frequency   string     `gorm:"type:varchar(16);not null"`
+	StartDate   time.Time  `gorm:"type:timestamp;not null"`
+	EndDate     *time.Time `gorm:"type:timestamp"`
+	Occurrences int        `gorm:"type:integer;not null;default:0"`
+	NextDate    time.Time  `gorm:"type:timestamp;not null"`
+
+	User     Users      `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Wallet   Wallets    `gorm:"foreignKey:WalletID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Category Categories `gorm:"foreignKey:CategoryID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
@@ -6,6 +6,7 @@ type Reports struct {
 	Base
 
 	UserID        string    `gorm:"type:uuid;not null" json:"user_id"`
+	Type          string    `gorm:"type:varchar(32);not null;default:financial" json:"type"`
 	FromDate      time.Time `gorm:"type:timestamptz;not null" json:"from_date"`
 	ToDate        time.Time `gorm:"type:timestamptz;not null" json:"to_date"`
 	RequestAt     time.Time `gorm:"type:timestamptz;not null" json:"request_at"`
@@ -0,0 +1,21 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScheduledJobRuns is the history of the scheduled jobs, ScheduledAt is the schedule slot of runs the scheduler
+// started and TriggeredBy the admin of runs started by hand
+type ScheduledJobRuns struct {
+	Base
+	JobName     string     `gorm:"type:varchar(100);not null"`
+	Trigger     string     `gorm:"type:varchar(20);not null"`
+	Status      string     `gorm:"type:varchar(20);not null"`
+	ScheduledAt *time.Time `gorm:"type:timestamptz"`
+	TriggeredBy *uuid.UUID `gorm:"type:uuid"`
+	StartedAt   *time.Time `gorm:"type:timestamptz"`
+	FinishedAt  *time.Time `gorm:"type:timestamptz"`
+	Error       *string    `gorm:"type:text"`
+}
@@ -0,0 +1,15 @@
+package entity
+
+import "github.com/google/uuid"
+
+// TargetAllocations are the target shares of the asset classes of a user, a nil InvestmentTypeID is cash
+type TargetAllocations struct {
+	Base
+	UserID           uuid.UUID  `gorm:"type:uuid;not null"`
+	InvestmentTypeID *uuid.UUID `gorm:"type:uuid"`
+	TargetPercent    float64    `gorm:"type:decimal(5,2);not null"`
+	TolerancePercent float64    `gorm:"type:decimal(5,2);not null;default:5"`
+
+	User           Users            `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	InvestmentType *InvestmentTypes `gorm:"foreignKey:InvestmentTypeID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
@@ -0,0 +1,37 @@
+package view
+
+import "time"
+
+// Cashflow is the income and expense of a group within a period, computed on demand from the transactions
+type Cashflow struct {
+	Period     time.Time `json:"period"`
+	GroupKey   string    `json:"group_key"`
+	GroupLabel string    `json:"group_label"`
+	Income     float64   `json:"income"`
+	Expense    float64   `json:"expense"`
+}
+
+// WalletBalance is a wallet of the user with its current balance
+type WalletBalance struct {
+	WalletID   string  `json:"wallet_id"`
+	Name       string  `json:"name"`
+	WalletType string  `json:"wallet_type"`
+	Balance    float64 `json:"balance"`
+}
+
+// WalletDailyFlow is the net amount booked on a wallet on a day, positive when money came in
+type WalletDailyFlow struct {
+	WalletID string    `json:"wallet_id"`
+	Date     time.Time `json:"date"`
+	Amount   float64   `json:"amount"`
+}
+
+// WalletTransaction is a past transaction of a wallet, Amount is negative when money left the wallet
+type WalletTransaction struct {
+	WalletID        string    `json:"wallet_id"`
+	CategoryID      string    `json:"category_id"`
+	CategoryName    string    `json:"category_name"`
+	CategoryType    string    `json:"category_type"`
+	Amount          float64   `json:"amount"`
+	TransactionDate time.Time `json:"transaction_date"`
+}
@@ -1,10 +1,15 @@
 package view
 
 type MVUserWalletDailySummaries struct {
-	UserID   string  `json:"user_id"`
-	Date     string  `json:"date"`
-	Physical float64 `json:"physical"`
-	EWallet  float64 `json:"e-wallet"`
-	Bank     float64 `json:"bank"`
-	Others   float64 `json:"others"`
+	UserID string             `json:"user_id"`
+	Date   string             `json:"date"`
+	Types  map[string]float64 `json:"types"` // balance by wallet type, e.g. bank or e-wallet
+}
+
+// ViewUserWalletTypeDailyBalances is a row of view_user_wallet_daily_summaries
+type ViewUserWalletTypeDailyBalances struct {
+	UserID     string  `json:"user_id"`
+	Date       string  `json:"date"`
+	WalletType string  `json:"wallet_type"`
+	Total      float64 `json:"total"`
 }
@@ -0,0 +1,12 @@
+package view
+
+type ViewUserAllocations struct {
+	UserID           string  `json:"user_id"`
+	InvestmentTypeID *string `json:"investment_type_id"` // nil is cash
+	InvestmentType   string  `json:"investment_type"`
+	CurrentValue     float64 `json:"current_value"`
+	CurrentPercent   float64 `json:"current_percent"`
+	TargetPercent    float64 `json:"target_percent"`
+	TolerancePercent float64 `json:"tolerance_percent"`
+	DriftPercent     float64 `json:"drift_percent"`
+}
@@ -1,12 +1,24 @@
 package view
 
 type ViewUserInvestments struct {
-	ID                 string  `json:"id"`
-	UserID             string  `json:"user_id"`
-	InvestmentType     string  `json:"investment_type"`
-	InvestmentName     string  `json:"investment_name"`
-	InvestmentAmount   float64 `json:"investment_amount"`
-	InvestmentQuantity float64 `json:"investment_quantity"`
-	InvestmentUnit     string  `json:"investment_unit"`
-	InvestmentDate     string  `json:"investment_date"`
+	ID                 string   `json:"id"`
+	UserID             string   `json:"user_id"`
+	InvestmentType     string   `json:"investment_type"`
+	InvestmentName     string   `json:"investment_name"`
+	InvestmentAmount   float64  `json:"investment_amount"`
+	InvestmentQuantity float64  `json:"investment_quantity"`
+	InvestmentUnit     string   `json:"investment_unit"`
+	InvestmentDate     string   `json:"investment_date"`
+	CostMethod         string   `json:"cost_method"`
+	TradeCount         int      `json:"trade_count"`
+	TotalInvested      float64  `json:"total_invested"`
+	TotalProceeds      float64  `json:"total_proceeds"`
+	TotalFees          float64  `json:"total_fees"`
+	RealizedPnL        float64  `json:"realized_pnl" gorm:"column:realized_pnl"`
+	LastPrice          *float64 `json:"last_price"`
+	MarketValue        *float64 `json:"market_value"`
+	UnrealizedPnL      *float64 `json:"unrealized_pnl" gorm:"column:unrealized_pnl"`
+	PriceDate          *string  `json:"price_date"`
+	InstrumentID       string   `json:"instrument_id"`
+	InstrumentCode     string   `json:"instrument_code"`
 }
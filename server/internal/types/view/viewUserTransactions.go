@@ -6,6 +6,9 @@ type Attachment struct {
 	Image         string `json:"image"`
 	Format        string `json:"format"`
 	Size          int64  `json:"size"`
+
+	ThumbnailSmall  string `json:"thumbnail_small"`
+	ThumbnailMedium string `json:"thumbnail_medium"`
 }
 
 type ViewUserTransactions struct {
@@ -0,0 +1,38 @@
+package analytics
+
+import (
+	"sort"
+	"time"
+)
+
+// Flow is the net amount booked on a wallet on a date, positive when money came in
+type Flow struct {
+	Date   time.Time
+	Amount float64
+}
+
+// BalancesAt works back from the current balance to the balance at the end of each date. Flows must hold
+// everything booked after the first date, including flows dated after the last one.
+func BalancesAt(balance float64, flows []Flow, dates []time.Time) []float64 {
+	sorted := make([]Flow, len(flows))
+	copy(sorted, flows)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Date.After(sorted[j].Date) })
+
+	order := make([]int, len(dates))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return dates[order[i]].After(dates[order[j]]) })
+
+	// Walk from the latest date back, taking off the flows booked after each date
+	balances := make([]float64, len(dates))
+	next := 0
+	for _, i := range order {
+		for next < len(sorted) && sorted[next].Date.After(dates[i]) {
+			balance -= sorted[next].Amount
+			next++
+		}
+		balances[i] = balance
+	}
+	return balances
+}
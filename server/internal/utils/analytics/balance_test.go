@@ -0,0 +1,25 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBalancesAt(t *testing.T) {
+	flows := []Flow{
+		{Date: date(2026, time.October, 5), Amount: 500},
+		{Date: date(2026, time.October, 2), Amount: -200},
+		{Date: date(2026, time.October, 20), Amount: 100},
+	}
+	dates := []time.Time{
+		date(2026, time.October, 1),
+		date(2026, time.October, 2),
+		date(2026, time.October, 10),
+	}
+
+	// * 1000 now, the flow of October 20 is after the last date but still taken off
+	assert.Equal(t, []float64{600, 400, 900}, BalancesAt(1000, flows, dates))
+	assert.Equal(t, []float64{1000}, BalancesAt(1000, nil, []time.Time{date(2026, time.October, 1)}))
+}
@@ -0,0 +1,265 @@
+package analytics
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// RECURRING_MONTHS is how many complete months an item must be booked in, once each, to count as recurring
+	RECURRING_MONTHS = 3
+	// recurringTolerance is how far the amounts of a recurring item may be from their median, as a fraction
+	recurringTolerance = 0.2
+
+	// SPENDING_WEEKS is the history the discretionary spending estimate is taken from
+	SPENDING_WEEKS = 12
+)
+
+// HistoryItem is a past transaction, Amount is negative when money left the wallet
+type HistoryItem struct {
+	WalletID      string
+	CategoryID    string
+	Category      string
+	Amount        float64
+	Date          time.Time
+	Discretionary bool // an expense, as opposed to income or a transfer
+}
+
+// Recurring is an item booked on the same wallet and category once every month
+type Recurring struct {
+	WalletID   string
+	CategoryID string
+	Category   string
+	Amount     float64
+	Day        int  // day of the month it is booked on
+	BookedNow  bool // already booked in the current month
+}
+
+// ScheduledItem is a known future change of a wallet's balance
+type ScheduledItem struct {
+	WalletID string
+	Label    string
+	Date     time.Time
+	Amount   float64
+}
+
+// SpendingEstimate is the expected spending per day of a category on a wallet, Daily is negative
+type SpendingEstimate struct {
+	WalletID   string
+	CategoryID string
+	Category   string
+	Daily      float64
+}
+
+func itemKey(walletID, categoryID string) string {
+	return walletID + "/" + categoryID
+}
+
+// DetectRecurring finds the items booked exactly once in each of the RECURRING_MONTHS complete months before the
+// month of today, at amounts within recurringTolerance of their median, e.g. salary, rent or an instalment
+func DetectRecurring(history []HistoryItem, today time.Time) []Recurring {
+	current := PeriodStart(today, GRANULARITY_MONTH)
+	first := current.AddDate(0, -RECURRING_MONTHS, 0)
+
+	type group struct {
+		months map[time.Time][]HistoryItem
+		now    bool
+	}
+	groups := map[string]*group{}
+	var keys []string
+	for _, item := range history {
+		month := PeriodStart(item.Date, GRANULARITY_MONTH)
+		if month.Before(first) || month.After(current) {
+			continue
+		}
+
+		key := itemKey(item.WalletID, item.CategoryID)
+		g, ok := groups[key]
+		if !ok {
+			g = &group{months: map[time.Time][]HistoryItem{}}
+			groups[key] = g
+			keys = append(keys, key)
+		}
+		if month.Equal(current) {
+			g.now = true
+			continue
+		}
+		g.months[month] = append(g.months[month], item)
+	}
+	sort.Strings(keys)
+
+	var recurring []Recurring
+	for _, key := range keys {
+		g := groups[key]
+
+		var items []HistoryItem
+		for month := first; month.Before(current); month = month.AddDate(0, 1, 0) {
+			if len(g.months[month]) != 1 {
+				items = nil
+				break
+			}
+			items = append(items, g.months[month][0])
+		}
+		if len(items) != RECURRING_MONTHS || !similarAmounts(items) {
+			continue
+		}
+
+		latest := items[len(items)-1]
+		recurring = append(recurring, Recurring{
+			WalletID:   latest.WalletID,
+			CategoryID: latest.CategoryID,
+			Category:   latest.Category,
+			Amount:     latest.Amount,
+			Day:        latest.Date.Day(),
+			BookedNow:  g.now,
+		})
+	}
+	return recurring
+}
+
+// ExcludeRecurring leaves out the recurring items booked on the wallet and category of one of exclude, e.g. the
+// items that have a recurring transaction set up and are scheduled from it instead
+func ExcludeRecurring(recurring, exclude []Recurring) []Recurring {
+	skip := map[string]bool{}
+	for _, r := range exclude {
+		skip[itemKey(r.WalletID, r.CategoryID)] = true
+	}
+
+	var kept []Recurring
+	for _, r := range recurring {
+		if !skip[itemKey(r.WalletID, r.CategoryID)] {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+func similarAmounts(items []HistoryItem) bool {
+	amounts := make([]float64, len(items))
+	for i, item := range items {
+		amounts[i] = item.Amount
+	}
+
+	mid := median(amounts)
+	if mid == 0 {
+		return false
+	}
+	for _, amount := range amounts {
+		if math.Signbit(amount) != math.Signbit(mid) || math.Abs(amount-mid) > math.Abs(mid)*recurringTolerance {
+			return false
+		}
+	}
+	return true
+}
+
+// Schedule lists the dates after today up to until on which the recurring items are booked. The day of the
+// month is moved to the last day in shorter months.
+func Schedule(recurring []Recurring, today, until time.Time) []ScheduledItem {
+	var items []ScheduledItem
+	for _, r := range recurring {
+		month := PeriodStart(today, GRANULARITY_MONTH)
+		if r.BookedNow {
+			month = month.AddDate(0, 1, 0)
+		}
+
+		for ; !month.After(until); month = month.AddDate(0, 1, 0) {
+			lastDay := month.AddDate(0, 1, -1).Day()
+			date := month.AddDate(0, 0, min(r.Day, lastDay)-1)
+			if !date.After(today) || date.After(until) {
+				continue
+			}
+			items = append(items, ScheduledItem{WalletID: r.WalletID, Label: r.Category, Date: date, Amount: r.Amount})
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Date.Before(items[j].Date) })
+	return items
+}
+
+// EstimateSpending takes the median of the weekly spending of each category on each wallet over the SPENDING_WEEKS
+// up to today, leaving out recurring items. The median keeps one-off purchases from inflating the estimate.
+func EstimateSpending(history []HistoryItem, recurring []Recurring, today time.Time) []SpendingEstimate {
+	skip := map[string]bool{}
+	for _, r := range recurring {
+		skip[itemKey(r.WalletID, r.CategoryID)] = true
+	}
+
+	start := today.AddDate(0, 0, -7*SPENDING_WEEKS+1)
+	weekly := map[string][]float64{}
+	labels := map[string]HistoryItem{}
+	var keys []string
+	for _, item := range history {
+		key := itemKey(item.WalletID, item.CategoryID)
+		if !item.Discretionary || item.Amount >= 0 || skip[key] || item.Date.Before(start) || item.Date.After(today) {
+			continue
+		}
+
+		if _, ok := weekly[key]; !ok {
+			weekly[key] = make([]float64, SPENDING_WEEKS)
+			labels[key] = item
+			keys = append(keys, key)
+		}
+		week := int(item.Date.Sub(start).Hours()/24) / 7
+		weekly[key][week] += item.Amount
+	}
+	sort.Strings(keys)
+
+	var estimates []SpendingEstimate
+	for _, key := range keys {
+		daily := median(weekly[key]) / 7
+		if daily == 0 {
+			continue
+		}
+
+		item := labels[key]
+		estimates = append(estimates, SpendingEstimate{
+			WalletID:   item.WalletID,
+			CategoryID: item.CategoryID,
+			Category:   item.Category,
+			Daily:      daily,
+		})
+	}
+	return estimates
+}
+
+// Project returns the balance of the wallet at the end of each of the days after today, starting from its
+// current balance and adding the scheduled items and estimated spending of the wallet
+func Project(walletID string, balance float64, today time.Time, days int, items []ScheduledItem, estimates []SpendingEstimate) []float64 {
+	var daily float64
+	for _, estimate := range estimates {
+		if estimate.WalletID == walletID {
+			daily += estimate.Daily
+		}
+	}
+
+	booked := map[time.Time]float64{}
+	for _, item := range items {
+		if item.WalletID == walletID {
+			booked[item.Date] += item.Amount
+		}
+	}
+
+	balances := make([]float64, days)
+	for i := range balances {
+		balance += daily + booked[today.AddDate(0, 0, i+1)]
+		balances[i] = balance
+	}
+	return balances
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,81 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectRecurring(t *testing.T) {
+	today := date(2026, time.October, 19)
+	history := []HistoryItem{
+		// * Salary on the 25th, a little higher in September
+		{WalletID: "bank", CategoryID: "salary", Category: "Gaji", Amount: 10000, Date: date(2026, time.July, 25)},
+		{WalletID: "bank", CategoryID: "salary", Category: "Gaji", Amount: 10000, Date: date(2026, time.August, 25)},
+		{WalletID: "bank", CategoryID: "salary", Category: "Gaji", Amount: 10500, Date: date(2026, time.September, 25)},
+		// * Rent on the 31st, already paid this month
+		{WalletID: "bank", CategoryID: "rent", Category: "Sewa", Amount: -3000, Date: date(2026, time.July, 31)},
+		{WalletID: "bank", CategoryID: "rent", Category: "Sewa", Amount: -3000, Date: date(2026, time.August, 31)},
+		{WalletID: "bank", CategoryID: "rent", Category: "Sewa", Amount: -3000, Date: date(2026, time.September, 30)},
+		{WalletID: "bank", CategoryID: "rent", Category: "Sewa", Amount: -3000, Date: date(2026, time.October, 1)},
+		// * Twice in August is not monthly
+		{WalletID: "cash", CategoryID: "food", Category: "Makan", Amount: -50, Date: date(2026, time.July, 3)},
+		{WalletID: "cash", CategoryID: "food", Category: "Makan", Amount: -50, Date: date(2026, time.August, 3)},
+		{WalletID: "cash", CategoryID: "food", Category: "Makan", Amount: -50, Date: date(2026, time.August, 4)},
+		{WalletID: "cash", CategoryID: "food", Category: "Makan", Amount: -50, Date: date(2026, time.September, 3)},
+		// * Amounts too far apart
+		{WalletID: "bank", CategoryID: "bonus", Category: "Bonus", Amount: 100, Date: date(2026, time.July, 1)},
+		{WalletID: "bank", CategoryID: "bonus", Category: "Bonus", Amount: 500, Date: date(2026, time.August, 1)},
+		{WalletID: "bank", CategoryID: "bonus", Category: "Bonus", Amount: 100, Date: date(2026, time.September, 1)},
+	}
+
+	recurring := DetectRecurring(history, today)
+	assert.Equal(t, []Recurring{
+		{WalletID: "bank", CategoryID: "rent", Category: "Sewa", Amount: -3000, Day: 30, BookedNow: true},
+		{WalletID: "bank", CategoryID: "salary", Category: "Gaji", Amount: 10500, Day: 25},
+	}, recurring)
+
+	// * Rent was paid this month, so it is next due at the end of November
+	items := Schedule(recurring, today, today.AddDate(0, 0, 45))
+	assert.Equal(t, []ScheduledItem{
+		{WalletID: "bank", Label: "Gaji", Date: date(2026, time.October, 25), Amount: 10500},
+		{WalletID: "bank", Label: "Gaji", Date: date(2026, time.November, 25), Amount: 10500},
+		{WalletID: "bank", Label: "Sewa", Date: date(2026, time.November, 30), Amount: -3000},
+	}, items)
+}
+
+func TestExcludeRecurring(t *testing.T) {
+	recurring := []Recurring{
+		{WalletID: "bank", CategoryID: "rent", Category: "Sewa", Amount: -3000, Day: 30},
+		{WalletID: "bank", CategoryID: "salary", Category: "Gaji", Amount: 10500, Day: 25},
+		{WalletID: "cash", CategoryID: "rent", Category: "Sewa", Amount: -500, Day: 1},
+	}
+
+	// * Only the same wallet and category are left out, whatever the amount or day
+	kept := ExcludeRecurring(recurring, []Recurring{{WalletID: "bank", CategoryID: "rent", Amount: -3500}})
+	assert.Equal(t, []Recurring{recurring[1], recurring[2]}, kept)
+
+	assert.Equal(t, recurring, ExcludeRecurring(recurring, nil))
+}
+
+func TestEstimateSpending(t *testing.T) {
+	today := date(2026, time.October, 19)
+	var history []HistoryItem
+	for week := 0; week < SPENDING_WEEKS; week++ {
+		history = append(history, HistoryItem{WalletID: "cash", CategoryID: "food", Category: "Makan", Amount: -70, Date: today.AddDate(0, 0, -7*week), Discretionary: true})
+	}
+	// * A one-off purchase does not move the median
+	history = append(history, HistoryItem{WalletID: "cash", CategoryID: "food", Category: "Makan", Amount: -5000, Date: today, Discretionary: true})
+	// * Income is not spending
+	history = append(history, HistoryItem{WalletID: "cash", CategoryID: "gift", Category: "Hadiah", Amount: 300, Date: today})
+
+	estimates := EstimateSpending(history, nil, today)
+	assert.Equal(t, []SpendingEstimate{{WalletID: "cash", CategoryID: "food", Category: "Makan", Daily: -10}}, estimates)
+
+	balances := Project("cash", 100, today, 3, []ScheduledItem{
+		{WalletID: "cash", Date: today.AddDate(0, 0, 2), Amount: 50},
+		{WalletID: "bank", Date: today.AddDate(0, 0, 1), Amount: 1000},
+	}, estimates)
+	assert.Equal(t, []float64{90, 130, 120}, balances)
+}
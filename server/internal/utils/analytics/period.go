@@ -0,0 +1,143 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	GRANULARITY_DAY   = "day"
+	GRANULARITY_WEEK  = "week"
+	GRANULARITY_MONTH = "month"
+	GRANULARITY_YEAR  = "year"
+
+	COMPARE_PREVIOUS_PERIOD = "previous_period"
+	COMPARE_PREVIOUS_YEAR   = "previous_year"
+
+	// Guard against huge series, e.g. a daily series over decades
+	maxPeriods = 1000
+)
+
+// Period is a bucket of a series, Start and End are clipped to the requested range so the first and last
+// period of a range that does not start or end on a boundary are shorter
+type Period struct {
+	Start time.Time
+	End   time.Time
+	Label string
+}
+
+// ValidGranularity reports whether the granularity is one of day, week, month or year
+func ValidGranularity(granularity string) bool {
+	switch granularity {
+	case GRANULARITY_DAY, GRANULARITY_WEEK, GRANULARITY_MONTH, GRANULARITY_YEAR:
+		return true
+	}
+	return false
+}
+
+// PeriodStart returns the start of the period containing the date, weeks start on Monday like date_trunc
+func PeriodStart(date time.Time, granularity string) time.Time {
+	year, month, day := date.Date()
+	switch granularity {
+	case GRANULARITY_WEEK:
+		offset := (int(date.Weekday()) + 6) % 7
+		return time.Date(year, month, day-offset, 0, 0, 0, 0, date.Location())
+	case GRANULARITY_MONTH:
+		return time.Date(year, month, 1, 0, 0, 0, 0, date.Location())
+	case GRANULARITY_YEAR:
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, date.Location())
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, date.Location())
+}
+
+func nextPeriod(start time.Time, granularity string) time.Time {
+	switch granularity {
+	case GRANULARITY_WEEK:
+		return start.AddDate(0, 0, 7)
+	case GRANULARITY_MONTH:
+		return start.AddDate(0, 1, 0)
+	case GRANULARITY_YEAR:
+		return start.AddDate(1, 0, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// Periods splits the dates from..to (inclusive) into periods of the granularity
+func Periods(from, to time.Time, granularity string) ([]Period, error) {
+	if !ValidGranularity(granularity) {
+		return nil, fmt.Errorf("unknown granularity '%s'", granularity)
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("end date is before start date")
+	}
+
+	var periods []Period
+	for start := PeriodStart(from, granularity); !start.After(to); start = nextPeriod(start, granularity) {
+		if len(periods) >= maxPeriods {
+			return nil, fmt.Errorf("series has more than %d periods, use a larger granularity", maxPeriods)
+		}
+
+		period := Period{
+			Start: start,
+			End:   nextPeriod(start, granularity).AddDate(0, 0, -1),
+			Label: Label(start, granularity),
+		}
+		if period.Start.Before(from) {
+			period.Start = from
+		}
+		if period.End.After(to) {
+			period.End = to
+		}
+		periods = append(periods, period)
+	}
+	return periods, nil
+}
+
+// Label names the period starting at start, e.g. 2026-10 for a month
+func Label(start time.Time, granularity string) string {
+	switch granularity {
+	case GRANULARITY_MONTH:
+		return start.Format("2006-01")
+	case GRANULARITY_YEAR:
+		return start.Format("2006")
+	}
+	return start.Format("2006-01-02")
+}
+
+// PreviousRange returns the range from..to is compared with. The previous period is the range of the same
+// length right before it, counted in months when the range covers whole months so that a quarter is compared
+// with the quarter before it. The previous year is the same dates a year earlier.
+func PreviousRange(from, to time.Time, compare string) (time.Time, time.Time, error) {
+	switch compare {
+	case COMPARE_PREVIOUS_PERIOD:
+		if from.Day() == 1 && to.AddDate(0, 0, 1).Day() == 1 {
+			months := (to.Year()-from.Year())*12 + int(to.Month()-from.Month()) + 1
+			return from.AddDate(0, -months, 0), from.AddDate(0, 0, -1), nil
+		}
+		days := int(to.Sub(from).Hours()/24) + 1
+		return from.AddDate(0, 0, -days), from.AddDate(0, 0, -1), nil
+	case COMPARE_PREVIOUS_YEAR:
+		return addYears(from, -1), addYears(to, -1), nil
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("unknown comparison '%s'", compare)
+}
+
+// addYears keeps the last day of February instead of rolling over into March
+func addYears(date time.Time, years int) time.Time {
+	shifted := date.AddDate(years, 0, 0)
+	if shifted.Day() != date.Day() {
+		return shifted.AddDate(0, 0, -shifted.Day())
+	}
+	return shifted
+}
+
+// ChangePercentage is the change from previous to current in percent, nil when there is nothing to compare with
+func ChangePercentage(current, previous float64) *float64 {
+	if previous == 0 {
+		return nil
+	}
+
+	change := math.Round((current-previous)/math.Abs(previous)*10000) / 100
+	return &change
+}
@@ -0,0 +1,75 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestPeriods(t *testing.T) {
+	t.Run("Months Clipped To Range", func(t *testing.T) {
+		periods, err := Periods(date(2026, time.January, 15), date(2026, time.March, 10), GRANULARITY_MONTH)
+		assert.Nil(t, err)
+		assert.Equal(t, []Period{
+			{Start: date(2026, time.January, 15), End: date(2026, time.January, 31), Label: "2026-01"},
+			{Start: date(2026, time.February, 1), End: date(2026, time.February, 28), Label: "2026-02"},
+			{Start: date(2026, time.March, 1), End: date(2026, time.March, 10), Label: "2026-03"},
+		}, periods)
+	})
+
+	t.Run("Weeks Start On Monday", func(t *testing.T) {
+		// * 2026-10-01 is a Thursday
+		periods, err := Periods(date(2026, time.October, 1), date(2026, time.October, 12), GRANULARITY_WEEK)
+		assert.Nil(t, err)
+		assert.Len(t, periods, 3)
+		assert.Equal(t, "2026-09-28", periods[0].Label)
+		assert.Equal(t, date(2026, time.October, 4), periods[0].End)
+		assert.Equal(t, date(2026, time.October, 12), periods[2].Start)
+	})
+
+	t.Run("Invalid Input", func(t *testing.T) {
+		_, err := Periods(date(2026, time.January, 1), date(2026, time.December, 31), "quarter")
+		assert.NotNil(t, err)
+
+		_, err = Periods(date(2026, time.March, 1), date(2026, time.January, 1), GRANULARITY_DAY)
+		assert.NotNil(t, err)
+
+		_, err = Periods(date(2000, time.January, 1), date(2026, time.January, 1), GRANULARITY_DAY)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestPreviousRange(t *testing.T) {
+	t.Run("Whole Months", func(t *testing.T) {
+		from, to, err := PreviousRange(date(2026, time.January, 1), date(2026, time.March, 31), COMPARE_PREVIOUS_PERIOD)
+		assert.Nil(t, err)
+		assert.Equal(t, date(2025, time.October, 1), from)
+		assert.Equal(t, date(2025, time.December, 31), to)
+	})
+
+	t.Run("Days", func(t *testing.T) {
+		from, to, err := PreviousRange(date(2026, time.October, 10), date(2026, time.October, 19), COMPARE_PREVIOUS_PERIOD)
+		assert.Nil(t, err)
+		assert.Equal(t, date(2026, time.September, 30), from)
+		assert.Equal(t, date(2026, time.October, 9), to)
+	})
+
+	t.Run("Previous Year Keeps End Of February", func(t *testing.T) {
+		from, to, err := PreviousRange(date(2028, time.February, 1), date(2028, time.February, 29), COMPARE_PREVIOUS_YEAR)
+		assert.Nil(t, err)
+		assert.Equal(t, date(2027, time.February, 1), from)
+		assert.Equal(t, date(2027, time.February, 28), to)
+	})
+}
+
+func TestChangePercentage(t *testing.T) {
+	assert.Nil(t, ChangePercentage(100, 0))
+	assert.Equal(t, 50.0, *ChangePercentage(150, 100))
+	// * A smaller loss is an improvement
+	assert.Equal(t, 50.0, *ChangePercentage(-50, -100))
+}
@@ -8,8 +8,94 @@ var (
 	REPORT_STATUS_PROCESSING = "processing"
 	REPORT_STATUS_COMPLETED  = "completed"
 	REPORT_STATUS_FAILED     = "failed"
+	REPORT_STATUS_CANCELLED  = "cancelled"
+
+	REPORT_TYPE_FINANCIAL = "financial"
+
+	ATTACHMENT_OWNER_TRANSACTION = "transaction"
+	ATTACHMENT_OWNER_WALLET      = "wallet"
+	ATTACHMENT_OWNER_INVESTMENT  = "investment"
+
+	INVESTMENT_CATEGORY      = "Investasi"
+	INVESTMENT_SALE_CATEGORY = "Penjualan Investasi"
+
+	// Domain event types, also the routing keys on the events exchange
+	EVENT_TRANSACTION_CREATED = "transaction.created.v1"
+	EVENT_TRANSACTION_UPDATED = "transaction.updated.v1"
+	EVENT_TRANSACTION_DELETED = "transaction.deleted.v1"
+	EVENT_TRANSFER_CREATED    = "transfer.created.v1"
+	EVENT_WALLET_CREATED      = "wallet.created.v1"
+	EVENT_WALLET_UPDATED      = "wallet.updated.v1"
+	EVENT_WALLET_DELETED      = "wallet.deleted.v1"
+	EVENT_INVESTMENT_CREATED  = "investment.created.v1"
+	EVENT_INVESTMENT_UPDATED  = "investment.updated.v1"
+	EVENT_INVESTMENT_DELETED  = "investment.deleted.v1"
+
+	SCHEDULED_JOB_REFRESH_SUMMARIES = "refresh_summaries"
+	SCHEDULED_JOB_CLEANUP           = "cleanup"
+	SCHEDULED_JOB_RECURRING         = "recurring_transactions"
+
+	RECURRING_FREQUENCY_DAILY   = "daily"
+	RECURRING_FREQUENCY_WEEKLY  = "weekly"
+	RECURRING_FREQUENCY_MONTHLY = "monthly"
+	RECURRING_FREQUENCY_YEARLY  = "yearly"
+
+	// Groupings of the cash flow analytics
+	CASHFLOW_GROUP_BY_CATEGORY        = "category"
+	CASHFLOW_GROUP_BY_PARENT_CATEGORY = "parent_category"
+	CASHFLOW_GROUP_BY_WALLET          = "wallet"
+	CASHFLOW_GROUP_BY_WALLET_TYPE     = "wallet_type"
+
+	SCHEDULED_JOB_TRIGGER_SCHEDULE = "schedule"
+	SCHEDULED_JOB_TRIGGER_MANUAL   = "manual"
+
+	SCHEDULED_JOB_STATUS_RUNNING   = "running"
+	SCHEDULED_JOB_STATUS_SUCCEEDED = "succeeded"
+	SCHEDULED_JOB_STATUS_FAILED    = "failed"
+	SCHEDULED_JOB_STATUS_SKIPPED   = "skipped"
+
+	// SCHEDULE_OFF as the schedule of a job only leaves it to be triggered by hand
+	SCHEDULE_OFF = "off"
 )
 
+// SCHEDULED_JOB_SCHEDULES are the default cron schedules of the jobs run by the worker scheduler,
+// the summaries rebuild keeps the 18:00 UTC of the former pg_cron jobs
+var SCHEDULED_JOB_SCHEDULES = map[string]string{
+	SCHEDULED_JOB_REFRESH_SUMMARIES: "CRON_TZ=UTC 0 18 * * *",
+	SCHEDULED_JOB_CLEANUP:           "CRON_TZ=UTC 0 19 * * *",
+	// Hourly so a booking is at most an hour late whatever the time of day of its date
+	SCHEDULED_JOB_RECURRING: "CRON_TZ=UTC 0 * * * *",
+}
+
+// REPORT_TYPES lists the report types users can request
+var REPORT_TYPES = []string{REPORT_TYPE_FINANCIAL}
+
+// FORECAST_DAYS lists how many days ahead balances can be forecast
+var FORECAST_DAYS = []int{30, 90, 180}
+
+// INVESTMENT_TYPE_CATEGORIES maps investment types to their expense category under INVESTMENT_CATEGORY
+var INVESTMENT_TYPE_CATEGORIES = map[string]string{
+	"Gold":                  "Emas",
+	"Government Securities": "Obligasi",
+	"Bonds":                 "Obligasi",
+	"Stocks":                "Saham",
+	"Mutual Funds":          "Reksadana",
+	"Deposits":              "Deposito",
+}
+
+// CategoryPath names a seeded category by its root category and its own name
+type CategoryPath struct {
+	Parent string
+	Name   string
+}
+
+// INVESTMENT_INCOME_CATEGORIES maps portfolio.INCOME_* to the seeded income categories they are booked under
+var INVESTMENT_INCOME_CATEGORIES = map[string]CategoryPath{
+	"dividend": {Parent: "Pendapatan Pasif", Name: "Dividen Saham"},
+	"coupon":   {Parent: "Pendapatan Lainnya", Name: "Pendapatan Investasi"},
+	"interest": {Parent: "Pendapatan Pasif", Name: "Bunga Deposito"},
+}
+
 type GitHubPlan struct {
 	Collaborators int    `json:"collaborators"`
 	Name          string `json:"name"`
@@ -65,3 +151,6 @@ type OTP struct {
 	Email string `json:"email"`
 	OTP   string `json:"otp"`
 }
+
+// RECURRING_FREQUENCIES lists the frequencies recurring transactions can repeat at
+var RECURRING_FREQUENCIES = []string{RECURRING_FREQUENCY_DAILY, RECURRING_FREQUENCY_WEEKLY, RECURRING_FREQUENCY_MONTHLY, RECURRING_FREQUENCY_YEARLY}
@@ -86,6 +86,7 @@ type smtpClient struct {
 
 type SMTPClientInterface interface {
 	SendSingleEmail(to string, subject string, htmlFile string, data any) error
+	SendHTMLEmail(to string, subject string, html string) error
 }
 
 func NewSMTPClient(smtpInterface SMTPInterface) SMTPClientInterface {
@@ -132,18 +133,28 @@ func parseHTML(file string, data any) (string, error) {
 	return bufferhtml.String(), nil
 }
 
-func (c *smtpClient) SendSingleEmail(to string, subject string, htmlFile string, data any) error {
-	auth := c.SMTPInterface.GetAuth()
-	addr := c.SMTPInterface.GetAddress()
-	user := c.SMTPInterface.GetUser()
+// RenderEmail executes the html template with the data, e.g. to queue the email and send it later
+func RenderEmail(htmlFile string, data any) (string, error) {
+	return parseHTML(htmlFile, data)
+}
 
-	mime := "MIME-version: 1.0;\r\nContent-Type: text/html; charset=\"UTF-8\";\r\n\r\n"
+func (c *smtpClient) SendSingleEmail(to string, subject string, htmlFile string, data any) error {
 	htmlFile, err := parseHTML(htmlFile, data)
 	if err != nil {
 		return err
 	}
 
-	msg := []byte(subject + mime + htmlFile)
+	return c.SendHTMLEmail(to, subject, htmlFile)
+}
+
+// SendHTMLEmail sends an already rendered email, see RenderEmail
+func (c *smtpClient) SendHTMLEmail(to string, subject string, html string) error {
+	auth := c.SMTPInterface.GetAuth()
+	addr := c.SMTPInterface.GetAddress()
+	user := c.SMTPInterface.GetUser()
+
+	mime := "MIME-version: 1.0;\r\nContent-Type: text/html; charset=\"UTF-8\";\r\n\r\n"
+	msg := []byte(subject + mime + html)
 
 	return smtp.SendMail(addr, auth, user, []string{to}, msg)
 }
@@ -88,12 +88,49 @@ func ConvertToResponseType(data interface{}) interface{} {
 			ID:               v.ID.String(),
 			UserID:           v.UserID.String(),
 			InvestmentTypeID: v.InvestmentTypeID.String(),
-			Name:             v.Name,
+			InstrumentID:     v.InstrumentID.String(),
+			Instrument:       v.Instrument.Code,
+			Name:             v.Instrument.Name,
 			Amount:           v.Amount,
 			Quantity:         v.Quantity,
+			CostMethod:       v.CostMethod,
 			InvestmentDate:   v.InvestmentDate,
 			Description:      v.Description,
+			CouponRate:       v.CouponRate,
+			CouponFrequency:  v.CouponFrequency,
+			MaturityDate:     v.MaturityDate,
 		}
+	case entity.InvestmentTrades:
+		trade := dto.InvestmentTradesResponse{
+			ID:           v.ID.String(),
+			InvestmentID: v.InvestmentID.String(),
+			Type:         v.Type,
+			TradeDate:    v.TradeDate,
+			Quantity:     v.Quantity,
+			Price:        v.Price,
+			Fee:          v.Fee,
+			Description:  v.Description,
+		}
+		if v.TransactionID != nil {
+			trade.TransactionID = v.TransactionID.String()
+		}
+		return trade
+	case entity.InvestmentIncomes:
+		income := dto.InvestmentIncomesResponse{
+			ID:           v.ID.String(),
+			InvestmentID: v.InvestmentID.String(),
+			Type:         v.Type,
+			PaymentDate:  v.PaymentDate,
+			GrossAmount:  v.GrossAmount,
+			TaxAmount:    v.TaxAmount,
+			NetAmount:    v.GrossAmount - v.TaxAmount,
+			WalletID:     v.WalletID.String(),
+			Description:  v.Description,
+		}
+		if v.TransactionID != nil {
+			income.TransactionID = v.TransactionID.String()
+		}
+		return income
 	case entity.WalletTypes:
 		return dto.WalletTypesResponse{
 			ID:          v.ID.String(),
@@ -101,17 +138,42 @@ func ConvertToResponseType(data interface{}) interface{} {
 			Type:        dto.WalletType(v.Type),
 			Description: v.Description,
 		}
+	case entity.InvestmentTypes:
+		return dto.InvestmentTypesResponse{
+			ID:   v.ID.String(),
+			Name: v.Name,
+			Unit: v.Unit,
+		}
+	case entity.Instruments:
+		instrument := dto.InstrumentsResponse{
+			ID:        v.ID.String(),
+			Code:      v.Code,
+			Name:      v.Name,
+			Issuer:    v.Issuer,
+			Currency:  v.Currency,
+			Unit:      v.Unit,
+			RiskLevel: v.RiskLevel,
+			Kind:      string(v.Kind),
+		}
+		if v.InvestmentTypeID != nil {
+			instrument.InvestmentTypeID = v.InvestmentTypeID.String()
+		}
+		if v.InvestmentTypes != nil {
+			instrument.InvestmentType = v.InvestmentTypes.Name
+		}
+		return instrument
 	default:
 		return nil
 	}
 }
 
-func GenerateToken(ID string, username string, email string) (string, error) {
+func GenerateToken(ID string, username string, email string, role string) (string, error) {
 	expirationTime := time.Now().Add(24 * time.Hour)
 	claims := jwt.MapClaims{
 		"id":       ID,
 		"username": username,
 		"email":    email,
+		"role":     role,
 		"exp":      expirationTime.Unix(),
 	}
 
@@ -138,10 +200,17 @@ func VerifyToken(jwtToken string) (dto.UserData, error) {
 		return dto.UserData{}, errors.New("token is invalid")
 	}
 
+	// Tokens issued before roles existed carry no role claim
+	role, ok := claims["role"].(string)
+	if !ok || role == "" {
+		role = string(entity.User)
+	}
+
 	return dto.UserData{
 		ID:       claims["id"].(string),
 		Username: claims["username"].(string),
 		Email:    claims["email"].(string),
+		Role:     role,
 	}, nil
 }
 
@@ -0,0 +1,50 @@
+package portfolio
+
+import "math"
+
+// AllocationClass is the current value of an asset class and its target share, in percent of the total
+type AllocationClass struct {
+	Key              string
+	Value            float64
+	TargetPercent    float64
+	TolerancePercent float64
+}
+
+// AllocationResult is an asset class after comparing it with its target, Suggested is the amount to buy (positive) or sell (negative)
+type AllocationResult struct {
+	AllocationClass
+	CurrentPercent float64
+	DriftPercent   float64
+	WithinBand     bool
+	Suggested      float64
+}
+
+// Rebalance compares every class with its target. When any class drifts out of its tolerance band the whole
+// portfolio is brought back to target, so the suggested buys and sells cancel out and no new money is needed.
+func Rebalance(classes []AllocationClass) ([]AllocationResult, bool) {
+	var total float64
+	for _, class := range classes {
+		total += class.Value
+	}
+
+	results := make([]AllocationResult, 0, len(classes))
+	needed := false
+	for _, class := range classes {
+		result := AllocationResult{AllocationClass: class}
+		if total > 0 {
+			result.CurrentPercent = class.Value / total * 100
+		}
+		result.DriftPercent = result.CurrentPercent - class.TargetPercent
+		result.WithinBand = math.Abs(result.DriftPercent) <= class.TolerancePercent
+		needed = needed || !result.WithinBand
+		results = append(results, result)
+	}
+
+	if needed && total > 0 {
+		for i := range results {
+			results[i].Suggested = total*results[i].TargetPercent/100 - results[i].Value
+		}
+	}
+
+	return results, needed
+}
@@ -0,0 +1,108 @@
+package portfolio
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRebalance(t *testing.T) {
+	tests := []struct {
+		name      string
+		classes   []AllocationClass
+		needed    bool
+		current   []float64
+		drift     []float64
+		within    []bool
+		suggested []float64
+	}{
+		{
+			name: "Within Tolerance",
+			classes: []AllocationClass{
+				{Key: "Stocks", Value: 6200, TargetPercent: 60, TolerancePercent: 5},
+				{Key: "Cash", Value: 3800, TargetPercent: 40, TolerancePercent: 5},
+			},
+			needed:    false,
+			current:   []float64{62, 38},
+			drift:     []float64{2, -2},
+			within:    []bool{true, true},
+			suggested: []float64{0, 0},
+		},
+		{
+			name: "On The Band Edge",
+			classes: []AllocationClass{
+				{Key: "Stocks", Value: 6500, TargetPercent: 60, TolerancePercent: 5},
+				{Key: "Cash", Value: 3500, TargetPercent: 40, TolerancePercent: 5},
+			},
+			needed:    false,
+			current:   []float64{65, 35},
+			drift:     []float64{5, -5},
+			within:    []bool{true, true},
+			suggested: []float64{0, 0},
+		},
+		{
+			// * One class out of its band rebalances every class, the suggestions add up to zero
+			name: "Out Of Tolerance",
+			classes: []AllocationClass{
+				{Key: "Stocks", Value: 7000, TargetPercent: 60, TolerancePercent: 5},
+				{Key: "Bonds", Value: 1500, TargetPercent: 20, TolerancePercent: 5},
+				{Key: "Cash", Value: 1500, TargetPercent: 20, TolerancePercent: 5},
+			},
+			needed:    true,
+			current:   []float64{70, 15, 15},
+			drift:     []float64{10, -5, -5},
+			within:    []bool{false, true, true},
+			suggested: []float64{-1000, 500, 500},
+		},
+		{
+			name: "Class Without Target",
+			classes: []AllocationClass{
+				{Key: "Stocks", Value: 8000, TargetPercent: 100, TolerancePercent: 5},
+				{Key: "Crypto", Value: 2000, TargetPercent: 0, TolerancePercent: 5},
+			},
+			needed:    true,
+			current:   []float64{80, 20},
+			drift:     []float64{-20, 20},
+			within:    []bool{false, false},
+			suggested: []float64{2000, -2000},
+		},
+		{
+			// * Nothing to sell, so nothing is suggested even though the classes are off target
+			name: "Empty Portfolio",
+			classes: []AllocationClass{
+				{Key: "Stocks", Value: 0, TargetPercent: 60, TolerancePercent: 5},
+				{Key: "Cash", Value: 0, TargetPercent: 40, TolerancePercent: 5},
+			},
+			needed:    true,
+			current:   []float64{0, 0},
+			drift:     []float64{-60, -40},
+			within:    []bool{false, false},
+			suggested: []float64{0, 0},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			results, needed := Rebalance(test.classes)
+			assert.Equal(t, test.needed, needed)
+			assert.Len(t, results, len(test.classes))
+
+			var sum float64
+			for i, result := range results {
+				assert.Equal(t, test.classes[i], result.AllocationClass)
+				assert.InDelta(t, test.current[i], result.CurrentPercent, 1e-9)
+				assert.InDelta(t, test.drift[i], result.DriftPercent, 1e-9)
+				assert.Equal(t, test.within[i], result.WithinBand)
+				assert.InDelta(t, test.suggested[i], result.Suggested, 1e-9)
+				sum += result.Suggested
+			}
+			assert.InDelta(t, 0, sum, 1e-9)
+		})
+	}
+
+	t.Run("No Classes", func(t *testing.T) {
+		results, needed := Rebalance(nil)
+		assert.False(t, needed)
+		assert.Empty(t, results)
+	})
+}
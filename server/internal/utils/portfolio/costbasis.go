@@ -0,0 +1,166 @@
+package portfolio
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	TRADE_BUY  = "buy"
+	TRADE_SELL = "sell"
+	TRADE_FEE  = "fee" // fee not tied to a buy or sell, e.g. custody or platform fee
+
+	COST_METHOD_FIFO    = "fifo"
+	COST_METHOD_AVERAGE = "average"
+
+	// Quantities are stored with 6 decimals, anything smaller is rounding noise
+	quantityEpsilon = 1e-6
+)
+
+var ErrInsufficientQuantity = errors.New("sell quantity exceeds held quantity")
+
+// Trade is a single buy, sell or fee of a position. Fee is paid on top of a buy and deducted from sell proceeds.
+type Trade struct {
+	ID       string
+	Type     string
+	Date     time.Time
+	Quantity float64
+	Price    float64
+	Fee      float64
+}
+
+// Sale is the realized result of one sell trade
+type Sale struct {
+	TradeID     string
+	Date        time.Time
+	Quantity    float64
+	Proceeds    float64 // quantity * price - fee
+	CostBasis   float64
+	RealizedPnL float64
+}
+
+// Position is the state of a holding after replaying all of its trades
+type Position struct {
+	Method      string
+	Quantity    float64
+	CostBasis   float64 // cost of the quantity still held, buy fees included
+	AverageCost float64
+	TotalFees   float64
+	RealizedPnL float64 // sales and standalone fees
+	Sales       []Sale
+}
+
+type lot struct {
+	quantity float64
+	unitCost float64
+}
+
+// ValidCostMethod reports whether the method is supported by ComputePosition
+func ValidCostMethod(method string) bool {
+	return method == COST_METHOD_FIFO || method == COST_METHOD_AVERAGE
+}
+
+// ComputePosition replays the trades in date order (input order for the same date) using the given cost method
+func ComputePosition(trades []Trade, method string) (Position, error) {
+	if !ValidCostMethod(method) {
+		return Position{}, fmt.Errorf("unknown cost method '%s'", method)
+	}
+
+	sorted := make([]Trade, len(trades))
+	copy(sorted, trades)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Date.Before(sorted[j].Date)
+	})
+
+	position := Position{Method: method}
+	var lots []lot
+
+	for _, trade := range sorted {
+		if trade.Quantity < 0 || trade.Price < 0 || trade.Fee < 0 {
+			return Position{}, fmt.Errorf("trade %s has negative values", trade.ID)
+		}
+		position.TotalFees += trade.Fee
+
+		switch trade.Type {
+		case TRADE_BUY:
+			if trade.Quantity <= 0 {
+				return Position{}, fmt.Errorf("buy trade %s has no quantity", trade.ID)
+			}
+			cost := trade.Quantity*trade.Price + trade.Fee
+			lots = append(lots, lot{quantity: trade.Quantity, unitCost: cost / trade.Quantity})
+			position.Quantity += trade.Quantity
+			position.CostBasis += cost
+
+		case TRADE_SELL:
+			if trade.Quantity <= 0 {
+				return Position{}, fmt.Errorf("sell trade %s has no quantity", trade.ID)
+			}
+			if trade.Quantity > position.Quantity+quantityEpsilon {
+				return Position{}, fmt.Errorf("%w: trade %s sells %.6f, held %.6f", ErrInsufficientQuantity, trade.ID, trade.Quantity, position.Quantity)
+			}
+
+			var cost float64
+			if method == COST_METHOD_FIFO {
+				cost, lots = consumeLots(lots, trade.Quantity)
+			} else {
+				cost = trade.Quantity * position.CostBasis / position.Quantity
+			}
+
+			sale := Sale{
+				TradeID:   trade.ID,
+				Date:      trade.Date,
+				Quantity:  trade.Quantity,
+				Proceeds:  trade.Quantity*trade.Price - trade.Fee,
+				CostBasis: cost,
+			}
+			sale.RealizedPnL = sale.Proceeds - sale.CostBasis
+			position.Sales = append(position.Sales, sale)
+			position.RealizedPnL += sale.RealizedPnL
+
+			position.Quantity -= trade.Quantity
+			position.CostBasis -= cost
+			if position.Quantity < quantityEpsilon {
+				position.Quantity, position.CostBasis, lots = 0, 0, nil
+			}
+
+		case TRADE_FEE:
+			position.RealizedPnL -= trade.Fee
+
+		default:
+			return Position{}, fmt.Errorf("unknown trade type '%s'", trade.Type)
+		}
+	}
+
+	if position.Quantity > 0 {
+		position.AverageCost = position.CostBasis / position.Quantity
+	}
+
+	return position, nil
+}
+
+// MarketValue values the held quantity at the given price
+func (position Position) MarketValue(price float64) float64 {
+	return position.Quantity * price
+}
+
+// UnrealizedPnL is the gain of the held quantity at the given price against its cost basis
+func (position Position) UnrealizedPnL(price float64) float64 {
+	return position.MarketValue(price) - position.CostBasis
+}
+
+// consumeLots removes quantity from the oldest lots and returns the cost of the removed quantity
+func consumeLots(lots []lot, quantity float64) (float64, []lot) {
+	var cost float64
+	for quantity > quantityEpsilon && len(lots) > 0 {
+		used := min(quantity, lots[0].quantity)
+		cost += used * lots[0].unitCost
+		quantity -= used
+		lots[0].quantity -= used
+		if lots[0].quantity < quantityEpsilon {
+			lots = lots[1:]
+		}
+	}
+	return cost, lots
+}
@@ -0,0 +1,160 @@
+package portfolio
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputePosition(t *testing.T) {
+	buy := func(id string, day int, quantity, price, fee float64) Trade {
+		return Trade{ID: id, Type: TRADE_BUY, Date: date(2026, time.January, day), Quantity: quantity, Price: price, Fee: fee}
+	}
+	sell := func(id string, day int, quantity, price, fee float64) Trade {
+		return Trade{ID: id, Type: TRADE_SELL, Date: date(2026, time.January, day), Quantity: quantity, Price: price, Fee: fee}
+	}
+
+	tests := []struct {
+		name        string
+		trades      []Trade
+		method      string
+		quantity    float64
+		costBasis   float64
+		averageCost float64
+		totalFees   float64
+		realizedPnL float64
+		sales       []float64 // realized P&L of every sale
+	}{
+		{
+			name:        "FIFO Buys Only",
+			trades:      []Trade{buy("b1", 1, 10, 100, 10), buy("b2", 2, 10, 120, 0)},
+			method:      COST_METHOD_FIFO,
+			quantity:    20,
+			costBasis:   2210,
+			averageCost: 110.5,
+			totalFees:   10,
+		},
+		{
+			// * Sells 10 of the first lot at 101 and 5 of the second lot at 120
+			name:        "FIFO Partial Sell Across Lots",
+			trades:      []Trade{buy("b1", 1, 10, 100, 10), buy("b2", 2, 10, 120, 0), sell("s1", 3, 15, 130, 15)},
+			method:      COST_METHOD_FIFO,
+			quantity:    5,
+			costBasis:   600,
+			averageCost: 120,
+			totalFees:   25,
+			realizedPnL: 1935 - 1610,
+			sales:       []float64{1935 - 1610},
+		},
+		{
+			name:        "Average Partial Sell",
+			trades:      []Trade{buy("b1", 1, 10, 100, 10), buy("b2", 2, 10, 120, 0), sell("s1", 3, 15, 130, 15)},
+			method:      COST_METHOD_AVERAGE,
+			quantity:    5,
+			costBasis:   552.5,
+			averageCost: 110.5,
+			totalFees:   25,
+			realizedPnL: 1935 - 1657.5,
+			sales:       []float64{1935 - 1657.5},
+		},
+		{
+			name:        "FIFO Sells In Date Order",
+			trades:      []Trade{sell("s1", 3, 5, 90, 0), buy("b2", 2, 10, 80, 0), buy("b1", 1, 10, 100, 0)},
+			method:      COST_METHOD_FIFO,
+			quantity:    15,
+			costBasis:   1300,
+			averageCost: 1300.0 / 15,
+			realizedPnL: -50,
+			sales:       []float64{-50},
+		},
+		{
+			// * The average restarts from the new buy once the position was sold completely
+			name:        "Average After Full Exit",
+			trades:      []Trade{buy("b1", 1, 10, 100, 0), sell("s1", 2, 10, 110, 0), buy("b2", 3, 4, 50, 2)},
+			method:      COST_METHOD_AVERAGE,
+			quantity:    4,
+			costBasis:   202,
+			averageCost: 50.5,
+			totalFees:   2,
+			realizedPnL: 100,
+			sales:       []float64{100},
+		},
+		{
+			name:        "Standalone Fee Is Realized",
+			trades:      []Trade{buy("b1", 1, 10, 100, 0), {ID: "f1", Type: TRADE_FEE, Date: date(2026, time.January, 2), Fee: 5}},
+			method:      COST_METHOD_FIFO,
+			quantity:    10,
+			costBasis:   1000,
+			averageCost: 100,
+			totalFees:   5,
+			realizedPnL: -5,
+		},
+		{
+			name:   "No Trades",
+			method: COST_METHOD_AVERAGE,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			position, err := ComputePosition(test.trades, test.method)
+			assert.Nil(t, err)
+			assert.Equal(t, test.method, position.Method)
+			assert.InDelta(t, test.quantity, position.Quantity, 1e-9)
+			assert.InDelta(t, test.costBasis, position.CostBasis, 1e-9)
+			assert.InDelta(t, test.averageCost, position.AverageCost, 1e-9)
+			assert.InDelta(t, test.totalFees, position.TotalFees, 1e-9)
+			assert.InDelta(t, test.realizedPnL, position.RealizedPnL, 1e-9)
+			assert.Len(t, position.Sales, len(test.sales))
+			for i, pnl := range test.sales {
+				assert.InDelta(t, pnl, position.Sales[i].RealizedPnL, 1e-9)
+			}
+		})
+	}
+
+	t.Run("Does Not Reorder Input", func(t *testing.T) {
+		trades := []Trade{buy("b2", 2, 1, 10, 0), buy("b1", 1, 1, 10, 0)}
+		_, err := ComputePosition(trades, COST_METHOD_FIFO)
+		assert.Nil(t, err)
+		assert.Equal(t, "b2", trades[0].ID)
+	})
+}
+
+func TestComputePositionErrors(t *testing.T) {
+	buy := Trade{ID: "b1", Type: TRADE_BUY, Date: date(2026, time.January, 1), Quantity: 10, Price: 100}
+
+	tests := []struct {
+		name   string
+		trades []Trade
+		method string
+	}{
+		{"Unknown Method", []Trade{buy}, "lifo"},
+		{"Unknown Trade Type", []Trade{{ID: "x", Type: "split", Date: date(2026, time.January, 1), Quantity: 1}}, COST_METHOD_FIFO},
+		{"Negative Values", []Trade{{ID: "b1", Type: TRADE_BUY, Date: date(2026, time.January, 1), Quantity: 1, Price: -1}}, COST_METHOD_FIFO},
+		{"Buy Without Quantity", []Trade{{ID: "b1", Type: TRADE_BUY, Date: date(2026, time.January, 1), Price: 1}}, COST_METHOD_FIFO},
+		{"Sell Without Quantity", []Trade{buy, {ID: "s1", Type: TRADE_SELL, Date: date(2026, time.January, 2), Price: 1}}, COST_METHOD_FIFO},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ComputePosition(test.trades, test.method)
+			assert.NotNil(t, err)
+		})
+	}
+
+	t.Run("Selling More Than Held", func(t *testing.T) {
+		for _, method := range []string{COST_METHOD_FIFO, COST_METHOD_AVERAGE} {
+			_, err := ComputePosition([]Trade{buy, {ID: "s1", Type: TRADE_SELL, Date: date(2026, time.January, 2), Quantity: 10.5, Price: 100}}, method)
+			assert.True(t, errors.Is(err, ErrInsufficientQuantity), method)
+		}
+	})
+
+	t.Run("Selling Everything Within Rounding", func(t *testing.T) {
+		position, err := ComputePosition([]Trade{buy, {ID: "s1", Type: TRADE_SELL, Date: date(2026, time.January, 2), Quantity: 10.0000005, Price: 100}}, COST_METHOD_FIFO)
+		assert.Nil(t, err)
+		assert.Equal(t, 0.0, position.Quantity)
+		assert.Equal(t, 0.0, position.CostBasis)
+	})
+}
@@ -0,0 +1,122 @@
+package portfolio
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	INCOME_DIVIDEND = "dividend"
+	INCOME_COUPON   = "coupon"
+	INCOME_INTEREST = "interest"
+
+	TRAILING_MONTHS = 12
+)
+
+// Income is a payout of a holding, tax is withheld before it reaches the wallet
+type Income struct {
+	Date  time.Time
+	Gross float64
+	Tax   float64
+}
+
+func (income Income) Net() float64 {
+	return income.Gross - income.Tax
+}
+
+// IncomeSummary is the total of the incomes paid in a period
+type IncomeSummary struct {
+	From  time.Time
+	To    time.Time
+	Gross float64
+	Tax   float64
+	Net   float64
+	Count int
+}
+
+// ValidIncomeType reports whether the type is one of INCOME_*
+func ValidIncomeType(incomeType string) bool {
+	return incomeType == INCOME_DIVIDEND || incomeType == INCOME_COUPON || incomeType == INCOME_INTEREST
+}
+
+// TrailingIncome sums the incomes paid in the months before asOf, asOf included
+func TrailingIncome(incomes []Income, asOf time.Time, months int) IncomeSummary {
+	summary := IncomeSummary{From: asOf.AddDate(0, -months, 0), To: asOf}
+	for _, income := range incomes {
+		if !income.Date.After(summary.From) || income.Date.After(asOf) {
+			continue
+		}
+		summary.Gross += income.Gross
+		summary.Tax += income.Tax
+		summary.Net += income.Net()
+		summary.Count++
+	}
+	return summary
+}
+
+// WithholdingTax is the tax withheld from a gross payout at a rate in percent, rounded to a whole unit
+func WithholdingTax(gross, rate float64) float64 {
+	return math.Round(gross*rate) / 100
+}
+
+// YieldPercent is income as a percentage of value, 0 when there is no value
+func YieldPercent(income, value float64) float64 {
+	if value <= 0 {
+		return 0
+	}
+	return income / value * 100
+}
+
+// CouponTerms are the fixed-income terms of a bond or deposit, rate is the annual rate in percent
+type CouponTerms struct {
+	Rate      float64
+	Frequency int // payments per year
+	Maturity  time.Time
+}
+
+// CouponPayment is an expected coupon of a holding
+type CouponPayment struct {
+	Date  time.Time
+	Gross float64
+}
+
+// CouponSchedule lists the coupons paid after from until maturity. Coupon dates are counted back from maturity
+// on its day of the month, or the last day of shorter months, every coupon pays faceValue * rate / frequency.
+func CouponSchedule(terms CouponTerms, faceValue float64, from time.Time) ([]CouponPayment, error) {
+	if terms.Rate <= 0 || terms.Maturity.IsZero() {
+		return nil, fmt.Errorf("coupon rate and maturity date are required")
+	}
+	if terms.Frequency <= 0 || 12%terms.Frequency != 0 {
+		return nil, fmt.Errorf("coupon frequency must be 1, 2, 3, 4, 6 or 12 payments per year")
+	}
+
+	months := 12 / terms.Frequency
+	gross := faceValue * terms.Rate / 100 / float64(terms.Frequency)
+
+	var payments []CouponPayment
+	for i := 0; ; i++ {
+		date := couponDate(terms.Maturity, i*months)
+		if !date.After(from) {
+			break
+		}
+		payments = append(payments, CouponPayment{Date: date, Gross: gross})
+		if len(payments) >= maxSeriesPoints {
+			return nil, fmt.Errorf("schedule has more than %d coupons", maxSeriesPoints)
+		}
+	}
+
+	sort.Slice(payments, func(i, j int) bool {
+		return payments[i].Date.Before(payments[j].Date)
+	})
+	return payments, nil
+}
+
+// couponDate is the date months before maturity, clamped to the last day of the month so a coupon
+// of a maturity on the 31st does not roll over into the next month
+func couponDate(maturity time.Time, months int) time.Time {
+	first := time.Date(maturity.Year(), maturity.Month(), 1, 0, 0, 0, 0, maturity.Location()).AddDate(0, -months, 0)
+	lastDay := first.AddDate(0, 1, -1).Day()
+	return time.Date(first.Year(), first.Month(), min(maturity.Day(), lastDay), maturity.Hour(), maturity.Minute(), maturity.Second(), maturity.Nanosecond(), maturity.Location())
+}
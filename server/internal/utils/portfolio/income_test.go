@@ -0,0 +1,150 @@
+package portfolio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithholdingTax(t *testing.T) {
+	tests := []struct {
+		name  string
+		gross float64
+		rate  float64
+		tax   float64
+	}{
+		{"Dividend Tax", 1_000_000, 10, 100_000},
+		{"Coupon Tax", 437_500, 10, 43_750},
+		{"Rounds Half Up", 1234.5, 15, 185.18},
+		{"Rounds Up", 1234.52, 15, 185.18},
+		{"Rounds Down", 12_345.67, 20, 2469.13},
+		{"No Rate", 1_000_000, 0, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.tax, WithholdingTax(test.gross, test.rate))
+		})
+	}
+}
+
+func TestTrailingIncome(t *testing.T) {
+	asOf := date(2026, time.October, 19)
+	incomes := []Income{
+		{Date: date(2025, time.October, 19), Gross: 1000, Tax: 100},
+		{Date: date(2025, time.October, 20), Gross: 200, Tax: 20},
+		{Date: date(2026, time.April, 1), Gross: 300, Tax: 30},
+		{Date: asOf, Gross: 400, Tax: 40},
+		{Date: date(2026, time.October, 20), Gross: 5000, Tax: 500},
+	}
+
+	summary := TrailingIncome(incomes, asOf, TRAILING_MONTHS)
+
+	assert.Equal(t, date(2025, time.October, 19), summary.From)
+	assert.Equal(t, asOf, summary.To)
+	assert.Equal(t, 3, summary.Count)
+	assert.Equal(t, 900.0, summary.Gross)
+	assert.Equal(t, 90.0, summary.Tax)
+	assert.Equal(t, 810.0, summary.Net)
+}
+
+func TestYieldPercent(t *testing.T) {
+	tests := []struct {
+		name   string
+		income float64
+		value  float64
+		yield  float64
+	}{
+		{"Yield On Cost", 810, 9000, 9},
+		{"Value Below Income", 500, 250, 200},
+		{"No Value", 810, 0, 0},
+		{"Negative Value", 810, -100, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.InDelta(t, test.yield, YieldPercent(test.income, test.value), 1e-9)
+		})
+	}
+}
+
+func TestCouponSchedule(t *testing.T) {
+	tests := []struct {
+		name  string
+		terms CouponTerms
+		from  time.Time
+		dates []time.Time
+		gross float64
+	}{
+		{
+			name:  "Monthly From Month End",
+			terms: CouponTerms{Rate: 6, Frequency: 12, Maturity: date(2027, time.May, 31)},
+			from:  date(2027, time.January, 15),
+			dates: []time.Time{
+				date(2027, time.January, 31),
+				date(2027, time.February, 28),
+				date(2027, time.March, 31),
+				date(2027, time.April, 30),
+				date(2027, time.May, 31),
+			},
+			gross: 5000,
+		},
+		{
+			name:  "Semiannual Into Leap February",
+			terms: CouponTerms{Rate: 6, Frequency: 2, Maturity: date(2028, time.August, 31)},
+			from:  date(2027, time.September, 1),
+			dates: []time.Time{date(2028, time.February, 29), date(2028, time.August, 31)},
+			gross: 30_000,
+		},
+		{
+			name:  "Quarterly On The 30th",
+			terms: CouponTerms{Rate: 8, Frequency: 4, Maturity: date(2027, time.November, 30)},
+			from:  date(2027, time.January, 1),
+			dates: []time.Time{
+				date(2027, time.February, 28),
+				date(2027, time.May, 30),
+				date(2027, time.August, 30),
+				date(2027, time.November, 30),
+			},
+			gross: 20_000,
+		},
+		{
+			name:  "Coupon On From Is Not Listed",
+			terms: CouponTerms{Rate: 5, Frequency: 1, Maturity: date(2028, time.March, 15)},
+			from:  date(2027, time.March, 15),
+			dates: []time.Time{date(2028, time.March, 15)},
+			gross: 50_000,
+		},
+		{
+			name:  "Matured",
+			terms: CouponTerms{Rate: 5, Frequency: 1, Maturity: date(2026, time.March, 15)},
+			from:  date(2026, time.March, 15),
+			dates: nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			payments, err := CouponSchedule(test.terms, 1_000_000, test.from)
+			assert.Nil(t, err)
+
+			var dates []time.Time
+			for _, payment := range payments {
+				dates = append(dates, payment.Date)
+				assert.Equal(t, test.gross, payment.Gross)
+			}
+			assert.Equal(t, test.dates, dates)
+		})
+	}
+}
+
+func TestCouponScheduleInvalidTerms(t *testing.T) {
+	maturity := date(2028, time.March, 15)
+
+	_, err := CouponSchedule(CouponTerms{Rate: 0, Frequency: 2, Maturity: maturity}, 1_000_000, date(2026, time.October, 19))
+	assert.EqualError(t, err, "coupon rate and maturity date are required")
+
+	_, err = CouponSchedule(CouponTerms{Rate: 6, Frequency: 5, Maturity: maturity}, 1_000_000, date(2026, time.October, 19))
+	assert.EqualError(t, err, "coupon frequency must be 1, 2, 3, 4, 6 or 12 payments per year")
+}
@@ -0,0 +1,170 @@
+package portfolio
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	PERIOD_YTD       = "ytd"
+	PERIOD_1Y        = "1y"
+	PERIOD_INCEPTION = "inception"
+
+	daysPerYear = 365.0
+
+	xirrTolerance     = 1e-9
+	xirrMaxIterations = 200
+)
+
+var (
+	ErrXIRRUndefined     = errors.New("xirr needs at least one positive and one negative cash flow")
+	ErrXIRRNoConvergence = errors.New("xirr did not converge")
+)
+
+// CashFlow is money moving between the investor and the portfolio, seen from the investor:
+// contributions and the starting value are negative, withdrawals, income and the ending value are positive
+type CashFlow struct {
+	Date   time.Time
+	Amount float64
+}
+
+// ValuePoint is the value of the portfolio at the end of a date, Contribution is the net external flow
+// into the portfolio on that date (buys minus sells and payouts), already included in Value
+type ValuePoint struct {
+	Date         time.Time
+	Value        float64
+	Contribution float64
+}
+
+// PeriodStart returns the start of a performance period ending at end, inception is the first activity of the portfolio
+func PeriodStart(period string, end, inception time.Time) (time.Time, error) {
+	var start time.Time
+	switch period {
+	case PERIOD_YTD:
+		start = time.Date(end.Year(), time.January, 1, 0, 0, 0, 0, end.Location())
+	case PERIOD_1Y:
+		start = end.AddDate(-1, 0, 0)
+	case PERIOD_INCEPTION, "":
+		return inception, nil
+	default:
+		return time.Time{}, fmt.Errorf("unknown period '%s'", period)
+	}
+
+	if start.Before(inception) {
+		return inception, nil
+	}
+	return start, nil
+}
+
+// XIRR is the annualized money-weighted return of the cash flows, days are counted as actual/365
+func XIRR(flows []CashFlow) (float64, error) {
+	var hasPositive, hasNegative bool
+	for _, flow := range flows {
+		hasPositive = hasPositive || flow.Amount > 0
+		hasNegative = hasNegative || flow.Amount < 0
+	}
+	if !hasPositive || !hasNegative {
+		return 0, ErrXIRRUndefined
+	}
+
+	sorted := make([]CashFlow, len(flows))
+	copy(sorted, flows)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Date.Before(sorted[j].Date)
+	})
+
+	first := sorted[0].Date
+	years := make([]float64, len(sorted))
+	for i, flow := range sorted {
+		years[i] = flow.Date.Sub(first).Hours() / 24 / daysPerYear
+	}
+
+	npv := func(rate float64) (value, derivative float64) {
+		for i, flow := range sorted {
+			discount := math.Pow(1+rate, years[i])
+			value += flow.Amount / discount
+			derivative -= years[i] * flow.Amount / (discount * (1 + rate))
+		}
+		return value, derivative
+	}
+
+	// * Newton converges fast from a sensible guess
+	rate := 0.1
+	for i := 0; i < xirrMaxIterations; i++ {
+		value, derivative := npv(rate)
+		if math.Abs(value) < xirrTolerance {
+			return rate, nil
+		}
+		if derivative == 0 {
+			break
+		}
+		next := rate - value/derivative
+		if next <= -1 || math.IsNaN(next) || math.IsInf(next, 0) {
+			break
+		}
+		if math.Abs(next-rate) < xirrTolerance {
+			return next, nil
+		}
+		rate = next
+	}
+
+	// ? Newton diverged, fall back to bisection between -100% and a rate large enough to change the sign
+	low, high := -1+1e-9, 1.0
+	lowValue, _ := npv(low)
+	highValue, _ := npv(high)
+	for lowValue*highValue > 0 && high < 1e6 {
+		high *= 10
+		highValue, _ = npv(high)
+	}
+	if lowValue*highValue > 0 {
+		return 0, ErrXIRRNoConvergence
+	}
+
+	for i := 0; i < xirrMaxIterations*5; i++ {
+		middle := (low + high) / 2
+		middleValue, _ := npv(middle)
+		if math.Abs(middleValue) < xirrTolerance || (high-low)/2 < xirrTolerance {
+			return middle, nil
+		}
+		if middleValue*lowValue < 0 {
+			high = middle
+		} else {
+			low, lowValue = middle, middleValue
+		}
+	}
+
+	return 0, ErrXIRRNoConvergence
+}
+
+// TWR is the cumulative time-weighted return of the points, which must start with the value at the start of the period.
+// The period is split at every point and flows are assumed at the end of their date, so every sub-period return is
+// (value - contribution) / previous value. Sub-periods that start without value (e.g. before the first buy) are skipped.
+func TWR(points []ValuePoint) float64 {
+	sorted := make([]ValuePoint, len(points))
+	copy(sorted, points)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Date.Before(sorted[j].Date)
+	})
+
+	growth := 1.0
+	for i := 1; i < len(sorted); i++ {
+		previous := sorted[i-1].Value
+		if previous <= 0 {
+			continue
+		}
+		growth *= (sorted[i].Value - sorted[i].Contribution) / previous
+	}
+	return growth - 1
+}
+
+// Annualize converts a cumulative return over from..to to a yearly return, periods shorter than a year are not annualized
+func Annualize(cumulative float64, from, to time.Time) (float64, bool) {
+	years := to.Sub(from).Hours() / 24 / daysPerYear
+	if years < 1 || cumulative <= -1 {
+		return 0, false
+	}
+	return math.Pow(1+cumulative, 1/years) - 1, true
+}
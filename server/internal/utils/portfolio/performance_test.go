@@ -0,0 +1,141 @@
+package portfolio
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestXIRR(t *testing.T) {
+	t.Run("Reference Case", func(t *testing.T) {
+		// * Reference example of the XIRR spreadsheet function
+		rate, err := XIRR([]CashFlow{
+			{Date: date(2008, time.January, 1), Amount: -10000},
+			{Date: date(2008, time.March, 1), Amount: 2750},
+			{Date: date(2008, time.October, 30), Amount: 4250},
+			{Date: date(2009, time.February, 15), Amount: 3250},
+			{Date: date(2009, time.April, 1), Amount: 2750},
+		})
+		assert.Nil(t, err)
+		assert.InDelta(t, 0.373362535, rate, 1e-6)
+	})
+
+	t.Run("One Year Growth", func(t *testing.T) {
+		rate, err := XIRR([]CashFlow{
+			{Date: date(2025, time.January, 1), Amount: -1000},
+			{Date: date(2026, time.January, 1), Amount: 1100},
+		})
+		assert.Nil(t, err)
+		assert.InDelta(t, 0.10, rate, 1e-9)
+	})
+
+	t.Run("Loss With Contribution", func(t *testing.T) {
+		rate, err := XIRR([]CashFlow{
+			{Date: date(2025, time.January, 1), Amount: -1000},
+			{Date: date(2025, time.July, 2), Amount: -1000},
+			{Date: date(2026, time.January, 1), Amount: 1500},
+		})
+		assert.Nil(t, err)
+		assert.Less(t, rate, 0.0)
+		// The rate must bring the net present value to zero
+		years := date(2025, time.July, 2).Sub(date(2025, time.January, 1)).Hours() / 24 / 365
+		npv := -1000 - 1000/math.Pow(1+rate, years) + 1500/(1+rate)
+		assert.InDelta(t, 0, npv, 1e-6)
+	})
+
+	t.Run("Unordered Flows", func(t *testing.T) {
+		rate, err := XIRR([]CashFlow{
+			{Date: date(2026, time.January, 1), Amount: 1100},
+			{Date: date(2025, time.January, 1), Amount: -1000},
+		})
+		assert.Nil(t, err)
+		assert.InDelta(t, 0.10, rate, 1e-9)
+	})
+
+	t.Run("Undefined Without Both Signs", func(t *testing.T) {
+		_, err := XIRR([]CashFlow{
+			{Date: date(2025, time.January, 1), Amount: -1000},
+			{Date: date(2026, time.January, 1), Amount: -100},
+		})
+		assert.ErrorIs(t, err, ErrXIRRUndefined)
+
+		_, err = XIRR(nil)
+		assert.ErrorIs(t, err, ErrXIRRUndefined)
+	})
+}
+
+func TestTWR(t *testing.T) {
+	t.Run("Contribution Does Not Count As Return", func(t *testing.T) {
+		// 100 grows 10% to 110, 50 is added, then 160 grows 10% to 176
+		twr := TWR([]ValuePoint{
+			{Date: date(2025, time.January, 1), Value: 100},
+			{Date: date(2025, time.June, 1), Value: 160, Contribution: 50},
+			{Date: date(2025, time.December, 31), Value: 176},
+		})
+		assert.InDelta(t, 0.21, twr, 1e-9)
+	})
+
+	t.Run("Withdrawal Does Not Count As Loss", func(t *testing.T) {
+		// 200 grows to 220, 120 is withdrawn, then 100 loses 10%
+		twr := TWR([]ValuePoint{
+			{Date: date(2025, time.January, 1), Value: 200},
+			{Date: date(2025, time.June, 1), Value: 100, Contribution: -120},
+			{Date: date(2025, time.December, 31), Value: 90},
+		})
+		assert.InDelta(t, 1.1*0.9-1, twr, 1e-9)
+	})
+
+	t.Run("Starts Empty", func(t *testing.T) {
+		// Nothing is held until the first buy of 100
+		twr := TWR([]ValuePoint{
+			{Date: date(2025, time.January, 1), Value: 0},
+			{Date: date(2025, time.March, 1), Value: 100, Contribution: 100},
+			{Date: date(2025, time.December, 31), Value: 125},
+		})
+		assert.InDelta(t, 0.25, twr, 1e-9)
+	})
+
+	t.Run("No Points", func(t *testing.T) {
+		assert.Equal(t, 0.0, TWR(nil))
+	})
+}
+
+func TestAnnualize(t *testing.T) {
+	annual, ok := Annualize(0.21, date(2024, time.January, 1), date(2025, time.December, 31))
+	assert.True(t, ok)
+	assert.InDelta(t, 0.10, annual, 1e-3)
+
+	_, ok = Annualize(0.05, date(2025, time.January, 1), date(2025, time.June, 1))
+	assert.False(t, ok)
+}
+
+func TestPeriodStart(t *testing.T) {
+	end := date(2026, time.October, 19)
+	inception := date(2024, time.May, 5)
+
+	start, err := PeriodStart(PERIOD_YTD, end, inception)
+	assert.Nil(t, err)
+	assert.Equal(t, date(2026, time.January, 1), start)
+
+	start, err = PeriodStart(PERIOD_1Y, end, inception)
+	assert.Nil(t, err)
+	assert.Equal(t, date(2025, time.October, 19), start)
+
+	start, err = PeriodStart(PERIOD_INCEPTION, end, inception)
+	assert.Nil(t, err)
+	assert.Equal(t, inception, start)
+
+	// * A period cannot start before the first activity
+	start, err = PeriodStart(PERIOD_1Y, end, date(2026, time.March, 1))
+	assert.Nil(t, err)
+	assert.Equal(t, date(2026, time.March, 1), start)
+
+	_, err = PeriodStart("5y", end, inception)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,101 @@
+package portfolio
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	INTERVAL_DAY   = "day"
+	INTERVAL_WEEK  = "week"
+	INTERVAL_MONTH = "month"
+
+	// Guard against huge series, e.g. a daily series over decades
+	maxSeriesPoints = 1000
+)
+
+// PricePoint is the price of an instrument on a date
+type PricePoint struct {
+	Date  time.Time
+	Price float64
+}
+
+// PriceSeries is sorted by date, see NewPriceSeries
+type PriceSeries []PricePoint
+
+// InstrumentKey normalizes an instrument name so every holding of the same instrument shares its prices
+func InstrumentKey(name string) string {
+	return strings.ToUpper(strings.TrimSpace(name))
+}
+
+func NewPriceSeries(points []PricePoint) PriceSeries {
+	series := make(PriceSeries, len(points))
+	copy(series, points)
+	sort.SliceStable(series, func(i, j int) bool {
+		return series[i].Date.Before(series[j].Date)
+	})
+	return series
+}
+
+// TradePrices builds a price series from buy and sell prices, used when no market price is known
+func TradePrices(trades []Trade) PriceSeries {
+	var points []PricePoint
+	for _, trade := range trades {
+		if trade.Type == TRADE_FEE {
+			continue
+		}
+		points = append(points, PricePoint{Date: trade.Date, Price: trade.Price})
+	}
+	return NewPriceSeries(points)
+}
+
+// At returns the latest price at or before the date
+func (series PriceSeries) At(date time.Time) (PricePoint, bool) {
+	i := sort.Search(len(series), func(i int) bool {
+		return series[i].Date.After(date)
+	})
+	if i == 0 {
+		return PricePoint{}, false
+	}
+	return series[i-1], true
+}
+
+// TradesUntil returns the trades made at or before the date
+func TradesUntil(trades []Trade, date time.Time) []Trade {
+	var result []Trade
+	for _, trade := range trades {
+		if !trade.Date.After(date) {
+			result = append(result, trade)
+		}
+	}
+	return result
+}
+
+// DateSeries returns the dates from..to (inclusive) at the given interval, the last point is always to
+func DateSeries(from, to time.Time, interval string) ([]time.Time, error) {
+	if to.Before(from) {
+		return nil, fmt.Errorf("end date is before start date")
+	}
+
+	next := func(date time.Time) time.Time { return date.AddDate(0, 0, 1) }
+	switch interval {
+	case INTERVAL_DAY, "":
+	case INTERVAL_WEEK:
+		next = func(date time.Time) time.Time { return date.AddDate(0, 0, 7) }
+	case INTERVAL_MONTH:
+		next = func(date time.Time) time.Time { return date.AddDate(0, 1, 0) }
+	default:
+		return nil, fmt.Errorf("unknown interval '%s'", interval)
+	}
+
+	var dates []time.Time
+	for date := from; date.Before(to); date = next(date) {
+		dates = append(dates, date)
+		if len(dates) >= maxSeriesPoints {
+			return nil, fmt.Errorf("series has more than %d points, use a larger interval", maxSeriesPoints)
+		}
+	}
+	return append(dates, to), nil
+}
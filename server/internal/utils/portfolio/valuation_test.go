@@ -0,0 +1,145 @@
+package portfolio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstrumentKey(t *testing.T) {
+	assert.Equal(t, "BBCA", InstrumentKey("  bbca "))
+	assert.Equal(t, InstrumentKey("Bitcoin"), InstrumentKey("BITCOIN"))
+}
+
+func TestPriceSeriesAt(t *testing.T) {
+	series := NewPriceSeries([]PricePoint{
+		{Date: date(2026, time.March, 1), Price: 120},
+		{Date: date(2026, time.January, 1), Price: 100},
+		{Date: date(2026, time.February, 1), Price: 110},
+	})
+
+	tests := []struct {
+		name  string
+		date  time.Time
+		price float64
+		found bool
+	}{
+		{"Before First Price", date(2025, time.December, 31), 0, false},
+		{"On A Price Date", date(2026, time.February, 1), 110, true},
+		{"Between Prices", date(2026, time.February, 20), 110, true},
+		{"After Last Price", date(2026, time.June, 1), 120, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			point, found := series.At(test.date)
+			assert.Equal(t, test.found, found)
+			assert.Equal(t, test.price, point.Price)
+		})
+	}
+
+	t.Run("Empty Series", func(t *testing.T) {
+		_, found := PriceSeries(nil).At(date(2026, time.January, 1))
+		assert.False(t, found)
+	})
+}
+
+func TestTradePrices(t *testing.T) {
+	series := TradePrices([]Trade{
+		{ID: "s1", Type: TRADE_SELL, Date: date(2026, time.March, 1), Quantity: 1, Price: 130},
+		{ID: "f1", Type: TRADE_FEE, Date: date(2026, time.February, 1), Fee: 5},
+		{ID: "b1", Type: TRADE_BUY, Date: date(2026, time.January, 1), Quantity: 1, Price: 100},
+	})
+
+	assert.Equal(t, PriceSeries{
+		{Date: date(2026, time.January, 1), Price: 100},
+		{Date: date(2026, time.March, 1), Price: 130},
+	}, series)
+}
+
+func TestTradesUntil(t *testing.T) {
+	trades := []Trade{
+		{ID: "b1", Date: date(2026, time.January, 1)},
+		{ID: "b2", Date: date(2026, time.January, 15)},
+		{ID: "b3", Date: date(2026, time.February, 1)},
+	}
+
+	tests := []struct {
+		name string
+		date time.Time
+		ids  []string
+	}{
+		{"Before All", date(2025, time.December, 31), nil},
+		{"Including The Date", date(2026, time.January, 15), []string{"b1", "b2"}},
+		{"After All", date(2026, time.March, 1), []string{"b1", "b2", "b3"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var ids []string
+			for _, trade := range TradesUntil(trades, test.date) {
+				ids = append(ids, trade.ID)
+			}
+			assert.Equal(t, test.ids, ids)
+		})
+	}
+}
+
+func TestDateSeries(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     time.Time
+		to       time.Time
+		interval string
+		dates    []time.Time
+	}{
+		{
+			name:     "Daily By Default",
+			from:     date(2026, time.January, 1),
+			to:       date(2026, time.January, 3),
+			interval: "",
+			dates:    []time.Time{date(2026, time.January, 1), date(2026, time.January, 2), date(2026, time.January, 3)},
+		},
+		{
+			name:     "Weekly Ends On The End Date",
+			from:     date(2026, time.January, 1),
+			to:       date(2026, time.January, 20),
+			interval: INTERVAL_WEEK,
+			dates:    []time.Time{date(2026, time.January, 1), date(2026, time.January, 8), date(2026, time.January, 15), date(2026, time.January, 20)},
+		},
+		{
+			name:     "Monthly",
+			from:     date(2026, time.January, 1),
+			to:       date(2026, time.March, 1),
+			interval: INTERVAL_MONTH,
+			dates:    []time.Time{date(2026, time.January, 1), date(2026, time.February, 1), date(2026, time.March, 1)},
+		},
+		{
+			name:     "Single Day",
+			from:     date(2026, time.January, 1),
+			to:       date(2026, time.January, 1),
+			interval: INTERVAL_DAY,
+			dates:    []time.Time{date(2026, time.January, 1)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dates, err := DateSeries(test.from, test.to, test.interval)
+			assert.Nil(t, err)
+			assert.Equal(t, test.dates, dates)
+		})
+	}
+
+	t.Run("Invalid Input", func(t *testing.T) {
+		_, err := DateSeries(date(2026, time.February, 1), date(2026, time.January, 1), INTERVAL_DAY)
+		assert.NotNil(t, err)
+
+		_, err = DateSeries(date(2026, time.January, 1), date(2026, time.February, 1), "quarter")
+		assert.NotNil(t, err)
+
+		_, err = DateSeries(date(2000, time.January, 1), date(2026, time.January, 1), INTERVAL_DAY)
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,390 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+)
+
+const (
+	pageMargin   = 15.0
+	contentWidth = 210 - 2*pageMargin // A4 portrait
+	rowHeight    = 6.0
+	chartHeight  = 55.0
+)
+
+var (
+	colorPrimary = [3]int{37, 99, 235}
+	colorIncome  = [3]int{22, 163, 74}
+	colorExpense = [3]int{220, 38, 38}
+	colorMuted   = [3]int{107, 114, 128}
+	colorBorder  = [3]int{229, 231, 235}
+	colorStripe  = [3]int{249, 250, 251}
+)
+
+type renderer struct {
+	pdf *fpdf.Fpdf
+	tr  func(string) string
+}
+
+// Render builds the PDF financial report
+func Render(data Data) ([]byte, error) {
+	summary := Summarize(data)
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pageMargin, pageMargin, pageMargin)
+	pdf.SetAutoPageBreak(true, pageMargin)
+	pdf.SetTitle("Refina Financial Report", true)
+	pdf.SetAuthor("Refina", true)
+	pdf.SetCreationDate(data.GeneratedAt)
+	pdf.AliasNbPages("")
+
+	r := &renderer{pdf: pdf, tr: pdf.UnicodeTranslatorFromDescriptor("")}
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-10)
+		r.font("", 8, colorMuted)
+		pdf.CellFormat(contentWidth/2, 5, r.tr("Generated "+data.GeneratedAt.Format("02 Jan 2006 15:04")), "", 0, "L", false, 0, "")
+		pdf.CellFormat(contentWidth/2, 5, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "", 0, "R", false, 0, "")
+	})
+
+	pdf.AddPage()
+	r.header(data)
+	r.totals(summary)
+	r.periodChart(summary)
+	r.categoryChart(summary)
+	r.categoryTable(summary)
+	r.walletTable(data.Wallets, summary)
+	r.transactionTable(data.Transactions)
+
+	var buffer bytes.Buffer
+	if err := pdf.Output(&buffer); err != nil {
+		return nil, fmt.Errorf("failed to render report: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+func (r *renderer) font(style string, size float64, color [3]int) {
+	r.pdf.SetFont("Helvetica", style, size)
+	r.pdf.SetTextColor(color[0], color[1], color[2])
+}
+
+func (r *renderer) fill(color [3]int) {
+	r.pdf.SetFillColor(color[0], color[1], color[2])
+}
+
+// ensureSpace starts a new page when less than height is left on the current one
+func (r *renderer) ensureSpace(height float64) {
+	_, pageHeight := r.pdf.GetPageSize()
+	if r.pdf.GetY()+height > pageHeight-pageMargin {
+		r.pdf.AddPage()
+	}
+}
+
+func (r *renderer) section(title string) {
+	r.ensureSpace(20)
+	r.pdf.Ln(4)
+	r.font("B", 12, colorPrimary)
+	r.pdf.CellFormat(contentWidth, 8, r.tr(title), "", 1, "L", false, 0, "")
+}
+
+func (r *renderer) header(data Data) {
+	r.font("B", 20, colorPrimary)
+	r.pdf.CellFormat(contentWidth, 10, "Financial Report", "", 1, "L", false, 0, "")
+	r.font("", 10, colorMuted)
+	r.pdf.CellFormat(contentWidth, 6, r.tr(fmt.Sprintf("%s - %s", data.From.Format("02 Jan 2006"), data.To.Format("02 Jan 2006"))), "", 1, "L", false, 0, "")
+	r.pdf.CellFormat(contentWidth, 6, r.tr(strings.TrimSpace(data.UserName+"  "+data.Email)), "", 1, "L", false, 0, "")
+
+	r.pdf.SetDrawColor(colorBorder[0], colorBorder[1], colorBorder[2])
+	r.pdf.Line(pageMargin, r.pdf.GetY()+2, pageMargin+contentWidth, r.pdf.GetY()+2)
+	r.pdf.Ln(4)
+}
+
+func (r *renderer) totals(summary Summary) {
+	r.section("Summary")
+
+	cards := []struct {
+		label string
+		value string
+		color [3]int
+	}{
+		{"Income", FormatCurrency(summary.Income), colorIncome},
+		{"Expense", FormatCurrency(summary.Expense), colorExpense},
+		{"Net", FormatCurrency(summary.Net), colorPrimary},
+		{"Savings rate", fmt.Sprintf("%.1f%%", summary.SavingsRate), colorPrimary},
+	}
+
+	width := contentWidth / float64(len(cards))
+	x, y := r.pdf.GetX(), r.pdf.GetY()
+	for i, card := range cards {
+		left := x + float64(i)*width
+		r.fill(colorStripe)
+		r.pdf.Rect(left+1, y, width-2, 18, "F")
+
+		r.pdf.SetXY(left+3, y+2)
+		r.font("", 8, colorMuted)
+		r.pdf.CellFormat(width-6, 5, card.label, "", 2, "L", false, 0, "")
+		r.font("B", 11, card.color)
+		r.pdf.CellFormat(width-6, 8, r.tr(card.value), "", 0, "L", false, 0, "")
+	}
+	r.pdf.SetXY(x, y+20)
+
+	if summary.Transfers > 0 {
+		r.font("", 8, colorMuted)
+		r.pdf.CellFormat(contentWidth, 5, r.tr("Transfers between wallets ("+FormatCurrency(summary.Transfers)+") are not counted as income or expense."), "", 1, "L", false, 0, "")
+	}
+}
+
+// periodChart draws income and expense bars per day or month
+func (r *renderer) periodChart(summary Summary) {
+	title := "Income and expense per day"
+	if summary.Interval == "month" {
+		title = "Income and expense per month"
+	}
+	r.section(title)
+	r.ensureSpace(chartHeight + 12)
+
+	maxValue := 0.0
+	for _, period := range summary.Periods {
+		maxValue = math.Max(maxValue, math.Max(period.Income, period.Expense))
+	}
+	if maxValue == 0 || len(summary.Periods) == 0 {
+		r.empty("No income or expense in this period.")
+		return
+	}
+
+	left, top := pageMargin+22, r.pdf.GetY()
+	width := contentWidth - 22
+	bottom := top + chartHeight
+
+	// Axis with the maximum and half of it
+	r.pdf.SetDrawColor(colorBorder[0], colorBorder[1], colorBorder[2])
+	r.font("", 7, colorMuted)
+	for _, fraction := range []float64{0, 0.5, 1} {
+		y := bottom - fraction*chartHeight
+		r.pdf.Line(left, y, left+width, y)
+		r.pdf.SetXY(pageMargin, y-2)
+		r.pdf.CellFormat(20, 4, r.tr(FormatCompact(maxValue*fraction)), "", 0, "R", false, 0, "")
+	}
+
+	slot := width / float64(len(summary.Periods))
+	bar := math.Min(slot*0.4, 8)
+	labelEvery := int(math.Ceil(float64(len(summary.Periods)) / 12))
+	for i, period := range summary.Periods {
+		x := left + float64(i)*slot + (slot-2*bar)/2
+
+		incomeHeight := period.Income / maxValue * chartHeight
+		r.fill(colorIncome)
+		r.pdf.Rect(x, bottom-incomeHeight, bar, incomeHeight, "F")
+
+		expenseHeight := period.Expense / maxValue * chartHeight
+		r.fill(colorExpense)
+		r.pdf.Rect(x+bar, bottom-expenseHeight, bar, expenseHeight, "F")
+
+		if i%labelEvery == 0 {
+			label := period.Start.Format("02/01")
+			if summary.Interval == "month" {
+				label = period.Start.Format("Jan 06")
+			}
+			r.pdf.SetXY(left+float64(i)*slot, bottom+1)
+			r.pdf.CellFormat(slot*float64(labelEvery), 4, label, "", 0, "L", false, 0, "")
+		}
+	}
+
+	r.pdf.SetXY(pageMargin, bottom+6)
+	r.legend([]string{"Income", "Expense"}, [][3]int{colorIncome, colorExpense})
+}
+
+// categoryChart draws the largest expense categories as horizontal bars
+func (r *renderer) categoryChart(summary Summary) {
+	categories := summary.TopCategories(TYPE_EXPENSE)
+	r.section("Expense by category")
+	if len(categories) == 0 {
+		r.empty("No expenses in this period.")
+		return
+	}
+	r.ensureSpace(float64(len(categories)) * 7)
+
+	maxValue := categories[0].Amount
+	for _, category := range categories {
+		maxValue = math.Max(maxValue, category.Amount)
+	}
+
+	labelWidth, valueWidth := 45.0, 40.0
+	barWidth := contentWidth - labelWidth - valueWidth
+	for _, category := range categories {
+		y := r.pdf.GetY()
+		r.font("", 9, [3]int{17, 24, 39})
+		r.pdf.CellFormat(labelWidth, 6, r.tr(truncate(category.Category, 28)), "", 0, "L", false, 0, "")
+
+		r.fill(colorStripe)
+		r.pdf.Rect(pageMargin+labelWidth, y+1, barWidth, 4, "F")
+		if maxValue > 0 {
+			r.fill(colorExpense)
+			r.pdf.Rect(pageMargin+labelWidth, y+1, barWidth*category.Amount/maxValue, 4, "F")
+		}
+
+		r.pdf.SetXY(pageMargin+labelWidth+barWidth, y)
+		share := 0.0
+		if summary.Expense > 0 {
+			share = category.Amount / summary.Expense * 100
+		}
+		r.pdf.CellFormat(valueWidth, 6, r.tr(fmt.Sprintf("%s (%.0f%%)", FormatCurrency(category.Amount), share)), "", 1, "R", false, 0, "")
+	}
+}
+
+func (r *renderer) categoryTable(summary Summary) {
+	r.section("Category breakdown")
+	if len(summary.Categories) == 0 {
+		r.empty("No income or expense in this period.")
+		return
+	}
+
+	columns := []column{{"Category", 80, "L"}, {"Type", 30, "L"}, {"Transactions", 30, "R"}, {"Amount", 40, "R"}}
+	r.tableHeader(columns)
+	for i, category := range summary.Categories {
+		r.tableRow(columns, i, []string{category.Category, typeLabel(category.Type), fmt.Sprint(category.Count), FormatCurrency(category.Amount)})
+	}
+}
+
+func (r *renderer) walletTable(wallets []Wallet, summary Summary) {
+	r.section("Wallet balances")
+	if len(wallets) == 0 {
+		r.empty("No wallets.")
+		return
+	}
+
+	columns := []column{{"Wallet", 60, "L"}, {"Type", 30, "L"}, {"Opening", 30, "R"}, {"Closing", 30, "R"}, {"Change", 30, "R"}}
+	r.tableHeader(columns)
+	for i, wallet := range wallets {
+		r.tableRow(columns, i, []string{wallet.Name, wallet.Type, FormatCurrency(wallet.Opening), FormatCurrency(wallet.Closing), FormatCurrency(wallet.Closing - wallet.Opening)})
+	}
+
+	r.font("B", 9, [3]int{17, 24, 39})
+	r.pdf.CellFormat(90, rowHeight, "Total", "T", 0, "L", false, 0, "")
+	r.pdf.CellFormat(30, rowHeight, r.tr(FormatCurrency(summary.OpeningTotal)), "T", 0, "R", false, 0, "")
+	r.pdf.CellFormat(30, rowHeight, r.tr(FormatCurrency(summary.ClosingTotal)), "T", 0, "R", false, 0, "")
+	r.pdf.CellFormat(30, rowHeight, r.tr(FormatCurrency(summary.ClosingTotal-summary.OpeningTotal)), "T", 1, "R", false, 0, "")
+}
+
+func (r *renderer) transactionTable(transactions []Transaction) {
+	r.section("Transactions")
+	if len(transactions) == 0 {
+		r.empty("No transactions in this period.")
+		return
+	}
+
+	columns := []column{{"Date", 22, "L"}, {"Wallet", 32, "L"}, {"Category", 36, "L"}, {"Description", 55, "L"}, {"Amount", 35, "R"}}
+	r.tableHeader(columns)
+	for i, transaction := range transactions {
+		if _, pageHeight := r.pdf.GetPageSize(); r.pdf.GetY()+rowHeight > pageHeight-pageMargin {
+			r.pdf.AddPage()
+			r.tableHeader(columns)
+		}
+
+		amount := FormatCurrency(transaction.Flow())
+		if transaction.Flow() > 0 {
+			amount = "+" + amount
+		}
+		r.tableRow(columns, i, []string{
+			transaction.Date.Format("02/01/2006"),
+			truncate(transaction.Wallet, 18),
+			truncate(transaction.Category, 20),
+			truncate(transaction.Description, 34),
+			amount,
+		})
+	}
+}
+
+type column struct {
+	title string
+	width float64
+	align string
+}
+
+func (r *renderer) tableHeader(columns []column) {
+	r.ensureSpace(2 * rowHeight)
+	r.font("B", 9, [3]int{255, 255, 255})
+	r.fill(colorPrimary)
+	for _, col := range columns {
+		r.pdf.CellFormat(col.width, rowHeight+1, col.title, "", 0, col.align, true, 0, "")
+	}
+	r.pdf.Ln(-1)
+}
+
+func (r *renderer) tableRow(columns []column, index int, values []string) {
+	r.font("", 8, [3]int{17, 24, 39})
+	r.fill(colorStripe)
+	for i, col := range columns {
+		r.pdf.CellFormat(col.width, rowHeight, r.tr(values[i]), "", 0, col.align, index%2 == 1, 0, "")
+	}
+	r.pdf.Ln(-1)
+}
+
+func (r *renderer) legend(labels []string, colors [][3]int) {
+	r.font("", 8, colorMuted)
+	for i, label := range labels {
+		r.fill(colors[i])
+		r.pdf.Rect(r.pdf.GetX(), r.pdf.GetY()+1, 3, 3, "F")
+		r.pdf.SetX(r.pdf.GetX() + 4)
+		r.pdf.CellFormat(20, 5, label, "", 0, "L", false, 0, "")
+	}
+	r.pdf.Ln(-1)
+}
+
+func (r *renderer) empty(message string) {
+	r.font("I", 9, colorMuted)
+	r.pdf.CellFormat(contentWidth, rowHeight, message, "", 1, "L", false, 0, "")
+}
+
+// FormatCurrency formats an amount as rupiah, e.g. "Rp 1.250.000" or "-Rp 50.000"
+func FormatCurrency(amount float64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	digits := fmt.Sprintf("%.0f", math.Round(amount))
+	var grouped strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+	return sign + "Rp " + grouped.String()
+}
+
+// FormatCompact formats an amount for chart axes, e.g. "1,5 jt"
+func FormatCompact(amount float64) string {
+	switch abs := math.Abs(amount); {
+	case abs >= 1e9:
+		return strings.Replace(fmt.Sprintf("%.1f M", amount/1e9), ".", ",", 1)
+	case abs >= 1e6:
+		return strings.Replace(fmt.Sprintf("%.1f jt", amount/1e6), ".", ",", 1)
+	case abs >= 1e3:
+		return fmt.Sprintf("%.0f rb", amount/1e3)
+	}
+	return fmt.Sprintf("%.0f", amount)
+}
+
+func typeLabel(categoryType string) string {
+	switch categoryType {
+	case TYPE_INCOME:
+		return "Income"
+	case TYPE_EXPENSE:
+		return "Expense"
+	}
+	return "Transfer"
+}
+
+func truncate(text string, length int) string {
+	runes := []rune(strings.TrimSpace(text))
+	if len(runes) <= length {
+		return string(runes)
+	}
+	return string(runes[:length-3]) + "..."
+}
@@ -0,0 +1,228 @@
+package report
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	TYPE_INCOME        = "income"
+	TYPE_EXPENSE       = "expense"
+	TYPE_FUND_TRANSFER = "fund_transfer"
+
+	// Fund transfers are recorded as a pair of transactions in these categories
+	CATEGORY_CASH_OUT = "Cash Out"
+	CATEGORY_CASH_IN  = "Cash In"
+
+	// Periods up to this many days are charted per day, longer periods per month
+	maxDailyChartDays = 62
+	// Categories beyond this are summed into CATEGORY_OTHERS in the charts
+	maxChartCategories = 8
+	CATEGORY_OTHERS    = "Others"
+)
+
+// Transaction is a transaction of the report period
+type Transaction struct {
+	Date        time.Time
+	WalletID    string
+	Wallet      string
+	Category    string
+	Type        string // TYPE_*
+	Amount      float64
+	Description string
+}
+
+// Flow is the effect of the transaction on its wallet balance
+func (transaction Transaction) Flow() float64 {
+	switch {
+	case transaction.Type == TYPE_INCOME:
+		return transaction.Amount
+	case transaction.Type == TYPE_EXPENSE:
+		return -transaction.Amount
+	case transaction.Category == CATEGORY_CASH_IN:
+		return transaction.Amount
+	case transaction.Category == CATEGORY_CASH_OUT:
+		return -transaction.Amount
+	}
+	return 0
+}
+
+// Wallet is a wallet with its balance at the start and the end of the report period
+type Wallet struct {
+	ID      string
+	Name    string
+	Type    string
+	Opening float64
+	Closing float64
+}
+
+type Data struct {
+	UserName    string
+	Email       string
+	From        time.Time
+	To          time.Time
+	GeneratedAt time.Time
+
+	Transactions []Transaction // transactions of the period, ordered by date
+	Wallets      []Wallet
+}
+
+type CategoryTotal struct {
+	Category string
+	Type     string
+	Amount   float64
+	Count    int
+}
+
+// PeriodTotal is the income and expense of a day or month, see Summary.Interval
+type PeriodTotal struct {
+	Start   time.Time
+	Income  float64
+	Expense float64
+}
+
+type Summary struct {
+	Income       float64
+	Expense      float64
+	Net          float64
+	SavingsRate  float64 // net / income, in percent
+	Transfers    float64 // money moved between wallets, not counted as income or expense
+	Categories   []CategoryTotal
+	Interval     string // "day" or "month"
+	Periods      []PeriodTotal
+	OpeningTotal float64
+	ClosingTotal float64
+}
+
+// Summarize totals the transactions of the report
+func Summarize(data Data) Summary {
+	summary := Summary{Interval: "day"}
+	if data.To.Sub(data.From) > maxDailyChartDays*24*time.Hour {
+		summary.Interval = "month"
+	}
+
+	categories := make(map[string]*CategoryTotal)
+	periods := make(map[time.Time]*PeriodTotal)
+	for _, transaction := range data.Transactions {
+		switch transaction.Type {
+		case TYPE_INCOME:
+			summary.Income += transaction.Amount
+		case TYPE_EXPENSE:
+			summary.Expense += transaction.Amount
+		default:
+			if transaction.Category == CATEGORY_CASH_IN {
+				summary.Transfers += transaction.Amount
+			}
+			continue
+		}
+
+		key := transaction.Type + "/" + transaction.Category
+		if categories[key] == nil {
+			categories[key] = &CategoryTotal{Category: transaction.Category, Type: transaction.Type}
+		}
+		categories[key].Amount += transaction.Amount
+		categories[key].Count++
+
+		start := periodStart(transaction.Date, summary.Interval)
+		if periods[start] == nil {
+			periods[start] = &PeriodTotal{Start: start}
+		}
+		if transaction.Type == TYPE_INCOME {
+			periods[start].Income += transaction.Amount
+		} else {
+			periods[start].Expense += transaction.Amount
+		}
+	}
+
+	summary.Net = summary.Income - summary.Expense
+	if summary.Income > 0 {
+		summary.SavingsRate = summary.Net / summary.Income * 100
+	}
+
+	for _, category := range categories {
+		summary.Categories = append(summary.Categories, *category)
+	}
+	sort.Slice(summary.Categories, func(i, j int) bool {
+		if summary.Categories[i].Type != summary.Categories[j].Type {
+			return summary.Categories[i].Type == TYPE_EXPENSE
+		}
+		if summary.Categories[i].Amount != summary.Categories[j].Amount {
+			return summary.Categories[i].Amount > summary.Categories[j].Amount
+		}
+		return summary.Categories[i].Category < summary.Categories[j].Category
+	})
+
+	// Every day or month of the period is charted, including those without transactions
+	for start := periodStart(data.From, summary.Interval); !start.After(data.To); start = nextPeriod(start, summary.Interval) {
+		period := PeriodTotal{Start: start}
+		if total, ok := periods[start]; ok {
+			period = *total
+		}
+		summary.Periods = append(summary.Periods, period)
+	}
+
+	for _, wallet := range data.Wallets {
+		summary.OpeningTotal += wallet.Opening
+		summary.ClosingTotal += wallet.Closing
+	}
+
+	return summary
+}
+
+// TopCategories returns the largest categories of the type, the rest is summed into CATEGORY_OTHERS
+func (summary Summary) TopCategories(categoryType string) []CategoryTotal {
+	var result []CategoryTotal
+	for _, category := range summary.Categories {
+		if category.Type != categoryType {
+			continue
+		}
+		if len(result) < maxChartCategories-1 {
+			result = append(result, category)
+			continue
+		}
+		if len(result) == maxChartCategories-1 {
+			result = append(result, CategoryTotal{Category: CATEGORY_OTHERS, Type: categoryType})
+		}
+		result[len(result)-1].Amount += category.Amount
+		result[len(result)-1].Count += category.Count
+	}
+	return result
+}
+
+// WalletBalances derives the balances at the start and the end of the period from the current balances.
+// The wallets carry their current balance in Closing, transactions are every transaction from the start of the period until now.
+func WalletBalances(wallets []Wallet, transactions []Transaction, from, to time.Time) []Wallet {
+	afterPeriod := make(map[string]float64)
+	inPeriod := make(map[string]float64)
+	for _, transaction := range transactions {
+		switch {
+		case transaction.Date.Before(from):
+		case transaction.Date.After(to):
+			afterPeriod[transaction.WalletID] += transaction.Flow()
+		default:
+			inPeriod[transaction.WalletID] += transaction.Flow()
+		}
+	}
+
+	result := make([]Wallet, 0, len(wallets))
+	for _, wallet := range wallets {
+		wallet.Closing -= afterPeriod[wallet.ID]
+		wallet.Opening = wallet.Closing - inPeriod[wallet.ID]
+		result = append(result, wallet)
+	}
+	return result
+}
+
+func periodStart(date time.Time, interval string) time.Time {
+	if interval == "month" {
+		return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func nextPeriod(start time.Time, interval string) time.Time {
+	if interval == "month" {
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
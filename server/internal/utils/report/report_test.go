@@ -0,0 +1,143 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestTransactionFlow(t *testing.T) {
+	tests := []struct {
+		name        string
+		transaction Transaction
+		flow        float64
+	}{
+		{"Income", Transaction{Type: TYPE_INCOME, Category: "Salary", Amount: 100}, 100},
+		{"Expense", Transaction{Type: TYPE_EXPENSE, Category: "Food", Amount: 100}, -100},
+		{"Transfer In", Transaction{Type: TYPE_FUND_TRANSFER, Category: CATEGORY_CASH_IN, Amount: 100}, 100},
+		{"Transfer Out", Transaction{Type: TYPE_FUND_TRANSFER, Category: CATEGORY_CASH_OUT, Amount: 100}, -100},
+		{"Unknown", Transaction{Type: "adjustment", Category: "Other", Amount: 100}, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.flow, test.transaction.Flow())
+		})
+	}
+}
+
+func TestWalletBalances(t *testing.T) {
+	from := date(2026, time.January, 1)
+	to := time.Date(2026, time.January, 31, 23, 59, 59, 0, time.UTC)
+
+	transaction := func(walletID string, date time.Time, transactionType, category string, amount float64) Transaction {
+		return Transaction{Date: date, WalletID: walletID, Type: transactionType, Category: category, Amount: amount}
+	}
+
+	tests := []struct {
+		name         string
+		closing      float64 // current balance
+		transactions []Transaction
+		opening      float64
+		periodEnd    float64
+	}{
+		{
+			name:      "No Transactions",
+			closing:   1000,
+			opening:   1000,
+			periodEnd: 1000,
+		},
+		{
+			name:    "Only In The Period",
+			closing: 1300,
+			transactions: []Transaction{
+				transaction("w1", date(2026, time.January, 5), TYPE_INCOME, "Salary", 500),
+				transaction("w1", date(2026, time.January, 20), TYPE_EXPENSE, "Food", 200),
+			},
+			opening:   1000,
+			periodEnd: 1300,
+		},
+		{
+			// * Transactions after the period are taken back out of the current balance first
+			name:    "After The Period",
+			closing: 900,
+			transactions: []Transaction{
+				transaction("w1", date(2026, time.January, 10), TYPE_INCOME, "Salary", 500),
+				transaction("w1", date(2026, time.February, 3), TYPE_EXPENSE, "Rent", 600),
+			},
+			opening:   1000,
+			periodEnd: 1500,
+		},
+		{
+			name:    "On The Period Edges",
+			closing: 1000,
+			transactions: []Transaction{
+				transaction("w1", from, TYPE_INCOME, "Salary", 100),
+				transaction("w1", to, TYPE_EXPENSE, "Food", 50),
+				transaction("w1", to.Add(time.Second), TYPE_EXPENSE, "Food", 30),
+			},
+			opening:   980,
+			periodEnd: 1030,
+		},
+		{
+			name:    "Fund Transfers",
+			closing: 700,
+			transactions: []Transaction{
+				transaction("w1", date(2026, time.January, 2), TYPE_FUND_TRANSFER, CATEGORY_CASH_OUT, 300),
+				transaction("w1", date(2026, time.February, 2), TYPE_FUND_TRANSFER, CATEGORY_CASH_IN, 200),
+			},
+			opening:   800,
+			periodEnd: 500,
+		},
+		{
+			name:    "Before The Period Is Ignored",
+			closing: 1000,
+			transactions: []Transaction{
+				transaction("w1", date(2025, time.December, 31), TYPE_INCOME, "Salary", 500),
+			},
+			opening:   1000,
+			periodEnd: 1000,
+		},
+		{
+			name:    "Other Wallets Are Ignored",
+			closing: 1000,
+			transactions: []Transaction{
+				transaction("w2", date(2026, time.January, 5), TYPE_INCOME, "Salary", 500),
+			},
+			opening:   1000,
+			periodEnd: 1000,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			wallets := []Wallet{{ID: "w1", Name: "Cash", Type: "Cash", Closing: test.closing}}
+			result := WalletBalances(wallets, test.transactions, from, to)
+
+			assert.Len(t, result, 1)
+			assert.Equal(t, "w1", result[0].ID)
+			assert.Equal(t, "Cash", result[0].Name)
+			assert.InDelta(t, test.opening, result[0].Opening, 1e-9)
+			assert.InDelta(t, test.periodEnd, result[0].Closing, 1e-9)
+			// The current balance of the input is left alone
+			assert.Equal(t, test.closing, wallets[0].Closing)
+		})
+	}
+
+	t.Run("Several Wallets", func(t *testing.T) {
+		result := WalletBalances([]Wallet{{ID: "w1", Closing: 100}, {ID: "w2", Closing: 200}}, []Transaction{
+			transaction("w1", date(2026, time.January, 5), TYPE_EXPENSE, "Food", 50),
+			transaction("w2", date(2026, time.February, 5), TYPE_INCOME, "Salary", 80),
+		}, from, to)
+
+		assert.Equal(t, []Wallet{
+			{ID: "w1", Opening: 150, Closing: 100},
+			{ID: "w2", Opening: 120, Closing: 120},
+		}, result)
+	})
+}